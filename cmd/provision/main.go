@@ -0,0 +1,178 @@
+// Package main is the bulk provisioning CLI for the Smart Bin Orchestrator.
+// It uploads a CSV or JSON device manifest to POST /api/v1/devices/import, prints the
+// per-row report and optionally writes the claim tokens to a CSV file for the factory.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  PROVISION - IMPORTACIÓN MASIVA DE DISPOSITIVOS                ║
+║                                                                ║
+║  go run ./cmd/provision -file bins.csv -tokens tokens.csv      ║
+║                                                                ║
+║  Exit codes: 0 todo creado, 1 alguna fila falló,               ║
+║              2 error de uso o de la petición                   ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// importResponse es el sobre estándar de la API con el reporte de importación.
+type importResponse struct {
+	Success bool                          `json:"success"`
+	Data    *models.ImportDevicesResponse `json:"data"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func main() {
+	url := flag.String("url", getEnv("ORCHESTRATOR_URL", "http://localhost:8080"), "Orchestrator base URL")
+	file := flag.String("file", "", "Manifest file (.csv or .json)")
+	format := flag.String("format", "", "Manifest format: csv or json (default: from the file extension)")
+	tokens := flag.String("tokens", "", "Write device_id,serial_number,claim_token,expires_at to this CSV file")
+	timeout := flag.Duration("timeout", 60*time.Second, "Request timeout")
+	apiKey := flag.String("api-key", getEnv("ORCHESTRATOR_API_KEY", ""), "API key of the organization (or the admin key)")
+	orgID := flag.String("org", "", "Organization of the devices (only with the admin key)")
+	flag.Parse()
+
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "usage: provision -file manifest.csv [-url http://localhost:8080] [-tokens tokens.csv]")
+		os.Exit(2)
+	}
+
+	report, err := importManifest(*url, *file, *format, *apiKey, *orgID, *timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		os.Exit(2)
+	}
+
+	for _, result := range report.Results {
+		if result.Status == models.ImportRowFailed {
+			fmt.Printf("row %d (%s): %s\n", result.Row, result.SerialNumber, result.Error)
+		}
+	}
+	fmt.Printf("%d devices: %d created, %d failed\n", report.Total, report.Created, report.Failed)
+
+	if *tokens != "" {
+		if err := writeTokens(*tokens, report); err != nil {
+			fmt.Fprintf(os.Stderr, "writing tokens: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Printf("claim tokens written to %s\n", *tokens)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// importManifest sube el manifiesto tal cual; el servidor lo valida y parsea.
+func importManifest(baseURL, file, format, apiKey, orgID string, timeout time.Duration) (*models.ImportDevicesResponse, error) {
+	body, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	}
+	var contentType string
+	switch format {
+	case "csv":
+		contentType = "text/csv"
+	case "json":
+		contentType = "application/json"
+	default:
+		return nil, fmt.Errorf("unknown manifest format %q (use -format csv|json)", format)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(baseURL, "/")+"/api/v1/devices/import", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	if orgID != "" {
+		req.Header.Set("X-Org-ID", orgID)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded importResponse
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("unexpected response (HTTP %d): %s", resp.StatusCode, raw)
+	}
+	if !decoded.Success || decoded.Data == nil {
+		if decoded.Error != nil {
+			return nil, fmt.Errorf("%s: %s", decoded.Error.Code, decoded.Error.Message)
+		}
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	return decoded.Data, nil
+}
+
+// writeTokens guarda los claim tokens de las filas creadas (solo se muestran una vez).
+func writeTokens(path string, report *models.ImportDevicesResponse) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(out)
+	_ = writer.Write([]string{"device_id", "serial_number", "claim_token", "expires_at"})
+	for _, result := range report.Results {
+		if result.Status != models.ImportRowCreated {
+			continue
+		}
+		expiresAt := ""
+		if result.ClaimTokenExpiresAt != nil {
+			expiresAt = result.ClaimTokenExpiresAt.Format(time.RFC3339)
+		}
+		_ = writer.Write([]string{result.DeviceID, result.SerialNumber, result.ClaimToken, expiresAt})
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// getEnv retorna la variable de entorno o el valor por defecto.
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,6 +13,13 @@ import (
 
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/api/router"
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/mqtt"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/pki"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -69,29 +77,22 @@ func main() {
 	// ═══════════════════════════════════════════════════════════════
 	// PASO 4: INICIALIZAR dependencies
 	// ═══════════════════════════════════════════════════════════════
-	// TODO: En pasos futuros aquí inicializaremos:
+	// Por ahora los repositorios, el presigner y la cola son adaptadores
+	// locales en memoria (internal/infrastructure/local).
 	//
-	// 1. AWS Clients:
+	// TODO: Reemplazar por los adaptadores AWS:
 	//    - DynamoDB (para guardar jobs y devices)
 	//    - S3 (para generar URLs prefirmadas)
 	//    - SQS (para publicar mensajes al Classifier)
 	//    - IoT Core (para enviar resultados a dispositivos)
-	//
-	// 2. HTTP Clients:
-	//    - Classifier Service client
-	//    - Decision Service client
-	//
-	// 3. Repositories:
-	//    - JobRepository (abstracción de DynamoDB)
-	//    - DeviceRepository (abstracción de DynamoDB)
-	//
-	// 4. Domain Services:
-	//    - OrchestrationService (lógica de orquestación)
-	//    - JobManager
-	//    - DeviceManager
-	//
-	// Por ahora lo dejamos comentado:
-	// deps := initializeDependencies(cfg)
+	authority := initializeCertificateAuthority(cfg)
+	deps, closeDependencies := initializeDependencies(cfg, authority)
+
+	// Tareas en segundo plano (retención de telemetría, etc.)
+	// Se detienen al cancelar workersCtx durante el shutdown.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	startBackgroundWorkers(workersCtx, deps)
 
 	// ═══════════════════════════════════════════════════════════════
 	// PASO 5: CONFIGURAR ROUTER HTTP
@@ -101,7 +102,7 @@ func main() {
 	// - Jobs API (/api/v1/jobs)
 	// - Devices API (/api/v1/devices)
 	// - Webhooks (/api/v1/webhooks)
-	httpRouter := router.NewRouter(cfg)
+	httpRouter := router.NewRouter(cfg, deps)
 
 	// ═══════════════════════════════════════════════════════════════
 	// PASO 6: CREAR SERVIDOR HTTP
@@ -119,6 +120,12 @@ func main() {
 		MaxHeaderBytes: 1 << 20,
 	}
 
+	// Listener mTLS opcional para dispositivos (MTLS_PORT)
+	mtlsServer := newMTLSServer(cfg, authority, deps)
+
+	// Métricas Prometheus en su propio puerto (METRICS_PORT), fuera del router público
+	metricsServer := newMetricsServer(cfg, deps.Metrics)
+
 	// ═══════════════════════════════════════════════════════════════
 	// PASO 7: INICIAR SERVIDOR HTTP (EN GOROUTINE)
 	// ═══════════════════════════════════════════════════════════════
@@ -136,6 +143,31 @@ func main() {
 		}
 	}()
 
+	if mtlsServer != nil {
+		go func() {
+			log.Info().
+				Str("port", cfg.Server.MTLS.Port).
+				Msgf("mTLS server listening on https://localhost:%s", cfg.Server.MTLS.Port)
+
+			// Los certificados ya vienen en TLSConfig
+			if err := mtlsServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatal().Err(err).Msg("Failed to start mTLS server")
+			}
+		}()
+	}
+
+	if metricsServer != nil {
+		go func() {
+			log.Info().
+				Str("port", cfg.Observability.MetricsPort).
+				Msgf("Metrics server listening on http://localhost:%s/metrics", cfg.Observability.MetricsPort)
+
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal().Err(err).Msg("Failed to start metrics server")
+			}
+		}()
+	}
+
 	// ═══════════════════════════════════════════════════════════════
 	// PASO 8: ESPERAR SEÑAL DE TERMINACIÓN
 	// ═══════════════════════════════════════════════════════════════
@@ -175,17 +207,245 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
+	if mtlsServer != nil {
+		if err := mtlsServer.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("mTLS server forced to shutdown")
+		}
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("Metrics server forced to shutdown")
+		}
+	}
+
+	stopWorkers()
 
-	// TODO: Cerrar otras conexiones
-	// En pasos futuros aquí cerraremos:
-	// - Conexiones de AWS clients
-	// - Conexiones de HTTP clients
-	// - Conexiones de base de datos (si hay)
-	// deps.Close()
+	// Cerrar conexiones externas (MQTT)
+	// TODO: Cerrar AWS clients y HTTP clients cuando existan
+	closeDependencies()
 
 	log.Info().Msg("Server stopped gracefully")
 }
 
+// initializeDependencies builds the adapters and domain services injected into the router.
+// The returned function closes the external connections opened here.
+func initializeDependencies(cfg *config.Config, authority *pki.Authority) (*router.Dependencies, func()) {
+	jobRepository := local.NewJobRepository()
+	deviceRepository := local.NewDeviceRepository()
+	telemetryRepository := local.NewTelemetryRepository()
+	alertRepository := local.NewAlertRepository()
+	shadowRepository := local.NewShadowRepository()
+	commandRepository := local.NewCommandRepository()
+	firmwareRepository := local.NewFirmwareRepository()
+	certificateRepository := local.NewCertificateRepository()
+	groupRepository := local.NewGroupRepository()
+	organizationRepository := local.NewOrganizationRepository()
+	credentialRepository := local.NewCredentialRepository()
+	auditRepository := local.NewAuditRepository()
+	eventBus := local.NewEventBus()
+
+	// Los clientes externos se envuelven para registrar cada llamada en las métricas
+	registry := metrics.NewRegistry()
+	presigner := metrics.InstrumentPresigner(local.NewPresigner(cfg.AWS.Region), registry)
+	publisher := metrics.InstrumentSQSPublisher(local.NewPublisher(), registry)
+
+	iotClient := initializeIoTClient(cfg)
+	var iotPublisher ports.IoTPublisher = local.NewIoTPublisher()
+	if iotClient != nil {
+		iotPublisher = mqtt.NewIoTPublisher(iotClient)
+	}
+	iotPublisher = metrics.InstrumentIoTPublisher(iotPublisher, registry)
+
+	jobManager := services.NewJobManager(cfg, jobRepository, deviceRepository, presigner, publisher, iotPublisher, registry)
+	deviceManager := services.NewDeviceManager(deviceRepository)
+	certificates := services.NewCertificateService(cfg, authority, certificateRepository, deviceManager)
+	provisioning := services.NewProvisioningService(cfg, deviceManager, deviceRepository, certificates)
+	telemetry := services.NewTelemetryService(cfg, telemetryRepository, deviceRepository)
+	heartbeat := services.NewHeartbeatMonitor(cfg, deviceManager, deviceRepository, eventBus)
+	alertRules, err := services.LoadAlertRules(cfg.Alerts.RulesFile)
+	if err != nil {
+		log.Fatal().Err(err).Str("file", cfg.Alerts.RulesFile).Msg("Failed to load alert rules")
+	}
+	alerts := services.NewAlertService(alertRepository, groupRepository, organizationRepository, deviceManager, alertRules)
+	forecast := services.NewForecastService(cfg, deviceManager, deviceRepository, jobRepository, telemetryRepository)
+	routePlanner := services.NewRoutePlanner(deviceRepository)
+	stats := services.NewStatsService(deviceRepository, jobRepository, groupRepository)
+	shadows := services.NewShadowService(shadowRepository, deviceRepository, iotPublisher)
+	eventBus.Subscribe(shadows.HandleConnectivityEvent)
+	commands := services.NewCommandService(commandRepository, deviceRepository, iotPublisher)
+	eventBus.Subscribe(commands.HandleConnectivityEvent)
+	firmware := services.NewFirmwareService(cfg, firmwareRepository, deviceManager, deviceRepository, presigner, iotPublisher)
+	eventBus.Subscribe(firmware.HandleConnectivityEvent)
+	deviceUpdates := services.NewDeviceUpdateService(deviceManager, telemetry, forecast, alerts, eventBus)
+	decommission := services.NewDecommissionService(deviceManager, certificates, commands, alerts)
+	groups := services.NewGroupService(groupRepository, deviceRepository, deviceUpdates, commands, shadows)
+	tenants := services.NewTenantService(cfg, organizationRepository, credentialRepository, deviceRepository, alerts)
+	if err := tenants.EnsureOrganization(context.Background(), models.DefaultOrgID, "Default"); err != nil {
+		log.Fatal().Err(err).Msg("Failed to create default organization")
+	}
+	if cfg.Security.AdminAPIKey == "" {
+		log.Warn().Msg("ADMIN_API_KEY not set, API requests without a key act as super-admin")
+	}
+	audit := services.NewAuditService(auditRepository)
+	deviceEvents := services.NewDeviceEventService(
+		jobManager, deviceManager, telemetry, heartbeat, alerts, forecast, shadows, commands, firmware,
+	)
+
+	closeIoT := func() {}
+	if iotClient != nil {
+		startEventSubscriber(cfg, iotClient, deviceEvents)
+		closeIoT = iotClient.Close
+	}
+
+	return &router.Dependencies{
+		JobManager:    jobManager,
+		DeviceManager: deviceManager,
+		DeviceEvents:  deviceEvents,
+		Telemetry:     telemetry,
+		Heartbeat:     heartbeat,
+		Alerts:        alerts,
+		Forecast:      forecast,
+		RoutePlanner:  routePlanner,
+		Stats:         stats,
+		Shadows:       shadows,
+		Commands:      commands,
+		Firmware:      firmware,
+		Certificates:  certificates,
+		Provisioning:  provisioning,
+		DeviceUpdates: deviceUpdates,
+		Decommission:  decommission,
+		Groups:        groups,
+		Tenants:       tenants,
+		Audit:         audit,
+		Metrics:       registry,
+	}, closeIoT
+}
+
+// initializeCertificateAuthority loads the device CA from DEVICE_CA_CERT_FILE and DEVICE_CA_KEY_FILE.
+// Without them (never in production, see Config.Validate) it generates a throwaway CA.
+func initializeCertificateAuthority(cfg *config.Config) *pki.Authority {
+	caConfig := cfg.Security.DeviceCA
+	if caConfig.CertFile == "" {
+		log.Warn().Msg("DEVICE_CA_CERT_FILE not set, using an ephemeral device CA: certificates will not survive a restart")
+
+		authority, err := pki.NewEphemeralAuthority(caConfig.CertValidity)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to generate ephemeral device CA")
+		}
+		return authority
+	}
+
+	authority, err := pki.LoadAuthority(caConfig.CertFile, caConfig.KeyFile, caConfig.CertValidity)
+	if err != nil {
+		log.Fatal().Err(err).Str("file", caConfig.CertFile).Msg("Failed to load device CA")
+	}
+
+	log.Info().
+		Str("subject", authority.Certificate().Subject.String()).
+		Time("expires_at", authority.Certificate().NotAfter).
+		Msg("Device CA loaded")
+
+	return authority
+}
+
+// newMTLSServer builds the device listener when MTLS_PORT is set (nil otherwise).
+// Clients must present a certificate signed by the device CA. The server certificate comes
+// from MTLS_CERT_FILE/MTLS_KEY_FILE or, without them, is issued by the same CA for MTLS_HOSTS.
+func newMTLSServer(cfg *config.Config, authority *pki.Authority, deps *router.Dependencies) *http.Server {
+	mtlsConfig := cfg.Server.MTLS
+	if mtlsConfig.Port == "" {
+		return nil
+	}
+
+	var serverCert tls.Certificate
+	var err error
+	if mtlsConfig.CertFile != "" {
+		serverCert, err = tls.LoadX509KeyPair(mtlsConfig.CertFile, mtlsConfig.KeyFile)
+	} else {
+		serverCert, err = authority.IssueServerCertificate(mtlsConfig.Hosts)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load mTLS server certificate")
+	}
+
+	return &http.Server{
+		Addr:      ":" + mtlsConfig.Port,
+		Handler:   router.NewDeviceRouter(cfg, deps),
+		TLSConfig: authority.ServerTLSConfig(serverCert),
+
+		ReadTimeout:    30 * time.Second,
+		WriteTimeout:   30 * time.Second,
+		IdleTimeout:    120 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+}
+
+// newMetricsServer builds the Prometheus listener when ENABLE_METRICS is set (nil otherwise).
+// It only serves GET /metrics in the Prometheus text format; the JSON summary stays on the API port.
+func newMetricsServer(cfg *config.Config, registry *metrics.Registry) *http.Server {
+	if !cfg.Observability.EnableMetrics {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+
+	return &http.Server{
+		Addr:    ":" + cfg.Observability.MetricsPort,
+		Handler: mux,
+
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   30 * time.Second,
+		IdleTimeout:    120 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+}
+
+// initializeIoTClient connects to the MQTT broker when IOT_ENDPOINT is set.
+// Without an endpoint it returns nil: decisions are kept in memory and device events
+// only arrive through the HTTP webhook. A broker that is down at startup is not fatal:
+// the client keeps retrying in the background.
+func initializeIoTClient(cfg *config.Config) *mqtt.Client {
+	if cfg.AWS.IoT.Endpoint == "" {
+		log.Warn().Msg("IOT_ENDPOINT not set, decisions will not be delivered to devices")
+		return nil
+	}
+
+	client, err := mqtt.NewClient(cfg.AWS.IoT)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure MQTT client")
+	}
+
+	if err := client.Connect(context.Background()); err != nil {
+		log.Warn().
+			Err(err).
+			Str("broker", mqtt.BrokerURL(cfg.AWS.IoT.Endpoint)).
+			Msg("MQTT broker not reachable yet, retrying in background")
+	}
+
+	return client
+}
+
+// startEventSubscriber routes MQTT device events (IOT_EVENTS_TOPIC) to the same handling as the webhook.
+func startEventSubscriber(cfg *config.Config, client *mqtt.Client, deviceEvents *services.DeviceEventService) {
+	if !cfg.AWS.IoT.SubscribeEvents {
+		return
+	}
+
+	subscriber := mqtt.NewEventSubscriber(client, deviceEvents)
+	if err := subscriber.Start(context.Background()); err != nil {
+		log.Warn().Err(err).Msg("MQTT event subscription failed, retrying on reconnect")
+	}
+}
+
+// startBackgroundWorkers launches the periodic domain tasks; they stop when ctx is canceled.
+func startBackgroundWorkers(ctx context.Context, deps *router.Dependencies) {
+	deps.Telemetry.StartRetention(ctx)
+	deps.Heartbeat.Start(ctx)
+	deps.Commands.Start(ctx)
+	deps.Certificates.Start(ctx)
+}
+
 // setupLogger configures the global logger based on environment.
 // Development mode uses colorized console output, while production uses structured JSON.
 func setupLogger() {
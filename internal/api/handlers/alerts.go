@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                            ║
+║  ALERTS.GO - HANDLER DE ALERTAS DE MANTENIMIENTO           ║
+║                                                            ║
+║  - GET  /api/v1/alerts                       - Listar      ║
+║  - GET  /api/v1/alerts/:alert_id             - Obtener     ║
+║  - POST /api/v1/alerts/:alert_id/acknowledge - Reconocer   ║
+║  - POST /api/v1/alerts/:alert_id/resolve     - Resolver    ║
+║                                                            ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// AlertsHandler maneja los endpoints de alertas de mantenimiento.
+type AlertsHandler struct {
+	config *config.Config
+	alerts *services.AlertService
+}
+
+// NewAlertsHandler crea una nueva instancia de AlertsHandler.
+func NewAlertsHandler(cfg *config.Config, alerts *services.AlertService) *AlertsHandler {
+	return &AlertsHandler{
+		config: cfg,
+		alerts: alerts,
+	}
+}
+
+// ListAlerts returns a paginated list of alerts, newest first.
+//
+// Query Parameters:
+//   - device_id, state (open, acknowledged, resolved), metric (filtros opcionales)
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *AlertsHandler) ListAlerts(c *gin.Context) {
+	var req models.ListAlertsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.alerts.ListAlerts(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// GetAlert retrieves an alert by ID.
+func (h *AlertsHandler) GetAlert(c *gin.Context) {
+	alert, err := h.alerts.GetAlert(c.Request.Context(), c.Param("alert_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     alert,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// AcknowledgeAlert marks an open alert as taken by the caller's credential.
+//
+// Request Body (optional):
+//
+//	{
+//	  "note": "Technician on the way"
+//	}
+func (h *AlertsHandler) AcknowledgeAlert(c *gin.Context) {
+	h.handleAction(c, h.alerts.AcknowledgeAlert)
+}
+
+// ResolveAlert closes an open or acknowledged alert.
+// Request body is the same as AcknowledgeAlert. Resolving errors_high resets the device error count.
+func (h *AlertsHandler) ResolveAlert(c *gin.Context) {
+	h.handleAction(c, h.alerts.ResolveAlert)
+}
+
+// handleAction parsea el body y aplica una acción sobre la alerta del path.
+func (h *AlertsHandler) handleAction(
+	c *gin.Context,
+	action func(ctx context.Context, alertID string, req *models.AlertActionRequest) (*models.Alert, error),
+) {
+	var req models.AlertActionRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondInvalidInput(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	alert, err := action(c.Request.Context(), c.Param("alert_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     alert,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *AlertsHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *AlertsHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                            ║
+║  AUDIT.GO - HANDLER DEL REGISTRO DE AUDITORÍA              ║
+║                                                            ║
+║  - GET /api/v1/audit               - Listar (filtros)      ║
+║  - GET /api/v1/audit/export        - Exportar JSONL/CSV    ║
+║  - GET /api/v1/audit/verify        - Verificar la cadena   ║
+║                                                            ║
+║  Las entradas las escribe middleware.Audit.                ║
+║                                                            ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// AuditHandler maneja los endpoints de consulta del registro de auditoría.
+type AuditHandler struct {
+	config *config.Config
+	audit  *services.AuditService
+}
+
+// NewAuditHandler crea una nueva instancia de AuditHandler.
+func NewAuditHandler(cfg *config.Config, audit *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		config: cfg,
+		audit:  audit,
+	}
+}
+
+// ListAudit returns the audit entries of the caller's organization, newest first.
+//
+// Query Parameters:
+//   - actor, action, resource_type, resource_id, request_id (optional)
+//   - from, to (optional, RFC3339)
+//   - limit (default: 10)
+//   - offset (default: 0)
+//
+// Response (200 OK):
+//
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "entries": [{"sequence": 42, "actor": "cred_1a2b3c4d", "action": "PATCH /api/v1/devices/:device_id",
+//	                 "changes": [{"field": "location.floor", "before": 1, "after": 2}], "hash": "..."}],
+//	    "total": 42, "limit": 10, "offset": 0
+//	  }
+//	}
+func (h *AuditHandler) ListAudit(c *gin.Context) {
+	var req models.ListAuditRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.audit.ListEntries(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ExportAudit downloads every entry matching the filters of ListAudit, oldest first.
+//
+// Query Parameters:
+//   - format: jsonl (default) or csv
+func (h *AuditHandler) ExportAudit(c *gin.Context) {
+	var req models.ListAuditRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	// Se arma completo antes de responder: un error todavía puede responderse como JSON
+	var export bytes.Buffer
+	if err := h.audit.ExportEntries(c.Request.Context(), &req, &export); err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	format, contentType := models.AuditExportJSONL, "application/x-ndjson"
+	if req.Format == models.AuditExportCSV {
+		format, contentType = models.AuditExportCSV, "text/csv"
+	}
+
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, contentType, export.Bytes())
+}
+
+// VerifyAudit recomputes the hash chain of the whole log.
+//
+// Response (200 OK):
+//
+//	{"success": true, "data": {"valid": true, "entries": 42, "last_hash": "9f2c..."}}
+func (h *AuditHandler) VerifyAudit(c *gin.Context) {
+	verification, err := h.audit.VerifyChain(c.Request.Context())
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     verification,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *AuditHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *AuditHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
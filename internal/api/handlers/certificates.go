@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════════════╗
+║                                                                        ║
+║  CERTIFICATES.GO - HANDLER DE CERTIFICADOS DE DISPOSITIVOS             ║
+║                                                                        ║
+║  - GET  /api/v1/devices/:device_id/certificates         - Listar       ║
+║  - POST /api/v1/devices/:device_id/certificates/rotate  - Rotar        ║
+║  - POST /api/v1/certificates/:serial/revoke             - Revocar      ║
+║  - GET  /api/v1/certificates/crl                        - CRL (PEM)    ║
+║  - GET  /api/v1/certificates/expiring                   - Por expirar  ║
+║                                                                        ║
+╚════════════════════════════════════════════════════════════════════════╝
+*/
+
+// CertificatesHandler maneja los endpoints de certificados de dispositivos.
+type CertificatesHandler struct {
+	config       *config.Config
+	certificates *services.CertificateService
+}
+
+// NewCertificatesHandler crea una nueva instancia de CertificatesHandler.
+func NewCertificatesHandler(cfg *config.Config, certificates *services.CertificateService) *CertificatesHandler {
+	return &CertificatesHandler{
+		config:       cfg,
+		certificates: certificates,
+	}
+}
+
+// ListDeviceCertificates returns every certificate issued to a device.
+func (h *CertificatesHandler) ListDeviceCertificates(c *gin.Context) {
+	certificates, err := h.certificates.ListDeviceCertificates(c.Request.Context(), c.Param("device_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"certificates": certificates,
+			"total":        len(certificates),
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// RotateCertificate issues a new certificate for the device. The body is optional.
+//
+// Request:
+//
+//	{
+//	  "csr": "-----BEGIN CERTIFICATE REQUEST-----...",
+//	  "overlap_hours": 24
+//	}
+//
+// Response (201 Created): el certificado nuevo (con private_key si no se envió CSR) y los
+// seriales reemplazados, que se aceptan hasta accepted_until.
+func (h *CertificatesHandler) RotateCertificate(c *gin.Context) {
+	var req models.RotateCertificateRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondInvalidInput(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	response, err := h.certificates.RotateCertificate(c.Request.Context(), c.Param("device_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// RevokeCertificate revokes a certificate by serial (hex).
+//
+// Request:
+//
+//	{"reason": "key_compromise"}
+func (h *CertificatesHandler) RevokeCertificate(c *gin.Context) {
+	var req models.RevokeCertificateRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondInvalidInput(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	certificate, err := h.certificates.RevokeCertificate(c.Request.Context(), c.Param("serial"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     certificate,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// GetCRL publishes the certificate revocation list signed by the device CA.
+// Responde el PEM directamente (no el sobre JSON) para que servidores y dispositivos lo consuman tal cual.
+func (h *CertificatesHandler) GetCRL(c *gin.Context) {
+	crl, err := h.certificates.CRL(c.Request.Context())
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, "application/x-pem-file", []byte(crl))
+}
+
+// ListExpiringCertificates returns the active certificates expiring within the window.
+//
+// Query Parameters:
+//   - days (default: DEVICE_CERT_EXPIRY_WARNING_DAYS)
+func (h *CertificatesHandler) ListExpiringCertificates(c *gin.Context) {
+	var req models.ExpiringCertificatesRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.certificates.ExpiringCertificates(c.Request.Context(), &req, time.Now())
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *CertificatesHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *CertificatesHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                            ║
+║  COMMANDS.GO - HANDLER DE COMANDOS REMOTOS                 ║
+║                                                            ║
+║  - POST /api/v1/devices/:device_id/commands      - Enviar  ║
+║  - GET  /api/v1/devices/:device_id/commands      - Listar  ║
+║  - GET  /api/v1/devices/:device_id/commands/:id  - Obtener ║
+║                                                            ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// CommandsHandler maneja los endpoints de comandos remotos.
+type CommandsHandler struct {
+	config   *config.Config
+	commands *services.CommandService
+}
+
+// NewCommandsHandler crea una nueva instancia de CommandsHandler.
+func NewCommandsHandler(cfg *config.Config, commands *services.CommandService) *CommandsHandler {
+	return &CommandsHandler{
+		config:   cfg,
+		commands: commands,
+	}
+}
+
+// CreateCommand sends a command to a device.
+//
+// Request:
+//
+//	{
+//	  "type": "open_compartment",
+//	  "params": {"compartment": "organic", "duration_s": 30},
+//	  "ttl_s": 300
+//	}
+//
+// Response (202 Accepted): el comando queda pending hasta que el dispositivo responda con command_ack.
+func (h *CommandsHandler) CreateCommand(c *gin.Context) {
+	var req models.CreateCommandRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	command, err := h.commands.CreateCommand(c.Request.Context(), c.Param("device_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success":  true,
+		"data":     command,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ListCommands returns the commands of a device, newest first.
+//
+// Query Parameters:
+//   - status (pending, delivered, succeeded, failed, expired)
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *CommandsHandler) ListCommands(c *gin.Context) {
+	var req models.ListCommandsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+	req.DeviceID = c.Param("device_id")
+
+	response, err := h.commands.ListCommands(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// GetCommand returns a command of a device.
+func (h *CommandsHandler) GetCommand(c *gin.Context) {
+	command, err := h.commands.GetCommand(c.Request.Context(), c.Param("device_id"), c.Param("command_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     command,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *CommandsHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *CommandsHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,28 +22,41 @@ import (
 ║  - GET    /api/v1/devices/:device_id - Obtener device      ║
 ║  - GET    /api/v1/devices            - Listar devices      ║
 ║  - PATCH  /api/v1/devices/:device_id - Actualizar device   ║
-║  - DELETE /api/v1/devices/:device_id - Eliminar device     ║
+║  - PUT    /api/v1/devices/:device_id/status - Cambiar estado║
+║  - DELETE /api/v1/devices/:device_id - Dar de baja device  ║
 ║                                                            ║
 ╚════════════════════════════════════════════════════════════════╝
 */
 
 // DevicesHandler maneja los endpoints relacionados con dispositivos.
 type DevicesHandler struct {
-	config *config.Config
-	// TODO: Agregar dependencies:
-	// deviceRepository ports.DeviceRepository
-	// iotClient        ports.IoTPublisher
+	config        *config.Config
+	deviceManager *services.DeviceManager
+	certificates  *services.CertificateService
+	updates       *services.DeviceUpdateService
+	decommission  *services.DecommissionService
 }
 
 // NewDevicesHandler crea una nueva instancia de DevicesHandler.
-func NewDevicesHandler(cfg *config.Config) *DevicesHandler {
+func NewDevicesHandler(
+	cfg *config.Config,
+	deviceManager *services.DeviceManager,
+	certificates *services.CertificateService,
+	updates *services.DeviceUpdateService,
+	decommission *services.DecommissionService,
+) *DevicesHandler {
 	return &DevicesHandler{
-		config: cfg,
+		config:        cfg,
+		deviceManager: deviceManager,
+		certificates:  certificates,
+		updates:       updates,
+		decommission:  decommission,
 	}
 }
 
 // RegisterDevice handles device registration requests.
-// It creates a new IoT device, generates certificates, and stores device info.
+// It issues the device certificate (from the CSR in the request, or with a generated key pair)
+// and stores the device info. The generated private key is only returned in this response.
 func (h *DevicesHandler) RegisterDevice(c *gin.Context) {
 	// ───────────────────────────────────────────────────────────────
 	// 1. PARSEAR Y VALIDAR REQUEST
@@ -65,60 +79,43 @@ func (h *DevicesHandler) RegisterDevice(c *gin.Context) {
 	}
 
 	// ───────────────────────────────────────────────────────────────
-	// 2. VERIFICAR QUE EL DEVICE NO EXISTA
+	// 2. EMITIR CERTIFICADO CON LA CA DE DISPOSITIVOS
 	// ───────────────────────────────────────────────────────────────
-	// TODO: Verificar en DynamoDB
-	// existingDevice, err := h.deviceRepository.GetByID(c.Request.Context(), req.DeviceID)
-	// if existingDevice != nil {
-	//     c.JSON(http.StatusConflict, gin.H{
-	//         "success": false,
-	//         "error": gin.H{
-	//             "code":    "DEVICE_ALREADY_EXISTS",
-	//             "message": "Device with this ID already exists",
-	//         },
-	//     })
-	//     return
-	// }
-
-	// ───────────────────────────────────────────────────────────────
-	// 3. CREAR DEVICE EN AWS IOT CORE
-	// ───────────────────────────────────────────────────────────────
-	// TODO: Crear Thing en IoT Core y generar certificados
-	// certificate, err := h.iotClient.CreateThing(c.Request.Context(), req.DeviceID)
-
-	// Por ahora, generar certificado mock
-	certificate := h.generateMockCertificate(req.DeviceID)
+	certificate, err := h.certificates.IssueCertificate(c.Request.Context(), req.DeviceID, req.CSR)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
 
 	// ───────────────────────────────────────────────────────────────
-	// 4. CREAR OBJETO DEVICE
+	// 3. GUARDAR DEVICE (FALLA SI YA EXISTE)
 	// ───────────────────────────────────────────────────────────────
-	now := time.Now()
-	device := &models.Device{
-		DeviceID:    req.DeviceID,
-		DeviceType:  req.DeviceType,
-		Status:      models.DeviceStatusActive,
-		Certificate: certificate,
-		CreatedAt:   now,
+	device, err := h.deviceManager.RegisterDevice(c.Request.Context(), &req, certificate)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
 	}
 
-	// ───────────────────────────────────────────────────────────────
-	// 5. GUARDAR DEVICE EN DYNAMODB
-	// ───────────────────────────────────────────────────────────────
-	// TODO: Guardar en DynamoDB
-	// if err := h.deviceRepository.Create(c.Request.Context(), device); err != nil {
-	//     c.JSON(http.StatusInternalServerError, gin.H{...})
-	//     return
-	// }
+	// El certificado solo se acepta en mTLS una vez registrado el dispositivo
+	if err := h.certificates.TrackCertificate(c.Request.Context(), certificate); err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
 
 	// ───────────────────────────────────────────────────────────────
-	// 6. CONSTRUIR RESPONSE
+	// 4. CONSTRUIR RESPONSE
 	// ───────────────────────────────────────────────────────────────
 	response := models.RegisterDeviceResponse{
-		DeviceID:    device.DeviceID,
-		DeviceType:  device.DeviceType,
-		Status:      device.Status,
-		Certificate: device.Certificate,
-		CreatedAt:   device.CreatedAt,
+		DeviceID:             device.DeviceID,
+		OrgID:                device.OrgID,
+		DeviceType:           device.DeviceType,
+		Status:               device.Status,
+		Certificate:          certificate.CertificatePEM,
+		CertificateSerial:    certificate.SerialNumber,
+		CertificateExpiresAt: certificate.ExpiresAt,
+		CACertificate:        h.certificates.CACertificate(),
+		PrivateKey:           certificate.PrivateKeyPEM,
+		CreatedAt:            device.CreatedAt,
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -161,21 +158,13 @@ func (h *DevicesHandler) GetDevice(c *gin.Context) {
 	}
 
 	// ───────────────────────────────────────────────────────────────
-	// 2. OBTENER DEVICE DE DYNAMODB
+	// 2. OBTENER DEVICE DEL REPOSITORIO
 	// ───────────────────────────────────────────────────────────────
-	// TODO: Obtener de DynamoDB
-	// device, err := h.deviceRepository.GetByID(c.Request.Context(), deviceID)
-	// if err != nil {
-	//     if err == ErrNotFound {
-	//         c.JSON(http.StatusNotFound, gin.H{...})
-	//         return
-	//     }
-	//     c.JSON(http.StatusInternalServerError, gin.H{...})
-	//     return
-	// }
-
-	// Por ahora, retornar device mock
-	device := h.getMockDevice(deviceID)
+	device, err := h.deviceManager.GetDevice(c.Request.Context(), deviceID)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
 
 	// ───────────────────────────────────────────────────────────────
 	// 3. RESPONDER
@@ -190,6 +179,12 @@ func (h *DevicesHandler) GetDevice(c *gin.Context) {
 // ListDevices returns a paginated list of all devices.
 //
 // Query Parameters:
+//   - status, device_type, bin_type (filtros opcionales)
+//   - online (true/false, estado calculado por el monitor de heartbeat)
+//   - accepts (compartimento: recyclable, organic, general; incluye los contenedores mixed)
+//   - near=lat,lng y radius_m (default: 1000): búsqueda por cercanía, ordenada por distancia
+//   - bbox=min_lat,min_lng,max_lat,max_lng: búsqueda dentro de un rectángulo
+//   - tag=key o tag=key:value (repetible; deben cumplirse todos)
 //   - limit (default: 10)
 //   - offset (default: 0)
 //
@@ -205,45 +200,155 @@ func (h *DevicesHandler) GetDevice(c *gin.Context) {
 //	  }
 //	}
 func (h *DevicesHandler) ListDevices(c *gin.Context) {
-	// TODO: Parsear query params y obtener de DynamoDB
+	var req models.ListDevicesRequest
 
-	// Por ahora, retornar lista mock
-	devices := h.getMockDevices()
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid query parameters",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	response, err := h.deviceManager.ListDevices(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"devices": devices,
-			"total":   len(devices),
-			"limit":   10,
-			"offset":  0,
-		},
+		"success":  true,
+		"data":     response,
 		"metadata": h.buildMetadata(c),
 	})
 }
 
-// UpdateDevice updates device information.
-// This endpoint is not yet implemented.
+// UpdateDevice applies a partial update to a device (merge semantics: absent fields are unchanged).
+//
+// Request:
+//
+//	{"status": "maintenance", "status_reason": "jammed lid", "fill_level": 40}
+//
+// Un cambio de status queda en status_history con la credencial de la petición.
+// Rangos: battery_level y fill_level 0-100, signal_strength -120 a 0 dBm.
+// Response (200 OK): el dispositivo actualizado.
+// Response (409 INVALID_TRANSITION): la máquina de estados no permite el cambio de status.
 func (h *DevicesHandler) UpdateDevice(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    "NOT_IMPLEMENTED",
-			"message": "Device update not yet implemented",
-		},
+	var req models.UpdateDeviceRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid request body",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	device, err := h.updates.UpdateDevice(c.Request.Context(), c.Param("device_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     device,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ChangeDeviceStatus moves a device to another status of its lifecycle.
+//
+// Request:
+//
+//	{"status": "maintenance", "reason": "lid sensor replacement"}
+//
+// La razón es obligatoria salvo al volver a active y queda en status_reason; changed_by
+// es la credencial de la petición.
+// Response (200 OK): el dispositivo actualizado, con el cambio en status_history.
+// Response (409 INVALID_TRANSITION): la máquina de estados no permite el cambio.
+func (h *DevicesHandler) ChangeDeviceStatus(c *gin.Context) {
+	var req models.ChangeDeviceStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid request body",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	device, err := h.updates.ChangeStatus(c.Request.Context(), c.Param("device_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     device,
 		"metadata": h.buildMetadata(c),
 	})
 }
 
-// DeleteDevice removes a device from the system.
-// This endpoint is not yet implemented.
+// DeleteDevice decommissions a device. The record is kept (status decommissioned) for audit;
+// its certificates are revoked, open commands canceled and active alerts resolved.
+//
+// Request (opcional):
+//
+//	{"reason": "vandalized"}
+//
+// Response (200 OK): el dispositivo dado de baja y el resumen de lo que se cerró.
 func (h *DevicesHandler) DeleteDevice(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    "NOT_IMPLEMENTED",
-			"message": "Device deletion not yet implemented",
-		},
+	var req models.DecommissionDeviceRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_INPUT",
+					"message": "Invalid request body",
+					"details": gin.H{
+						"error": err.Error(),
+					},
+				},
+				"metadata": h.buildMetadata(c),
+			})
+			return
+		}
+	}
+
+	response, err := h.decommission.Decommission(c.Request.Context(), c.Param("device_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
 		"metadata": h.buildMetadata(c),
 	})
 }
@@ -252,11 +357,6 @@ func (h *DevicesHandler) DeleteDevice(c *gin.Context) {
 //                     HELPER FUNCTIONS
 // ═══════════════════════════════════════════════════════════════════
 
-// generateMockCertificate genera un certificado IoT mock.
-func (h *DevicesHandler) generateMockCertificate(deviceID string) string {
-	return "-----BEGIN CERTIFICATE-----\nMOCK_CERTIFICATE_FOR_" + deviceID + "\n-----END CERTIFICATE-----"
-}
-
 // buildMetadata construye el objeto metadata estándar.
 func (h *DevicesHandler) buildMetadata(c *gin.Context) gin.H {
 	return gin.H{
@@ -266,52 +366,3 @@ func (h *DevicesHandler) buildMetadata(c *gin.Context) gin.H {
 		"version":    h.config.Server.Version,
 	}
 }
-
-// getMockDevice retorna un device mock para testing.
-func (h *DevicesHandler) getMockDevice(deviceID string) *models.Device {
-	lastSeen := time.Now().Add(-2 * time.Minute)
-
-	return &models.Device{
-		DeviceID:   deviceID,
-		DeviceType: "smart_bin_v1",
-		Status:     models.DeviceStatusActive,
-		Location: &models.Location{
-			Building:  "Edificio A",
-			Floor:     2,
-			Area:      "Cafeteria",
-			Latitude:  4.6097,
-			Longitude: -74.0817,
-		},
-		BinType:   "recyclable",
-		LastSeen:  &lastSeen,
-		CreatedAt: time.Now().Add(-24 * time.Hour),
-		UpdatedAt: time.Now().Add(-2 * time.Minute),
-	}
-}
-
-// getMockDevices retorna lista de devices mock.
-func (h *DevicesHandler) getMockDevices() []*models.Device {
-	return []*models.Device{
-		{
-			DeviceID:   "smart-bin-001",
-			DeviceType: "smart_bin_v1",
-			Status:     models.DeviceStatusActive,
-			BinType:    "recyclable",
-			CreatedAt:  time.Now().Add(-48 * time.Hour),
-		},
-		{
-			DeviceID:   "smart-bin-002",
-			DeviceType: "smart_bin_v1",
-			Status:     models.DeviceStatusActive,
-			BinType:    "organic",
-			CreatedAt:  time.Now().Add(-36 * time.Hour),
-		},
-		{
-			DeviceID:   "smart-bin-003",
-			DeviceType: "smart_bin_v1",
-			Status:     models.DeviceStatusMaintenance,
-			BinType:    "general",
-			CreatedAt:  time.Now().Add(-12 * time.Hour),
-		},
-	}
-}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/gin-gonic/gin"
+)
+
+// domainErrorStatus traduce un error del dominio a status HTTP y código de error.
+func domainErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, models.ErrJobNotFound):
+		return http.StatusNotFound, "JOB_NOT_FOUND"
+	case errors.Is(err, models.ErrDeviceNotFound):
+		return http.StatusNotFound, "DEVICE_NOT_FOUND"
+	case errors.Is(err, models.ErrAlertNotFound):
+		return http.StatusNotFound, "ALERT_NOT_FOUND"
+	case errors.Is(err, models.ErrCommandNotFound):
+		return http.StatusNotFound, "COMMAND_NOT_FOUND"
+	case errors.Is(err, models.ErrFirmwareReleaseNotFound):
+		return http.StatusNotFound, "FIRMWARE_RELEASE_NOT_FOUND"
+	case errors.Is(err, models.ErrRolloutNotFound):
+		return http.StatusNotFound, "ROLLOUT_NOT_FOUND"
+	case errors.Is(err, models.ErrCertificateNotFound):
+		return http.StatusNotFound, "CERTIFICATE_NOT_FOUND"
+	case errors.Is(err, models.ErrGroupNotFound):
+		return http.StatusNotFound, "GROUP_NOT_FOUND"
+	case errors.Is(err, models.ErrOrganizationNotFound):
+		return http.StatusNotFound, "ORGANIZATION_NOT_FOUND"
+	case errors.Is(err, models.ErrCredentialNotFound):
+		return http.StatusNotFound, "CREDENTIAL_NOT_FOUND"
+	case errors.Is(err, models.ErrOrganizationExists):
+		return http.StatusConflict, "ORGANIZATION_EXISTS"
+	case errors.Is(err, models.ErrFirmwareReleaseExists):
+		return http.StatusConflict, "FIRMWARE_RELEASE_EXISTS"
+	case errors.Is(err, models.ErrRolloutInProgress):
+		return http.StatusConflict, "ROLLOUT_IN_PROGRESS"
+	case errors.Is(err, models.ErrCertificateRevoked):
+		return http.StatusConflict, "CERTIFICATE_REVOKED"
+	case errors.Is(err, models.ErrCommandNotSupported):
+		return http.StatusUnprocessableEntity, "COMMAND_NOT_SUPPORTED"
+	case errors.Is(err, models.ErrDeviceAlreadyExists):
+		return http.StatusConflict, "DEVICE_ALREADY_EXISTS"
+	case errors.Is(err, models.ErrDeviceInactive):
+		return http.StatusConflict, "DEVICE_INACTIVE"
+	case errors.Is(err, models.ErrInvalidTransition):
+		return http.StatusConflict, "INVALID_TRANSITION"
+	case errors.Is(err, models.ErrStaleEvent):
+		return http.StatusConflict, "STALE_EVENT"
+	case errors.Is(err, models.ErrShadowVersionConflict):
+		return http.StatusConflict, "VERSION_CONFLICT"
+	case errors.Is(err, models.ErrInvalidInput),
+		errors.Is(err, models.ErrInvalidJobID),
+		errors.Is(err, models.ErrInvalidDeviceID),
+		errors.Is(err, models.ErrInvalidDeviceType),
+		errors.Is(err, models.ErrInvalidStatus),
+		errors.Is(err, models.ErrInvalidTimestamp),
+		errors.Is(err, models.ErrInvalidBatteryLevel),
+		errors.Is(err, models.ErrInvalidFillLevel),
+		errors.Is(err, models.ErrInvalidSignalStrength),
+		errors.Is(err, models.ErrInvalidMetric),
+		errors.Is(err, models.ErrInvalidTimeRange),
+		errors.Is(err, models.ErrInvalidCoordinates),
+		errors.Is(err, models.ErrInvalidAction),
+		errors.Is(err, models.ErrInvalidBinCompartment),
+		errors.Is(err, models.ErrInvalidMessage),
+		errors.Is(err, models.ErrInvalidShadowState),
+		errors.Is(err, models.ErrInvalidCommand),
+		errors.Is(err, models.ErrInvalidFirmware),
+		errors.Is(err, models.ErrInvalidRollout),
+		errors.Is(err, models.ErrInvalidCSR),
+		errors.Is(err, models.ErrInvalidGroup),
+		errors.Is(err, models.ErrInvalidAlertRule),
+		errors.Is(err, models.ErrInvalidURL),
+		errors.Is(err, models.ErrMissingRequiredField):
+		return http.StatusBadRequest, "INVALID_INPUT"
+	case errors.Is(err, models.ErrInvalidClaimToken):
+		return http.StatusUnauthorized, "INVALID_CLAIM_TOKEN"
+	case errors.Is(err, models.ErrClaimTokenExpired):
+		return http.StatusUnauthorized, "CLAIM_TOKEN_EXPIRED"
+	case errors.Is(err, models.ErrUnauthorized):
+		return http.StatusUnauthorized, "UNAUTHORIZED"
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden, "FORBIDDEN"
+	case errors.Is(err, models.ErrNotImplemented):
+		return http.StatusNotImplemented, "NOT_IMPLEMENTED"
+	case errors.Is(err, models.ErrS3Operation),
+		errors.Is(err, models.ErrSQSOperation),
+		errors.Is(err, models.ErrIoTOperation),
+		errors.Is(err, models.ErrDynamoDBOperation):
+		return http.StatusBadGateway, "DEPENDENCY_ERROR"
+	default:
+		return http.StatusInternalServerError, "INTERNAL_ERROR"
+	}
+}
+
+// respondDomainError escribe la respuesta de error estándar para un error del dominio.
+func respondDomainError(c *gin.Context, err error, metadata gin.H) {
+	status, code := domainErrorStatus(err)
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    code,
+			"message": err.Error(),
+		},
+		"metadata": metadata,
+	})
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                            ║
+║  FIRMWARE.GO - HANDLER DE RELEASES Y ROLLOUTS OTA          ║
+║                                                            ║
+║  - POST  /api/v1/firmware/releases            - Registrar  ║
+║  - GET   /api/v1/firmware/releases            - Listar     ║
+║  - GET   /api/v1/firmware/releases/:id        - Obtener    ║
+║  - POST  /api/v1/firmware/rollouts            - Iniciar    ║
+║  - GET   /api/v1/firmware/rollouts            - Listar     ║
+║  - GET   /api/v1/firmware/rollouts/:id        - Obtener    ║
+║  - PATCH /api/v1/firmware/rollouts/:id        - Ampliar    ║
+║  - POST  /api/v1/firmware/rollouts/:id/pause  - Pausar     ║
+║  - POST  /api/v1/firmware/rollouts/:id/resume - Reanudar   ║
+║                                                            ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// FirmwareHandler maneja los endpoints de firmware OTA.
+type FirmwareHandler struct {
+	config   *config.Config
+	firmware *services.FirmwareService
+}
+
+// NewFirmwareHandler crea una nueva instancia de FirmwareHandler.
+func NewFirmwareHandler(cfg *config.Config, firmware *services.FirmwareService) *FirmwareHandler {
+	return &FirmwareHandler{
+		config:   cfg,
+		firmware: firmware,
+	}
+}
+
+// CreateRelease registers a firmware release.
+//
+// Request:
+//
+//	{
+//	  "version": "2.1.0",
+//	  "device_type": "smart_bin_v2",
+//	  "checksum_sha256": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	  "size_bytes": 1048576,
+//	  "notes": "Camera driver fixes"
+//	}
+//
+// Response (201 Created): la release y una URL prefirmada para subir el binario a S3.
+func (h *FirmwareHandler) CreateRelease(c *gin.Context) {
+	var req models.CreateFirmwareReleaseRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.firmware.CreateRelease(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ListReleases returns the firmware releases, newest first.
+//
+// Query Parameters:
+//   - device_type
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *FirmwareHandler) ListReleases(c *gin.Context) {
+	var req models.ListFirmwareReleasesRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.firmware.ListReleases(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	h.respondOK(c, response)
+}
+
+// GetRelease returns a firmware release.
+func (h *FirmwareHandler) GetRelease(c *gin.Context) {
+	release, err := h.firmware.GetRelease(c.Request.Context(), c.Param("release_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	h.respondOK(c, release)
+}
+
+// CreateRollout starts a rollout.
+//
+// Request (porcentaje de la flota o grupo de dispositivos, no ambos):
+//
+//	{
+//	  "release_id": "fw_1a2b3c4d",
+//	  "percentage": 10,
+//	  "failure_threshold": 0.2,
+//	  "min_samples": 5
+//	}
+func (h *FirmwareHandler) CreateRollout(c *gin.Context) {
+	var req models.CreateRolloutRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	rollout, err := h.firmware.CreateRollout(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"data":     rollout,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ListRollouts returns the rollouts with their progress, newest first.
+//
+// Query Parameters:
+//   - device_type
+//   - status (active, paused, completed)
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *FirmwareHandler) ListRollouts(c *gin.Context) {
+	var req models.ListRolloutsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.firmware.ListRollouts(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	h.respondOK(c, response)
+}
+
+// GetRollout returns a rollout with its progress.
+func (h *FirmwareHandler) GetRollout(c *gin.Context) {
+	rollout, err := h.firmware.GetRollout(c.Request.Context(), c.Param("rollout_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	h.respondOK(c, rollout)
+}
+
+// UpdateRollout widens a percentage rollout.
+//
+// Request:
+//
+//	{"percentage": 50}
+func (h *FirmwareHandler) UpdateRollout(c *gin.Context) {
+	var req models.UpdateRolloutRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	rollout, err := h.firmware.UpdateRollout(c.Request.Context(), c.Param("rollout_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	h.respondOK(c, rollout)
+}
+
+// PauseRollout pauses an active rollout. The body is optional.
+//
+// Request:
+//
+//	{"reason": "Battery drain reports after update"}
+func (h *FirmwareHandler) PauseRollout(c *gin.Context) {
+	var req models.PauseRolloutRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondInvalidInput(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	rollout, err := h.firmware.PauseRollout(c.Request.Context(), c.Param("rollout_id"), req.Reason)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	h.respondOK(c, rollout)
+}
+
+// ResumeRollout resumes a paused rollout.
+func (h *FirmwareHandler) ResumeRollout(c *gin.Context) {
+	rollout, err := h.firmware.ResumeRollout(c.Request.Context(), c.Param("rollout_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	h.respondOK(c, rollout)
+}
+
+// respondOK responde 200 con data.
+func (h *FirmwareHandler) respondOK(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     data,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *FirmwareHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *FirmwareHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
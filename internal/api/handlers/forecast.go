@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ForecastHandler maneja las consultas de pronóstico de llenado.
+type ForecastHandler struct {
+	config   *config.Config
+	forecast *services.ForecastService
+}
+
+// NewForecastHandler crea una nueva instancia de ForecastHandler.
+func NewForecastHandler(cfg *config.Config, forecast *services.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		config:   cfg,
+		forecast: forecast,
+	}
+}
+
+// ListForecasts returns the bins sorted by how soon they will be full.
+// ENDPOINT: GET /api/v1/devices/forecast
+//
+// Query Parameters:
+//   - within (ej: 24h): solo contenedores que se llenan dentro de esa ventana
+//   - bin_type (filtro opcional)
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *ForecastHandler) ListForecasts(c *gin.Context) {
+	var req models.ListForecastRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid query parameters",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	response, err := h.forecast.ListForecasts(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *ForecastHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                            ║
+║  GROUPS.GO - HANDLER DE GRUPOS DE DISPOSITIVOS             ║
+║                                                            ║
+║  - POST   /api/v1/groups                    - Crear        ║
+║  - GET    /api/v1/groups                    - Listar       ║
+║  - GET    /api/v1/groups/:id                - Obtener      ║
+║  - PATCH  /api/v1/groups/:id                - Actualizar   ║
+║  - DELETE /api/v1/groups/:id                - Eliminar     ║
+║  - GET    /api/v1/groups/:id/devices        - Miembros     ║
+║  - POST   /api/v1/groups/:id/status         - Estado       ║
+║  - POST   /api/v1/groups/:id/commands       - Comando      ║
+║  - PATCH  /api/v1/groups/:id/configuration  - Shadow       ║
+║                                                            ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// GroupsHandler maneja los endpoints de grupos y operaciones masivas.
+type GroupsHandler struct {
+	config *config.Config
+	groups *services.GroupService
+}
+
+// NewGroupsHandler crea una nueva instancia de GroupsHandler.
+func NewGroupsHandler(cfg *config.Config, groups *services.GroupService) *GroupsHandler {
+	return &GroupsHandler{
+		config: cfg,
+		groups: groups,
+	}
+}
+
+// CreateGroup creates a static or dynamic device group.
+//
+// Request (static):
+//
+//	{"name": "Cafeterías", "type": "static", "device_ids": ["smart-bin-001", "smart-bin-002"]}
+//
+// Request (dynamic):
+//
+//	{"name": "Campus norte", "type": "dynamic", "selector": {"tags": {"campus": "norte"}, "building": "A"}}
+//
+// Response (201 Created)
+func (h *GroupsHandler) CreateGroup(c *gin.Context) {
+	var req models.CreateGroupRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	group, err := h.groups.CreateGroup(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"data":     group,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ListGroups returns the groups ordered by name.
+//
+// Query Parameters:
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *GroupsHandler) ListGroups(c *gin.Context) {
+	var req models.ListGroupsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.groups.ListGroups(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// GetGroup returns a group definition.
+func (h *GroupsHandler) GetGroup(c *gin.Context) {
+	group, err := h.groups.GetGroup(c.Request.Context(), c.Param("group_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     group,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// UpdateGroup renames a group, adds or removes members of a static group or replaces a dynamic selector.
+//
+// Request:
+//
+//	{"name": "Cafeterías", "add_devices": ["smart-bin-003"], "remove_devices": ["smart-bin-001"]}
+func (h *GroupsHandler) UpdateGroup(c *gin.Context) {
+	var req models.UpdateGroupRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	group, err := h.groups.UpdateGroup(c.Request.Context(), c.Param("group_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     group,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// DeleteGroup deletes a group. Its devices are not modified.
+func (h *GroupsHandler) DeleteGroup(c *gin.Context) {
+	groupID := c.Param("group_id")
+
+	if err := h.groups.DeleteGroup(c.Request.Context(), groupID); err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     gin.H{"group_id": groupID},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ListGroupDevices returns the current members of a group (dynamic groups are resolved on each call).
+//
+// Query Parameters:
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *GroupsHandler) ListGroupDevices(c *gin.Context) {
+	var req models.ListGroupDevicesRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.groups.ListGroupDevices(c.Request.Context(), c.Param("group_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ChangeGroupStatus moves every member of a group to another status.
+//
+// Request:
+//
+//	{"status": "maintenance", "reason": "semester cleaning"}
+//
+// Response (200 OK): resultado por dispositivo; un miembro que falla no detiene al resto.
+func (h *GroupsHandler) ChangeGroupStatus(c *gin.Context) {
+	var req models.ChangeDeviceStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.groups.ChangeStatus(c.Request.Context(), c.Param("group_id"), &req)
+	h.respondOperation(c, http.StatusOK, response, err)
+}
+
+// SendGroupCommand creates a command for every member of a group.
+//
+// Request: igual a POST /devices/:device_id/commands.
+//
+// Response (202 Accepted): command_id por dispositivo.
+func (h *GroupsHandler) SendGroupCommand(c *gin.Context) {
+	var req models.CreateCommandRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.groups.SendCommand(c.Request.Context(), c.Param("group_id"), &req)
+	h.respondOperation(c, http.StatusAccepted, response, err)
+}
+
+// UpdateGroupConfiguration merges a desired state into the shadow of every member of a group.
+//
+// Request:
+//
+//	{"desired": {"report_interval_s": 600}}
+func (h *GroupsHandler) UpdateGroupConfiguration(c *gin.Context) {
+	var req models.UpdateShadowRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.groups.UpdateConfiguration(c.Request.Context(), c.Param("group_id"), &req)
+	h.respondOperation(c, http.StatusOK, response, err)
+}
+
+// respondOperation responde el resultado de una operación masiva.
+func (h *GroupsHandler) respondOperation(c *gin.Context, status int, response *models.GroupOperationResponse, err error) {
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *GroupsHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *GroupsHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
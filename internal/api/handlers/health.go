@@ -6,19 +6,22 @@ import (
 	"time"
 
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
 	"github.com/gin-gonic/gin"
 )
 
 // HealthHandler maneja los endpoints de health check.
 type HealthHandler struct {
 	config    *config.Config
+	metrics   *metrics.Registry
 	startTime time.Time
 }
 
 // NewHealthHandler crea una nueva instancia de HealthHandler.
-func NewHealthHandler(cfg *config.Config) *HealthHandler {
+func NewHealthHandler(cfg *config.Config, metrics *metrics.Registry) *HealthHandler {
 	return &HealthHandler{
 		config:    cfg,
+		metrics:   metrics,
 		startTime: time.Now(),
 	}
 }
@@ -71,28 +74,31 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 	}
 }
 
-// Metrics returns service metrics for monitoring (Prometheus-compatible).
+// Metrics returns a JSON summary of the Prometheus metrics (requests, latency, jobs, outbound clients
+// and Go runtime). The full metrics are served in the Prometheus text format on METRICS_PORT.
 func (h *HealthHandler) Metrics(c *gin.Context) {
-	uptime := time.Since(h.startTime)
+	summary, err := h.metrics.Summary()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INTERNAL_ERROR",
+				"message": "Failed to gather metrics",
+			},
+		})
+		return
+	}
 
 	metrics := gin.H{
 		"service":        h.config.Server.ServiceName,
 		"version":        h.config.Server.Version,
-		"uptime_seconds": uptime.Seconds(),
+		"uptime_seconds": time.Since(h.startTime).Seconds(),
 
-		"requests": gin.H{
-			"total":           1000,
-			"success":         950,
-			"errors":          50,
-			"rate_per_minute": 10.5,
-		},
-
-		"latency": gin.H{
-			"p50": 45.0,
-			"p95": 120.0,
-			"p99": 350.0,
-			"max": 1200.0,
-		},
+		"requests":  summary.Requests,
+		"latency":   summary.Latency,
+		"jobs":      summary.Jobs,
+		"outbound":  summary.Outbound,
+		"resources": summary.Resources,
 
 		"dependencies": gin.H{
 			"dynamodb": "healthy",
@@ -100,11 +106,6 @@ func (h *HealthHandler) Metrics(c *gin.Context) {
 			"sqs":      "healthy",
 			"iot_core": "healthy",
 		},
-
-		"resources": gin.H{
-			"goroutines": 0,
-			"memory_mb":  0,
-		},
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -1,14 +1,13 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 /*
@@ -22,24 +21,22 @@ import (
 ║  - GET    /api/v1/jobs           - Listar jobs                ║
 ║  - PATCH  /api/v1/jobs/:job_id   - Actualizar job             ║
 ║  - DELETE /api/v1/jobs/:job_id   - Eliminar job               ║
+║  - POST   /api/v1/jobs/:job_id/review - Resolver revisión     ║
 ║                                                               ║
 ╚═══════════════════════════════════════════════════════════════╝
 */
 
 // JobsHandler maneja los endpoints relacionados con jobs.
 type JobsHandler struct {
-	config *config.Config
-	// TODO: Agregar dependencies:
-	// jobRepository    ports.JobRepository
-	// s3Presigner      ports.S3Presigner
-	// classifierClient ports.ClassifierClient
+	config     *config.Config
+	jobManager *services.JobManager
 }
 
 // NewJobsHandler crea una nueva instancia de JobsHandler.
-func NewJobsHandler(cfg *config.Config) *JobsHandler {
+func NewJobsHandler(cfg *config.Config, jobManager *services.JobManager) *JobsHandler {
 	return &JobsHandler{
-		config: cfg,
-		// TODO: Inyectar dependencies cuando estén listas
+		config:     cfg,
+		jobManager: jobManager,
 	}
 }
 
@@ -67,48 +64,17 @@ func (h *JobsHandler) CreateJob(c *gin.Context) {
 	}
 
 	// ───────────────────────────────────────────────────────────────
-	// 2. GENERAR JOB ID ÚNICO
+	// 2. CREAR JOB (ID, URL PREFIRMADA O ENCOLADO)
 	// ───────────────────────────────────────────────────────────────
-	jobID := h.generateJobID()
-
-	// ───────────────────────────────────────────────────────────────
-	// 3. CREAR OBJETO JOB
-	// ───────────────────────────────────────────────────────────────
-	job := &models.Job{
-		JobID:     jobID,
-		DeviceID:  req.DeviceID,
-		Status:    models.JobStatusPending,
-		ImageKey:  h.generateImageKey(req.DeviceID, jobID),
-		CreatedAt: time.Now(),
-		// Metadata y UpdatedAt se agregarán cuando implementemos el repository
-	}
-
-	// ───────────────────────────────────────────────────────────────
-	// 4. GENERAR URL PREFIRMADA DE S3
-	// ───────────────────────────────────────────────────────────────
-	// TODO: Usar S3Presigner real
-	// uploadURL, err := h.s3Presigner.GeneratePresignedPutURL(
-	//     c.Request.Context(),
-	//     h.config.AWS.S3.BucketImages,
-	//     job.ImageKey,
-	//     h.config.AWS.S3.PresignedURLExpiry,
-	// )
-
-	// Por ahora, generar URL mock
-	uploadURL := h.generateMockS3URL(job.ImageKey)
-	uploadExpiresAt := time.Now().Add(h.config.AWS.S3.PresignedURLExpiry)
-
-	response := models.CreateJobResponse{
-		JobID:           job.JobID,
-		DeviceID:        job.DeviceID,
-		Status:          job.Status,
-		UploadURL:       uploadURL,
-		UploadExpiresAt: uploadExpiresAt,
-		CreatedAt:       job.CreatedAt,
+	// El mismo camino lo usan los eventos image_captured de los dispositivos.
+	response, err := h.jobManager.CreateJob(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
 	}
 
 	// ───────────────────────────────────────────────────────────────
-	// 7. RESPONDER
+	// 3. RESPONDER
 	// ───────────────────────────────────────────────────────────────
 	c.JSON(http.StatusCreated, gin.H{
 		"success":  true,
@@ -137,7 +103,11 @@ func (h *JobsHandler) GetJob(c *gin.Context) {
 		return
 	}
 
-	job := h.getMockJob(jobID)
+	job, err := h.jobManager.GetJob(c.Request.Context(), jobID)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":  true,
@@ -148,6 +118,7 @@ func (h *JobsHandler) GetJob(c *gin.Context) {
 
 // ListJobs returns a paginated list of classification jobs with optional filters.
 // ENDPOINT: GET /api/v1/jobs
+// Con pending_review=true solo retorna los jobs que esperan revisión manual.
 func (h *JobsHandler) ListJobs(c *gin.Context) {
 	// 1. PARSEAR QUERY PARAMETERS
 	var req models.ListJobsRequest
@@ -167,34 +138,57 @@ func (h *JobsHandler) ListJobs(c *gin.Context) {
 		return
 	}
 
-	// Valores por defecto
-	if req.Limit == 0 {
-		req.Limit = 10
-	}
-
 	// ───────────────────────────────────────────────────────────────
-	// 2. OBTENER JOBS DE DYNAMODB
+	// 2. OBTENER JOBS DEL REPOSITORIO
 	// ───────────────────────────────────────────────────────────────
-	// TODO: Consultar DynamoDB con filtros
-	// jobs, total, err := h.jobRepository.List(c.Request.Context(), &req)
+	response, err := h.jobManager.ListJobs(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
 
-	// Por ahora, retornar jobs mock
-	jobs := h.getMockJobs()
-	total := len(jobs)
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
 
-	// ───────────────────────────────────────────────────────────────
-	// 3. CONSTRUIR RESPONSE
-	// ───────────────────────────────────────────────────────────────
-	response := models.ListJobsResponse{
-		Jobs:   jobs,
-		Total:  total,
-		Limit:  req.Limit,
-		Offset: req.Offset,
+// ResolveReview replaces the manual_review decision of a job with the reviewer's decision.
+// ENDPOINT: POST /api/v1/jobs/:job_id/review
+//
+// Request:
+//
+//	{"action": "accept", "bin_compartment": "recyclable", "notes": "PET bottle"}
+//
+// Response (200 OK): job con la decisión revisada y la original en review.
+func (h *JobsHandler) ResolveReview(c *gin.Context) {
+	var req models.ResolveReviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid request body",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	job, err := h.jobManager.ResolveReview(c.Request.Context(), c.Param("job_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":  true,
-		"data":     response,
+		"data":     job,
 		"metadata": h.buildMetadata(c),
 	})
 }
@@ -237,25 +231,6 @@ func (h *JobsHandler) DeleteJob(c *gin.Context) {
 //                     HELPER FUNCTIONS
 // ═══════════════════════════════════════════════════════════════════
 
-// generateJobID genera un ID único para un job.
-func (h *JobsHandler) generateJobID() string {
-	return "job_" + uuid.New().String()[:8]
-}
-
-// generateImageKey genera la key S3 para la imagen del job.
-func (h *JobsHandler) generateImageKey(deviceID, jobID string) string {
-	return fmt.Sprintf("uploads/%s/%s.jpg", deviceID, jobID)
-}
-
-// generateMockS3URL genera una URL S3 mock (temporal).
-func (h *JobsHandler) generateMockS3URL(imageKey string) string {
-	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s?X-Amz-Algorithm=...",
-		h.config.AWS.S3.BucketImages,
-		h.config.AWS.Region,
-		imageKey,
-	)
-}
-
 // buildMetadata construye el objeto metadata estándar.
 func (h *JobsHandler) buildMetadata(c *gin.Context) gin.H {
 	return gin.H{
@@ -265,59 +240,3 @@ func (h *JobsHandler) buildMetadata(c *gin.Context) gin.H {
 		"version":    h.config.Server.Version,
 	}
 }
-
-// getMockJob retorna un job mock para testing.
-func (h *JobsHandler) getMockJob(jobID string) *models.Job {
-	completedAt := time.Now().Add(-1 * time.Minute)
-
-	return &models.Job{
-		JobID:       jobID,
-		DeviceID:    "smart-bin-001",
-		Status:      models.JobStatusCompleted,
-		ImageKey:    fmt.Sprintf("uploads/smart-bin-001/%s.jpg", jobID),
-		CreatedAt:   time.Now().Add(-5 * time.Minute),
-		UpdatedAt:   time.Now().Add(-1 * time.Minute),
-		CompletedAt: &completedAt,
-		Classification: &models.Classification{
-			Label:        "plastic_bottle",
-			Confidence:   0.94,
-			ModelVersion: "v1.2.3",
-			Alternatives: []models.Alternative{
-				{Label: "glass_bottle", Confidence: 0.03},
-				{Label: "aluminum_can", Confidence: 0.02},
-			},
-			ProcessingTime: 342,
-		},
-		Decision: &models.Decision{
-			Action:                 "accept",
-			BinCompartment:         "recyclable",
-			Message:                "Item classified correctly as recyclable plastic",
-			ConfidenceThresholdMet: true,
-			RuleApplied:            "recyclable_plastics_high_confidence",
-		},
-	}
-}
-
-// getMockJobs retorna lista de jobs mock para testing.
-func (h *JobsHandler) getMockJobs() []*models.Job {
-	return []*models.Job{
-		{
-			JobID:     "job_abc123",
-			DeviceID:  "smart-bin-001",
-			Status:    models.JobStatusCompleted,
-			CreatedAt: time.Now().Add(-10 * time.Minute),
-		},
-		{
-			JobID:     "job_def456",
-			DeviceID:  "smart-bin-002",
-			Status:    models.JobStatusPending,
-			CreatedAt: time.Now().Add(-5 * time.Minute),
-		},
-		{
-			JobID:     "job_ghi789",
-			DeviceID:  "smart-bin-001",
-			Status:    models.JobStatusProcessing,
-			CreatedAt: time.Now().Add(-2 * time.Minute),
-		},
-	}
-}
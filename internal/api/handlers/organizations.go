@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                            ║
+║  ORGANIZATIONS.GO - HANDLER DE ORGANIZACIONES              ║
+║                                                            ║
+║  Organización del llamante (API key o X-Org-ID):           ║
+║  - GET    /api/v1/organization              - Obtener      ║
+║  - PATCH  /api/v1/organization              - Config       ║
+║  - GET    /api/v1/organization/credentials  - Listar keys  ║
+║  - POST   /api/v1/organization/credentials  - Crear key    ║
+║  - PUT    /api/v1/organization/credentials/:id/roles       ║
+║  - DELETE /api/v1/organization/credentials/:id - Revocar   ║
+║                                                            ║
+║  Super-admin:                                              ║
+║  - POST   /api/v1/admin/organizations       - Crear        ║
+║  - GET    /api/v1/admin/organizations       - Vista global ║
+║  - GET    /api/v1/admin/organizations/:id   - Obtener      ║
+║  - PATCH  /api/v1/admin/organizations/:id   - Config       ║
+║                                                            ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// OrganizationsHandler maneja los endpoints de organizaciones y credenciales de API.
+type OrganizationsHandler struct {
+	config  *config.Config
+	tenants *services.TenantService
+}
+
+// NewOrganizationsHandler crea una nueva instancia de OrganizationsHandler.
+func NewOrganizationsHandler(cfg *config.Config, tenants *services.TenantService) *OrganizationsHandler {
+	return &OrganizationsHandler{
+		config:  cfg,
+		tenants: tenants,
+	}
+}
+
+// GetCurrentOrganization returns the organization of the caller, including its tenant config.
+func (h *OrganizationsHandler) GetCurrentOrganization(c *gin.Context) {
+	org, err := h.tenants.CurrentOrganization(c.Request.Context())
+	h.respondOrganization(c, http.StatusOK, org, err)
+}
+
+// UpdateCurrentOrganization updates the name, alert thresholds or branding of the caller's organization.
+//
+// Request:
+//
+//	{"alert_thresholds": {"fill_high": {"threshold": 85, "clear_threshold": 70}},
+//	 "branding": {"display_name": "Uniandes", "primary_color": "#FFD100"}}
+//
+// Un umbral null vuelve al de la regla global.
+func (h *OrganizationsHandler) UpdateCurrentOrganization(c *gin.Context) {
+	h.updateOrganization(c, models.CreationOrgID(c.Request.Context()))
+}
+
+// ListCredentials returns the API keys of the caller's organization (without the secret).
+func (h *OrganizationsHandler) ListCredentials(c *gin.Context) {
+	credentials, err := h.tenants.ListCredentials(c.Request.Context(), models.CreationOrgID(c.Request.Context()))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     gin.H{"credentials": credentials, "total": len(credentials)},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// CreateCredential issues an API key for the caller's organization.
+//
+// Request:
+//
+//	{"name": "dashboard", "roles": ["operator"]}
+//	{"name": "bin-001", "roles": ["device"], "device_id": "smart-bin-001"}
+//
+// Keys with the device role must be bound to a device_id and only act for that device.
+//
+// Response (201 Created): la key en claro solo se retorna en esta respuesta.
+func (h *OrganizationsHandler) CreateCredential(c *gin.Context) {
+	var req models.CreateCredentialRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.tenants.CreateCredential(c.Request.Context(), models.CreationOrgID(c.Request.Context()), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// AssignRoles replaces the roles of an API key of the caller's organization.
+//
+// Request:
+//
+//	{"roles": ["reviewer", "operator"]}
+func (h *OrganizationsHandler) AssignRoles(c *gin.Context) {
+	var req models.AssignRolesRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	credential, err := h.tenants.AssignRoles(
+		c.Request.Context(),
+		models.CreationOrgID(c.Request.Context()),
+		c.Param("credential_id"),
+		&req,
+	)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     credential,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// RevokeCredential revokes an API key of the caller's organization.
+func (h *OrganizationsHandler) RevokeCredential(c *gin.Context) {
+	credential, err := h.tenants.RevokeCredential(
+		c.Request.Context(),
+		models.CreationOrgID(c.Request.Context()),
+		c.Param("credential_id"),
+	)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     credential,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// CreateOrganization creates a tenant (super-admin).
+//
+// Request:
+//
+//	{"org_id": "uniandes", "name": "Universidad de los Andes",
+//	 "config": {"branding": {"display_name": "Uniandes"}}}
+//
+// Response (201 Created)
+func (h *OrganizationsHandler) CreateOrganization(c *gin.Context) {
+	var req models.CreateOrganizationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	org, err := h.tenants.CreateOrganization(c.Request.Context(), &req)
+	h.respondOrganization(c, http.StatusCreated, org, err)
+}
+
+// ListOrganizations returns every organization with its device and credential totals (super-admin).
+//
+// Query Parameters:
+//   - limit (default: 10)
+//   - offset (default: 0)
+func (h *OrganizationsHandler) ListOrganizations(c *gin.Context) {
+	var req models.ListOrganizationsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.tenants.ListOrganizations(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// GetOrganization returns an organization (super-admin).
+func (h *OrganizationsHandler) GetOrganization(c *gin.Context) {
+	org, err := h.tenants.GetOrganization(c.Request.Context(), c.Param("org_id"))
+	h.respondOrganization(c, http.StatusOK, org, err)
+}
+
+// UpdateOrganization updates the name, alert thresholds or branding of an organization (super-admin).
+func (h *OrganizationsHandler) UpdateOrganization(c *gin.Context) {
+	h.updateOrganization(c, c.Param("org_id"))
+}
+
+// updateOrganization aplica un PATCH a la organización.
+func (h *OrganizationsHandler) updateOrganization(c *gin.Context, orgID string) {
+	var req models.UpdateOrganizationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	org, err := h.tenants.UpdateOrganization(c.Request.Context(), orgID, &req)
+	h.respondOrganization(c, http.StatusOK, org, err)
+}
+
+// respondOrganization responde una organización o el error del dominio.
+func (h *OrganizationsHandler) respondOrganization(c *gin.Context, status int, org *models.Organization, err error) {
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"success":  true,
+		"data":     org,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *OrganizationsHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *OrganizationsHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  PROVISIONING.GO - HANDLER DE APROVISIONAMIENTO MASIVO         ║
+║                                                                ║
+║  - POST /api/v1/devices/import  - Importar manifiesto CSV/JSON ║
+║  - POST /api/v1/devices/claim   - Canjear claim token          ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// ProvisioningHandler maneja la importación masiva y el canje de claim tokens.
+type ProvisioningHandler struct {
+	config       *config.Config
+	provisioning *services.ProvisioningService
+	certificates *services.CertificateService
+}
+
+// NewProvisioningHandler crea una nueva instancia de ProvisioningHandler.
+func NewProvisioningHandler(
+	cfg *config.Config,
+	provisioning *services.ProvisioningService,
+	certificates *services.CertificateService,
+) *ProvisioningHandler {
+	return &ProvisioningHandler{
+		config:       cfg,
+		provisioning: provisioning,
+		certificates: certificates,
+	}
+}
+
+// ImportDevices pre-registers the devices of a manifest in provisioning state.
+//
+// Content-Type text/csv: header con serial_number, device_type y opcionalmente device_id,
+// bin_type, capacity, building, floor, area, zone, latitude, longitude.
+//
+// Content-Type application/json:
+//
+//	{"devices": [{"serial_number": "SB2-0001", "device_type": "smart_bin_v2", "bin_type": "recyclable"}]}
+//
+// Response (200 OK): reporte por fila con el claim token de cada dispositivo creado.
+func (h *ProvisioningHandler) ImportDevices(c *gin.Context) {
+	var req models.ImportDevicesRequest
+
+	if strings.HasPrefix(c.ContentType(), "text/csv") {
+		rows, err := models.ParseProvisioningCSV(c.Request.Body)
+		if err != nil {
+			respondDomainError(c, err, h.buildMetadata(c))
+			return
+		}
+		req.Devices = rows
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	report, err := h.provisioning.ImportDevices(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     report,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// ClaimDevice exchanges a claim token for the device credentials on first boot.
+//
+// Request:
+//
+//	{"claim_token": "smart-bin-sb2-0001.Zx...", "csr": "-----BEGIN CERTIFICATE REQUEST-----..."}
+//
+// Response (200 OK): mismo formato que el registro; private_key solo si no se envió CSR.
+func (h *ProvisioningHandler) ClaimDevice(c *gin.Context) {
+	var req models.ClaimDeviceRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondInvalidInput(c, "Invalid request body", err)
+		return
+	}
+
+	device, certificate, err := h.provisioning.ClaimDevice(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.RegisterDeviceResponse{
+			DeviceID:             device.DeviceID,
+			OrgID:                device.OrgID,
+			DeviceType:           device.DeviceType,
+			Status:               device.Status,
+			Certificate:          certificate.CertificatePEM,
+			CertificateSerial:    certificate.SerialNumber,
+			CertificateExpiresAt: certificate.ExpiresAt,
+			CACertificate:        h.certificates.CACertificate(),
+			PrivateKey:           certificate.PrivateKeyPEM,
+			CreatedAt:            device.CreatedAt,
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// respondInvalidInput responde 400 con el error de binding.
+func (h *ProvisioningHandler) respondInvalidInput(c *gin.Context, message string, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_INPUT",
+			"message": message,
+			"details": gin.H{
+				"error": err.Error(),
+			},
+		},
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *ProvisioningHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RoutesHandler maneja la planeación de rutas de recolección.
+type RoutesHandler struct {
+	config  *config.Config
+	planner *services.RoutePlanner
+}
+
+// NewRoutesHandler crea una nueva instancia de RoutesHandler.
+func NewRoutesHandler(cfg *config.Config, planner *services.RoutePlanner) *RoutesHandler {
+	return &RoutesHandler{
+		config:  cfg,
+		planner: planner,
+	}
+}
+
+// PlanRoute computes the visit order for the bins that need emptying.
+// ENDPOINT: POST /api/v1/routes/plan
+//
+// Request Body:
+//
+//	{
+//	  "start": {"latitude": 4.6097, "longitude": -74.0817},
+//	  "fill_threshold": 80,
+//	  "bin_type": "recyclable",
+//	  "building": "Edificio A"
+//	}
+//
+// Response (200 OK): paradas en orden de visita con distancia desde la parada
+// anterior y acumulada, agrupadas por edificio/piso.
+func (h *RoutesHandler) PlanRoute(c *gin.Context) {
+	var req models.PlanRouteRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid request body",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	plan, err := h.planner.PlanRoute(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     plan,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *RoutesHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
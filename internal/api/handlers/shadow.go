@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                            ║
+║  SHADOW.GO - HANDLER DEL SHADOW DE DISPOSITIVOS            ║
+║                                                            ║
+║  - GET   /api/v1/devices/:device_id/shadow - Obtener       ║
+║  - PATCH /api/v1/devices/:device_id/shadow - Desired    ║
+║                                                            ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// ShadowHandler maneja los endpoints del shadow de dispositivos.
+type ShadowHandler struct {
+	config  *config.Config
+	shadows *services.ShadowService
+}
+
+// NewShadowHandler crea una nueva instancia de ShadowHandler.
+func NewShadowHandler(cfg *config.Config, shadows *services.ShadowService) *ShadowHandler {
+	return &ShadowHandler{
+		config:  cfg,
+		shadows: shadows,
+	}
+}
+
+// GetShadow returns the desired, reported and delta state of a device.
+func (h *ShadowHandler) GetShadow(c *gin.Context) {
+	shadow, err := h.shadows.GetShadow(c.Request.Context(), c.Param("device_id"))
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     shadow,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// UpdateDesired merges changes into the desired state of a device.
+//
+// Request:
+//
+//	{
+//	  "desired": {"capture_interval_s": 30, "led": {"brightness": 80}, "old_key": null},
+//	  "version": 4
+//	}
+//
+// Los objetos se mezclan recursivamente y null elimina la clave. Con version,
+// la actualización falla con 409 VERSION_CONFLICT si el shadow cambió.
+func (h *ShadowHandler) UpdateDesired(c *gin.Context) {
+	var req models.UpdateShadowRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid request body",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	shadow, err := h.shadows.UpdateDesired(c.Request.Context(), c.Param("device_id"), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     shadow,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *ShadowHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
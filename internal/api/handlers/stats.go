@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler maneja las estadísticas agregadas.
+type StatsHandler struct {
+	config *config.Config
+	stats  *services.StatsService
+}
+
+// NewStatsHandler crea una nueva instancia de StatsHandler.
+func NewStatsHandler(cfg *config.Config, stats *services.StatsService) *StatsHandler {
+	return &StatsHandler{
+		config: cfg,
+		stats:  stats,
+	}
+}
+
+// GetLocationStats returns device and job aggregates grouped by a level of the location hierarchy.
+// ENDPOINT: GET /api/v1/stats/locations
+//
+// Query Parameters:
+//   - building, floor, area, zone: camino en la jerarquía (drill-down)
+//   - level (building, floor, area, zone; default: el nivel siguiente al filtro más profundo)
+//   - from, to (RFC3339, default: últimos 7 días): rango de creación de los jobs
+//   - group_id: solo los miembros del grupo
+//
+// Ejemplo: GET /api/v1/stats/locations?building=Edificio%20A → estadísticas por piso del edificio.
+func (h *StatsHandler) GetLocationStats(c *gin.Context) {
+	var req models.LocationStatsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid query parameters",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	response, err := h.stats.LocationStats(c.Request.Context(), &req)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     response,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *StatsHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
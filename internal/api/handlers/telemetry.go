@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TelemetryHandler maneja las consultas del histórico de telemetría.
+type TelemetryHandler struct {
+	config    *config.Config
+	telemetry *services.TelemetryService
+}
+
+// NewTelemetryHandler crea una nueva instancia de TelemetryHandler.
+func NewTelemetryHandler(cfg *config.Config, telemetry *services.TelemetryService) *TelemetryHandler {
+	return &TelemetryHandler{
+		config:    cfg,
+		telemetry: telemetry,
+	}
+}
+
+// GetDeviceTelemetry returns a downsampled metric series for a device.
+// ENDPOINT: GET /api/v1/devices/:device_id/telemetry
+//
+// Query Parameters:
+//   - metric (obligatorio): battery_level, fill_level, signal_strength
+//   - from, to (RFC3339, default: últimas 24h)
+//   - step (ej: 15m, 1h, 1d; default: automático según el rango)
+func (h *TelemetryHandler) GetDeviceTelemetry(c *gin.Context) {
+	query, err := h.parseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_INPUT",
+				"message": "Invalid query parameters",
+				"details": gin.H{
+					"error": err.Error(),
+				},
+			},
+			"metadata": h.buildMetadata(c),
+		})
+		return
+	}
+
+	series, err := h.telemetry.Query(c.Request.Context(), query)
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     series,
+		"metadata": h.buildMetadata(c),
+	})
+}
+
+// parseQuery construye la consulta desde el path y los query params.
+func (h *TelemetryHandler) parseQuery(c *gin.Context) (*models.TelemetryQuery, error) {
+	query := &models.TelemetryQuery{
+		DeviceID: c.Param("device_id"),
+		Metric:   models.TelemetryMetric(c.Query("metric")),
+	}
+
+	if query.Metric == "" {
+		return nil, fmt.Errorf("metric is required")
+	}
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if query.From, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("from must be RFC3339: %w", err)
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if query.To, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("to must be RFC3339: %w", err)
+		}
+	}
+	if value := c.Query("step"); value != "" {
+		if query.Step, err = parseStep(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
+}
+
+// parseStep acepta duraciones de Go (15m, 1h) y días (1d, 7d).
+func parseStep(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	step, err := time.ParseDuration(value)
+	if err != nil || step <= 0 {
+		return 0, fmt.Errorf("invalid step %q", value)
+	}
+	return step, nil
+}
+
+// buildMetadata construye el objeto metadata estándar.
+func (h *TelemetryHandler) buildMetadata(c *gin.Context) gin.H {
+	return gin.H{
+		"timestamp":  time.Now(),
+		"request_id": c.GetString("request_id"),
+		"service":    h.config.Server.ServiceName,
+		"version":    h.config.Server.Version,
+	}
+}
@@ -7,28 +7,44 @@ import (
 
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 )
 
 // WebhooksHandler maneja los endpoints de webhooks y callbacks.
 type WebhooksHandler struct {
-	config *config.Config
+	config       *config.Config
+	jobManager   *services.JobManager
+	deviceEvents *services.DeviceEventService
 }
 
 // NewWebhooksHandler crea una nueva instancia de WebhooksHandler.
-func NewWebhooksHandler(cfg *config.Config) *WebhooksHandler {
+func NewWebhooksHandler(
+	cfg *config.Config,
+	jobManager *services.JobManager,
+	deviceEvents *services.DeviceEventService,
+) *WebhooksHandler {
 	return &WebhooksHandler{
-		config: cfg,
+		config:       cfg,
+		jobManager:   jobManager,
+		deviceEvents: deviceEvents,
 	}
 }
 
 // ClassificationCallback receives callbacks from the Classifier Service when classification completes.
+//
+// Payload:
+//   - status "completed" con classification y decision: el job se completa y la
+//     decisión se publica al dispositivo (bins/{device_id}/decisions)
+//   - status "completed" sin decision: se registra; falta integrar el Decision Service
+//   - status "failed" con error: el job se marca como fallido
 func (h *WebhooksHandler) ClassificationCallback(c *gin.Context) {
 	var payload struct {
 		JobID          string                 `json:"job_id" binding:"required"`
 		Status         string                 `json:"status" binding:"required"`
 		Classification *models.Classification `json:"classification"`
+		Decision       *models.Decision       `json:"decision,omitempty"`
 		Error          string                 `json:"error,omitempty"`
 	}
 
@@ -55,40 +71,55 @@ func (h *WebhooksHandler) ClassificationCallback(c *gin.Context) {
 		Str("request_id", c.GetString("request_id")).
 		Msg("Received classification callback")
 
-	if payload.Status == "completed" && payload.Classification != nil {
+	var (
+		job *models.Job
+		err error
+	)
+
+	switch {
+	case payload.Status == "completed" && payload.Decision != nil:
+		job, err = h.jobManager.CompleteJob(c.Request.Context(), payload.JobID, payload.Classification, payload.Decision)
+
+	case payload.Status == "completed" && payload.Classification != nil:
+		// TODO: Llamar al Decision Service cuando exista el cliente HTTP
 		log.Info().
 			Str("job_id", payload.JobID).
 			Str("label", payload.Classification.Label).
 			Float64("confidence", payload.Classification.Confidence).
 			Msg("Classification successful, would call Decision Service")
-	}
 
-	if payload.Status == "failed" {
+	case payload.Status == "failed":
 		log.Error().
 			Str("job_id", payload.JobID).
 			Str("error", payload.Error).
 			Msg("Classification failed")
+		job, err = h.jobManager.FailJob(c.Request.Context(), payload.JobID, payload.Error)
+	}
+
+	if err != nil {
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
+	}
+
+	data := gin.H{
+		"job_id":       payload.JobID,
+		"received":     true,
+		"processed_at": time.Now(),
+	}
+	if job != nil {
+		data["status"] = job.Status
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"job_id":       payload.JobID,
-			"received":     true,
-			"processed_at": time.Now(),
-		},
+		"success":  true,
+		"data":     data,
 		"metadata": h.buildMetadata(c),
 	})
 }
 
 // DeviceEventCallback receives events from IoT devices.
 func (h *WebhooksHandler) DeviceEventCallback(c *gin.Context) {
-	var event struct {
-		EventType string                 `json:"event_type" binding:"required"`
-		DeviceID  string                 `json:"device_id" binding:"required"`
-		Timestamp string                 `json:"timestamp" binding:"required"`
-		Data      map[string]interface{} `json:"data,omitempty"`
-	}
+	var event models.DeviceEvent
 
 	if err := c.ShouldBindJSON(&event); err != nil {
 		log.Error().
@@ -109,41 +140,33 @@ func (h *WebhooksHandler) DeviceEventCallback(c *gin.Context) {
 
 	log.Info().
 		Str("device_id", event.DeviceID).
-		Str("event_type", event.EventType).
+		Str("event_type", string(event.EventType)).
 		Str("request_id", c.GetString("request_id")).
 		Msg("Received device event")
 
-	switch event.EventType {
-	case "image_captured":
-		log.Info().
-			Str("device_id", event.DeviceID).
-			Msg("Image captured event - would create new job")
-
-	case "device_status":
-		log.Info().
-			Str("device_id", event.DeviceID).
-			Interface("data", event.Data).
-			Msg("Device status update")
-
-	case "error":
+	result, err := h.deviceEvents.HandleEvent(c.Request.Context(), &event)
+	if err != nil {
 		log.Error().
+			Err(err).
 			Str("device_id", event.DeviceID).
-			Interface("data", event.Data).
-			Msg("Device error reported")
+			Str("event_type", string(event.EventType)).
+			Str("request_id", c.GetString("request_id")).
+			Msg("Failed to process device event")
 
-	default:
-		log.Warn().
-			Str("device_id", event.DeviceID).
-			Str("event_type", event.EventType).
-			Msg("Unknown event type")
+		respondDomainError(c, err, h.buildMetadata(c))
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"event_id":     "evt_" + c.GetString("request_id"),
-			"received":     true,
-			"processed_at": time.Now(),
+			"event_id":        "evt_" + c.GetString("request_id"),
+			"received":        true,
+			"job":             result.Job,
+			"device":          result.Device,
+			"command":         result.Command,
+			"firmware_update": result.FirmwareUpdate,
+			"processed_at":    result.ProcessedAt,
 		},
 		"metadata": h.buildMetadata(c),
 	})
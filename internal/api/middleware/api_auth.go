@@ -0,0 +1,112 @@
+// Package middleware provides HTTP middleware functions for the Gin router.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// APIAuthenticator resolves the principal of a request from its API key and X-Org-ID header.
+type APIAuthenticator interface {
+	Authenticate(ctx context.Context, apiKey, orgID string) (*models.Principal, error)
+}
+
+// DeviceLookup retrieves a device with the organization scope of the context.
+type DeviceLookup interface {
+	GetDevice(ctx context.Context, deviceID string) (*models.Device, error)
+}
+
+// APIKey authenticates API clients with "Authorization: Bearer <key>" or "X-API-Key".
+// The principal is stored in the request context, so every repository query is limited to its
+// organization, and its organization in the gin context ("org_id").
+func APIKey(authenticator APIAuthenticator, serviceName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+		if bearer := c.GetHeader("Authorization"); strings.HasPrefix(bearer, "Bearer ") {
+			apiKey = strings.TrimPrefix(bearer, "Bearer ")
+		}
+
+		principal, err := authenticator.Authenticate(c.Request.Context(), apiKey, c.GetHeader("X-Org-ID"))
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("request_id", c.GetString("request_id")).
+				Msg("API key rejected")
+
+			switch {
+			case errors.Is(err, models.ErrUnauthorized):
+				abortAuth(c, serviceName, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+			case errors.Is(err, models.ErrForbidden):
+				abortAuth(c, serviceName, http.StatusForbidden, "FORBIDDEN", err.Error())
+			case errors.Is(err, models.ErrOrganizationNotFound):
+				abortAuth(c, serviceName, http.StatusNotFound, "ORGANIZATION_NOT_FOUND", err.Error())
+			default:
+				abortAuth(c, serviceName, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to authenticate request")
+			}
+			return
+		}
+
+		c.Request = c.Request.WithContext(models.WithPrincipal(c.Request.Context(), principal))
+		c.Set("org_id", principal.OrgID)
+		c.Next()
+	}
+}
+
+// SuperAdmin only lets the super-admin through (organization management, firmware, PKI).
+func SuperAdmin(serviceName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := models.PrincipalFromContext(c.Request.Context())
+		if principal == nil || !principal.SuperAdmin {
+			abortAuth(c, serviceName, http.StatusForbidden, "FORBIDDEN", "Super-admin API key required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission rejects with 403 the principals whose roles do not grant the permission.
+func RequirePermission(permission models.Permission, serviceName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := models.PrincipalFromContext(c.Request.Context())
+		if principal == nil || !principal.Can(permission) {
+			err := fmt.Errorf("%w: permission %s required", models.ErrForbidden, permission)
+			log.Warn().
+				Err(err).
+				Str("request_id", c.GetString("request_id")).
+				Msg("Permission denied")
+
+			abortAuth(c, serviceName, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		c.Next()
+	}
+}
+
+// TenantDevice rejects routes with a :device_id param whose device is not visible to the caller.
+// Telemetry, shadows and commands are stored per device, so the check is done once here.
+func TenantDevice(devices DeviceLookup, serviceName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deviceID := c.Param("device_id")
+		if deviceID == "" {
+			c.Next()
+			return
+		}
+
+		if _, err := devices.GetDevice(c.Request.Context(), deviceID); err != nil {
+			if errors.Is(err, models.ErrDeviceNotFound) {
+				abortAuth(c, serviceName, http.StatusNotFound, "DEVICE_NOT_FOUND", err.Error())
+				return
+			}
+			abortAuth(c, serviceName, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve device")
+			return
+		}
+		c.Next()
+	}
+}
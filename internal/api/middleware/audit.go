@@ -0,0 +1,181 @@
+// Package middleware provides HTTP middleware functions for the Gin router.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditRecorder appends entries to the audit log.
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+}
+
+// SnapshotFunc loads the current state of the resource identified by a route param.
+type SnapshotFunc func(ctx context.Context, id string) (interface{}, error)
+
+// Audit records an audit entry for every successful mutating request (POST, PUT, PATCH, DELETE).
+// snapshots maps route params (e.g. "device_id") to the loader of their resource: the entry stores
+// the diff between the resource before and after the handler. Creations (201, 202) and routes
+// without a loader use the "data" of the response as the after state.
+func Audit(recorder AuditRecorder, snapshots map[string]SnapshotFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		resourceType, param := auditResource(c)
+		load := snapshots[param.Key]
+
+		// El antes se lee sin scope: la entrada guarda la organización del recurso
+		var before map[string]interface{}
+		if load != nil {
+			before = auditSnapshot(c.Request.Context(), load, param.Value)
+		}
+
+		capture := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = capture
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusBadRequest {
+			return
+		}
+
+		// Altas (201) y encolados (202, ej: comandos) registran lo creado, no el recurso padre
+		var after map[string]interface{}
+		switch {
+		case status == http.StatusCreated || status == http.StatusAccepted:
+			before = nil
+			after = responseData(capture.body.Bytes())
+		case load != nil:
+			after = auditSnapshot(c.Request.Context(), load, param.Value)
+		default:
+			after = responseData(capture.body.Bytes())
+		}
+
+		entry := &models.AuditEntry{
+			OrgID:        auditOrgID(c.Request.Context(), before, after),
+			Actor:        "anonymous",
+			Action:       c.Request.Method + " " + c.FullPath(),
+			ResourceType: resourceType,
+			ResourceID:   param.Value,
+			Changes:      models.DiffAuditSnapshots(before, after),
+			RequestID:    c.GetString("request_id"),
+			IP:           c.ClientIP(),
+			StatusCode:   status,
+		}
+		if principal := models.PrincipalFromContext(c.Request.Context()); principal != nil {
+			entry.Actor = principal.Actor()
+		}
+
+		// La respuesta ya se envió: un fallo del registro solo se loguea
+		if err := recorder.Record(c.Request.Context(), entry); err != nil {
+			log.Error().
+				Err(err).
+				Str("action", entry.Action).
+				Str("request_id", entry.RequestID).
+				Msg("Failed to record audit entry")
+		}
+	}
+}
+
+// bodyRecorder copia el body de la respuesta para extraer el "data" de las altas.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write escribe la respuesta y guarda una copia.
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString escribe la respuesta y guarda una copia.
+func (w *bodyRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// auditResource retorna el tipo de recurso y el último param de la ruta.
+// El tipo es el segmento anterior al param ("/devices/:device_id/status" → devices) o,
+// sin params, el primero ("/jobs" → jobs); el prefijo /admin se ignora.
+func auditResource(c *gin.Context) (string, gin.Param) {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(c.FullPath(), "/api/v1"), "/"), "/")
+	if len(segments) > 0 && segments[0] == "admin" {
+		segments = segments[1:]
+	}
+
+	resourceType := ""
+	if len(segments) > 0 {
+		resourceType = segments[0]
+	}
+
+	var param gin.Param
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") && i > 0 {
+			resourceType = segments[i-1]
+			param = gin.Param{Key: segment[1:], Value: c.Param(segment[1:])}
+		}
+	}
+	return resourceType, param
+}
+
+// auditSnapshot carga el recurso; si no existe (o ya no existe) retorna nil.
+func auditSnapshot(ctx context.Context, load SnapshotFunc, id string) map[string]interface{} {
+	resource, err := load(models.WithPrincipal(ctx, nil), id)
+	if err != nil {
+		return nil
+	}
+
+	snapshot, err := models.AuditSnapshot(resource)
+	if err != nil {
+		log.Warn().Err(err).Str("resource_id", id).Msg("Failed to snapshot audited resource")
+		return nil
+	}
+	return snapshot
+}
+
+// responseData retorna el "data" de una respuesta estándar de la API, sin secretos.
+func responseData(body []byte) map[string]interface{} {
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Data) == 0 {
+		return nil
+	}
+
+	snapshot, err := models.AuditSnapshot(response.Data)
+	if err != nil {
+		return nil
+	}
+	return snapshot
+}
+
+// auditOrgID retorna la organización del principal o, para el super-admin y las rutas sin
+// API key, la del recurso. Si el recurso no tiene, la del super-admin es la de sus altas.
+func auditOrgID(ctx context.Context, before, after map[string]interface{}) string {
+	if scope := models.OrgScope(ctx); scope != "" {
+		return scope
+	}
+	for _, snapshot := range []map[string]interface{}{after, before} {
+		if orgID, ok := snapshot["org_id"].(string); ok {
+			return orgID
+		}
+	}
+	if models.PrincipalFromContext(ctx) != nil {
+		return models.CreationOrgID(ctx)
+	}
+	return ""
+}
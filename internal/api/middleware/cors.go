@@ -15,7 +15,7 @@ func CORS() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Headers",
 			"Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, "+
 				"Authorization, accept, origin, Cache-Control, X-Requested-With, "+
-				"X-API-KEY, X-Request-ID")
+				"X-API-KEY, X-Org-ID, X-Request-ID")
 		c.Header("Access-Control-Allow-Methods",
 			"POST, GET, OPTIONS, PUT, DELETE, PATCH")
 		c.Header("Access-Control-Max-Age", "86400")
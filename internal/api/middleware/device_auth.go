@@ -0,0 +1,90 @@
+// Package middleware provides HTTP middleware functions for the Gin router.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/pki"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// DeviceAuthenticator resolves the device that owns a client certificate,
+// rejecting revoked or superseded certificates.
+type DeviceAuthenticator interface {
+	AuthenticateDevice(ctx context.Context, deviceID, serial string) (*models.Device, error)
+}
+
+// DeviceCertificate authenticates devices on the mTLS listener.
+// The TLS handshake already verified the chain against the device CA; this middleware maps the
+// certificate CN to the device, rejects revoked certificates and inactive or decommissioned
+// devices, and stores the device ID in the context ("device_id") and the device principal in the
+// request context. Routes with a :device_id param only accept the authenticated device.
+func DeviceCertificate(devices DeviceAuthenticator, serviceName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 {
+			abortAuth(c, serviceName, http.StatusUnauthorized, "UNAUTHORIZED", "Client certificate required")
+			return
+		}
+
+		certificate := c.Request.TLS.VerifiedChains[0][0]
+		deviceID := certificate.Subject.CommonName
+		serial := pki.SerialHex(certificate.SerialNumber)
+		device, err := devices.AuthenticateDevice(c.Request.Context(), deviceID, serial)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("device_id", deviceID).
+				Str("serial", serial).
+				Str("request_id", c.GetString("request_id")).
+				Msg("Device certificate rejected")
+
+			switch {
+			case errors.Is(err, models.ErrDeviceInactive):
+				abortAuth(c, serviceName, http.StatusForbidden, "DEVICE_INACTIVE", err.Error())
+			case errors.Is(err, models.ErrCertificateRevoked):
+				abortAuth(c, serviceName, http.StatusUnauthorized, "CERTIFICATE_REVOKED", err.Error())
+			case errors.Is(err, models.ErrCertificateSuperseded):
+				abortAuth(c, serviceName, http.StatusUnauthorized, "CERTIFICATE_SUPERSEDED", err.Error())
+			case errors.Is(err, models.ErrUnauthorized):
+				abortAuth(c, serviceName, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+			default:
+				abortAuth(c, serviceName, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to authenticate device")
+			}
+			return
+		}
+
+		if param := c.Param("device_id"); param != "" && param != deviceID {
+			abortAuth(c, serviceName, http.StatusForbidden, "FORBIDDEN", "Certificate does not belong to device "+param)
+			return
+		}
+
+		// El dispositivo solo ve los registros de su organización
+		principal := &models.Principal{OrgID: device.OrgID, DeviceID: deviceID, Roles: []models.Role{models.RoleDevice}}
+		c.Request = c.Request.WithContext(models.WithPrincipal(c.Request.Context(), principal))
+
+		c.Set("device_id", deviceID)
+		c.Set("org_id", device.OrgID)
+		c.Next()
+	}
+}
+
+// abortAuth corta la petición con la respuesta de error estándar de la API.
+func abortAuth(c *gin.Context, serviceName string, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+		"metadata": gin.H{
+			"timestamp":  time.Now(),
+			"request_id": c.GetString("request_id"),
+			"service":    serviceName,
+		},
+	})
+}
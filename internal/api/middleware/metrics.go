@@ -0,0 +1,27 @@
+// Package middleware provides HTTP middleware functions for the Gin router.
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPMetrics records the HTTP requests (see metrics.Registry).
+type HTTPMetrics interface {
+	// RequestStarted counts a request in flight and returns the function that observes its end.
+	RequestStarted(method, route string) func(status int)
+}
+
+// Metrics records every request by method, route template and status code.
+// Requests without a matching route are grouped as "unmatched" to keep the label cardinality bounded.
+func Metrics(metrics HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		finished := metrics.RequestStarted(c.Request.Method, route)
+		c.Next()
+		finished(c.Writer.Status())
+	}
+}
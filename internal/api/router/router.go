@@ -3,16 +3,44 @@
 package router
 
 import (
+	"context"
 	"time"
 
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/api/handlers"
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/api/middleware"
 	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
 	"github.com/gin-gonic/gin"
 )
 
+// Dependencies agrupa los servicios del dominio que necesitan los handlers.
+type Dependencies struct {
+	JobManager    *services.JobManager
+	DeviceManager *services.DeviceManager
+	DeviceEvents  *services.DeviceEventService
+	Telemetry     *services.TelemetryService
+	Heartbeat     *services.HeartbeatMonitor
+	Alerts        *services.AlertService
+	Forecast      *services.ForecastService
+	RoutePlanner  *services.RoutePlanner
+	Stats         *services.StatsService
+	Shadows       *services.ShadowService
+	Commands      *services.CommandService
+	Firmware      *services.FirmwareService
+	Certificates  *services.CertificateService
+	Provisioning  *services.ProvisioningService
+	DeviceUpdates *services.DeviceUpdateService
+	Decommission  *services.DecommissionService
+	Groups        *services.GroupService
+	Tenants       *services.TenantService
+	Audit         *services.AuditService
+	Metrics       *metrics.Registry
+}
+
 // NewRouter crea y configura el router HTTP principal.
-func NewRouter(cfg *config.Config) *gin.Engine {
+func NewRouter(cfg *config.Config, deps *Dependencies) *gin.Engine {
 	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
@@ -24,38 +52,206 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 	router.Use(gin.Recovery())
 	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger())
+	router.Use(middleware.Metrics(deps.Metrics))
 	router.Use(middleware.CORS())
 	router.Use(middleware.SecurityHeaders())
 
-	healthHandler := handlers.NewHealthHandler(cfg)
+	healthHandler := handlers.NewHealthHandler(cfg, deps.Metrics)
 	router.GET("/health", healthHandler.Health)
 	router.GET("/ready", healthHandler.Ready)
 	router.GET("/metrics", healthHandler.Metrics)
 
-	v1 := router.Group("/api/v1")
-
-	jobsHandler := handlers.NewJobsHandler(cfg)
-	jobs := v1.Group("/jobs")
-	jobs.POST("", jobsHandler.CreateJob)
-	jobs.GET("/:job_id", jobsHandler.GetJob)
-	jobs.GET("", jobsHandler.ListJobs)
-	jobs.PATCH("/:job_id", jobsHandler.UpdateJob)
-	jobs.DELETE("/:job_id", jobsHandler.DeleteJob)
-
-	devicesHandler := handlers.NewDevicesHandler(cfg)
-	devices := v1.Group("/devices")
-	devices.POST("/register", devicesHandler.RegisterDevice)
-	devices.GET("/:device_id", devicesHandler.GetDevice)
-	devices.GET("", devicesHandler.ListDevices)
-	devices.PATCH("/:device_id", devicesHandler.UpdateDevice)
-	devices.DELETE("/:device_id", devicesHandler.DeleteDevice)
-
-	webhooksHandler := handlers.NewWebhooksHandler(cfg)
-	webhooks := v1.Group("/webhooks")
-	webhooks.POST("/classification", webhooksHandler.ClassificationCallback)
-	webhooks.POST("/device-event", webhooksHandler.DeviceEventCallback)
-
-	router.NoRoute(func(c *gin.Context) {
+	// Toda modificación exitosa queda en el registro de auditoría
+	v1 := router.Group("/api/v1", middleware.Audit(deps.Audit, auditSnapshots(deps)))
+
+	// Las rutas de api exigen API key; el principal limita las consultas a su organización
+	// y cada ruta exige un permiso de sus roles. Quedan fuera el claim de dispositivos
+	// (autenticado con su claim token) y la CRL
+	api := v1.Group("", middleware.APIKey(deps.Tenants, cfg.Server.ServiceName))
+	superAdmin := middleware.SuperAdmin(cfg.Server.ServiceName)
+	allow := func(permission models.Permission) gin.HandlerFunc {
+		return middleware.RequirePermission(permission, cfg.Server.ServiceName)
+	}
+	read := allow(models.PermissionRead)
+	manage := allow(models.PermissionDevicesManage)
+
+	jobsHandler := handlers.NewJobsHandler(cfg, deps.JobManager)
+	jobs := api.Group("/jobs")
+	jobs.POST("", allow(models.PermissionJobsCreate), jobsHandler.CreateJob)
+	jobs.GET("/:job_id", allow(models.PermissionJobsRead), jobsHandler.GetJob)
+	jobs.GET("", allow(models.PermissionJobsRead), jobsHandler.ListJobs)
+	jobs.PATCH("/:job_id", manage, jobsHandler.UpdateJob)
+	jobs.DELETE("/:job_id", manage, jobsHandler.DeleteJob)
+	jobs.POST("/:job_id/review", allow(models.PermissionReviewsResolve), jobsHandler.ResolveReview)
+
+	devicesHandler := handlers.NewDevicesHandler(cfg, deps.DeviceManager, deps.Certificates, deps.DeviceUpdates, deps.Decommission)
+	devices := api.Group("/devices", middleware.TenantDevice(deps.DeviceManager, cfg.Server.ServiceName))
+	devices.POST("/register", manage, devicesHandler.RegisterDevice)
+	devices.GET("/:device_id", read, devicesHandler.GetDevice)
+	devices.GET("", read, devicesHandler.ListDevices)
+	devices.PATCH("/:device_id", manage, devicesHandler.UpdateDevice)
+	devices.PUT("/:device_id/status", manage, devicesHandler.ChangeDeviceStatus)
+	devices.DELETE("/:device_id", manage, devicesHandler.DeleteDevice)
+
+	provisioningHandler := handlers.NewProvisioningHandler(cfg, deps.Provisioning, deps.Certificates)
+	devices.POST("/import", manage, provisioningHandler.ImportDevices)
+	// El dispositivo se autentica con su claim token
+	v1.POST("/devices/claim", provisioningHandler.ClaimDevice)
+
+	telemetryHandler := handlers.NewTelemetryHandler(cfg, deps.Telemetry)
+	devices.GET("/:device_id/telemetry", read, telemetryHandler.GetDeviceTelemetry)
+
+	shadowHandler := handlers.NewShadowHandler(cfg, deps.Shadows)
+	devices.GET("/:device_id/shadow", read, shadowHandler.GetShadow)
+	devices.PATCH("/:device_id/shadow", manage, shadowHandler.UpdateDesired)
+
+	certificatesHandler := handlers.NewCertificatesHandler(cfg, deps.Certificates)
+	devices.GET("/:device_id/certificates", read, certificatesHandler.ListDeviceCertificates)
+	devices.POST("/:device_id/certificates/rotate", manage, certificatesHandler.RotateCertificate)
+
+	v1.GET("/certificates/crl", certificatesHandler.GetCRL)
+	certificates := api.Group("/certificates", superAdmin)
+	certificates.GET("/expiring", certificatesHandler.ListExpiringCertificates)
+	certificates.POST("/:serial/revoke", certificatesHandler.RevokeCertificate)
+
+	commandsHandler := handlers.NewCommandsHandler(cfg, deps.Commands)
+	devices.POST("/:device_id/commands", manage, commandsHandler.CreateCommand)
+	devices.GET("/:device_id/commands", read, commandsHandler.ListCommands)
+	devices.GET("/:device_id/commands/:command_id", read, commandsHandler.GetCommand)
+
+	forecastHandler := handlers.NewForecastHandler(cfg, deps.Forecast)
+	devices.GET("/forecast", read, forecastHandler.ListForecasts)
+
+	firmwareHandler := handlers.NewFirmwareHandler(cfg, deps.Firmware)
+	firmware := api.Group("/firmware", superAdmin)
+	firmware.POST("/releases", firmwareHandler.CreateRelease)
+	firmware.GET("/releases", firmwareHandler.ListReleases)
+	firmware.GET("/releases/:release_id", firmwareHandler.GetRelease)
+	firmware.POST("/rollouts", firmwareHandler.CreateRollout)
+	firmware.GET("/rollouts", firmwareHandler.ListRollouts)
+	firmware.GET("/rollouts/:rollout_id", firmwareHandler.GetRollout)
+	firmware.PATCH("/rollouts/:rollout_id", firmwareHandler.UpdateRollout)
+	firmware.POST("/rollouts/:rollout_id/pause", firmwareHandler.PauseRollout)
+	firmware.POST("/rollouts/:rollout_id/resume", firmwareHandler.ResumeRollout)
+
+	routesHandler := handlers.NewRoutesHandler(cfg, deps.RoutePlanner)
+	routes := api.Group("/routes")
+	routes.POST("/plan", read, routesHandler.PlanRoute)
+
+	groupsHandler := handlers.NewGroupsHandler(cfg, deps.Groups)
+	groups := api.Group("/groups")
+	groups.POST("", manage, groupsHandler.CreateGroup)
+	groups.GET("", read, groupsHandler.ListGroups)
+	groups.GET("/:group_id", read, groupsHandler.GetGroup)
+	groups.PATCH("/:group_id", manage, groupsHandler.UpdateGroup)
+	groups.DELETE("/:group_id", manage, groupsHandler.DeleteGroup)
+	groups.GET("/:group_id/devices", read, groupsHandler.ListGroupDevices)
+	groups.POST("/:group_id/status", manage, groupsHandler.ChangeGroupStatus)
+	groups.POST("/:group_id/commands", manage, groupsHandler.SendGroupCommand)
+	groups.PATCH("/:group_id/configuration", manage, groupsHandler.UpdateGroupConfiguration)
+
+	statsHandler := handlers.NewStatsHandler(cfg, deps.Stats)
+	stats := api.Group("/stats")
+	stats.GET("/locations", read, statsHandler.GetLocationStats)
+
+	alertsHandler := handlers.NewAlertsHandler(cfg, deps.Alerts)
+	alerts := api.Group("/alerts")
+	alerts.GET("", read, alertsHandler.ListAlerts)
+	alerts.GET("/:alert_id", read, alertsHandler.GetAlert)
+	alerts.POST("/:alert_id/acknowledge", manage, alertsHandler.AcknowledgeAlert)
+	alerts.POST("/:alert_id/resolve", manage, alertsHandler.ResolveAlert)
+
+	organizationsHandler := handlers.NewOrganizationsHandler(cfg, deps.Tenants)
+	organization := api.Group("/organization")
+	organizationManage := allow(models.PermissionOrganizationManage)
+	organization.GET("", read, organizationsHandler.GetCurrentOrganization)
+	organization.PATCH("", organizationManage, organizationsHandler.UpdateCurrentOrganization)
+	organization.GET("/credentials", organizationManage, organizationsHandler.ListCredentials)
+	organization.POST("/credentials", organizationManage, organizationsHandler.CreateCredential)
+	organization.PUT("/credentials/:credential_id/roles", organizationManage, organizationsHandler.AssignRoles)
+	organization.DELETE("/credentials/:credential_id", organizationManage, organizationsHandler.RevokeCredential)
+
+	auditHandler := handlers.NewAuditHandler(cfg, deps.Audit)
+	audit := api.Group("/audit")
+	audit.GET("", allow(models.PermissionAuditRead), auditHandler.ListAudit)
+	audit.GET("/export", allow(models.PermissionAuditRead), auditHandler.ExportAudit)
+	audit.GET("/verify", superAdmin, auditHandler.VerifyAudit)
+
+	admin := api.Group("/admin", superAdmin)
+	admin.POST("/organizations", organizationsHandler.CreateOrganization)
+	admin.GET("/organizations", organizationsHandler.ListOrganizations)
+	admin.GET("/organizations/:org_id", organizationsHandler.GetOrganization)
+	admin.PATCH("/organizations/:org_id", organizationsHandler.UpdateOrganization)
+
+	// El servicio de clasificación usa una API key con rol classifier (o la del super-admin si
+	// atiende varias organizaciones); los dispositivos publican eventos con la suya
+	webhooksHandler := handlers.NewWebhooksHandler(cfg, deps.JobManager, deps.DeviceEvents)
+	api.POST("/webhooks/classification", allow(models.PermissionJobsComplete), webhooksHandler.ClassificationCallback)
+	api.POST("/webhooks/device-event", allow(models.PermissionEventsPost), webhooksHandler.DeviceEventCallback)
+
+	router.NoRoute(notFound(cfg))
+
+	return router
+}
+
+// NewDeviceRouter crea el router del listener mTLS.
+// Solo expone las rutas que usan los dispositivos y exige que el certificado
+// de cliente corresponda a un dispositivo activo (ver middleware.DeviceCertificate).
+func NewDeviceRouter(cfg *config.Config, deps *Dependencies) *gin.Engine {
+	router := gin.New()
+
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Logger())
+	router.Use(middleware.Metrics(deps.Metrics))
+	router.Use(middleware.SecurityHeaders())
+
+	v1 := router.Group("/api/v1", middleware.Audit(deps.Audit, auditSnapshots(deps)))
+	v1.Use(middleware.DeviceCertificate(deps.Certificates, cfg.Server.ServiceName))
+
+	webhooksHandler := handlers.NewWebhooksHandler(cfg, deps.JobManager, deps.DeviceEvents)
+	v1.POST("/webhooks/device-event", webhooksHandler.DeviceEventCallback)
+
+	shadowHandler := handlers.NewShadowHandler(cfg, deps.Shadows)
+	v1.GET("/devices/:device_id/shadow", shadowHandler.GetShadow)
+
+	// El dispositivo rota su propio certificado con el vigente (o el anterior durante el solape)
+	certificatesHandler := handlers.NewCertificatesHandler(cfg, deps.Certificates)
+	v1.POST("/devices/:device_id/certificates/rotate", certificatesHandler.RotateCertificate)
+
+	commandsHandler := handlers.NewCommandsHandler(cfg, deps.Commands)
+	v1.GET("/devices/:device_id/commands", commandsHandler.ListCommands)
+	v1.GET("/devices/:device_id/commands/:command_id", commandsHandler.GetCommand)
+
+	router.NoRoute(notFound(cfg))
+
+	return router
+}
+
+// auditSnapshots retorna el loader de cada param de ruta para el diff de middleware.Audit.
+func auditSnapshots(deps *Dependencies) map[string]middleware.SnapshotFunc {
+	return map[string]middleware.SnapshotFunc{
+		"device_id":     snapshot(deps.DeviceManager.GetDevice),
+		"job_id":        snapshot(deps.JobManager.GetJob),
+		"group_id":      snapshot(deps.Groups.GetGroup),
+		"alert_id":      snapshot(deps.Alerts.GetAlert),
+		"release_id":    snapshot(deps.Firmware.GetRelease),
+		"rollout_id":    snapshot(deps.Firmware.GetRollout),
+		"org_id":        snapshot(deps.Tenants.GetOrganization),
+		"credential_id": snapshot(deps.Tenants.GetCredential),
+	}
+}
+
+// snapshot adapta un getter del dominio a middleware.SnapshotFunc.
+func snapshot[T any](get func(context.Context, string) (T, error)) middleware.SnapshotFunc {
+	return func(ctx context.Context, id string) (interface{}, error) {
+		return get(ctx, id)
+	}
+}
+
+// notFound responde 404 con el formato estándar de error.
+func notFound(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		c.JSON(404, gin.H{
 			"success": false,
 			"error": gin.H{
@@ -68,7 +264,5 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 				"service":    cfg.Server.ServiceName,
 			},
 		})
-	})
-
-	return router
+	}
 }
@@ -9,6 +9,7 @@ import (
 	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,6 +21,11 @@ type Config struct {
 	Security      SecurityConfig
 	Features      FeaturesConfig
 	Observability ObservabilityConfig
+	Telemetry     TelemetryConfig
+	Heartbeat     HeartbeatConfig
+	Alerts        AlertsConfig
+	Forecast      ForecastConfig
+	Provisioning  ProvisioningConfig
 }
 
 // ServerConfig contains HTTP server configuration.
@@ -29,6 +35,21 @@ type ServerConfig struct {
 	ServiceName             string
 	Version                 string
 	GracefulShutdownTimeout time.Duration
+
+	// MTLS - Listener HTTPS opcional para dispositivos con certificado de cliente
+	MTLS MTLSConfig
+}
+
+// MTLSConfig contains the optional HTTPS listener that authenticates devices by client certificate.
+// Without CertFile/KeyFile the server certificate is issued at startup by the device CA.
+type MTLSConfig struct {
+	// Port - Vacío deshabilita el listener
+	Port     string
+	CertFile string
+	KeyFile  string
+
+	// Hosts - Nombres (o IPs) del certificado de servidor emitido por la CA
+	Hosts []string
 }
 
 // AWSConfig contains all AWS service configurations.
@@ -60,6 +81,7 @@ type DynamoDBConfig struct {
 type S3Config struct {
 	Endpoint           string // For LocalStack
 	BucketImages       string
+	BucketFirmware     string
 	PresignedURLExpiry time.Duration
 }
 
@@ -70,9 +92,53 @@ type SQSConfig struct {
 	QueueURLDLQ            string
 }
 
-// IoTConfig contains AWS IoT Core settings.
+// IoTConfig contains the MQTT settings used to talk to devices (AWS IoT Core or any MQTT 3.1.1 broker).
+// Endpoint accepts a full broker URL (tcp://, ssl://, tls://) or an IoT Core host name, which defaults to ssl://host:8883.
+// With an empty Endpoint decisions are kept in an in-memory publisher and device events only arrive through the HTTP webhook.
 type IoTConfig struct {
 	Endpoint string
+
+	// ClientID - ID de cliente MQTT. Con SharedGroup se le agrega -<hostname>-<pid> (ver
+	// instanceClientID): el broker desconecta a la sesión anterior cuando otra usa el mismo ID
+	ClientID string
+	Username string
+	Password string
+
+	// CAFile, CertFile, KeyFile - TLS (IoT Core requiere certificado de cliente)
+	CAFile   string
+	CertFile string
+	KeyFile  string
+
+	// QoS - Nivel de QoS de las publicaciones (default: 1)
+	QoS byte
+
+	// DecisionTopic - Topic de decisiones; {device_id} se reemplaza por el ID del dispositivo
+	DecisionTopic string
+
+	// ShadowDeltaTopic - Topic donde se publica el delta del shadow ({device_id} se reemplaza)
+	ShadowDeltaTopic string
+
+	// CommandTopic - Topic donde se publican los comandos remotos ({device_id} se reemplaza)
+	CommandTopic string
+
+	// FirmwareTopic - Topic donde se publican las actualizaciones OTA ({device_id} se reemplaza)
+	FirmwareTopic string
+
+	// SubscribeEvents - Recibir eventos de dispositivos por MQTT además del webhook HTTP
+	SubscribeEvents bool
+
+	// EventsTopic - Filtro de eventos; el segundo nivel es el ID del dispositivo (bins/+/events)
+	EventsTopic string
+
+	// SharedGroup - Grupo de suscripción compartida ($share/<grupo>/...).
+	// Con varias réplicas el broker reparte los eventos entre ellas; vacío = suscripción normal.
+	SharedGroup string
+
+	// DeadLetterTopic - Topic donde se reenvían los payloads que no se pueden procesar
+	DeadLetterTopic string
+
+	ConnectTimeout time.Duration
+	PublishTimeout time.Duration
 }
 
 // ServicesConfig contains external service URLs and settings.
@@ -102,8 +168,38 @@ type SecurityConfig struct {
 	CognitoClientID   string
 	JWTSecret         string
 
+	// AdminAPIKey - API key del super-admin (todas las organizaciones).
+	// Vacía (nunca en producción) deja la API abierta: las peticiones sin key actúan como super-admin
+	AdminAPIKey string
+
 	RateLimit      RateLimitConfig
 	CircuitBreaker CircuitBreakerConfig
+
+	// DeviceCA - CA interna que firma los certificados de los dispositivos
+	DeviceCA DeviceCAConfig
+}
+
+// DeviceCAConfig contains the internal CA used to issue device certificates.
+// Without CertFile/KeyFile a throwaway CA is generated at startup (not allowed in production):
+// certificates issued by it stop validating after a restart.
+type DeviceCAConfig struct {
+	CertFile string
+	KeyFile  string
+
+	// CertValidity - Vigencia de los certificados emitidos (default: 1 año)
+	CertValidity time.Duration
+
+	// RotationOverlap - Tiempo que se sigue aceptando el certificado anterior tras rotar (default: 72h)
+	RotationOverlap time.Duration
+
+	// CRLValidity - NextUpdate de la CRL publicada (default: 24h)
+	CRLValidity time.Duration
+
+	// ExpiryWarningDays - Ventana del reporte de certificados por expirar (default: 30 días)
+	ExpiryWarningDays int
+
+	// ExpiryScanInterval - Cada cuánto se retiran los certificados rotados y se reportan los que expiran
+	ExpiryScanInterval time.Duration
 }
 
 // RateLimitConfig contains rate limiting settings.
@@ -134,6 +230,50 @@ type ObservabilityConfig struct {
 	MetricsPort   string
 }
 
+// TelemetryConfig contains telemetry history retention settings.
+type TelemetryConfig struct {
+	RawRetention      time.Duration
+	HourlyRetention   time.Duration
+	DailyRetention    time.Duration
+	RetentionInterval time.Duration
+}
+
+// HeartbeatConfig contains offline-detection settings.
+// OfflineAfterByType overrides OfflineAfter for specific device types.
+type HeartbeatConfig struct {
+	ScanInterval       time.Duration
+	OfflineAfter       time.Duration
+	OfflineAfterByType map[string]time.Duration
+}
+
+// OfflineThreshold returns the time without heartbeat after which a device of the given type is offline.
+func (h HeartbeatConfig) OfflineThreshold(deviceType string) time.Duration {
+	if threshold, ok := h.OfflineAfterByType[deviceType]; ok {
+		return threshold
+	}
+	return h.OfflineAfter
+}
+
+// AlertsConfig contains maintenance alerting settings.
+// RulesFile points to a JSON array of alert rules; empty uses the built-in defaults.
+type AlertsConfig struct {
+	RulesFile string
+}
+
+// ForecastConfig contains fill-level forecasting settings.
+// Window is the history used to fit the fill rate; FillPerJob is the fill
+// percentage added by each classified item when there is not enough telemetry.
+type ForecastConfig struct {
+	Window     time.Duration
+	FillPerJob float64
+}
+
+// ProvisioningConfig contains bulk provisioning settings.
+// ClaimTokenTTL is how long an imported device can wait to exchange its claim token.
+type ProvisioningConfig struct {
+	ClaimTokenTTL time.Duration
+}
+
 // Load loads configuration from environment variables.
 func Load() (*Config, error) {
 	cfg := &Config{
@@ -143,6 +283,12 @@ func Load() (*Config, error) {
 			ServiceName:             getEnv("SERVICE_NAME", "orchestrator"),
 			Version:                 getEnv("VERSION", "1.0.0"),
 			GracefulShutdownTimeout: getDurationEnv("GRACEFUL_SHUTDOWN_TIMEOUT", "30s"),
+			MTLS: MTLSConfig{
+				Port:     getEnv("MTLS_PORT", ""),
+				CertFile: getEnv("MTLS_CERT_FILE", ""),
+				KeyFile:  getEnv("MTLS_KEY_FILE", ""),
+				Hosts:    getSliceEnv("MTLS_HOSTS", []string{"localhost", "127.0.0.1"}),
+			},
 		},
 		AWS: AWSConfig{
 			Region:    getEnv("AWS_REGION", "us-east-1"),
@@ -155,6 +301,7 @@ func Load() (*Config, error) {
 			S3: S3Config{
 				Endpoint:           getEnv("S3_ENDPOINT", ""),
 				BucketImages:       getEnv("S3_BUCKET_IMAGES", "smart-bin-dev-images"),
+				BucketFirmware:     getEnv("S3_BUCKET_FIRMWARE", "smart-bin-dev-firmware"),
 				PresignedURLExpiry: getDurationEnv("S3_PRESIGNED_URL_EXPIRY", "15m"),
 			},
 			SQS: SQSConfig{
@@ -163,7 +310,24 @@ func Load() (*Config, error) {
 				QueueURLDLQ:            getEnv("SQS_QUEUE_URL_DLQ", ""),
 			},
 			IoT: IoTConfig{
-				Endpoint: getEnv("IOT_ENDPOINT", ""),
+				Endpoint:         getEnv("IOT_ENDPOINT", ""),
+				ClientID:         getEnv("IOT_CLIENT_ID", "orchestrator"),
+				Username:         getEnv("IOT_USERNAME", ""),
+				Password:         getEnv("IOT_PASSWORD", ""),
+				CAFile:           getEnv("IOT_CA_FILE", ""),
+				CertFile:         getEnv("IOT_CERT_FILE", ""),
+				KeyFile:          getEnv("IOT_KEY_FILE", ""),
+				QoS:              byte(getIntEnv("IOT_QOS", 1)),
+				DecisionTopic:    getEnv("IOT_DECISION_TOPIC", "bins/{device_id}/decisions"),
+				ShadowDeltaTopic: getEnv("IOT_SHADOW_DELTA_TOPIC", "bins/{device_id}/shadow/delta"),
+				CommandTopic:     getEnv("IOT_COMMAND_TOPIC", "bins/{device_id}/commands"),
+				FirmwareTopic:    getEnv("IOT_FIRMWARE_TOPIC", "bins/{device_id}/ota"),
+				SubscribeEvents:  getBoolEnv("IOT_SUBSCRIBE_EVENTS", true),
+				EventsTopic:      getEnv("IOT_EVENTS_TOPIC", "bins/+/events"),
+				SharedGroup:      getEnv("IOT_SHARED_GROUP", ""),
+				DeadLetterTopic:  getEnv("IOT_DEAD_LETTER_TOPIC", "orchestrator/events/dead-letter"),
+				ConnectTimeout:   getDurationEnv("IOT_CONNECT_TIMEOUT", "10s"),
+				PublishTimeout:   getDurationEnv("IOT_PUBLISH_TIMEOUT", "5s"),
 			},
 		},
 		Services: ServicesConfig{
@@ -182,6 +346,17 @@ func Load() (*Config, error) {
 			CognitoUserPoolID: getEnv("COGNITO_USER_POOL_ID", ""),
 			CognitoClientID:   getEnv("COGNITO_CLIENT_ID", ""),
 			JWTSecret:         getEnv("JWT_SECRET", ""),
+			AdminAPIKey:       getEnv("ADMIN_API_KEY", ""),
+			DeviceCA: DeviceCAConfig{
+				CertFile:     getEnv("DEVICE_CA_CERT_FILE", ""),
+				KeyFile:      getEnv("DEVICE_CA_KEY_FILE", ""),
+				CertValidity: getDurationEnv("DEVICE_CERT_VALIDITY", "8760h"),
+
+				RotationOverlap:    getDurationEnv("DEVICE_CERT_ROTATION_OVERLAP", "72h"),
+				CRLValidity:        getDurationEnv("DEVICE_CRL_VALIDITY", "24h"),
+				ExpiryWarningDays:  getIntEnv("DEVICE_CERT_EXPIRY_WARNING_DAYS", 30),
+				ExpiryScanInterval: getDurationEnv("DEVICE_CERT_EXPIRY_SCAN_INTERVAL", "12h"),
+			},
 			RateLimit: RateLimitConfig{
 				Requests: getIntEnv("RATE_LIMIT_REQUESTS", 100),
 				Window:   getDurationEnv("RATE_LIMIT_WINDOW", "1m"),
@@ -209,6 +384,32 @@ func Load() (*Config, error) {
 			EnableMetrics: getBoolEnv("ENABLE_METRICS", true),
 			MetricsPort:   getEnv("METRICS_PORT", "9090"),
 		},
+		Telemetry: TelemetryConfig{
+			RawRetention:      getDurationEnv("TELEMETRY_RAW_RETENTION", "168h"),
+			HourlyRetention:   getDurationEnv("TELEMETRY_HOURLY_RETENTION", "2160h"),
+			DailyRetention:    getDurationEnv("TELEMETRY_DAILY_RETENTION", "17520h"),
+			RetentionInterval: getDurationEnv("TELEMETRY_RETENTION_INTERVAL", "1h"),
+		},
+		Heartbeat: HeartbeatConfig{
+			ScanInterval:       getDurationEnv("HEARTBEAT_SCAN_INTERVAL", "30s"),
+			OfflineAfter:       getDurationEnv("HEARTBEAT_OFFLINE_AFTER", "5m"),
+			OfflineAfterByType: getDurationMapEnv("HEARTBEAT_OFFLINE_AFTER_BY_TYPE"),
+		},
+		Alerts: AlertsConfig{
+			RulesFile: getEnv("ALERT_RULES_FILE", ""),
+		},
+		Forecast: ForecastConfig{
+			Window:     getDurationEnv("FORECAST_WINDOW", "168h"),
+			FillPerJob: getFloatEnv("FORECAST_FILL_PER_JOB", 0.5),
+		},
+		Provisioning: ProvisioningConfig{
+			ClaimTokenTTL: getDurationEnv("PROVISIONING_CLAIM_TOKEN_TTL", "720h"),
+		},
+	}
+
+	// Con suscripción compartida corren varias réplicas: cada una necesita su propio ID
+	if cfg.AWS.IoT.SharedGroup != "" {
+		cfg.AWS.IoT.ClientID = instanceClientID(cfg.AWS.IoT.ClientID)
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -218,12 +419,29 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// instanceClientID agrega al ID base un sufijo único por proceso (hostname y PID).
+func instanceClientID(base string) string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s-%d", base, hostname, os.Getpid())
+}
+
 // Validate validates the configuration.
 func (c *Config) Validate() error {
 	if c.Server.Port == "" {
 		return fmt.Errorf("PORT is required")
 	}
 
+	if c.Server.MTLS.Port != "" && c.Server.MTLS.Port == c.Server.Port {
+		return fmt.Errorf("MTLS_PORT must differ from PORT")
+	}
+
+	if (c.Server.MTLS.CertFile == "") != (c.Server.MTLS.KeyFile == "") {
+		return fmt.Errorf("MTLS_CERT_FILE and MTLS_KEY_FILE must be set together")
+	}
+
 	if c.AWS.Region == "" {
 		return fmt.Errorf("AWS_REGION is required")
 	}
@@ -244,6 +462,46 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("DECISION_SERVICE_URL is required")
 	}
 
+	if c.AWS.IoT.QoS > 2 {
+		return fmt.Errorf("IOT_QOS must be 0, 1 or 2")
+	}
+
+	if strings.ContainsAny(c.AWS.IoT.SharedGroup, "/+#") {
+		return fmt.Errorf("IOT_SHARED_GROUP cannot contain '/', '+' or '#'")
+	}
+
+	if (c.Security.DeviceCA.CertFile == "") != (c.Security.DeviceCA.KeyFile == "") {
+		return fmt.Errorf("DEVICE_CA_CERT_FILE and DEVICE_CA_KEY_FILE must be set together")
+	}
+
+	if c.IsProduction() && c.Security.DeviceCA.CertFile == "" {
+		return fmt.Errorf("DEVICE_CA_CERT_FILE and DEVICE_CA_KEY_FILE are required in production")
+	}
+
+	if c.IsProduction() && c.Security.AdminAPIKey == "" {
+		return fmt.Errorf("ADMIN_API_KEY is required in production")
+	}
+
+	if c.Security.DeviceCA.CertValidity <= 0 {
+		return fmt.Errorf("DEVICE_CERT_VALIDITY must be positive")
+	}
+
+	if c.Security.DeviceCA.RotationOverlap < 0 {
+		return fmt.Errorf("DEVICE_CERT_ROTATION_OVERLAP cannot be negative")
+	}
+
+	if c.Security.DeviceCA.CRLValidity <= 0 {
+		return fmt.Errorf("DEVICE_CRL_VALIDITY must be positive")
+	}
+
+	if c.Provisioning.ClaimTokenTTL <= 0 {
+		return fmt.Errorf("PROVISIONING_CLAIM_TOKEN_TTL must be positive")
+	}
+
+	if c.Security.DeviceCA.ExpiryWarningDays <= 0 {
+		return fmt.Errorf("DEVICE_CERT_EXPIRY_WARNING_DAYS must be positive")
+	}
+
 	return nil
 }
 
@@ -280,6 +538,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		return value == "true" || value == "1" || value == "yes"
@@ -302,6 +569,44 @@ func getDurationEnv(key, defaultValue string) time.Duration {
 	return duration
 }
 
+// getDurationMapEnv parses "key=duration" pairs separated by commas
+// (ej: "smart_bin_v1=5m,smart_bin_industrial=15m"). Invalid pairs are skipped.
+func getDurationMapEnv(key string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("Warning: %s has invalid duration for %s, ignoring", key, name)
+			continue
+		}
+		result[strings.TrimSpace(name)] = duration
+	}
+
+	return result
+}
+
+// getSliceEnv parses a comma-separated list, skipping empty items.
+func getSliceEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // Validar antes de convertir.
 func getEnvAsUint32(key string, defaultVal uint32) uint32 {
 	valStr := os.Getenv(key)
@@ -0,0 +1,293 @@
+package models
+
+import (
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  ALERT.GO - ALERTAS DE MANTENIMIENTO                           ║
+║                                                                ║
+║  Reglas configurables por tipo de dispositivo y alertas con    ║
+║  ciclo de vida open → acknowledged → resolved.                 ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// AlertState representa el estado de una alerta.
+type AlertState string
+
+const (
+	// AlertStateOpen - Alerta activa sin atender.
+	AlertStateOpen AlertState = "open"
+
+	// AlertStateAcknowledged - Alerta activa que un operador ya tomó.
+	AlertStateAcknowledged AlertState = "acknowledged"
+
+	// AlertStateResolved - La condición desapareció o un operador la cerró.
+	AlertStateResolved AlertState = "resolved"
+)
+
+// AlertSeverity representa la severidad de una alerta.
+type AlertSeverity string
+
+const (
+	// AlertSeverityWarning - Requiere atención pronto.
+	AlertSeverityWarning AlertSeverity = "warning"
+
+	// AlertSeverityCritical - Requiere atención inmediata.
+	AlertSeverityCritical AlertSeverity = "critical"
+)
+
+// AlertMetric es el valor del dispositivo que evalúa una regla.
+type AlertMetric string
+
+const (
+	// AlertMetricBatteryLevel - Device.BatteryLevel (%).
+	AlertMetricBatteryLevel AlertMetric = "battery_level"
+
+	// AlertMetricFillLevel - Device.FillLevel (%).
+	AlertMetricFillLevel AlertMetric = "fill_level"
+
+	// AlertMetricTotalErrors - Device.TotalErrors (acumulado: solo baja al resolver la alerta a mano).
+	AlertMetricTotalErrors AlertMetric = "total_errors"
+)
+
+// IsCumulative retorna true si la métrica es un contador que nunca cruza el umbral de
+// resolución por sí solo: resolver la alerta a mano reinicia el contador del dispositivo.
+func (m AlertMetric) IsCumulative() bool {
+	return m == AlertMetricTotalErrors
+}
+
+// AlertOperator indica en qué dirección se dispara una regla.
+type AlertOperator string
+
+const (
+	// AlertOperatorAbove - Se dispara cuando el valor supera el umbral.
+	AlertOperatorAbove AlertOperator = "above"
+
+	// AlertOperatorBelow - Se dispara cuando el valor baja del umbral.
+	AlertOperatorBelow AlertOperator = "below"
+)
+
+// AlertRule define cuándo se abre y cuándo se resuelve una alerta.
+//
+// La histéresis evita que una alerta oscile: se abre al cruzar Threshold
+// y solo se resuelve al cruzar ClearThreshold en sentido contrario.
+// Ej: fill_level above 90, clear 80 → un contenedor entre 80 y 90 no cambia de estado.
+type AlertRule struct {
+	// RuleID - ID único de la regla (ej: "fill_high")
+	RuleID string `json:"rule_id"`
+
+	// DeviceType - Tipo de dispositivo al que aplica ("" aplica a todos)
+	DeviceType string `json:"device_type,omitempty"`
+
+	// GroupID - Grupo al que aplica; reemplaza a las reglas por tipo y genéricas de la misma métrica
+	GroupID string `json:"group_id,omitempty"`
+
+	// Metric - Valor evaluado
+	Metric AlertMetric `json:"metric"`
+
+	// Operator - Dirección del umbral (above, below)
+	Operator AlertOperator `json:"operator"`
+
+	// Threshold - Umbral que abre la alerta
+	Threshold float64 `json:"threshold"`
+
+	// ClearThreshold - Umbral que resuelve la alerta
+	ClearThreshold float64 `json:"clear_threshold"`
+
+	// Severity - Severidad de las alertas de esta regla
+	Severity AlertSeverity `json:"severity"`
+
+	// Message - Descripción legible de la condición
+	Message string `json:"message"`
+}
+
+// DefaultAlertRules retorna las reglas equivalentes a Device.NeedsMaintenance.
+func DefaultAlertRules() []AlertRule {
+	return []AlertRule{
+		{
+			RuleID:         "battery_low",
+			Metric:         AlertMetricBatteryLevel,
+			Operator:       AlertOperatorBelow,
+			Threshold:      20,
+			ClearThreshold: 25,
+			Severity:       AlertSeverityWarning,
+			Message:        "Battery level below 20%",
+		},
+		{
+			RuleID:         "fill_high",
+			Metric:         AlertMetricFillLevel,
+			Operator:       AlertOperatorAbove,
+			Threshold:      90,
+			ClearThreshold: 80,
+			Severity:       AlertSeverityCritical,
+			Message:        "Bin is almost full",
+		},
+		{
+			RuleID:         "errors_high",
+			Metric:         AlertMetricTotalErrors,
+			Operator:       AlertOperatorAbove,
+			Threshold:      10,
+			ClearThreshold: 10,
+			Severity:       AlertSeverityWarning,
+			Message:        "Device reported more than 10 errors",
+		},
+	}
+}
+
+// Validate valida que la regla sea consistente.
+func (r *AlertRule) Validate() error {
+	if r.RuleID == "" {
+		return ErrInvalidAlertRule
+	}
+
+	switch r.Metric {
+	case AlertMetricBatteryLevel, AlertMetricFillLevel, AlertMetricTotalErrors:
+	default:
+		return ErrInvalidAlertRule
+	}
+
+	// La zona de histéresis debe quedar del lado "sano" del umbral
+	switch r.Operator {
+	case AlertOperatorAbove:
+		if r.ClearThreshold > r.Threshold {
+			return ErrInvalidAlertRule
+		}
+	case AlertOperatorBelow:
+		if r.ClearThreshold < r.Threshold {
+			return ErrInvalidAlertRule
+		}
+	default:
+		return ErrInvalidAlertRule
+	}
+
+	return nil
+}
+
+// IsTriggered retorna true si el valor abre la alerta.
+func (r *AlertRule) IsTriggered(value float64) bool {
+	if r.Operator == AlertOperatorBelow {
+		return value < r.Threshold
+	}
+	return value > r.Threshold
+}
+
+// IsCleared retorna true si el valor resuelve la alerta.
+func (r *AlertRule) IsCleared(value float64) bool {
+	if r.Operator == AlertOperatorBelow {
+		return value >= r.ClearThreshold
+	}
+	return value <= r.ClearThreshold
+}
+
+// MetricValue retorna el valor de la métrica en el dispositivo.
+// Retorna false si el dispositivo no reportó ese valor.
+func (d *Device) MetricValue(metric AlertMetric) (float64, bool) {
+	switch metric {
+	case AlertMetricBatteryLevel:
+		if d.BatteryLevel == nil {
+			return 0, false
+		}
+		return float64(*d.BatteryLevel), true
+	case AlertMetricFillLevel:
+		if d.FillLevel == nil {
+			return 0, false
+		}
+		return float64(*d.FillLevel), true
+	case AlertMetricTotalErrors:
+		return float64(d.TotalErrors), true
+	default:
+		return 0, false
+	}
+}
+
+// Alert representa una alerta de mantenimiento de un dispositivo.
+type Alert struct {
+	AlertID    string        `json:"alert_id" dynamodbav:"alert_id"`
+	OrgID      string        `json:"org_id,omitempty" dynamodbav:"org_id"`
+	DeviceID   string        `json:"device_id" dynamodbav:"device_id"`
+	DeviceType string        `json:"device_type" dynamodbav:"device_type"`
+	RuleID     string        `json:"rule_id" dynamodbav:"rule_id"`
+	Metric     AlertMetric   `json:"metric" dynamodbav:"metric"`
+	Severity   AlertSeverity `json:"severity" dynamodbav:"severity"`
+	State      AlertState    `json:"state" dynamodbav:"state"`
+	Message    string        `json:"message" dynamodbav:"message"`
+
+	// Value - Último valor que disparó la alerta
+	Value float64 `json:"value" dynamodbav:"value"`
+
+	// Threshold - Umbral de la regla al abrirse
+	Threshold float64 `json:"threshold" dynamodbav:"threshold"`
+
+	// Occurrences - Veces que la condición se repitió mientras la alerta estaba activa
+	Occurrences int `json:"occurrences" dynamodbav:"occurrences"`
+
+	OpenedAt        time.Time  `json:"opened_at" dynamodbav:"opened_at"`
+	LastTriggeredAt time.Time  `json:"last_triggered_at" dynamodbav:"last_triggered_at"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty" dynamodbav:"acknowledged_at,omitempty"`
+	AcknowledgedBy  string     `json:"acknowledged_by,omitempty" dynamodbav:"acknowledged_by,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty" dynamodbav:"resolved_at,omitempty"`
+	ResolvedBy      string     `json:"resolved_by,omitempty" dynamodbav:"resolved_by,omitempty"`
+	Note            string     `json:"note,omitempty" dynamodbav:"note,omitempty"`
+}
+
+// IsActive retorna true si la alerta no está resuelta.
+func (a *Alert) IsActive() bool {
+	return a.State == AlertStateOpen || a.State == AlertStateAcknowledged
+}
+
+// Acknowledge marca la alerta como atendida por actor.
+func (a *Alert) Acknowledge(actor, note string) error {
+	if a.State != AlertStateOpen {
+		return ErrInvalidTransition
+	}
+	now := time.Now()
+	a.State = AlertStateAcknowledged
+	a.AcknowledgedAt = &now
+	a.AcknowledgedBy = actor
+	if note != "" {
+		a.Note = note
+	}
+	return nil
+}
+
+// Resolve cierra la alerta. actor vacío indica resolución automática.
+func (a *Alert) Resolve(actor, note string) error {
+	if !a.IsActive() {
+		return ErrInvalidTransition
+	}
+	now := time.Now()
+	a.State = AlertStateResolved
+	a.ResolvedAt = &now
+	a.ResolvedBy = actor
+	if note != "" {
+		a.Note = note
+	}
+	return nil
+}
+
+// ListAlertsRequest - Filtros para listar alertas.
+type ListAlertsRequest struct {
+	DeviceID string      `form:"device_id"`
+	State    AlertState  `form:"state"`
+	Metric   AlertMetric `form:"metric"`
+	Limit    int         `form:"limit,default=10"`
+	Offset   int         `form:"offset,default=0"`
+}
+
+// ListAlertsResponse - Página de alertas.
+type ListAlertsResponse struct {
+	Alerts []*Alert `json:"alerts"`
+	Total  int      `json:"total"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+}
+
+// AlertActionRequest - Request para reconocer o resolver una alerta.
+// El actor sale del principal de la petición (ver ActorFromContext), nunca del body.
+type AlertActionRequest struct {
+	Note string `json:"note,omitempty"`
+}
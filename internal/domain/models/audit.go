@@ -0,0 +1,330 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  AUDIT.GO - REGISTRO DE AUDITORÍA                              ║
+║                                                                ║
+║  Cada llamada que modifica datos deja una entrada: quién,      ║
+║  qué acción, sobre qué recurso, los campos que cambiaron,      ║
+║  el request ID y la IP.                                        ║
+║                                                                ║
+║  El registro es append-only y encadenado: cada entrada guarda  ║
+║  el hash de la anterior y el suyo propio, así que editar o     ║
+║  borrar una entrada rompe la cadena (ver VerifyAuditChain).    ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// AuditGenesisHash - PrevHash de la primera entrada de la cadena.
+const AuditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditRedacted - Valor que reemplaza a los secretos en los snapshots.
+const AuditRedacted = "[REDACTED]"
+
+// auditSecretFields - Campos que nunca se guardan en claro (keys, llaves privadas, claim tokens).
+var auditSecretFields = map[string]bool{
+	"api_key":     true,
+	"private_key": true,
+	"claim_token": true,
+	"key_hash":    true,
+	"password":    true,
+	"secret":      true,
+}
+
+// AuditEntry es una entrada del registro de auditoría.
+type AuditEntry struct {
+	EntryID string `json:"entry_id" dynamodbav:"entry_id"`
+
+	// Sequence - Posición en la cadena (empieza en 1)
+	Sequence int64 `json:"sequence" dynamodbav:"sequence"`
+
+	// OrgID - Organización del recurso; vacío si la acción no pertenece a ninguna
+	OrgID string `json:"org_id,omitempty" dynamodbav:"org_id,omitempty"`
+
+	// Actor - Credencial, dispositivo, super-admin o anonymous (ver Principal.Actor)
+	Actor string `json:"actor" dynamodbav:"actor"`
+
+	// Action - Método y ruta (ej: "PUT /api/v1/devices/:device_id/status")
+	Action string `json:"action" dynamodbav:"action"`
+
+	ResourceType string `json:"resource_type" dynamodbav:"resource_type"`
+	ResourceID   string `json:"resource_id,omitempty" dynamodbav:"resource_id,omitempty"`
+
+	// Changes - Campos que cambiaron (diff entre el antes y el después)
+	Changes []AuditChange `json:"changes" dynamodbav:"changes"`
+
+	RequestID  string    `json:"request_id" dynamodbav:"request_id"`
+	IP         string    `json:"ip" dynamodbav:"ip"`
+	StatusCode int       `json:"status_code" dynamodbav:"status_code"`
+	CreatedAt  time.Time `json:"created_at" dynamodbav:"created_at"`
+
+	// PrevHash - Hash de la entrada anterior (AuditGenesisHash para la primera)
+	PrevHash string `json:"prev_hash" dynamodbav:"prev_hash"`
+
+	// Hash - SHA-256 (hex) de la entrada con PrevHash y sin Hash
+	Hash string `json:"hash" dynamodbav:"hash"`
+}
+
+// AuditChange es un campo que cambió. Before es nil en las altas y After en las bajas.
+type AuditChange struct {
+	// Field - Ruta del campo con puntos (ej: "location.building")
+	Field  string      `json:"field" dynamodbav:"field"`
+	Before interface{} `json:"before,omitempty" dynamodbav:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" dynamodbav:"after,omitempty"`
+}
+
+// ComputeHash calcula el hash de la entrada (incluye PrevHash, excluye Hash).
+func (e *AuditEntry) ComputeHash() (string, error) {
+	unsigned := *e
+	unsigned.Hash = ""
+
+	// encoding/json ordena las claves de los mapas: la serialización es estable
+	content, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", fmt.Errorf("encoding audit entry: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Seal encadena la entrada a la anterior (nil para la primera) y calcula su hash.
+func (e *AuditEntry) Seal(previous *AuditEntry) error {
+	e.Sequence = 1
+	e.PrevHash = AuditGenesisHash
+	if previous != nil {
+		e.Sequence = previous.Sequence + 1
+		e.PrevHash = previous.Hash
+	}
+
+	hash, err := e.ComputeHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+	return nil
+}
+
+// AuditSnapshot convierte un recurso a su representación JSON genérica, sin secretos.
+// Un valor que no es un objeto se guarda en la clave "value".
+func AuditSnapshot(resource interface{}) (map[string]interface{}, error) {
+	if resource == nil {
+		return nil, nil
+	}
+	if value := reflect.ValueOf(resource); value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil, nil
+	}
+
+	content, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit snapshot: %w", err)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		return nil, fmt.Errorf("decoding audit snapshot: %w", err)
+	}
+
+	snapshot, ok := redactAuditValue(decoded).(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{"value": decoded}, nil
+	}
+	return snapshot, nil
+}
+
+// redactAuditValue reemplaza los secretos en cualquier nivel del valor.
+func redactAuditValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			if auditSecretFields[key] {
+				typed[key] = AuditRedacted
+				continue
+			}
+			typed[key] = redactAuditValue(field)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redactAuditValue(item)
+		}
+	}
+	return value
+}
+
+// DiffAuditSnapshots retorna los campos (aplanados con puntos) que difieren entre dos snapshots,
+// ordenados por nombre. Las listas se comparan como un solo valor.
+func DiffAuditSnapshots(before, after map[string]interface{}) []AuditChange {
+	flatBefore := make(map[string]interface{})
+	flatAfter := make(map[string]interface{})
+	flattenAuditSnapshot("", before, flatBefore)
+	flattenAuditSnapshot("", after, flatAfter)
+
+	fields := make(map[string]bool, len(flatBefore)+len(flatAfter))
+	for field := range flatBefore {
+		fields[field] = true
+	}
+	for field := range flatAfter {
+		fields[field] = true
+	}
+
+	changes := make([]AuditChange, 0)
+	for field := range fields {
+		oldValue, newValue := flatBefore[field], flatAfter[field]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, AuditChange{Field: field, Before: oldValue, After: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// flattenAuditSnapshot aplana los objetos anidados en claves "a.b.c".
+func flattenAuditSnapshot(prefix string, snapshot map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range snapshot {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenAuditSnapshot(field, nested, flat)
+			continue
+		}
+		flat[field] = value
+	}
+}
+
+// VerifyAuditChain recorre las entradas (en orden de Sequence) y retorna la verificación de la cadena.
+func VerifyAuditChain(entries []*AuditEntry) *AuditVerification {
+	verification := &AuditVerification{Valid: true, Entries: len(entries)}
+
+	var previous *AuditEntry
+	for _, entry := range entries {
+		expectedSequence, expectedPrev := int64(1), AuditGenesisHash
+		if previous != nil {
+			expectedSequence, expectedPrev = previous.Sequence+1, previous.Hash
+		}
+
+		hash, err := entry.ComputeHash()
+		switch {
+		case entry.Sequence != expectedSequence:
+			verification.fail(entry, "sequence gap, expected "+strconv.FormatInt(expectedSequence, 10))
+		case entry.PrevHash != expectedPrev:
+			verification.fail(entry, "prev_hash does not match the previous entry")
+		case err != nil || hash != entry.Hash:
+			verification.fail(entry, "hash does not match the entry content")
+		}
+		if !verification.Valid {
+			return verification
+		}
+		previous = entry
+	}
+
+	if previous != nil {
+		verification.LastHash = previous.Hash
+	}
+	return verification
+}
+
+// AuditVerification - Resultado de verificar la cadena.
+type AuditVerification struct {
+	Valid   bool `json:"valid"`
+	Entries int  `json:"entries"`
+
+	// LastHash - Hash de la última entrada (para anclarlo fuera del servicio)
+	LastHash string `json:"last_hash,omitempty"`
+
+	// BrokenAt - Primera entrada inválida
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// fail marca la cadena como rota en la entrada.
+func (v *AuditVerification) fail(entry *AuditEntry, reason string) {
+	sequence := entry.Sequence
+	v.Valid = false
+	v.BrokenAt = &sequence
+	v.Reason = reason
+}
+
+// AuditExportFormat - Formato de GET /audit/export.
+type AuditExportFormat string
+
+const (
+	// AuditExportJSONL - Una entrada JSON por línea.
+	AuditExportJSONL AuditExportFormat = "jsonl"
+
+	// AuditExportCSV - CSV con los cambios serializados en JSON.
+	AuditExportCSV AuditExportFormat = "csv"
+)
+
+// ListAuditRequest - Filtros de GET /audit y GET /audit/export.
+type ListAuditRequest struct {
+	Actor        string    `form:"actor"`
+	Action       string    `form:"action"`
+	ResourceType string    `form:"resource_type"`
+	ResourceID   string    `form:"resource_id"`
+	RequestID    string    `form:"request_id"`
+	From         time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To           time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Format - Solo export: jsonl (por defecto) o csv
+	Format AuditExportFormat `form:"format"`
+
+	Limit  int `form:"limit,default=10"`
+	Offset int `form:"offset,default=0"`
+}
+
+// Validate valida el rango de fechas y el formato.
+func (r *ListAuditRequest) Validate() error {
+	if !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From) {
+		return fmt.Errorf("%w: to must be after from", ErrInvalidInput)
+	}
+	switch r.Format {
+	case "", AuditExportJSONL, AuditExportCSV:
+		return nil
+	default:
+		return fmt.Errorf("%w: format must be jsonl or csv", ErrInvalidInput)
+	}
+}
+
+// Matches retorna true si la entrada cumple los filtros.
+func (r *ListAuditRequest) Matches(entry *AuditEntry) bool {
+	switch {
+	case r.Actor != "" && entry.Actor != r.Actor:
+		return false
+	case r.Action != "" && entry.Action != r.Action:
+		return false
+	case r.ResourceType != "" && entry.ResourceType != r.ResourceType:
+		return false
+	case r.ResourceID != "" && entry.ResourceID != r.ResourceID:
+		return false
+	case r.RequestID != "" && entry.RequestID != r.RequestID:
+		return false
+	case !r.From.IsZero() && entry.CreatedAt.Before(r.From):
+		return false
+	case !r.To.IsZero() && entry.CreatedAt.After(r.To):
+		return false
+	}
+	return true
+}
+
+// ListAuditResponse - Página de entradas, las más recientes primero.
+type ListAuditResponse struct {
+	Entries []*AuditEntry `json:"entries"`
+	Total   int           `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  CERTIFICATE.GO - CERTIFICADOS X.509 DE DISPOSITIVOS           ║
+║                                                                ║
+║  La CA interna firma un certificado por dispositivo con el     ║
+║  device_id como CN y SAN. El dispositivo lo usa como           ║
+║  certificado de cliente (mTLS).                                ║
+║                                                                ║
+║  Ciclo de vida:                                                ║
+║  active → superseded (rotación, válido durante el solape)      ║
+║         → revoked (manual o al terminar el solape)             ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// CertificateStatus representa el estado de un certificado emitido.
+type CertificateStatus string
+
+const (
+	// CertificateStatusActive - Certificado vigente del dispositivo.
+	CertificateStatusActive CertificateStatus = "active"
+
+	// CertificateStatusSuperseded - Reemplazado por una rotación; se acepta hasta AcceptedUntil.
+	CertificateStatusSuperseded CertificateStatus = "superseded"
+
+	// CertificateStatusRevoked - Revocado; aparece en la CRL.
+	CertificateStatusRevoked CertificateStatus = "revoked"
+)
+
+// RevocationReason es el motivo de revocación (se publica en la CRL).
+type RevocationReason string
+
+const (
+	// RevocationUnspecified - Sin motivo específico.
+	RevocationUnspecified RevocationReason = "unspecified"
+
+	// RevocationKeyCompromise - La llave privada se filtró.
+	RevocationKeyCompromise RevocationReason = "key_compromise"
+
+	// RevocationSuperseded - Reemplazado por otro certificado.
+	RevocationSuperseded RevocationReason = "superseded"
+
+	// RevocationCessationOfOperation - El dispositivo dejó de operar.
+	RevocationCessationOfOperation RevocationReason = "cessation_of_operation"
+)
+
+// IsValid retorna true si el motivo es conocido.
+func (r RevocationReason) IsValid() bool {
+	switch r {
+	case RevocationUnspecified, RevocationKeyCompromise, RevocationSuperseded, RevocationCessationOfOperation:
+		return true
+	}
+	return false
+}
+
+// DeviceCertificate es un certificado emitido por la CA de dispositivos.
+type DeviceCertificate struct {
+	DeviceID string `json:"device_id"`
+
+	// CertificatePEM - Certificado firmado
+	CertificatePEM string `json:"certificate"`
+
+	// PrivateKeyPEM - Llave privada PKCS#8, solo si la generó el orchestrator (sin CSR).
+	// Se entrega una vez y nunca se persiste.
+	PrivateKeyPEM string `json:"private_key,omitempty"`
+
+	// SerialNumber - Serial del certificado en hex (minúsculas)
+	SerialNumber string `json:"serial_number"`
+
+	NotBefore time.Time `json:"not_before"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	Status CertificateStatus `json:"status"`
+
+	// SupersededAt/AcceptedUntil - Rotación y fin del periodo de solapamiento
+	SupersededAt  *time.Time `json:"superseded_at,omitempty"`
+	AcceptedUntil *time.Time `json:"accepted_until,omitempty"`
+
+	RevokedAt        *time.Time       `json:"revoked_at,omitempty"`
+	RevocationReason RevocationReason `json:"revocation_reason,omitempty"`
+}
+
+// ApplyTo guarda en el dispositivo el certificado vigente (sin la llave privada).
+func (c *DeviceCertificate) ApplyTo(device *Device) {
+	expiresAt := c.ExpiresAt
+	device.Certificate = c.CertificatePEM
+	device.CertificateSerial = c.SerialNumber
+	device.CertificateExpiresAt = &expiresAt
+}
+
+// Supersede marca el certificado como reemplazado; se sigue aceptando durante overlap
+// (sin pasar de su expiración) para que el dispositivo instale el nuevo.
+func (c *DeviceCertificate) Supersede(now time.Time, overlap time.Duration) {
+	acceptedUntil := now.Add(overlap)
+	if acceptedUntil.After(c.ExpiresAt) {
+		acceptedUntil = c.ExpiresAt
+	}
+
+	c.Status = CertificateStatusSuperseded
+	c.SupersededAt = &now
+	c.AcceptedUntil = &acceptedUntil
+}
+
+// Revoke revoca el certificado. Retorna ErrCertificateRevoked si ya estaba revocado.
+func (c *DeviceCertificate) Revoke(reason RevocationReason, now time.Time) error {
+	if c.Status == CertificateStatusRevoked {
+		return ErrCertificateRevoked
+	}
+
+	c.Status = CertificateStatusRevoked
+	c.RevokedAt = &now
+	c.RevocationReason = reason
+	return nil
+}
+
+// OverlapEnded retorna true si el certificado fue reemplazado y su solapamiento terminó.
+func (c *DeviceCertificate) OverlapEnded(now time.Time) bool {
+	return c.Status == CertificateStatusSuperseded && c.AcceptedUntil != nil && !now.Before(*c.AcceptedUntil)
+}
+
+// CheckAccepted retorna nil si el certificado sirve para autenticarse en now.
+func (c *DeviceCertificate) CheckAccepted(now time.Time) error {
+	switch {
+	case c.Status == CertificateStatusRevoked:
+		return ErrCertificateRevoked
+	case c.OverlapEnded(now):
+		return ErrCertificateSuperseded
+	case !now.Before(c.ExpiresAt):
+		return fmt.Errorf("%w: certificate expired at %s", ErrUnauthorized, c.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     REQUESTS Y RESPONSES
+// ═══════════════════════════════════════════════════════════════════
+
+// MaxRotationOverlapHours - Solapamiento máximo permitido al rotar (30 días).
+const MaxRotationOverlapHours = 720
+
+// RotateCertificateRequest - Body para POST /devices/:device_id/certificates/rotate.
+type RotateCertificateRequest struct {
+	// CSR - Opcional; sin CSR se genera el par de llaves
+	CSR string `json:"csr,omitempty"`
+
+	// OverlapHours - Horas que se sigue aceptando el certificado anterior (default: configuración)
+	OverlapHours *int `json:"overlap_hours,omitempty"`
+}
+
+// Validate valida el solapamiento.
+func (r *RotateCertificateRequest) Validate() error {
+	if r.OverlapHours != nil && (*r.OverlapHours < 0 || *r.OverlapHours > MaxRotationOverlapHours) {
+		return fmt.Errorf("%w: overlap_hours must be between 0 and %d", ErrInvalidInput, MaxRotationOverlapHours)
+	}
+	return nil
+}
+
+// RotateCertificateResponse - Certificado nuevo y seriales reemplazados.
+type RotateCertificateResponse struct {
+	*DeviceCertificate
+
+	CACertificate     string   `json:"ca_certificate"`
+	SupersededSerials []string `json:"superseded_serials"`
+}
+
+// RevokeCertificateRequest - Body para POST /certificates/:serial/revoke.
+type RevokeCertificateRequest struct {
+	// Reason - Default: unspecified
+	Reason RevocationReason `json:"reason,omitempty"`
+}
+
+// Validate valida el motivo y aplica el default.
+func (r *RevokeCertificateRequest) Validate() error {
+	if r.Reason == "" {
+		r.Reason = RevocationUnspecified
+	}
+	if !r.Reason.IsValid() {
+		return fmt.Errorf("%w: unknown revocation reason %q", ErrInvalidInput, r.Reason)
+	}
+	return nil
+}
+
+// ListCertificatesRequest - Filtros para buscar certificados en el repositorio.
+type ListCertificatesRequest struct {
+	DeviceID string
+	Status   CertificateStatus
+
+	// ExpiresBefore - Solo certificados que expiran antes de este momento
+	ExpiresBefore *time.Time
+}
+
+// ExpiringCertificatesRequest - Query params para GET /certificates/expiring.
+type ExpiringCertificatesRequest struct {
+	// Days - Ventana en días (default: configuración)
+	Days int `form:"days"`
+}
+
+// ExpiringCertificatesResponse - Certificados activos que expiran dentro de la ventana.
+type ExpiringCertificatesResponse struct {
+	Certificates []*DeviceCertificate `json:"certificates"`
+	Total        int                  `json:"total"`
+	Days         int                  `json:"days"`
+}
@@ -0,0 +1,328 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  COMMAND.GO - COMANDOS REMOTOS                                 ║
+║                                                                ║
+║  Órdenes que un operador envía a un dispositivo (reiniciar,    ║
+║  abrir compartimento, ...) con ciclo de vida                   ║
+║  pending → delivered → succeeded/failed (o expired).           ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// CommandType representa el tipo de comando.
+type CommandType string
+
+const (
+	// CommandReboot - Reiniciar el dispositivo. Params: delay_s (opcional, 0-3600).
+	CommandReboot CommandType = "reboot"
+
+	// CommandOpenCompartment - Abrir un compartimento. Params: compartment (requerido), duration_s (opcional, 1-300).
+	CommandOpenCompartment CommandType = "open_compartment"
+
+	// CommandRecalibrateCamera - Recalibrar la cámara.
+	CommandRecalibrateCamera CommandType = "recalibrate_camera"
+
+	// CommandTestCapture - Tomar una captura de prueba (genera un image_captured normal).
+	CommandTestCapture CommandType = "test_capture"
+)
+
+// CommandStatus representa el estado de un comando.
+type CommandStatus string
+
+const (
+	// CommandStatusPending - Creado, el dispositivo aún no confirmó la recepción.
+	CommandStatusPending CommandStatus = "pending"
+
+	// CommandStatusDelivered - El dispositivo confirmó la recepción.
+	CommandStatusDelivered CommandStatus = "delivered"
+
+	// CommandStatusSucceeded - El dispositivo ejecutó el comando.
+	CommandStatusSucceeded CommandStatus = "succeeded"
+
+	// CommandStatusFailed - El dispositivo no pudo ejecutar el comando.
+	CommandStatusFailed CommandStatus = "failed"
+
+	// CommandStatusExpired - Venció el TTL sin respuesta final del dispositivo.
+	CommandStatusExpired CommandStatus = "expired"
+
+	// CommandStatusCanceled - Cancelado por el orchestrator (ej: el dispositivo se dio de baja).
+	CommandStatusCanceled CommandStatus = "canceled"
+)
+
+const (
+	// DefaultCommandTTL - TTL cuando el request no indica uno.
+	DefaultCommandTTL = 5 * time.Minute
+
+	// MaxCommandTTL - TTL máximo permitido.
+	MaxCommandTTL = 24 * time.Hour
+)
+
+// commandParam define un parámetro numérico o de texto de un comando.
+type commandParam struct {
+	required bool
+	min, max float64 // rango para parámetros numéricos
+	text     bool    // el valor es un string
+}
+
+// commandParams define los parámetros aceptados por cada tipo de comando.
+var commandParams = map[CommandType]map[string]commandParam{
+	CommandReboot: {
+		"delay_s": {min: 0, max: 3600},
+	},
+	CommandOpenCompartment: {
+		"compartment": {required: true, text: true},
+		"duration_s":  {min: 1, max: 300},
+	},
+	CommandRecalibrateCamera: {},
+	CommandTestCapture:       {},
+}
+
+// supportedCommands define los comandos que soporta cada tipo de dispositivo.
+// El v1 no tiene tapa motorizada, así que no puede abrir compartimentos.
+var supportedCommands = map[DeviceType][]CommandType{
+	DeviceTypeSmartBinV1:         {CommandReboot, CommandRecalibrateCamera, CommandTestCapture},
+	DeviceTypeSmartBinV2:         {CommandReboot, CommandOpenCompartment, CommandRecalibrateCamera, CommandTestCapture},
+	DeviceTypeSmartBinIndustrial: {CommandReboot, CommandOpenCompartment, CommandRecalibrateCamera, CommandTestCapture},
+}
+
+// SupportsCommand retorna true si el tipo de dispositivo acepta el comando.
+func (d *Device) SupportsCommand(commandType CommandType) bool {
+	for _, supported := range supportedCommands[DeviceType(d.DeviceType)] {
+		if supported == commandType {
+			return true
+		}
+	}
+	return false
+}
+
+// Command representa un comando enviado a un dispositivo.
+type Command struct {
+	CommandID string                 `json:"command_id" dynamodbav:"command_id"`
+	DeviceID  string                 `json:"device_id" dynamodbav:"device_id"`
+	Type      CommandType            `json:"type" dynamodbav:"type"`
+	Params    map[string]interface{} `json:"params,omitempty" dynamodbav:"params,omitempty"`
+	Status    CommandStatus          `json:"status" dynamodbav:"status"`
+
+	// Result - Datos que devolvió el dispositivo al completar el comando
+	Result map[string]interface{} `json:"result,omitempty" dynamodbav:"result,omitempty"`
+
+	// Error - Motivo del fallo reportado por el dispositivo (o de la expiración)
+	Error string `json:"error,omitempty" dynamodbav:"error,omitempty"`
+
+	// RequestedBy - Credencial que creó el comando (ver ActorFromContext)
+	RequestedBy string `json:"requested_by,omitempty" dynamodbav:"requested_by,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at" dynamodbav:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at" dynamodbav:"expires_at"`
+	SentAt      *time.Time `json:"sent_at,omitempty" dynamodbav:"sent_at,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty" dynamodbav:"delivered_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" dynamodbav:"completed_at,omitempty"`
+}
+
+// IsFinal retorna true si el comando ya no puede cambiar de estado.
+func (c *Command) IsFinal() bool {
+	switch c.Status {
+	case CommandStatusSucceeded, CommandStatusFailed, CommandStatusExpired, CommandStatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsExpired retorna true si el comando sigue abierto y venció su TTL.
+func (c *Command) IsExpired(now time.Time) bool {
+	return !c.IsFinal() && !now.Before(c.ExpiresAt)
+}
+
+// CanTransitionTo valida la transición de estado.
+// Un ack final puede llegar sin el de recepción, así que pending → succeeded/failed es válido.
+func (c *Command) CanTransitionTo(status CommandStatus) bool {
+	switch c.Status {
+	case CommandStatusPending:
+		return status == CommandStatusDelivered ||
+			status == CommandStatusSucceeded ||
+			status == CommandStatusFailed ||
+			status == CommandStatusExpired ||
+			status == CommandStatusCanceled
+	case CommandStatusDelivered:
+		return status == CommandStatusSucceeded ||
+			status == CommandStatusFailed ||
+			status == CommandStatusExpired ||
+			status == CommandStatusCanceled
+	default:
+		return false
+	}
+}
+
+// Transition aplica un ack o la expiración del comando.
+func (c *Command) Transition(status CommandStatus, result map[string]interface{}, reason string, at time.Time) error {
+	if !c.CanTransitionTo(status) {
+		return ErrInvalidTransition
+	}
+
+	c.Status = status
+	switch status {
+	case CommandStatusDelivered:
+		c.DeliveredAt = &at
+	default:
+		c.CompletedAt = &at
+		c.Result = result
+		c.Error = reason
+	}
+	return nil
+}
+
+// CreateCommandRequest - Request para enviar un comando a un dispositivo.
+type CreateCommandRequest struct {
+	Type   CommandType            `json:"type" binding:"required"`
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// TTLSeconds - Segundos para que el dispositivo responda (default: 300, máximo: 86400)
+	TTLSeconds int `json:"ttl_s,omitempty"`
+}
+
+// TTL retorna el TTL del request o DefaultCommandTTL.
+func (r *CreateCommandRequest) TTL() time.Duration {
+	if r.TTLSeconds == 0 {
+		return DefaultCommandTTL
+	}
+	return time.Duration(r.TTLSeconds) * time.Second
+}
+
+// Validate valida el comando contra el tipo y configuración del dispositivo.
+func (r *CreateCommandRequest) Validate(device *Device) error {
+	params, known := commandParams[r.Type]
+	if !known {
+		return fmt.Errorf("%w: unknown command %q", ErrInvalidCommand, r.Type)
+	}
+	if !device.SupportsCommand(r.Type) {
+		return fmt.Errorf("%w: %s on %s", ErrCommandNotSupported, r.Type, device.DeviceType)
+	}
+
+	// Se compara en segundos: un ttl_s enorme desborda la conversión a time.Duration
+	if r.TTLSeconds < 0 || r.TTLSeconds > int(MaxCommandTTL/time.Second) {
+		return fmt.Errorf("%w: ttl_s must be between 1 and %d", ErrInvalidCommand, int(MaxCommandTTL.Seconds()))
+	}
+
+	for name := range r.Params {
+		if _, ok := params[name]; !ok {
+			return fmt.Errorf("%w: unknown param %q for %s", ErrInvalidCommand, name, r.Type)
+		}
+	}
+
+	for name, spec := range params {
+		value, present := r.Params[name]
+		if !present || value == nil {
+			if spec.required {
+				return fmt.Errorf("%w: param %q is required for %s", ErrInvalidCommand, name, r.Type)
+			}
+			continue
+		}
+		if err := spec.validate(name, value); err != nil {
+			return err
+		}
+	}
+
+	if r.Type == CommandOpenCompartment {
+		compartment, _ := r.Params["compartment"].(string)
+		if !device.AcceptsCompartment(compartment) {
+			return fmt.Errorf("%w: device has no %q compartment", ErrInvalidBinCompartment, compartment)
+		}
+	}
+
+	return nil
+}
+
+// validate valida el tipo y rango de un parámetro.
+func (p commandParam) validate(name string, value interface{}) error {
+	if p.text {
+		if text, ok := value.(string); !ok || text == "" {
+			return fmt.Errorf("%w: param %q must be a non-empty string", ErrInvalidCommand, name)
+		}
+		return nil
+	}
+
+	// encoding/json decodifica los números como float64
+	number, ok := value.(float64)
+	if !ok || number != math.Trunc(number) || number < p.min || number > p.max {
+		return fmt.Errorf("%w: param %q must be an integer between %g and %g", ErrInvalidCommand, name, p.min, p.max)
+	}
+	return nil
+}
+
+// ListCommandsRequest - Filtros para listar los comandos de un dispositivo.
+type ListCommandsRequest struct {
+	DeviceID string        `form:"-"`
+	Status   CommandStatus `form:"status"`
+	Limit    int           `form:"limit,default=10"`
+	Offset   int           `form:"offset,default=0"`
+}
+
+// ListCommandsResponse - Página de comandos.
+type ListCommandsResponse struct {
+	Commands []*Command `json:"commands"`
+	Total    int        `json:"total"`
+	Limit    int        `json:"limit"`
+	Offset   int        `json:"offset"`
+}
+
+// CommandMessage es el mensaje que recibe el dispositivo.
+// Se publica en bins/{device_id}/commands; el dispositivo responde con eventos command_ack.
+type CommandMessage struct {
+	CommandID string                 `json:"command_id"`
+	DeviceID  string                 `json:"device_id"`
+	Type      CommandType            `json:"type"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+// NewCommandMessage construye el mensaje del comando para el dispositivo.
+func NewCommandMessage(command *Command) *CommandMessage {
+	return &CommandMessage{
+		CommandID: command.CommandID,
+		DeviceID:  command.DeviceID,
+		Type:      command.Type,
+		Params:    command.Params,
+		ExpiresAt: command.ExpiresAt,
+	}
+}
+
+// CommandAck es la respuesta del dispositivo a un comando (Data de un evento command_ack).
+//
+//	{"command_id": "cmd_...", "status": "delivered|succeeded|failed", "result": {...}, "error": "..."}
+type CommandAck struct {
+	CommandID string
+	Status    CommandStatus
+	Result    map[string]interface{}
+	Error     string
+}
+
+// CommandAckFromEvent extrae el ack de un evento command_ack.
+func CommandAckFromEvent(event *DeviceEvent) (*CommandAck, error) {
+	ack := &CommandAck{
+		CommandID: event.GetString("command_id"),
+		Status:    CommandStatus(event.GetString("status")),
+		Result:    event.GetMap("result"),
+		Error:     event.GetString("error"),
+	}
+
+	if ack.CommandID == "" {
+		return nil, fmt.Errorf("%w: command_id", ErrMissingRequiredField)
+	}
+	switch ack.Status {
+	case CommandStatusDelivered, CommandStatusSucceeded, CommandStatusFailed:
+	default:
+		return nil, fmt.Errorf("%w: ack status %q", ErrInvalidStatus, ack.Status)
+	}
+
+	return ack, nil
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCreateCommandRequestValidate verifica la validación por tipo de dispositivo y parámetros.
+func TestCreateCommandRequestValidate(t *testing.T) {
+	v1 := &Device{DeviceID: "smart-bin-001", DeviceType: string(DeviceTypeSmartBinV1), BinType: string(BinTypeRecyclable)}
+	v2 := &Device{DeviceID: "smart-bin-002", DeviceType: string(DeviceTypeSmartBinV2), BinType: string(BinTypeMixed)}
+
+	tests := []struct {
+		name    string
+		device  *Device
+		req     CreateCommandRequest
+		wantErr error
+	}{
+		{
+			name:   "Reboot with delay",
+			device: v1,
+			req:    CreateCommandRequest{Type: CommandReboot, Params: map[string]interface{}{"delay_s": 10.0}},
+		},
+		{
+			name:    "Unknown command",
+			device:  v1,
+			req:     CreateCommandRequest{Type: "self_destruct"},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "Open compartment not supported on v1",
+			device:  v1,
+			req:     CreateCommandRequest{Type: CommandOpenCompartment, Params: map[string]interface{}{"compartment": "recyclable"}},
+			wantErr: ErrCommandNotSupported,
+		},
+		{
+			name:   "Open compartment on mixed v2",
+			device: v2,
+			req:    CreateCommandRequest{Type: CommandOpenCompartment, Params: map[string]interface{}{"compartment": "organic", "duration_s": 30.0}},
+		},
+		{
+			name:    "Missing required param",
+			device:  v2,
+			req:     CreateCommandRequest{Type: CommandOpenCompartment},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "Param out of range",
+			device:  v1,
+			req:     CreateCommandRequest{Type: CommandReboot, Params: map[string]interface{}{"delay_s": 7200.0}},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "Unknown param",
+			device:  v1,
+			req:     CreateCommandRequest{Type: CommandTestCapture, Params: map[string]interface{}{"flash": true}},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "TTL too long",
+			device:  v1,
+			req:     CreateCommandRequest{Type: CommandTestCapture, TTLSeconds: 90000},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "TTL overflowing time.Duration",
+			device:  v1,
+			req:     CreateCommandRequest{Type: CommandTestCapture, TTLSeconds: 9_300_000_000},
+			wantErr: ErrInvalidCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate(tt.device)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCommandTransitions verifica el ciclo pending → delivered → succeeded/failed/expired.
+func TestCommandTransitions(t *testing.T) {
+	now := time.Now()
+	command := &Command{Status: CommandStatusPending, CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+
+	if command.IsExpired(now) {
+		t.Error("IsExpired() before TTL = true, want false")
+	}
+	if err := command.Transition(CommandStatusDelivered, nil, "", now); err != nil {
+		t.Fatalf("Transition(delivered) error = %v", err)
+	}
+	if err := command.Transition(CommandStatusDelivered, nil, "", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition(delivered again) error = %v, want %v", err, ErrInvalidTransition)
+	}
+	if err := command.Transition(CommandStatusSucceeded, map[string]interface{}{"uptime_s": 3.0}, "", now); err != nil {
+		t.Fatalf("Transition(succeeded) error = %v", err)
+	}
+	if !command.IsFinal() || command.CompletedAt == nil || command.Result["uptime_s"] != 3.0 {
+		t.Errorf("Command = %+v, want final with result", command)
+	}
+	if command.IsExpired(now.Add(time.Hour)) {
+		t.Error("IsExpired() on final command = true, want false")
+	}
+	if err := command.Transition(CommandStatusFailed, nil, "late", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition(failed after succeeded) error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
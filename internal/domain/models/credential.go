@@ -0,0 +1,184 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  CREDENTIAL.GO - CREDENCIALES DE API Y PRINCIPAL               ║
+║                                                                ║
+║  - APICredential: API key de una organización; solo se         ║
+║    guarda su SHA-256, la key se muestra una vez al crearla     ║
+║  - Principal: quién hace la petición (credencial, admin o      ║
+║    dispositivo mTLS); viaja en el context y los repositorios   ║
+║    filtran por su organización                                 ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// APIKeyPrefix - Prefijo de las API keys (facilita detectarlas en logs y repositorios).
+const APIKeyPrefix = "sbk_"
+
+// APICredential es una API key emitida a una organización.
+type APICredential struct {
+	CredentialID string `json:"credential_id" dynamodbav:"credential_id"`
+	OrgID        string `json:"org_id" dynamodbav:"org_id"`
+	Name         string `json:"name" dynamodbav:"name"`
+
+	// Roles - Roles asignados (ver Role)
+	Roles []Role `json:"roles" dynamodbav:"roles"`
+
+	// DeviceID - Dispositivo al que está atada la key; obligatorio con el rol device
+	DeviceID string `json:"device_id,omitempty" dynamodbav:"device_id,omitempty"`
+
+	// KeyPrefix - Primeros caracteres de la key para identificarla
+	KeyPrefix string `json:"key_prefix" dynamodbav:"key_prefix"`
+
+	// KeyHash - SHA-256 (hex) de la key; nunca se expone
+	KeyHash string `json:"-" dynamodbav:"key_hash"`
+
+	CreatedAt  time.Time  `json:"created_at" dynamodbav:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" dynamodbav:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" dynamodbav:"revoked_at,omitempty"`
+}
+
+// IsActive retorna true si la credencial no fue revocada.
+func (c *APICredential) IsActive() bool {
+	return c.RevokedAt == nil
+}
+
+// Revoke revoca la credencial.
+func (c *APICredential) Revoke(at time.Time) error {
+	if !c.IsActive() {
+		return fmt.Errorf("%w: credential already revoked", ErrInvalidTransition)
+	}
+	c.RevokedAt = &at
+	return nil
+}
+
+// CreateCredentialRequest - Body para POST /organization/credentials.
+type CreateCredentialRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Roles []Role `json:"roles" binding:"required"`
+
+	// DeviceID - Dispositivo de la organización al que se ata la key (obligatorio con el rol device)
+	DeviceID string `json:"device_id,omitempty"`
+}
+
+// ValidateCredentialDevice exige que las keys con el rol device estén atadas a un dispositivo:
+// una key de dispositivo filtrada no puede actuar en nombre del resto de la flota.
+func ValidateCredentialDevice(roles []Role, deviceID string) error {
+	if deviceID != "" {
+		return nil
+	}
+	for _, role := range roles {
+		if role == RoleDevice {
+			return fmt.Errorf("%w: the device role requires a device_id", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// CreateCredentialResponse - La key en claro solo se retorna aquí.
+type CreateCredentialResponse struct {
+	Credential *APICredential `json:"credential"`
+	APIKey     string         `json:"api_key"`
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     PRINCIPAL
+// ═══════════════════════════════════════════════════════════════════
+
+// Principal identifica a quien hace la petición.
+type Principal struct {
+	// OrgID - Organización del principal; vacío para el super-admin sin X-Org-ID
+	OrgID string `json:"org_id,omitempty"`
+
+	// CredentialID - Credencial de API usada (vacío para el super-admin y los dispositivos)
+	CredentialID string `json:"credential_id,omitempty"`
+
+	// DeviceID - Dispositivo autenticado por certificado (listener mTLS) o al que está atada
+	// la API key; el principal solo puede actuar en nombre de ese dispositivo
+	DeviceID string `json:"device_id,omitempty"`
+
+	// Roles - Roles de la credencial o del dispositivo (el super-admin no los necesita)
+	Roles []Role `json:"roles,omitempty"`
+
+	// SuperAdmin - Acceso a todas las organizaciones
+	SuperAdmin bool `json:"super_admin,omitempty"`
+}
+
+// Actor identifica al principal en logs y registros (credencial, dispositivo o super-admin).
+func (p *Principal) Actor() string {
+	switch {
+	case p.CredentialID != "":
+		return p.CredentialID
+	case p.DeviceID != "":
+		return "device:" + p.DeviceID
+	case p.SuperAdmin:
+		return "super-admin"
+	default:
+		return "anonymous"
+	}
+}
+
+// CheckDevice retorna ErrForbidden si el principal está atado a otro dispositivo.
+func (p *Principal) CheckDevice(deviceID string) error {
+	if p.DeviceID != "" && p.DeviceID != deviceID {
+		return fmt.Errorf("%w: %s cannot act for device %s", ErrForbidden, p.Actor(), deviceID)
+	}
+	return nil
+}
+
+// CheckDeviceFromContext aplica Principal.CheckDevice al principal de la petición, si hay uno.
+func CheckDeviceFromContext(ctx context.Context, deviceID string) error {
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		return principal.CheckDevice(deviceID)
+	}
+	return nil
+}
+
+// principalKey - Clave del principal en el context.
+type principalKey struct{}
+
+// WithPrincipal retorna un context con el principal de la petición.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext retorna el principal de la petición, o nil en procesos internos
+// (workers, eventos MQTT), que no están limitados a una organización.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// OrgScope retorna la organización a la que se limitan las consultas, o "" si no hay límite
+// (super-admin sin X-Org-ID o procesos internos).
+func OrgScope(ctx context.Context) string {
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		return principal.OrgID
+	}
+	return ""
+}
+
+// CreationOrgID retorna la organización de los registros creados en la petición.
+// Sin organización en el principal se usa DefaultOrgID.
+func CreationOrgID(ctx context.Context) string {
+	if scope := OrgScope(ctx); scope != "" {
+		return scope
+	}
+	return DefaultOrgID
+}
+
+// ActorFromContext retorna el actor de la petición, o "system" en procesos internos.
+func ActorFromContext(ctx context.Context) string {
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		return principal.Actor()
+	}
+	return "system"
+}
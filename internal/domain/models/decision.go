@@ -1,5 +1,9 @@
 package models
 
+import (
+	"time"
+)
+
 /*
 ╔════════════════════════════════════════════════════════════════╗
 ║                                                                ║
@@ -117,6 +121,37 @@ func (d *Decision) Validate() error {
 	return nil
 }
 
+// DecisionMessage es el mensaje que recibe el dispositivo con el resultado de un job.
+// Se publica en bins/{device_id}/decisions.
+type DecisionMessage struct {
+	JobID    string    `json:"job_id"`
+	DeviceID string    `json:"device_id"`
+	Decision *Decision `json:"decision"`
+
+	// Label - Etiqueta de la clasificación (si existe)
+	Label string `json:"label,omitempty"`
+
+	// Confidence - Confianza de la clasificación (si existe)
+	Confidence float64 `json:"confidence,omitempty"`
+
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// NewDecisionMessage construye el mensaje para el dispositivo a partir del job completado.
+func NewDecisionMessage(job *Job) *DecisionMessage {
+	message := &DecisionMessage{
+		JobID:       job.JobID,
+		DeviceID:    job.DeviceID,
+		Decision:    job.Decision,
+		PublishedAt: time.Now(),
+	}
+	if job.HasClassification() {
+		message.Label = job.Classification.Label
+		message.Confidence = job.Classification.Confidence
+	}
+	return message
+}
+
 /*
 ═══════════════════════════════════════════════════════════════════
                     EJEMPLO DE USO
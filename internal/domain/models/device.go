@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -33,6 +34,9 @@ const (
 
 	// DeviceStatusDecommissioned - Dispositivo dado de baja.
 	DeviceStatusDecommissioned DeviceStatus = "decommissioned"
+
+	// DeviceStatusProvisioning - Pre-registrado por importación; espera que el equipo canjee su claim token.
+	DeviceStatusProvisioning DeviceStatus = "provisioning"
 )
 
 // DeviceType representa el tipo/modelo de dispositivo.
@@ -49,6 +53,16 @@ const (
 	DeviceTypeSmartBinIndustrial DeviceType = "smart_bin_industrial"
 )
 
+// IsValid retorna true si el tipo de dispositivo es conocido.
+func (t DeviceType) IsValid() bool {
+	switch t {
+	case DeviceTypeSmartBinV1, DeviceTypeSmartBinV2, DeviceTypeSmartBinIndustrial:
+		return true
+	default:
+		return false
+	}
+}
+
 // BinType representa el tipo de contenedor.
 type BinType string
 
@@ -66,6 +80,16 @@ const (
 	BinTypeMixed BinType = "mixed"
 )
 
+// IsValid retorna true si el tipo de contenedor es conocido.
+func (t BinType) IsValid() bool {
+	switch t {
+	case BinTypeRecyclable, BinTypeOrganic, BinTypeGeneral, BinTypeMixed:
+		return true
+	default:
+		return false
+	}
+}
+
 // Device representa un dispositivo IoT Smart Bin.
 type Device struct {
 	// ═══════════════════════════════════════════════════════════════
@@ -75,6 +99,9 @@ type Device struct {
 	// DeviceID - ID único del dispositivo (ej: "smart-bin-001")
 	DeviceID string `json:"device_id" dynamodbav:"device_id"`
 
+	// OrgID - Organización dueña del dispositivo
+	OrgID string `json:"org_id,omitempty" dynamodbav:"org_id"`
+
 	// DeviceType - Tipo/modelo del dispositivo
 	DeviceType string `json:"device_type" dynamodbav:"device_type"`
 
@@ -91,6 +118,18 @@ type Device struct {
 	// StatusReason - Razón del estado actual (si aplica)
 	StatusReason string `json:"status_reason,omitempty" dynamodbav:"status_reason,omitempty"`
 
+	// StatusHistory - Últimos cambios de estado con su razón (ver TransitionStatus)
+	StatusHistory []StatusChange `json:"status_history,omitempty" dynamodbav:"status_history,omitempty"`
+
+	// DecommissionedAt - Momento de la baja; el registro se conserva para auditoría
+	DecommissionedAt *time.Time `json:"decommissioned_at,omitempty" dynamodbav:"decommissioned_at,omitempty"`
+
+	// Online - Estado de conectividad calculado por el monitor de heartbeat
+	Online bool `json:"online" dynamodbav:"online"`
+
+	// OnlineChangedAt - Última vez que cambió el estado de conectividad
+	OnlineChangedAt *time.Time `json:"online_changed_at,omitempty" dynamodbav:"online_changed_at,omitempty"`
+
 	// ═══════════════════════════════════════════════════════════════
 	// UBICACIÓN
 	// ═══════════════════════════════════════════════════════════════
@@ -102,6 +141,9 @@ type Device struct {
 	// CONFIGURACIÓN
 	// ═══════════════════════════════════════════════════════════════
 
+	// Tags - Pares key/value libres (ej: campus=norte); los usan los grupos dynamic
+	Tags map[string]string `json:"tags,omitempty" dynamodbav:"tags,omitempty"`
+
 	// BinType - Tipo de contenedor
 	BinType string `json:"bin_type,omitempty" dynamodbav:"bin_type,omitempty"`
 
@@ -112,9 +154,25 @@ type Device struct {
 	// CONECTIVIDAD IOT
 	// ═══════════════════════════════════════════════════════════════
 
-	// Certificate - Certificado de AWS IoT Core
+	// Certificate - Certificado X.509 (PEM) emitido por la CA de dispositivos
 	Certificate string `json:"certificate,omitempty" dynamodbav:"certificate,omitempty"`
 
+	// CertificateSerial - Serial del certificado vigente (hex)
+	CertificateSerial string `json:"certificate_serial,omitempty" dynamodbav:"certificate_serial,omitempty"`
+
+	// CertificateExpiresAt - Vencimiento del certificado vigente
+	CertificateExpiresAt *time.Time `json:"certificate_expires_at,omitempty" dynamodbav:"certificate_expires_at,omitempty"`
+
+	// ClaimTokenHash - SHA-256 (hex) del claim token de un dispositivo en provisioning.
+	// El token en claro solo se entrega en el reporte de importación.
+	ClaimTokenHash string `json:"-" dynamodbav:"claim_token_hash,omitempty"`
+
+	// ClaimTokenExpiresAt - Vencimiento del claim token
+	ClaimTokenExpiresAt *time.Time `json:"claim_token_expires_at,omitempty" dynamodbav:"claim_token_expires_at,omitempty"`
+
+	// ClaimedAt - Momento en que el equipo canjeó su claim token
+	ClaimedAt *time.Time `json:"claimed_at,omitempty" dynamodbav:"claimed_at,omitempty"`
+
 	// ThingName - Nombre del Thing en AWS IoT Core
 	ThingName string `json:"thing_name,omitempty" dynamodbav:"thing_name,omitempty"`
 
@@ -128,9 +186,24 @@ type Device struct {
 	// FillLevel - Nivel de llenado del contenedor (0-100)
 	FillLevel *int `json:"fill_level,omitempty" dynamodbav:"fill_level,omitempty"`
 
+	// FillRatePerHour - Velocidad de llenado estimada (% por hora)
+	FillRatePerHour *float64 `json:"fill_rate_per_hour,omitempty" dynamodbav:"fill_rate_per_hour,omitempty"`
+
+	// PredictedFullAt - Momento estimado en que el contenedor llegará al 100%
+	PredictedFullAt *time.Time `json:"predicted_full_at,omitempty" dynamodbav:"predicted_full_at,omitempty"`
+
+	// DistanceMeters - Distancia al punto de búsqueda (solo en resultados de near/bbox)
+	DistanceMeters *float64 `json:"distance_m,omitempty" dynamodbav:"-"`
+
 	// SignalStrength - Fuerza de señal en dBm (ej: -45)
 	SignalStrength *int `json:"signal_strength,omitempty" dynamodbav:"signal_strength,omitempty"`
 
+	// FirmwareVersion - Versión de firmware reportada por el dispositivo
+	FirmwareVersion string `json:"firmware_version,omitempty" dynamodbav:"firmware_version,omitempty"`
+
+	// FirmwareUpdatedAt - Momento en que se detectó el cambio de versión de firmware
+	FirmwareUpdatedAt *time.Time `json:"firmware_updated_at,omitempty" dynamodbav:"firmware_updated_at,omitempty"`
+
 	// ═══════════════════════════════════════════════════════════════
 	// METADATA
 	// ═══════════════════════════════════════════════════════════════
@@ -145,6 +218,9 @@ type Device struct {
 	// LastSeen - Última vez que el dispositivo se conectó
 	LastSeen *time.Time `json:"last_seen,omitempty" dynamodbav:"last_seen,omitempty"`
 
+	// LastReportedAt - Timestamp (del dispositivo) del último reporte de estado aplicado
+	LastReportedAt *time.Time `json:"last_reported_at,omitempty" dynamodbav:"last_reported_at,omitempty"`
+
 	// CreatedAt - Moment en que se registró el dispositivo
 	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
 
@@ -209,18 +285,126 @@ type RegisterDeviceRequest struct {
 
 	// Metadata - Datos adicionales (opcional)
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Tags - Tags key/value (opcional)
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// CSR - Certificate signing request en PEM (opcional).
+	// Sin CSR el orchestrator genera el par de llaves y devuelve la llave privada una sola vez.
+	CSR string `json:"csr,omitempty"`
 }
 
 // RegisterDeviceResponse - Response al registrar un dispositivo.
 type RegisterDeviceResponse struct {
-	DeviceID    string       `json:"device_id"`
-	DeviceType  string       `json:"device_type"`
-	Status      DeviceStatus `json:"status"`
-	Certificate string       `json:"certificate"`
-	CreatedAt   time.Time    `json:"created_at"`
+	DeviceID             string       `json:"device_id"`
+	OrgID                string       `json:"org_id"`
+	DeviceType           string       `json:"device_type"`
+	Status               DeviceStatus `json:"status"`
+	Certificate          string       `json:"certificate"`
+	CertificateSerial    string       `json:"certificate_serial"`
+	CertificateExpiresAt time.Time    `json:"certificate_expires_at"`
+	CACertificate        string       `json:"ca_certificate"`
+
+	// PrivateKey - Solo si el registro no incluyó CSR. No se guarda en el orchestrator.
+	PrivateKey string `json:"private_key,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListDevicesRequest - Filtros para listar dispositivos.
+type ListDevicesRequest struct {
+	Status     DeviceStatus `form:"status"`
+	Online     *bool        `form:"online"`
+	DeviceType string       `form:"device_type"`
+	BinType    string       `form:"bin_type"`
+	Limit      int          `form:"limit,default=10"`
+	Offset     int          `form:"offset,default=0"`
+
+	// Accepts - Compartimento que debe aceptar el contenedor (los mixed aceptan todos)
+	Accepts string `form:"accepts"`
+
+	// Near - Punto de búsqueda "lat,lng"; los resultados se ordenan por distancia
+	Near string `form:"near"`
+
+	// RadiusMeters - Radio de búsqueda alrededor de Near (default: 1000)
+	RadiusMeters float64 `form:"radius_m"`
+
+	// BBox - Rectángulo de búsqueda "min_lat,min_lng,max_lat,max_lng"
+	BBox string `form:"bbox"`
+
+	// Tag - Filtros repetibles "key" (tiene el tag) o "key:value"
+	Tag []string `form:"tag"`
+
+	// Center y Bounds se calculan con ParseGeoFilters
+	Center *GeoPoint  `form:"-"`
+	Bounds *GeoBounds `form:"-"`
+
+	// Tags se calcula con ParseTagFilters
+	Tags map[string]string `form:"-"`
+}
+
+const (
+	// DefaultSearchRadiusMeters - Radio usado cuando near no trae radius_m.
+	DefaultSearchRadiusMeters = 1000
+
+	// MaxSearchRadiusMeters - Radio máximo aceptado en búsquedas por cercanía.
+	MaxSearchRadiusMeters = 50000
+)
+
+// ParseGeoFilters interpreta near, radius_m y bbox y llena Center y Bounds.
+// Con near sin bbox, Bounds es el rectángulo que contiene el círculo de búsqueda.
+func (r *ListDevicesRequest) ParseGeoFilters() error {
+	if r.BBox != "" {
+		bounds, err := ParseGeoBounds(r.BBox)
+		if err != nil {
+			return err
+		}
+		r.Bounds = &bounds
+	}
+
+	if r.Near == "" {
+		if r.RadiusMeters != 0 {
+			return fmt.Errorf("%w: radius_m requires near", ErrInvalidInput)
+		}
+		return nil
+	}
+
+	center, err := ParseGeoPoint(r.Near)
+	if err != nil {
+		return err
+	}
+	r.Center = &center
+
+	if r.RadiusMeters == 0 {
+		r.RadiusMeters = DefaultSearchRadiusMeters
+	}
+	if r.RadiusMeters < 0 || r.RadiusMeters > MaxSearchRadiusMeters {
+		return fmt.Errorf("%w: radius_m must be between 0 and %d", ErrInvalidInput, MaxSearchRadiusMeters)
+	}
+
+	if r.Bounds == nil {
+		bounds := BoundsAround(center, r.RadiusMeters)
+		r.Bounds = &bounds
+	}
+
+	return nil
+}
+
+// AcceptsCompartment retorna true si el contenedor recibe residuos del compartimento dado.
+func (d *Device) AcceptsCompartment(compartment string) bool {
+	return d.BinType == compartment || d.BinType == string(BinTypeMixed)
+}
+
+// ListDevicesResponse - Página de dispositivos.
+type ListDevicesResponse struct {
+	Devices []*Device `json:"devices"`
+	Total   int       `json:"total"`
+	Limit   int       `json:"limit"`
+	Offset  int       `json:"offset"`
 }
 
 // UpdateDeviceRequest - Request para actualizar un dispositivo.
+// Semántica merge: los campos ausentes (nil) no cambian; location se reemplaza completa.
 type UpdateDeviceRequest struct {
 	Status         *DeviceStatus `json:"status,omitempty"`
 	StatusReason   *string       `json:"status_reason,omitempty"`
@@ -228,23 +412,183 @@ type UpdateDeviceRequest struct {
 	BatteryLevel   *int          `json:"battery_level,omitempty"`
 	FillLevel      *int          `json:"fill_level,omitempty"`
 	SignalStrength *int          `json:"signal_strength,omitempty"`
+
+	// Tags - Merge de tags: un valor null elimina la key
+	Tags map[string]*string `json:"tags,omitempty"`
+}
+
+// Telemetry retorna las lecturas del request (batería, llenado, señal) para el histórico,
+// o nil si no trae ninguna.
+func (r *UpdateDeviceRequest) Telemetry(at time.Time) *DeviceTelemetry {
+	if r.BatteryLevel == nil && r.FillLevel == nil && r.SignalStrength == nil {
+		return nil
+	}
+	return &DeviceTelemetry{
+		BatteryLevel:   r.BatteryLevel,
+		FillLevel:      r.FillLevel,
+		SignalStrength: r.SignalStrength,
+		ReportedAt:     at,
+	}
+}
+
+// IsEmpty retorna true si el request no cambia ningún campo.
+func (r *UpdateDeviceRequest) IsEmpty() bool {
+	return r.Status == nil &&
+		r.StatusReason == nil &&
+		r.Location == nil &&
+		r.BatteryLevel == nil &&
+		r.FillLevel == nil &&
+		r.SignalStrength == nil &&
+		r.Tags == nil
+}
+
+// Validate valida los rangos de los campos presentes; las transiciones se validan al aplicarlo.
+func (r *UpdateDeviceRequest) Validate() error {
+	if r.IsEmpty() {
+		return fmt.Errorf("%w: no fields to update", ErrInvalidInput)
+	}
+	if r.Status != nil && !r.Status.IsValid() {
+		return fmt.Errorf("%w: unknown device status %q", ErrInvalidStatus, *r.Status)
+	}
+	if r.StatusReason != nil && len(*r.StatusReason) > MaxStatusReasonLength {
+		return fmt.Errorf("%w: status_reason exceeds %d characters", ErrInvalidInput, MaxStatusReasonLength)
+	}
+	if r.BatteryLevel != nil && !inRange(*r.BatteryLevel, MinPercentage, MaxPercentage) {
+		return fmt.Errorf("%w: must be between %d and %d", ErrInvalidBatteryLevel, MinPercentage, MaxPercentage)
+	}
+	if r.FillLevel != nil && !inRange(*r.FillLevel, MinPercentage, MaxPercentage) {
+		return fmt.Errorf("%w: must be between %d and %d", ErrInvalidFillLevel, MinPercentage, MaxPercentage)
+	}
+	if r.SignalStrength != nil && !inRange(*r.SignalStrength, MinSignalStrength, MaxSignalStrength) {
+		return fmt.Errorf("%w: must be between %d and %d dBm", ErrInvalidSignalStrength, MinSignalStrength, MaxSignalStrength)
+	}
+	if r.Location != nil {
+		point := GeoPoint{Latitude: r.Location.Latitude, Longitude: r.Location.Longitude}
+		if err := point.Validate(); err != nil {
+			return err
+		}
+	}
+	for key, value := range r.Tags {
+		if value == nil {
+			continue
+		}
+		if err := ValidateTag(key, *value); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ═══════════════════════════════════════════════════════════════════
 //                     MÉTODOS DEL MODELO
 // ═══════════════════════════════════════════════════════════════════
 
+// ApplyUpdate aplica un UpdateDeviceRequest ya validado con semántica merge.
+// Un status distinto al actual pasa por la máquina de estados; con el mismo status
+// (o sin status) status_reason solo reemplaza la razón. Los dados de baja son de solo lectura.
+// actor queda en el historial si el request cambia el estado.
+func (d *Device) ApplyUpdate(r *UpdateDeviceRequest, actor string, at time.Time) error {
+	if d.Status == DeviceStatusDecommissioned {
+		return fmt.Errorf("%w: decommissioned devices are read-only", ErrInvalidTransition)
+	}
+
+	reason := d.StatusReason
+	if r.StatusReason != nil {
+		reason = *r.StatusReason
+	}
+
+	if r.Status != nil && *r.Status != d.Status {
+		if r.StatusReason == nil {
+			// La razón del estado anterior no aplica al nuevo
+			reason = ""
+		}
+		if err := d.ChangeStatusManually(*r.Status, reason, actor, at); err != nil {
+			return err
+		}
+	} else if r.StatusReason != nil {
+		if reason == "" && d.Status.RequiresReason() {
+			return fmt.Errorf("%w: %s devices need a status_reason", ErrMissingRequiredField, d.Status)
+		}
+		d.StatusReason = reason
+	}
+
+	if r.Location != nil {
+		location := *r.Location
+		d.Location = &location
+	}
+	if r.BatteryLevel != nil {
+		battery := *r.BatteryLevel
+		d.BatteryLevel = &battery
+	}
+	if r.FillLevel != nil {
+		fill := *r.FillLevel
+		d.FillLevel = &fill
+	}
+	if r.SignalStrength != nil {
+		signal := *r.SignalStrength
+		d.SignalStrength = &signal
+	}
+	if len(r.Tags) > 0 {
+		tags := make(map[string]string, len(d.Tags)+len(r.Tags))
+		for key, value := range d.Tags {
+			tags[key] = value
+		}
+		for key, value := range r.Tags {
+			if value == nil {
+				delete(tags, key)
+			} else {
+				tags[key] = *value
+			}
+		}
+		if len(tags) > MaxDeviceTags {
+			return fmt.Errorf("%w: at most %d tags per device", ErrInvalidInput, MaxDeviceTags)
+		}
+		d.Tags = tags
+	}
+
+	d.UpdatedAt = at
+	return nil
+}
+
 // IsActive retorna true si el dispositivo está activo.
 func (d *Device) IsActive() bool {
 	return d.Status == DeviceStatusActive
 }
 
+// IsReachable retorna false si el dispositivo está inactivo, dado de baja o aún sin canjear
+// su claim token: no recibe comandos ni puede autenticarse con su certificado.
+func (d *Device) IsReachable() bool {
+	return d.Status != DeviceStatusInactive &&
+		d.Status != DeviceStatusDecommissioned &&
+		d.Status != DeviceStatusProvisioning
+}
+
+// DefaultOfflineThreshold - Tiempo sin heartbeat tras el cual un dispositivo se considera offline.
+const DefaultOfflineThreshold = 5 * time.Minute
+
 // IsOnline Se considera online si se conectó en los últimos 5 minutos.
 func (d *Device) IsOnline() bool {
+	return d.IsOnlineWithin(DefaultOfflineThreshold, time.Now())
+}
+
+// IsOnlineWithin retorna true si el dispositivo se conectó dentro de threshold antes de now.
+func (d *Device) IsOnlineWithin(threshold time.Duration, now time.Time) bool {
 	if d.LastSeen == nil {
 		return false
 	}
-	return time.Since(*d.LastSeen) < 5*time.Minute
+	return now.Sub(*d.LastSeen) < threshold
+}
+
+// SetOnline actualiza el estado de conectividad.
+// Retorna true si el estado cambió.
+func (d *Device) SetOnline(online bool, at time.Time) bool {
+	if d.Online == online {
+		return false
+	}
+	d.Online = online
+	d.OnlineChangedAt = &at
+	d.UpdatedAt = at
+	return true
 }
 
 // MarkAsSeen actualiza el timestamp de LastSeen.
@@ -337,6 +681,12 @@ func (d *Device) IncrementErrorCount() {
 	d.UpdatedAt = time.Now()
 }
 
+// ResetErrorCount reinicia el contador de errores (p.ej. al resolver a mano la alerta errors_high).
+func (d *Device) ResetErrorCount() {
+	d.TotalErrors = 0
+	d.UpdatedAt = time.Now()
+}
+
 // Validate valida que el dispositivo tenga los campos obligatorios.
 func (d *Device) Validate() error {
 	if d.DeviceID == "" {
@@ -348,7 +698,7 @@ func (d *Device) Validate() error {
 	if d.Status == "" {
 		return ErrInvalidStatus
 	}
-	return nil
+	return ValidateTags(d.Tags)
 }
 
 // GetDisplayName retorna un nombre legible del dispositivo.
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  DEVICE_STATUS.GO - CICLO DE VIDA DEL DISPOSITIVO              ║
+║                                                                ║
+║  - provisioning → active al canjear el claim token             ║
+║  - active, inactive, maintenance y error se alternan           ║
+║    (salvo inactive → error: apagado no reporta fallas)         ║
+║  - cualquier estado → decommissioned, que es terminal          ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// MaxStatusReasonLength - Largo máximo de la razón de un cambio de estado.
+const MaxStatusReasonLength = 256
+
+// MaxStatusHistory - Cambios de estado que se conservan en el dispositivo (los más recientes).
+const MaxStatusHistory = 50
+
+// deviceStatusTransitions - Estados destino permitidos desde cada estado.
+// provisioning → active solo ocurre al canjear el claim token (ver ClaimDevice).
+var deviceStatusTransitions = map[DeviceStatus][]DeviceStatus{
+	DeviceStatusProvisioning:   {DeviceStatusActive, DeviceStatusDecommissioned},
+	DeviceStatusActive:         {DeviceStatusInactive, DeviceStatusMaintenance, DeviceStatusError, DeviceStatusDecommissioned},
+	DeviceStatusInactive:       {DeviceStatusActive, DeviceStatusMaintenance, DeviceStatusDecommissioned},
+	DeviceStatusMaintenance:    {DeviceStatusActive, DeviceStatusInactive, DeviceStatusError, DeviceStatusDecommissioned},
+	DeviceStatusError:          {DeviceStatusActive, DeviceStatusInactive, DeviceStatusMaintenance, DeviceStatusDecommissioned},
+	DeviceStatusDecommissioned: nil,
+}
+
+// IsValid retorna true si el estado es conocido.
+func (s DeviceStatus) IsValid() bool {
+	_, ok := deviceStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo retorna true si la máquina de estados permite pasar de s a to.
+func (s DeviceStatus) CanTransitionTo(to DeviceStatus) bool {
+	for _, allowed := range deviceStatusTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresReason retorna true si entrar en el estado exige una razón.
+// Volver a active no la exige: la razón anterior simplemente se limpia.
+func (s DeviceStatus) RequiresReason() bool {
+	return s != DeviceStatusActive
+}
+
+// StatusChange es una entrada del historial de estados del dispositivo.
+type StatusChange struct {
+	From      DeviceStatus `json:"from" dynamodbav:"from"`
+	To        DeviceStatus `json:"to" dynamodbav:"to"`
+	Reason    string       `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+	ChangedBy string       `json:"changed_by,omitempty" dynamodbav:"changed_by,omitempty"`
+	ChangedAt time.Time    `json:"changed_at" dynamodbav:"changed_at"`
+}
+
+// TransitionStatus cambia el estado validando la transición y la razón, y la registra en el historial.
+// Al dar de baja marca DecommissionedAt y deja el dispositivo offline.
+func (d *Device) TransitionStatus(to DeviceStatus, reason, actor string, at time.Time) error {
+	if !to.IsValid() {
+		return fmt.Errorf("%w: unknown device status %q", ErrInvalidStatus, to)
+	}
+	if !d.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, d.Status, to)
+	}
+	if reason == "" && to.RequiresReason() {
+		return fmt.Errorf("%w: reason is required to move a device to %s", ErrMissingRequiredField, to)
+	}
+	if len(reason) > MaxStatusReasonLength {
+		return fmt.Errorf("%w: reason exceeds %d characters", ErrInvalidInput, MaxStatusReasonLength)
+	}
+
+	d.StatusHistory = append(d.StatusHistory, StatusChange{
+		From:      d.Status,
+		To:        to,
+		Reason:    reason,
+		ChangedBy: actor,
+		ChangedAt: at,
+	})
+	if len(d.StatusHistory) > MaxStatusHistory {
+		d.StatusHistory = d.StatusHistory[len(d.StatusHistory)-MaxStatusHistory:]
+	}
+
+	d.Status = to
+	d.StatusReason = reason
+	d.UpdatedAt = at
+
+	if to == DeviceStatusDecommissioned {
+		d.DecommissionedAt = &at
+		d.Online = false
+		d.ClaimTokenHash = ""
+		d.ClaimTokenExpiresAt = nil
+	}
+	return nil
+}
+
+// ChangeStatusManually aplica un cambio de estado pedido por un operador (API).
+// La baja no se hace por aquí porque además revoca credenciales (ver DecommissionService),
+// y un dispositivo en provisioning solo se activa canjeando su claim token.
+func (d *Device) ChangeStatusManually(to DeviceStatus, reason, actor string, at time.Time) error {
+	if to == DeviceStatusDecommissioned {
+		return fmt.Errorf("%w: decommission the device to retire it", ErrInvalidTransition)
+	}
+	if d.Status == DeviceStatusProvisioning && to == DeviceStatusActive {
+		return fmt.Errorf("%w: provisioning devices are activated by claiming their token", ErrInvalidTransition)
+	}
+	return d.TransitionStatus(to, reason, actor, at)
+}
+
+// ChangeDeviceStatusRequest - Body para PUT /devices/:device_id/status.
+type ChangeDeviceStatusRequest struct {
+	Status DeviceStatus `json:"status" binding:"required"`
+
+	// Reason - Obligatoria salvo al volver a active
+	Reason string `json:"reason,omitempty"`
+}
+
+// DefaultDecommissionReason - Razón usada si la baja no indica una.
+const DefaultDecommissionReason = "decommissioned"
+
+// DecommissionDeviceRequest - Body opcional para DELETE /devices/:device_id.
+type DecommissionDeviceRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// DecommissionDeviceResponse - Resultado de la baja: el registro se conserva para auditoría.
+type DecommissionDeviceResponse struct {
+	Device *Device `json:"device"`
+
+	// RevokedCertificates - Seriales revocados por cessation_of_operation
+	RevokedCertificates []string `json:"revoked_certificates"`
+
+	// CanceledCommands - Comandos abiertos que se cancelaron
+	CanceledCommands int `json:"canceled_commands"`
+
+	// ResolvedAlerts - Alertas activas que se cerraron
+	ResolvedAlerts int `json:"resolved_alerts"`
+}
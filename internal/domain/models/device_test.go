@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -435,3 +436,182 @@ func TestDeviceGetDisplayName(t *testing.T) {
 		})
 	}
 }
+
+// TestDeviceTransitionStatus verifica la máquina de estados, la razón obligatoria y el historial.
+func TestDeviceTransitionStatus(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		from    DeviceStatus
+		to      DeviceStatus
+		reason  string
+		wantErr error
+	}{
+		{"Active to maintenance", DeviceStatusActive, DeviceStatusMaintenance, "lid sensor replacement", nil},
+		{"Maintenance back to active without reason", DeviceStatusMaintenance, DeviceStatusActive, "", nil},
+		{"Error to inactive", DeviceStatusError, DeviceStatusInactive, "powered off", nil},
+		{"Inactive to error is not allowed", DeviceStatusInactive, DeviceStatusError, "fault", ErrInvalidTransition},
+		{"Same status is not a transition", DeviceStatusActive, DeviceStatusActive, "", ErrInvalidTransition},
+		{"Decommissioned is terminal", DeviceStatusDecommissioned, DeviceStatusActive, "", ErrInvalidTransition},
+		{"Reason required", DeviceStatusActive, DeviceStatusError, "", ErrMissingRequiredField},
+		{"Unknown status", DeviceStatusActive, DeviceStatus("broken"), "x", ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := &Device{DeviceID: "smart-bin-001", Status: tt.from, StatusReason: "previous"}
+
+			err := device.TransitionStatus(tt.to, tt.reason, "ops", now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransitionStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if device.Status != tt.from || len(device.StatusHistory) != 0 {
+					t.Errorf("Device changed on a rejected transition: %s, %d history entries", device.Status, len(device.StatusHistory))
+				}
+				return
+			}
+
+			if device.Status != tt.to || device.StatusReason != tt.reason {
+				t.Errorf("Status = %s (%q), want %s (%q)", device.Status, device.StatusReason, tt.to, tt.reason)
+			}
+			if len(device.StatusHistory) != 1 {
+				t.Fatalf("History entries = %d, want 1", len(device.StatusHistory))
+			}
+			if change := device.StatusHistory[0]; change.From != tt.from || change.To != tt.to || change.ChangedBy != "ops" {
+				t.Errorf("History entry = %+v", change)
+			}
+		})
+	}
+
+	t.Run("Decommission marks the device", func(t *testing.T) {
+		device := &Device{DeviceID: "smart-bin-001", Status: DeviceStatusActive, Online: true}
+		if err := device.TransitionStatus(DeviceStatusDecommissioned, "vandalized", "ops", now); err != nil {
+			t.Fatalf("TransitionStatus() error = %v", err)
+		}
+		if device.DecommissionedAt == nil || device.Online || device.IsReachable() {
+			t.Errorf("DecommissionedAt = %v, online = %v, reachable = %v", device.DecommissionedAt, device.Online, device.IsReachable())
+		}
+	})
+
+	t.Run("History is bounded", func(t *testing.T) {
+		device := &Device{DeviceID: "smart-bin-001", Status: DeviceStatusActive}
+		for i := 0; i < MaxStatusHistory+5; i++ {
+			to := DeviceStatusMaintenance
+			if device.Status == DeviceStatusMaintenance {
+				to = DeviceStatusActive
+			}
+			if err := device.TransitionStatus(to, "cycle", "", now.Add(time.Duration(i)*time.Second)); err != nil {
+				t.Fatalf("TransitionStatus() error = %v", err)
+			}
+		}
+		if len(device.StatusHistory) != MaxStatusHistory {
+			t.Errorf("History entries = %d, want %d", len(device.StatusHistory), MaxStatusHistory)
+		}
+	})
+}
+
+// TestDeviceApplyUpdate verifica la semántica merge y la validación de rangos del PATCH.
+func TestDeviceApplyUpdate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+	statusPtr := func(v DeviceStatus) *DeviceStatus { return &v }
+
+	tests := []struct {
+		name       string
+		status     DeviceStatus
+		req        UpdateDeviceRequest
+		wantErr    error
+		wantStatus DeviceStatus
+		wantReason string
+	}{
+		{
+			name:       "Only fill level",
+			status:     DeviceStatusActive,
+			req:        UpdateDeviceRequest{FillLevel: intPtr(40)},
+			wantStatus: DeviceStatusActive,
+		},
+		{
+			name:       "Status with reason",
+			status:     DeviceStatusActive,
+			req:        UpdateDeviceRequest{Status: statusPtr(DeviceStatusMaintenance), StatusReason: strPtr("jammed lid")},
+			wantStatus: DeviceStatusMaintenance,
+			wantReason: "jammed lid",
+		},
+		{
+			name:       "Reason only on the current status",
+			status:     DeviceStatusMaintenance,
+			req:        UpdateDeviceRequest{StatusReason: strPtr("waiting for parts")},
+			wantStatus: DeviceStatusMaintenance,
+			wantReason: "waiting for parts",
+		},
+		{
+			name:    "Clearing the reason of a maintenance device",
+			status:  DeviceStatusMaintenance,
+			req:     UpdateDeviceRequest{StatusReason: strPtr("")},
+			wantErr: ErrMissingRequiredField,
+		},
+		{
+			name:    "Decommission through PATCH",
+			status:  DeviceStatusActive,
+			req:     UpdateDeviceRequest{Status: statusPtr(DeviceStatusDecommissioned), StatusReason: strPtr("retired")},
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:    "Decommissioned devices are read-only",
+			status:  DeviceStatusDecommissioned,
+			req:     UpdateDeviceRequest{FillLevel: intPtr(10)},
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:    "Battery out of range",
+			status:  DeviceStatusActive,
+			req:     UpdateDeviceRequest{BatteryLevel: intPtr(101)},
+			wantErr: ErrInvalidBatteryLevel,
+		},
+		{
+			name:    "Signal above 0 dBm",
+			status:  DeviceStatusActive,
+			req:     UpdateDeviceRequest{SignalStrength: intPtr(5)},
+			wantErr: ErrInvalidSignalStrength,
+		},
+		{
+			name:    "Empty update",
+			status:  DeviceStatusActive,
+			req:     UpdateDeviceRequest{},
+			wantErr: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			battery := 80
+			device := &Device{DeviceID: "smart-bin-001", Status: tt.status, StatusReason: "previous", BatteryLevel: &battery}
+			if tt.status == DeviceStatusActive {
+				device.StatusReason = ""
+			}
+
+			err := tt.req.Validate()
+			if err == nil {
+				err = device.ApplyUpdate(&tt.req, "cred_ops", time.Now())
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if device.Status != tt.wantStatus || device.StatusReason != tt.wantReason {
+				t.Errorf("Status = %s (%q), want %s (%q)", device.Status, device.StatusReason, tt.wantStatus, tt.wantReason)
+			}
+			if device.BatteryLevel == nil || *device.BatteryLevel != 80 {
+				t.Error("Absent battery_level was overwritten")
+			}
+			if device.UpdatedAt.IsZero() {
+				t.Error("UpdatedAt not bumped")
+			}
+		})
+	}
+}
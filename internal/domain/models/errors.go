@@ -63,6 +63,156 @@ var (
 	ErrDeviceNotOnline = errors.New("device not online")
 )
 
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE GRUPOS
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrGroupNotFound - Grupo de dispositivos no encontrado.
+	ErrGroupNotFound = errors.New("device group not found")
+
+	// ErrInvalidGroup - Definición de grupo inválida (tipo, selector o miembros).
+	ErrInvalidGroup = errors.New("invalid device group")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE ORGANIZACIONES
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrOrganizationNotFound - Organización no encontrada.
+	ErrOrganizationNotFound = errors.New("organization not found")
+
+	// ErrOrganizationExists - Ya existe una organización con el ID.
+	ErrOrganizationExists = errors.New("organization already exists")
+
+	// ErrCredentialNotFound - Credencial de API no encontrada.
+	ErrCredentialNotFound = errors.New("api credential not found")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE CERTIFICADOS
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrInvalidCSR - CSR mal formado, con firma inválida o para otro dispositivo.
+	ErrInvalidCSR = errors.New("invalid certificate signing request")
+
+	// ErrCertificateIssuance - La CA no pudo emitir el certificado.
+	ErrCertificateIssuance = errors.New("certificate issuance failed")
+
+	// ErrCertificateNotFound - Certificado no encontrado.
+	ErrCertificateNotFound = errors.New("certificate not found")
+
+	// ErrCertificateRevoked - Certificado revocado.
+	ErrCertificateRevoked = errors.New("certificate is revoked")
+
+	// ErrCertificateSuperseded - Certificado reemplazado por una rotación y fuera del periodo de solapamiento.
+	ErrCertificateSuperseded = errors.New("certificate was superseded")
+
+	// ErrInvalidClaimToken - Claim token desconocido o ya canjeado.
+	ErrInvalidClaimToken = errors.New("invalid claim token")
+
+	// ErrClaimTokenExpired - Claim token vencido; hay que reimportar el dispositivo.
+	ErrClaimTokenExpired = errors.New("claim token expired")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE TELEMETRÍA
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrInvalidBatteryLevel - Nivel de batería fuera de rango (0-100).
+	ErrInvalidBatteryLevel = errors.New("invalid battery level")
+
+	// ErrInvalidFillLevel - Nivel de llenado fuera de rango (0-100).
+	ErrInvalidFillLevel = errors.New("invalid fill level")
+
+	// ErrInvalidSignalStrength - Señal fuera de rango en dBm.
+	ErrInvalidSignalStrength = errors.New("invalid signal strength")
+
+	// ErrStaleEvent - Evento más antiguo que el último estado guardado.
+	ErrStaleEvent = errors.New("event is older than last reported state")
+
+	// ErrInvalidMetric - Métrica de telemetría desconocida.
+	ErrInvalidMetric = errors.New("invalid telemetry metric")
+
+	// ErrInvalidTimeRange - Rango de tiempo o step inválido.
+	ErrInvalidTimeRange = errors.New("invalid time range")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE ALERTAS
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrAlertNotFound - Alerta no encontrada.
+	ErrAlertNotFound = errors.New("alert not found")
+
+	// ErrInvalidAlertRule - Regla de alerta inválida.
+	ErrInvalidAlertRule = errors.New("invalid alert rule")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE SHADOW
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrInvalidShadowState - Estado deseado o reportado inválido.
+	ErrInvalidShadowState = errors.New("invalid shadow state")
+
+	// ErrShadowVersionConflict - La versión esperada no coincide con la actual.
+	ErrShadowVersionConflict = errors.New("shadow version conflict")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE COMANDOS
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrCommandNotFound - Comando no encontrado.
+	ErrCommandNotFound = errors.New("command not found")
+
+	// ErrInvalidCommand - Tipo de comando o parámetros inválidos.
+	ErrInvalidCommand = errors.New("invalid command")
+
+	// ErrCommandNotSupported - El tipo de dispositivo no soporta el comando.
+	ErrCommandNotSupported = errors.New("command not supported by device type")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES DE FIRMWARE
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrFirmwareReleaseNotFound - Release de firmware no encontrada.
+	ErrFirmwareReleaseNotFound = errors.New("firmware release not found")
+
+	// ErrFirmwareReleaseExists - Ya existe la versión para el tipo de dispositivo.
+	ErrFirmwareReleaseExists = errors.New("firmware release already exists")
+
+	// ErrInvalidFirmware - Versión, checksum o tamaño inválidos.
+	ErrInvalidFirmware = errors.New("invalid firmware release")
+
+	// ErrRolloutNotFound - Rollout no encontrado.
+	ErrRolloutNotFound = errors.New("rollout not found")
+
+	// ErrRolloutInProgress - Ya hay un rollout abierto para el tipo de dispositivo.
+	ErrRolloutInProgress = errors.New("rollout already in progress for device type")
+
+	// ErrInvalidRollout - Porcentaje, grupo o umbral inválidos.
+	ErrInvalidRollout = errors.New("invalid rollout")
+)
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ERRORES GEOESPACIALES
+// ═══════════════════════════════════════════════════════════════════
+
+var (
+	// ErrInvalidCoordinates - Latitud o longitud fuera de rango.
+	ErrInvalidCoordinates = errors.New("invalid coordinates")
+)
+
 // ═══════════════════════════════════════════════════════════════════
 //                     ERRORES DE CLASSIFICATION
 // ═══════════════════════════════════════════════════════════════════
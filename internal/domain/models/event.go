@@ -0,0 +1,156 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  EVENT.GO - EVENTOS DE DISPOSITIVOS                            ║
+║                                                                ║
+║  Representa los eventos que envían los dispositivos IoT        ║
+║  (captura de imagen, estado, errores) al orchestrator.         ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// DeviceEventType representa el tipo de evento enviado por un dispositivo.
+type DeviceEventType string
+
+const (
+	// DeviceEventImageCaptured - El dispositivo capturó una imagen de un residuo.
+	DeviceEventImageCaptured DeviceEventType = "image_captured"
+
+	// DeviceEventStatus - Reporte periódico de estado del hardware.
+	DeviceEventStatus DeviceEventType = "device_status"
+
+	// DeviceEventError - El dispositivo reportó un error.
+	DeviceEventError DeviceEventType = "error"
+
+	// DeviceEventCommandAck - El dispositivo confirmó la recepción o el resultado de un comando.
+	DeviceEventCommandAck DeviceEventType = "command_ack"
+
+	// DeviceEventFirmwareUpdate - El dispositivo reportó el avance de una actualización OTA.
+	DeviceEventFirmwareUpdate DeviceEventType = "firmware_update"
+)
+
+const (
+	// DeviceEventOffline - Emitido por el orchestrator cuando un dispositivo deja de enviar heartbeats.
+	DeviceEventOffline DeviceEventType = "device_offline"
+
+	// DeviceEventOnline - Emitido por el orchestrator cuando un dispositivo vuelve a conectarse.
+	DeviceEventOnline DeviceEventType = "device_online"
+
+	// DeviceEventMaintenanceRequired - Emitido cuando una actualización deja al dispositivo necesitando mantenimiento.
+	DeviceEventMaintenanceRequired DeviceEventType = "maintenance_required"
+
+	// DeviceEventMaintenanceCleared - Emitido cuando una actualización deja de requerir mantenimiento.
+	DeviceEventMaintenanceCleared DeviceEventType = "maintenance_cleared"
+)
+
+// DeviceEvent representa un evento recibido desde un dispositivo IoT.
+type DeviceEvent struct {
+	// EventType - Tipo de evento (image_captured, device_status, error, command_ack, firmware_update)
+	EventType DeviceEventType `json:"event_type" binding:"required"`
+
+	// DeviceID - ID del dispositivo que originó el evento
+	DeviceID string `json:"device_id" binding:"required"`
+
+	// Timestamp - Momento en que el dispositivo generó el evento (RFC3339)
+	Timestamp string `json:"timestamp" binding:"required"`
+
+	// Data - Payload específico del tipo de evento
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// DeviceEventResult resume el efecto de procesar un evento.
+type DeviceEventResult struct {
+	// EventType - Tipo de evento procesado
+	EventType DeviceEventType `json:"event_type"`
+
+	// Job - Job creado a partir del evento (solo image_captured)
+	Job *CreateJobResponse `json:"job,omitempty"`
+
+	// Device - Estado del dispositivo tras aplicar el evento (solo device_status)
+	Device *Device `json:"device,omitempty"`
+
+	// Command - Comando tras aplicar el ack (solo command_ack)
+	Command *Command `json:"command,omitempty"`
+
+	// FirmwareUpdate - Actualización OTA tras aplicar el avance (solo firmware_update)
+	FirmwareUpdate *FirmwareUpdate `json:"firmware_update,omitempty"`
+
+	// ProcessedAt - Momento en que el orchestrator procesó el evento
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// GetString retorna el valor string de una clave de Data, o "" si no existe.
+func (e *DeviceEvent) GetString(key string) string {
+	if e.Data == nil {
+		return ""
+	}
+	value, ok := e.Data[key].(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// GetMap retorna el valor map de una clave de Data, o nil si no existe.
+func (e *DeviceEvent) GetMap(key string) map[string]interface{} {
+	if e.Data == nil {
+		return nil
+	}
+	value, ok := e.Data[key].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+// GetInt retorna el valor entero de una clave de Data.
+// Retorna nil si la clave no existe y ErrInvalidInput si no es numérica o tiene decimales.
+func (e *DeviceEvent) GetInt(key string) (*int, error) {
+	if e.Data == nil {
+		return nil, nil
+	}
+	raw, ok := e.Data[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	// encoding/json decodifica los números como float64
+	number, ok := raw.(float64)
+	if !ok || number != math.Trunc(number) {
+		return nil, fmt.Errorf("%w: %s must be an integer", ErrInvalidInput, key)
+	}
+
+	value := int(number)
+	return &value, nil
+}
+
+// ParseTimestamp interpreta el Timestamp del evento (RFC3339).
+func (e *DeviceEvent) ParseTimestamp() (time.Time, error) {
+	timestamp, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTimestamp, e.Timestamp)
+	}
+	return timestamp, nil
+}
+
+// Validate valida que el evento tenga los campos obligatorios.
+func (e *DeviceEvent) Validate() error {
+	if e.DeviceID == "" {
+		return ErrInvalidDeviceID
+	}
+	if e.EventType == "" {
+		return ErrMissingRequiredField
+	}
+	if e.Timestamp == "" {
+		return ErrInvalidTimestamp
+	}
+	return nil
+}
@@ -0,0 +1,429 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  FIRMWARE.GO - RELEASES DE FIRMWARE Y ROLLOUTS OTA             ║
+║                                                                ║
+║  Una release es un binario por tipo de dispositivo guardado    ║
+║  en S3. Un rollout la distribuye a un porcentaje de la flota   ║
+║  o a una lista de dispositivos, y se pausa solo si la tasa de  ║
+║  fallos supera el umbral.                                      ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+const (
+	// DefaultRolloutFailureThreshold - Tasa de fallos que pausa un rollout (20%).
+	DefaultRolloutFailureThreshold = 0.2
+
+	// DefaultRolloutMinSamples - Actualizaciones terminadas antes de evaluar la tasa de fallos.
+	DefaultRolloutMinSamples = 5
+)
+
+// firmwareVersionPattern acepta versiones semver (1.4.2, 2.0.0-rc.1).
+var firmwareVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// FirmwareRelease es una versión de firmware publicada para un tipo de dispositivo.
+type FirmwareRelease struct {
+	ReleaseID  string `json:"release_id" dynamodbav:"release_id"`
+	Version    string `json:"version" dynamodbav:"version"`
+	DeviceType string `json:"device_type" dynamodbav:"device_type"`
+
+	// ArtifactKey - Key S3 del binario (firmware/{device_type}/{version}.bin)
+	ArtifactKey string `json:"artifact_key" dynamodbav:"artifact_key"`
+
+	// ChecksumSHA256 - SHA-256 del binario en hex; el dispositivo lo verifica antes de instalar
+	ChecksumSHA256 string `json:"checksum_sha256" dynamodbav:"checksum_sha256"`
+
+	SizeBytes int64     `json:"size_bytes" dynamodbav:"size_bytes"`
+	Notes     string    `json:"notes,omitempty" dynamodbav:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+// FirmwareArtifactKey retorna la key S3 del binario de una release.
+func FirmwareArtifactKey(deviceType, version string) string {
+	return fmt.Sprintf("firmware/%s/%s.bin", deviceType, version)
+}
+
+// CreateFirmwareReleaseRequest - Request para registrar una release.
+type CreateFirmwareReleaseRequest struct {
+	Version        string `json:"version" binding:"required"`
+	DeviceType     string `json:"device_type" binding:"required"`
+	ChecksumSHA256 string `json:"checksum_sha256" binding:"required"`
+	SizeBytes      int64  `json:"size_bytes" binding:"required"`
+	Notes          string `json:"notes,omitempty"`
+}
+
+// Validate valida el request.
+func (r *CreateFirmwareReleaseRequest) Validate() error {
+	if !firmwareVersionPattern.MatchString(r.Version) {
+		return fmt.Errorf("%w: version %q is not semver", ErrInvalidFirmware, r.Version)
+	}
+	if !DeviceType(r.DeviceType).IsValid() {
+		return ErrInvalidDeviceType
+	}
+	if checksum, err := hex.DecodeString(r.ChecksumSHA256); err != nil || len(checksum) != 32 {
+		return fmt.Errorf("%w: checksum_sha256 must be 64 hex characters", ErrInvalidFirmware)
+	}
+	if r.SizeBytes <= 0 {
+		return fmt.Errorf("%w: size_bytes must be positive", ErrInvalidFirmware)
+	}
+	return nil
+}
+
+// CreateFirmwareReleaseResponse - Release creada y URL para subir el binario.
+type CreateFirmwareReleaseResponse struct {
+	Release   *FirmwareRelease `json:"release"`
+	UploadURL string           `json:"upload_url"`
+	ExpiresAt time.Time        `json:"expires_at"`
+}
+
+// ListFirmwareReleasesRequest - Filtros para listar releases.
+type ListFirmwareReleasesRequest struct {
+	DeviceType string `form:"device_type"`
+	Limit      int    `form:"limit,default=10"`
+	Offset     int    `form:"offset,default=0"`
+}
+
+// ListFirmwareReleasesResponse - Página de releases.
+type ListFirmwareReleasesResponse struct {
+	Releases []*FirmwareRelease `json:"releases"`
+	Total    int                `json:"total"`
+	Limit    int                `json:"limit"`
+	Offset   int                `json:"offset"`
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ROLLOUTS
+// ═══════════════════════════════════════════════════════════════════
+
+// RolloutStatus representa el estado de un rollout.
+type RolloutStatus string
+
+const (
+	// RolloutStatusActive - Enviando la actualización a los dispositivos seleccionados.
+	RolloutStatusActive RolloutStatus = "active"
+
+	// RolloutStatusPaused - Detenido por un operador o por superar el umbral de fallos.
+	RolloutStatusPaused RolloutStatus = "paused"
+
+	// RolloutStatusCompleted - Todos los dispositivos del 100% terminaron.
+	RolloutStatusCompleted RolloutStatus = "completed"
+)
+
+// FirmwareRollout distribuye una release a parte de la flota de un tipo de dispositivo.
+type FirmwareRollout struct {
+	RolloutID  string `json:"rollout_id" dynamodbav:"rollout_id"`
+	ReleaseID  string `json:"release_id" dynamodbav:"release_id"`
+	Version    string `json:"version" dynamodbav:"version"`
+	DeviceType string `json:"device_type" dynamodbav:"device_type"`
+
+	// Percentage - Porcentaje de la flota incluido (1-100). Se puede subir por etapas.
+	Percentage int `json:"percentage,omitempty" dynamodbav:"percentage,omitempty"`
+
+	// DeviceIDs - Grupo explícito de dispositivos (alternativa a Percentage)
+	DeviceIDs []string `json:"device_ids,omitempty" dynamodbav:"device_ids,omitempty"`
+
+	// FailureThreshold - Tasa de fallos (0-1) que pausa el rollout
+	FailureThreshold float64 `json:"failure_threshold" dynamodbav:"failure_threshold"`
+
+	// MinSamples - Actualizaciones terminadas antes de evaluar FailureThreshold
+	MinSamples int `json:"min_samples" dynamodbav:"min_samples"`
+
+	Status      RolloutStatus `json:"status" dynamodbav:"status"`
+	PauseReason string        `json:"pause_reason,omitempty" dynamodbav:"pause_reason,omitempty"`
+
+	// ResumedAt - Última reanudación; el umbral de fallos solo cuenta lo terminado después
+	ResumedAt *time.Time `json:"resumed_at,omitempty" dynamodbav:"resumed_at,omitempty"`
+
+	// Progress - Conteo de actualizaciones por estado (calculado)
+	Progress *RolloutProgress `json:"progress,omitempty" dynamodbav:"-"`
+
+	CreatedAt   time.Time  `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" dynamodbav:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" dynamodbav:"completed_at,omitempty"`
+}
+
+// Targets retorna true si el dispositivo entra en el rollout.
+// Con porcentaje, cada dispositivo cae en un bucket 0-99 estable por rollout, así que
+// subir el porcentaje solo agrega dispositivos: nunca saca a los que ya se actualizaron.
+func (r *FirmwareRollout) Targets(deviceID string) bool {
+	if len(r.DeviceIDs) > 0 {
+		for _, id := range r.DeviceIDs {
+			if id == deviceID {
+				return true
+			}
+		}
+		return false
+	}
+	return rolloutBucket(r.RolloutID, deviceID) < r.Percentage
+}
+
+// IsFullFleet retorna true si el rollout ya no puede crecer (100% o grupo explícito).
+func (r *FirmwareRollout) IsFullFleet() bool {
+	return len(r.DeviceIDs) > 0 || r.Percentage >= 100
+}
+
+// rolloutBucket asigna al dispositivo un bucket estable entre 0 y 99.
+func rolloutBucket(rolloutID, deviceID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(rolloutID + "/" + deviceID))
+	return int(h.Sum32() % 100)
+}
+
+// Pause detiene el rollout.
+func (r *FirmwareRollout) Pause(reason string, now time.Time) error {
+	if r.Status != RolloutStatusActive {
+		return ErrInvalidTransition
+	}
+	r.Status = RolloutStatusPaused
+	r.PauseReason = reason
+	r.UpdatedAt = now
+	return nil
+}
+
+// Resume reactiva un rollout pausado.
+func (r *FirmwareRollout) Resume(now time.Time) error {
+	if r.Status != RolloutStatusPaused {
+		return ErrInvalidTransition
+	}
+	r.Status = RolloutStatusActive
+	r.PauseReason = ""
+	r.ResumedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// RolloutProgress resume el estado de las actualizaciones de un rollout.
+type RolloutProgress struct {
+	Targeted   int `json:"targeted"`
+	Pending    int `json:"pending"`
+	InProgress int `json:"in_progress"`
+	Succeeded  int `json:"succeeded"`
+	Failed     int `json:"failed"`
+
+	// FailureRate - Failed / (Succeeded + Failed)
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// NewRolloutProgress cuenta las actualizaciones por estado.
+func NewRolloutProgress(updates []*FirmwareUpdate) *RolloutProgress {
+	progress := &RolloutProgress{Targeted: len(updates)}
+	for _, update := range updates {
+		switch update.Status {
+		case FirmwareUpdatePending:
+			progress.Pending++
+		case FirmwareUpdateDownloading, FirmwareUpdateInstalling:
+			progress.InProgress++
+		case FirmwareUpdateSucceeded:
+			progress.Succeeded++
+		case FirmwareUpdateFailed:
+			progress.Failed++
+		}
+	}
+	if finished := progress.Finished(); finished > 0 {
+		progress.FailureRate = float64(progress.Failed) / float64(finished)
+	}
+	return progress
+}
+
+// Finished retorna las actualizaciones terminadas (con éxito o fallidas).
+func (p *RolloutProgress) Finished() int {
+	return p.Succeeded + p.Failed
+}
+
+// FailureWindow retorna el avance que cuenta para el umbral de fallos: las actualizaciones
+// terminadas desde la última reanudación. Así reanudar tras investigar no vuelve a pausar
+// el rollout por los mismos fallos.
+func (r *FirmwareRollout) FailureWindow(updates []*FirmwareUpdate) *RolloutProgress {
+	if r.ResumedAt == nil {
+		return NewRolloutProgress(updates)
+	}
+
+	recent := make([]*FirmwareUpdate, 0, len(updates))
+	for _, update := range updates {
+		if update.IsFinished() && update.UpdatedAt.After(*r.ResumedAt) {
+			recent = append(recent, update)
+		}
+	}
+	return NewRolloutProgress(recent)
+}
+
+// ExceedsFailureThreshold retorna true si el rollout debe pausarse.
+func (r *FirmwareRollout) ExceedsFailureThreshold(updates []*FirmwareUpdate) bool {
+	window := r.FailureWindow(updates)
+	return window.Finished() >= r.MinSamples && window.FailureRate > r.FailureThreshold
+}
+
+// CreateRolloutRequest - Request para iniciar un rollout.
+// Se indica Percentage o DeviceIDs, no ambos.
+type CreateRolloutRequest struct {
+	ReleaseID        string   `json:"release_id" binding:"required"`
+	Percentage       int      `json:"percentage,omitempty"`
+	DeviceIDs        []string `json:"device_ids,omitempty"`
+	FailureThreshold *float64 `json:"failure_threshold,omitempty"`
+	MinSamples       *int     `json:"min_samples,omitempty"`
+}
+
+// Validate valida el request.
+func (r *CreateRolloutRequest) Validate() error {
+	hasPercentage := r.Percentage != 0
+	hasDevices := len(r.DeviceIDs) > 0
+	if hasPercentage == hasDevices {
+		return fmt.Errorf("%w: set either percentage or device_ids", ErrInvalidRollout)
+	}
+	if hasPercentage && (r.Percentage < 1 || r.Percentage > 100) {
+		return fmt.Errorf("%w: percentage must be between 1 and 100", ErrInvalidRollout)
+	}
+	if r.FailureThreshold != nil && (*r.FailureThreshold < 0 || *r.FailureThreshold > 1) {
+		return fmt.Errorf("%w: failure_threshold must be between 0 and 1", ErrInvalidRollout)
+	}
+	if r.MinSamples != nil && *r.MinSamples < 1 {
+		return fmt.Errorf("%w: min_samples must be at least 1", ErrInvalidRollout)
+	}
+	return nil
+}
+
+// UpdateRolloutRequest - Request para ampliar un rollout por porcentaje.
+type UpdateRolloutRequest struct {
+	Percentage int `json:"percentage" binding:"required"`
+}
+
+// PauseRolloutRequest - Request opcional para pausar un rollout.
+type PauseRolloutRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListRolloutsRequest - Filtros para listar rollouts.
+type ListRolloutsRequest struct {
+	DeviceType string        `form:"device_type"`
+	Status     RolloutStatus `form:"status"`
+	Limit      int           `form:"limit,default=10"`
+	Offset     int           `form:"offset,default=0"`
+}
+
+// ListRolloutsResponse - Página de rollouts.
+type ListRolloutsResponse struct {
+	Rollouts []*FirmwareRollout `json:"rollouts"`
+	Total    int                `json:"total"`
+	Limit    int                `json:"limit"`
+	Offset   int                `json:"offset"`
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ACTUALIZACIÓN POR DISPOSITIVO
+// ═══════════════════════════════════════════════════════════════════
+
+// FirmwareUpdateStatus representa el avance de la actualización en un dispositivo.
+type FirmwareUpdateStatus string
+
+const (
+	// FirmwareUpdatePending - Seleccionado, el dispositivo aún no reportó avance.
+	FirmwareUpdatePending FirmwareUpdateStatus = "pending"
+
+	// FirmwareUpdateDownloading - Descargando el binario.
+	FirmwareUpdateDownloading FirmwareUpdateStatus = "downloading"
+
+	// FirmwareUpdateInstalling - Checksum verificado, instalando.
+	FirmwareUpdateInstalling FirmwareUpdateStatus = "installing"
+
+	// FirmwareUpdateSucceeded - Reinició con la nueva versión.
+	FirmwareUpdateSucceeded FirmwareUpdateStatus = "succeeded"
+
+	// FirmwareUpdateFailed - Falló la descarga, el checksum o la instalación.
+	FirmwareUpdateFailed FirmwareUpdateStatus = "failed"
+)
+
+// firmwareUpdateOrder ordena los estados; el avance nunca retrocede.
+var firmwareUpdateOrder = map[FirmwareUpdateStatus]int{
+	FirmwareUpdatePending:     0,
+	FirmwareUpdateDownloading: 1,
+	FirmwareUpdateInstalling:  2,
+	FirmwareUpdateSucceeded:   3,
+	FirmwareUpdateFailed:      3,
+}
+
+// FirmwareUpdate es la actualización de un dispositivo dentro de un rollout.
+type FirmwareUpdate struct {
+	RolloutID string               `json:"rollout_id" dynamodbav:"rollout_id"`
+	DeviceID  string               `json:"device_id" dynamodbav:"device_id"`
+	Version   string               `json:"version" dynamodbav:"version"`
+	Status    FirmwareUpdateStatus `json:"status" dynamodbav:"status"`
+	Error     string               `json:"error,omitempty" dynamodbav:"error,omitempty"`
+	CreatedAt time.Time            `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// IsFinished retorna true si la actualización terminó.
+func (u *FirmwareUpdate) IsFinished() bool {
+	return u.Status == FirmwareUpdateSucceeded || u.Status == FirmwareUpdateFailed
+}
+
+// Advance aplica el avance reportado por el dispositivo.
+// Un reporte repetido o atrasado (llega downloading después de installing) se ignora.
+func (u *FirmwareUpdate) Advance(status FirmwareUpdateStatus, reason string, now time.Time) (bool, error) {
+	if u.IsFinished() {
+		if status == u.Status {
+			return false, nil
+		}
+		return false, ErrInvalidTransition
+	}
+	if firmwareUpdateOrder[status] <= firmwareUpdateOrder[u.Status] {
+		return false, nil
+	}
+
+	u.Status = status
+	u.Error = reason
+	u.UpdatedAt = now
+	return true, nil
+}
+
+// FirmwareProgress es el avance reportado por un dispositivo (Data de un evento firmware_update).
+//
+//	{"rollout_id": "rollout_...", "status": "downloading|installing|succeeded|failed", "error": "..."}
+type FirmwareProgress struct {
+	RolloutID string
+	Status    FirmwareUpdateStatus
+	Error     string
+}
+
+// FirmwareProgressFromEvent extrae el avance de un evento firmware_update.
+func FirmwareProgressFromEvent(event *DeviceEvent) (*FirmwareProgress, error) {
+	progress := &FirmwareProgress{
+		RolloutID: event.GetString("rollout_id"),
+		Status:    FirmwareUpdateStatus(event.GetString("status")),
+		Error:     event.GetString("error"),
+	}
+
+	if progress.RolloutID == "" {
+		return nil, fmt.Errorf("%w: rollout_id", ErrMissingRequiredField)
+	}
+	switch progress.Status {
+	case FirmwareUpdateDownloading, FirmwareUpdateInstalling, FirmwareUpdateSucceeded, FirmwareUpdateFailed:
+	default:
+		return nil, fmt.Errorf("%w: firmware status %q", ErrInvalidStatus, progress.Status)
+	}
+
+	return progress, nil
+}
+
+// FirmwareUpdateMessage es el mensaje OTA que recibe el dispositivo.
+// Se publica en bins/{device_id}/ota.
+type FirmwareUpdateMessage struct {
+	RolloutID      string    `json:"rollout_id"`
+	DeviceID       string    `json:"device_id"`
+	Version        string    `json:"version"`
+	URL            string    `json:"url"`
+	ChecksumSHA256 string    `json:"checksum_sha256"`
+	SizeBytes      int64     `json:"size_bytes"`
+	URLExpiresAt   time.Time `json:"url_expires_at"`
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCreateFirmwareReleaseRequestValidate verifica versión, tipo de dispositivo y checksum.
+func TestCreateFirmwareReleaseRequestValidate(t *testing.T) {
+	checksum := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name    string
+		req     CreateFirmwareReleaseRequest
+		wantErr error
+	}{
+		{
+			name: "Valid release",
+			req:  CreateFirmwareReleaseRequest{Version: "2.1.0", DeviceType: "smart_bin_v2", ChecksumSHA256: checksum, SizeBytes: 1024},
+		},
+		{
+			name: "Pre-release version",
+			req:  CreateFirmwareReleaseRequest{Version: "2.1.0-rc.1", DeviceType: "smart_bin_v2", ChecksumSHA256: checksum, SizeBytes: 1024},
+		},
+		{
+			name:    "Version is not semver",
+			req:     CreateFirmwareReleaseRequest{Version: "v2", DeviceType: "smart_bin_v2", ChecksumSHA256: checksum, SizeBytes: 1024},
+			wantErr: ErrInvalidFirmware,
+		},
+		{
+			name:    "Unknown device type",
+			req:     CreateFirmwareReleaseRequest{Version: "2.1.0", DeviceType: "toaster", ChecksumSHA256: checksum, SizeBytes: 1024},
+			wantErr: ErrInvalidDeviceType,
+		},
+		{
+			name:    "Short checksum",
+			req:     CreateFirmwareReleaseRequest{Version: "2.1.0", DeviceType: "smart_bin_v2", ChecksumSHA256: "abcd", SizeBytes: 1024},
+			wantErr: ErrInvalidFirmware,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestFirmwareRolloutTargets verifica que subir el porcentaje solo agrega dispositivos.
+func TestFirmwareRolloutTargets(t *testing.T) {
+	rollout := &FirmwareRollout{RolloutID: "rollout_test", Percentage: 10}
+
+	selected := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		deviceID := fmt.Sprintf("smart-bin-%04d", i)
+		if rollout.Targets(deviceID) {
+			selected[deviceID] = true
+		}
+	}
+	if len(selected) < 50 || len(selected) > 150 {
+		t.Errorf("10%% rollout selected %d of 1000 devices", len(selected))
+	}
+
+	rollout.Percentage = 50
+	for deviceID := range selected {
+		if !rollout.Targets(deviceID) {
+			t.Fatalf("Device %s left the rollout when widening to 50%%", deviceID)
+		}
+	}
+
+	group := &FirmwareRollout{RolloutID: "rollout_group", DeviceIDs: []string{"smart-bin-0001"}}
+	if !group.Targets("smart-bin-0001") || group.Targets("smart-bin-0002") {
+		t.Error("Group rollout must target only its devices")
+	}
+}
+
+// TestFirmwareRolloutFailureThreshold verifica que el umbral espera MinSamples
+// y que al reanudar solo cuentan los fallos posteriores.
+func TestFirmwareRolloutFailureThreshold(t *testing.T) {
+	rollout := &FirmwareRollout{FailureThreshold: 0.2, MinSamples: 5}
+	updates := func(succeeded, failed int) []*FirmwareUpdate {
+		var list []*FirmwareUpdate
+		for i := 0; i < succeeded; i++ {
+			list = append(list, &FirmwareUpdate{Status: FirmwareUpdateSucceeded})
+		}
+		for i := 0; i < failed; i++ {
+			list = append(list, &FirmwareUpdate{Status: FirmwareUpdateFailed})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name              string
+		succeeded, failed int
+		want              bool
+	}{
+		{name: "Not enough samples", succeeded: 0, failed: 4, want: false},
+		{name: "At threshold", succeeded: 4, failed: 1, want: false},
+		{name: "Above threshold", succeeded: 3, failed: 2, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := updates(tt.succeeded, tt.failed)
+			if got := rollout.ExceedsFailureThreshold(list); got != tt.want {
+				t.Errorf("ExceedsFailureThreshold() = %v, want %v (rate %.2f)", got, tt.want, NewRolloutProgress(list).FailureRate)
+			}
+		})
+	}
+
+	resumedAt := time.Now()
+	rollout.ResumedAt = &resumedAt
+	if rollout.ExceedsFailureThreshold(updates(0, 5)) {
+		t.Error("Failures before resuming must not pause the rollout again")
+	}
+}
+
+// TestFirmwareUpdateAdvance verifica que el avance no retrocede.
+func TestFirmwareUpdateAdvance(t *testing.T) {
+	update := &FirmwareUpdate{Status: FirmwareUpdatePending}
+	now := time.Now()
+
+	steps := []struct {
+		status      FirmwareUpdateStatus
+		wantChanged bool
+		wantErr     error
+	}{
+		{status: FirmwareUpdateInstalling, wantChanged: true},
+		{status: FirmwareUpdateDownloading, wantChanged: false},
+		{status: FirmwareUpdateFailed, wantChanged: true},
+		{status: FirmwareUpdateFailed, wantChanged: false},
+		{status: FirmwareUpdateSucceeded, wantErr: ErrInvalidTransition},
+	}
+
+	for _, step := range steps {
+		changed, err := update.Advance(step.status, "", now)
+		if !errors.Is(err, step.wantErr) || changed != step.wantChanged {
+			t.Errorf("Advance(%s) = %v, %v, want %v, %v", step.status, changed, err, step.wantChanged, step.wantErr)
+		}
+	}
+}
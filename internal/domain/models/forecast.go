@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  FORECAST.GO - PRONÓSTICO DE LLENADO                           ║
+║                                                                ║
+║  Estimación de cuándo cada contenedor llegará al 100%.         ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// PredictFullAt calcula cuándo el contenedor llegará al 100% con la velocidad dada.
+// El llenado se mide desde LastReportedAt (o now si no hay reporte).
+// Retorna nil si no hay nivel de llenado o la velocidad no es positiva.
+func (d *Device) PredictFullAt(ratePerHour float64, now time.Time) *time.Time {
+	if d.FillLevel == nil {
+		return nil
+	}
+
+	measuredAt := now
+	if d.LastReportedAt != nil {
+		measuredAt = *d.LastReportedAt
+	}
+
+	if *d.FillLevel >= MaxPercentage {
+		return &measuredAt
+	}
+	if ratePerHour <= 0 {
+		return nil
+	}
+
+	remaining := float64(MaxPercentage-*d.FillLevel) / ratePerHour
+	fullAt := measuredAt.Add(time.Duration(remaining * float64(time.Hour)))
+	return &fullAt
+}
+
+// FillForecast es el pronóstico de llenado de un dispositivo.
+type FillForecast struct {
+	DeviceID   string    `json:"device_id"`
+	DeviceType string    `json:"device_type"`
+	BinType    string    `json:"bin_type,omitempty"`
+	Location   *Location `json:"location,omitempty"`
+
+	// FillLevel - Último nivel de llenado reportado (%)
+	FillLevel *int `json:"fill_level,omitempty"`
+
+	// FillRatePerHour - Velocidad de llenado estimada (% por hora)
+	FillRatePerHour *float64 `json:"fill_rate_per_hour,omitempty"`
+
+	// PredictedFullAt - Momento estimado de llenado (nil si no se puede estimar)
+	PredictedFullAt *time.Time `json:"predicted_full_at,omitempty"`
+
+	// HoursUntilFull - Horas restantes hasta el llenado (0 si ya está lleno o vencido)
+	HoursUntilFull *float64 `json:"hours_until_full,omitempty"`
+}
+
+// NewFillForecast construye el pronóstico a partir del estado guardado del dispositivo.
+func NewFillForecast(device *Device, now time.Time) *FillForecast {
+	forecast := &FillForecast{
+		DeviceID:        device.DeviceID,
+		DeviceType:      device.DeviceType,
+		BinType:         device.BinType,
+		Location:        device.Location,
+		FillLevel:       device.FillLevel,
+		FillRatePerHour: device.FillRatePerHour,
+		PredictedFullAt: device.PredictedFullAt,
+	}
+
+	if device.PredictedFullAt != nil {
+		hours := device.PredictedFullAt.Sub(now).Hours()
+		if hours < 0 {
+			hours = 0
+		}
+		forecast.HoursUntilFull = &hours
+	}
+
+	return forecast
+}
+
+// ListForecastRequest - Filtros para listar pronósticos.
+type ListForecastRequest struct {
+	// Within - Solo contenedores que se llenan dentro de esta ventana (ej: 24h)
+	Within time.Duration `form:"within"`
+
+	BinType string `form:"bin_type"`
+	Limit   int    `form:"limit,default=10"`
+	Offset  int    `form:"offset,default=0"`
+}
+
+// Validate rechaza una paginación negativa.
+func (r *ListForecastRequest) Validate() error {
+	if r.Limit < 0 || r.Offset < 0 {
+		return fmt.Errorf("%w: limit and offset cannot be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ListForecastResponse - Pronósticos ordenados por urgencia.
+type ListForecastResponse struct {
+	Forecasts []*FillForecast `json:"forecasts"`
+	Total     int             `json:"total"`
+	Limit     int             `json:"limit"`
+	Offset    int             `json:"offset"`
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  GEO.GO - UTILIDADES GEOESPACIALES                             ║
+║                                                                ║
+║  Puntos GPS y distancias entre ubicaciones de dispositivos.    ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// earthRadiusMeters - Radio medio de la Tierra usado por Haversine.
+const earthRadiusMeters = 6371000
+
+// GeoPoint es una coordenada GPS.
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Validate verifica que la coordenada esté dentro de los rangos válidos.
+func (p GeoPoint) Validate() error {
+	if p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// DistanceTo retorna la distancia en metros (Haversine) hasta other.
+func (p GeoPoint) DistanceTo(other GeoPoint) float64 {
+	lat1 := p.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (other.Longitude - p.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+// Point retorna las coordenadas de la ubicación.
+// Retorna false si la ubicación no tiene coordenadas GPS.
+func (l *Location) Point() (GeoPoint, bool) {
+	if l == nil || (l.Latitude == 0 && l.Longitude == 0) {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Latitude: l.Latitude, Longitude: l.Longitude}, true
+}
+
+// ParseGeoPoint interpreta "lat,lng".
+func ParseGeoPoint(value string) (GeoPoint, error) {
+	values, err := parseCoordinates(value, 2)
+	if err != nil {
+		return GeoPoint{}, err
+	}
+
+	point := GeoPoint{Latitude: values[0], Longitude: values[1]}
+	return point, point.Validate()
+}
+
+// parseCoordinates interpreta n números separados por coma.
+func parseCoordinates(value string, n int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("%w: expected %d comma-separated values", ErrInvalidCoordinates, n)
+	}
+
+	values := make([]float64, n)
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCoordinates, part)
+		}
+		values[i] = number
+	}
+	return values, nil
+}
+
+// GeoBounds es un rectángulo de coordenadas (no cruza el antimeridiano).
+type GeoBounds struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// Validate verifica que las esquinas sean válidas y estén ordenadas.
+func (b GeoBounds) Validate() error {
+	lower := GeoPoint{Latitude: b.MinLatitude, Longitude: b.MinLongitude}
+	upper := GeoPoint{Latitude: b.MaxLatitude, Longitude: b.MaxLongitude}
+	if lower.Validate() != nil || upper.Validate() != nil ||
+		b.MinLatitude > b.MaxLatitude || b.MinLongitude > b.MaxLongitude {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// ParseGeoBounds interpreta "min_lat,min_lng,max_lat,max_lng".
+func ParseGeoBounds(value string) (GeoBounds, error) {
+	values, err := parseCoordinates(value, 4)
+	if err != nil {
+		return GeoBounds{}, err
+	}
+
+	bounds := GeoBounds{
+		MinLatitude:  values[0],
+		MinLongitude: values[1],
+		MaxLatitude:  values[2],
+		MaxLongitude: values[3],
+	}
+	return bounds, bounds.Validate()
+}
+
+// Contains retorna true si el punto está dentro del rectángulo (bordes incluidos).
+func (b GeoBounds) Contains(p GeoPoint) bool {
+	return p.Latitude >= b.MinLatitude && p.Latitude <= b.MaxLatitude &&
+		p.Longitude >= b.MinLongitude && p.Longitude <= b.MaxLongitude
+}
+
+// Center retorna el centro del rectángulo.
+func (b GeoBounds) Center() GeoPoint {
+	return GeoPoint{
+		Latitude:  (b.MinLatitude + b.MaxLatitude) / 2,
+		Longitude: (b.MinLongitude + b.MaxLongitude) / 2,
+	}
+}
+
+// BoundsAround retorna el rectángulo que contiene el círculo de radio radiusMeters.
+func BoundsAround(center GeoPoint, radiusMeters float64) GeoBounds {
+	dLat := radiusMeters / earthRadiusMeters * 180 / math.Pi
+	dLng := 180.0
+	if cos := math.Cos(center.Latitude * math.Pi / 180); cos > 1e-9 {
+		dLng = math.Min(dLat/cos, 180)
+	}
+
+	return GeoBounds{
+		MinLatitude:  math.Max(center.Latitude-dLat, -90),
+		MinLongitude: math.Max(center.Longitude-dLng, -180),
+		MaxLatitude:  math.Min(center.Latitude+dLat, 90),
+		MaxLongitude: math.Min(center.Longitude+dLng, 180),
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     GEOHASH
+// ═══════════════════════════════════════════════════════════════════
+
+// GeohashPrecision - Precisión con la que se indexan los dispositivos (~150m x 150m).
+const GeohashPrecision = 7
+
+// geohashAlphabet - Alfabeto base32 de geohash.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash codifica el punto como geohash de la precisión dada.
+func EncodeGeohash(p GeoPoint, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+
+	hash := make([]byte, 0, precision)
+	bit, char, even := 0, 0, true
+	for len(hash) < precision {
+		// Los bits pares codifican longitud y los impares latitud
+		value, bounds := p.Latitude, &latRange
+		if even {
+			value, bounds = p.Longitude, &lngRange
+		}
+
+		mid := (bounds[0] + bounds[1]) / 2
+		char <<= 1
+		if value >= mid {
+			char |= 1
+			bounds[0] = mid
+		} else {
+			bounds[1] = mid
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			hash = append(hash, geohashAlphabet[char])
+			bit, char = 0, 0
+		}
+	}
+
+	return string(hash)
+}
+
+// geohashCellSize retorna el alto y ancho en grados de una celda de la precisión dada.
+func geohashCellSize(precision int) (float64, float64) {
+	bits := 5 * precision
+	latBits := bits / 2
+	lngBits := bits - latBits
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lngBits))
+}
+
+// GeohashCover retorna las celdas geohash que cubren el rectángulo.
+// Usa la precisión más fina (hasta GeohashPrecision) que no exceda maxCells celdas.
+func GeohashCover(b GeoBounds, maxCells int) []string {
+	for precision := GeohashPrecision; precision > 1; precision-- {
+		cellLat, cellLng := geohashCellSize(precision)
+		rows := int(math.Floor((b.MaxLatitude+90)/cellLat)-math.Floor((b.MinLatitude+90)/cellLat)) + 1
+		cols := int(math.Floor((b.MaxLongitude+180)/cellLng)-math.Floor((b.MinLongitude+180)/cellLng)) + 1
+		if rows*cols <= maxCells {
+			return geohashGrid(b, precision, rows, cols)
+		}
+	}
+
+	return geohashGrid(b, 1, 0, 0)
+}
+
+// geohashGrid enumera las celdas de la precisión dada que tocan el rectángulo.
+func geohashGrid(b GeoBounds, precision, rows, cols int) []string {
+	cellLat, cellLng := geohashCellSize(precision)
+	firstLat := (math.Floor((b.MinLatitude+90)/cellLat)+0.5)*cellLat - 90
+	firstLng := (math.Floor((b.MinLongitude+180)/cellLng)+0.5)*cellLng - 180
+
+	if rows == 0 {
+		rows = int(math.Ceil((b.MaxLatitude-firstLat)/cellLat)) + 1
+		cols = int(math.Ceil((b.MaxLongitude-firstLng)/cellLng)) + 1
+	}
+
+	seen := make(map[string]bool, rows*cols)
+	cells := make([]string, 0, rows*cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			center := GeoPoint{
+				Latitude:  math.Min(firstLat+float64(row)*cellLat, 90),
+				Longitude: math.Min(firstLng+float64(col)*cellLng, 180),
+			}
+			cell := EncodeGeohash(center, precision)
+			if !seen[cell] {
+				seen[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+
+	return cells
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEncodeGeohash verifica la codificación contra valores conocidos.
+func TestEncodeGeohash(t *testing.T) {
+	tests := []struct {
+		name      string
+		point     GeoPoint
+		precision int
+		want      string
+	}{
+		{"Jutland", GeoPoint{Latitude: 57.64911, Longitude: 10.40744}, 11, "u4pruydqqvj"},
+		{"Bogotá", GeoPoint{Latitude: 4.6097, Longitude: -74.0817}, 7, "d2g64pp"},
+		{"Origin", GeoPoint{}, 5, "s0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodeGeohash(tt.point, tt.precision); got != tt.want {
+				t.Errorf("EncodeGeohash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGeohashCoverContainsPoints verifica que las celdas de la cobertura incluyen los puntos del rectángulo.
+func TestGeohashCoverContainsPoints(t *testing.T) {
+	center := GeoPoint{Latitude: 4.6097, Longitude: -74.0817}
+	bounds := BoundsAround(center, 500)
+	cells := GeohashCover(bounds, 32)
+
+	if len(cells) == 0 || len(cells) > 32 {
+		t.Fatalf("GeohashCover() returned %d cells", len(cells))
+	}
+
+	corners := []GeoPoint{
+		center,
+		{Latitude: bounds.MinLatitude, Longitude: bounds.MinLongitude},
+		{Latitude: bounds.MaxLatitude, Longitude: bounds.MaxLongitude},
+		{Latitude: bounds.MinLatitude, Longitude: bounds.MaxLongitude},
+	}
+	for _, corner := range corners {
+		hash := EncodeGeohash(corner, GeohashPrecision)
+		covered := false
+		for _, cell := range cells {
+			if hash[:len(cell)] == cell {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			t.Errorf("Point %+v (%s) not covered by %v", corner, hash, cells)
+		}
+	}
+}
+
+// TestGeoPointDistanceTo verifica Haversine con una distancia conocida.
+func TestGeoPointDistanceTo(t *testing.T) {
+	// Un grado de latitud ≈ 111.19 km
+	a := GeoPoint{Latitude: 0, Longitude: 0}
+	b := GeoPoint{Latitude: 1, Longitude: 0}
+
+	if got := a.DistanceTo(b); math.Abs(got-111195) > 1 {
+		t.Errorf("DistanceTo() = %v, want ≈111195", got)
+	}
+}
+
+// TestParseGeoFilters verifica la interpretación de near, radius_m y bbox.
+func TestParseGeoFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ListDevicesRequest
+		wantErr bool
+	}{
+		{"No filters", ListDevicesRequest{}, false},
+		{"Near with default radius", ListDevicesRequest{Near: "4.6097,-74.0817"}, false},
+		{"Bounding box", ListDevicesRequest{BBox: "4.60,-74.09,4.62,-74.07"}, false},
+		{"Invalid latitude", ListDevicesRequest{Near: "94.6,-74.08"}, true},
+		{"Malformed near", ListDevicesRequest{Near: "4.6"}, true},
+		{"Inverted box", ListDevicesRequest{BBox: "4.62,-74.07,4.60,-74.09"}, true},
+		{"Radius without near", ListDevicesRequest{RadiusMeters: 100}, true},
+		{"Radius too large", ListDevicesRequest{Near: "4.6,-74.08", RadiusMeters: 100000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.ParseGeoFilters()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGeoFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.req.Near != "" && tt.req.RadiusMeters != DefaultSearchRadiusMeters {
+				t.Errorf("RadiusMeters = %v, want default %v", tt.req.RadiusMeters, DefaultSearchRadiusMeters)
+			}
+		})
+	}
+}
@@ -0,0 +1,429 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  GROUP.GO - TAGS Y GRUPOS DE DISPOSITIVOS                      ║
+║                                                                ║
+║  - Tags: pares key/value libres en cada dispositivo            ║
+║  - Grupo static: lista explícita de device_ids                 ║
+║  - Grupo dynamic: selector por tags, tipo y ubicación;         ║
+║    sus miembros se resuelven en cada consulta                  ║
+║                                                                ║
+║  Los grupos se usan para operaciones masivas (estado,          ║
+║  comandos, configuración), reglas de alerta y reportes.        ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+const (
+	// MaxDeviceTags - Tags máximos por dispositivo.
+	MaxDeviceTags = 50
+
+	// MaxTagValueLength - Largo máximo del valor de un tag.
+	MaxTagValueLength = 256
+
+	// MaxGroupDevices - Dispositivos máximos en un grupo static.
+	MaxGroupDevices = 1000
+
+	// MaxGroupNameLength - Largo máximo del nombre de un grupo.
+	MaxGroupNameLength = 128
+)
+
+// tagKeyPattern - Formato de las keys de tags (ej: "campus", "owner.team", "cost-center").
+var tagKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_./-]{0,62}$`)
+
+// ValidateTag valida una key y su valor.
+func ValidateTag(key, value string) error {
+	if !tagKeyPattern.MatchString(key) {
+		return fmt.Errorf("%w: invalid tag key %q", ErrInvalidInput, key)
+	}
+	if value == "" || len(value) > MaxTagValueLength {
+		return fmt.Errorf("%w: tag %q value must have 1 to %d characters", ErrInvalidInput, key, MaxTagValueLength)
+	}
+	return nil
+}
+
+// ValidateTags valida un conjunto de tags completo.
+func ValidateTags(tags map[string]string) error {
+	if len(tags) > MaxDeviceTags {
+		return fmt.Errorf("%w: at most %d tags per device", ErrInvalidInput, MaxDeviceTags)
+	}
+	for key, value := range tags {
+		if err := ValidateTag(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchesTags retorna true si el dispositivo tiene todos los tags del selector.
+// Un valor vacío en el selector solo exige que la key exista.
+func (d *Device) MatchesTags(selector map[string]string) bool {
+	for key, value := range selector {
+		current, ok := d.Tags[key]
+		if !ok || (value != "" && current != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTagFilters interpreta los filtros tag=key o tag=key:value de ListDevicesRequest.
+func ParseTagFilters(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	selector := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		key, value, _ := strings.Cut(filter, ":")
+		if !tagKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: invalid tag filter %q", ErrInvalidInput, filter)
+		}
+		selector[key] = value
+	}
+	return selector, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     GRUPOS
+// ═══════════════════════════════════════════════════════════════════
+
+// GroupType indica cómo se definen los miembros de un grupo.
+type GroupType string
+
+const (
+	// GroupTypeStatic - Miembros listados explícitamente.
+	GroupTypeStatic GroupType = "static"
+
+	// GroupTypeDynamic - Miembros resueltos con un selector.
+	GroupTypeDynamic GroupType = "dynamic"
+)
+
+// DeviceSelector define los miembros de un grupo dynamic. Todos los criterios presentes deben cumplirse.
+type DeviceSelector struct {
+	// Tags - Tags requeridos; un valor vacío solo exige la key
+	Tags map[string]string `json:"tags,omitempty" dynamodbav:"tags,omitempty"`
+
+	DeviceType string `json:"device_type,omitempty" dynamodbav:"device_type,omitempty"`
+	BinType    string `json:"bin_type,omitempty" dynamodbav:"bin_type,omitempty"`
+
+	Building string `json:"building,omitempty" dynamodbav:"building,omitempty"`
+	Floor    *int   `json:"floor,omitempty" dynamodbav:"floor,omitempty"`
+	Area     string `json:"area,omitempty" dynamodbav:"area,omitempty"`
+	Zone     string `json:"zone,omitempty" dynamodbav:"zone,omitempty"`
+}
+
+// Validate valida que el selector tenga al menos un criterio.
+func (s *DeviceSelector) Validate() error {
+	if len(s.Tags) == 0 && s.DeviceType == "" && s.BinType == "" &&
+		s.Building == "" && s.Floor == nil && s.Area == "" && s.Zone == "" {
+		return fmt.Errorf("%w: selector needs at least one criterion", ErrInvalidGroup)
+	}
+	for key := range s.Tags {
+		if !tagKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: invalid tag key %q", ErrInvalidGroup, key)
+		}
+	}
+	if s.DeviceType != "" && !DeviceType(s.DeviceType).IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidDeviceType, s.DeviceType)
+	}
+	if s.BinType != "" && !BinType(s.BinType).IsValid() {
+		return fmt.Errorf("%w: unknown bin_type %q", ErrInvalidGroup, s.BinType)
+	}
+	return nil
+}
+
+// Matches retorna true si el dispositivo cumple el selector.
+func (s *DeviceSelector) Matches(device *Device) bool {
+	if !device.MatchesTags(s.Tags) {
+		return false
+	}
+	if (s.DeviceType != "" && device.DeviceType != s.DeviceType) ||
+		(s.BinType != "" && device.BinType != s.BinType) {
+		return false
+	}
+
+	location := device.Location
+	if location == nil {
+		location = &Location{}
+	}
+	return (s.Building == "" || location.Building == s.Building) &&
+		(s.Floor == nil || location.Floor == *s.Floor) &&
+		(s.Area == "" || location.Area == s.Area) &&
+		(s.Zone == "" || location.Zone == s.Zone)
+}
+
+// DeviceGroup es un conjunto de dispositivos direccionable como uno solo.
+type DeviceGroup struct {
+	GroupID     string    `json:"group_id" dynamodbav:"group_id"`
+	OrgID       string    `json:"org_id,omitempty" dynamodbav:"org_id"`
+	Name        string    `json:"name" dynamodbav:"name"`
+	Description string    `json:"description,omitempty" dynamodbav:"description,omitempty"`
+	Type        GroupType `json:"type" dynamodbav:"type"`
+
+	// DeviceIDs - Miembros de un grupo static (ordenados)
+	DeviceIDs []string `json:"device_ids,omitempty" dynamodbav:"device_ids,omitempty"`
+
+	// Selector - Criterio de un grupo dynamic
+	Selector *DeviceSelector `json:"selector,omitempty" dynamodbav:"selector,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// Contains retorna true si el dispositivo pertenece al grupo.
+// Los dispositivos dados de baja o de otra organización no pertenecen al grupo.
+func (g *DeviceGroup) Contains(device *Device) bool {
+	if device.Status == DeviceStatusDecommissioned || device.OrgID != g.OrgID {
+		return false
+	}
+	if g.Type == GroupTypeDynamic {
+		return g.Selector != nil && g.Selector.Matches(device)
+	}
+
+	i := sort.SearchStrings(g.DeviceIDs, device.DeviceID)
+	return i < len(g.DeviceIDs) && g.DeviceIDs[i] == device.DeviceID
+}
+
+// SetDevices reemplaza los miembros de un grupo static (sin duplicados, ordenados).
+func (g *DeviceGroup) SetDevices(deviceIDs []string) error {
+	unique := make(map[string]struct{}, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		if deviceID == "" {
+			return fmt.Errorf("%w: empty device_id", ErrInvalidGroup)
+		}
+		unique[deviceID] = struct{}{}
+	}
+	if len(unique) > MaxGroupDevices {
+		return fmt.Errorf("%w: at most %d devices per group", ErrInvalidGroup, MaxGroupDevices)
+	}
+
+	g.DeviceIDs = make([]string, 0, len(unique))
+	for deviceID := range unique {
+		g.DeviceIDs = append(g.DeviceIDs, deviceID)
+	}
+	sort.Strings(g.DeviceIDs)
+	return nil
+}
+
+// Clone retorna una copia del grupo que no comparte miembros ni selector.
+func (g *DeviceGroup) Clone() *DeviceGroup {
+	clone := *g
+	if g.DeviceIDs != nil {
+		clone.DeviceIDs = append([]string(nil), g.DeviceIDs...)
+	}
+	if g.Selector != nil {
+		selector := *g.Selector
+		if g.Selector.Tags != nil {
+			selector.Tags = make(map[string]string, len(g.Selector.Tags))
+			for key, value := range g.Selector.Tags {
+				selector.Tags[key] = value
+			}
+		}
+		if g.Selector.Floor != nil {
+			floor := *g.Selector.Floor
+			selector.Floor = &floor
+		}
+		clone.Selector = &selector
+	}
+	return &clone
+}
+
+// CreateGroupRequest - Body para POST /groups.
+type CreateGroupRequest struct {
+	Name        string          `json:"name" binding:"required"`
+	Description string          `json:"description,omitempty"`
+	Type        GroupType       `json:"type" binding:"required"`
+	DeviceIDs   []string        `json:"device_ids,omitempty"`
+	Selector    *DeviceSelector `json:"selector,omitempty"`
+}
+
+// Validate valida que el tipo de grupo traiga su definición (y solo esa).
+func (r *CreateGroupRequest) Validate() error {
+	if len(r.Name) > MaxGroupNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidGroup, MaxGroupNameLength)
+	}
+
+	switch r.Type {
+	case GroupTypeStatic:
+		if r.Selector != nil {
+			return fmt.Errorf("%w: static groups list device_ids, not a selector", ErrInvalidGroup)
+		}
+	case GroupTypeDynamic:
+		if len(r.DeviceIDs) > 0 {
+			return fmt.Errorf("%w: dynamic groups use a selector, not device_ids", ErrInvalidGroup)
+		}
+		if r.Selector == nil {
+			return fmt.Errorf("%w: dynamic groups need a selector", ErrInvalidGroup)
+		}
+		return r.Selector.Validate()
+	default:
+		return fmt.Errorf("%w: unknown group type %q", ErrInvalidGroup, r.Type)
+	}
+	return nil
+}
+
+// UpdateGroupRequest - Body para PATCH /groups/:group_id. Los campos ausentes no cambian.
+type UpdateGroupRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+
+	// AddDevices, RemoveDevices - Solo grupos static
+	AddDevices    []string `json:"add_devices,omitempty"`
+	RemoveDevices []string `json:"remove_devices,omitempty"`
+
+	// Selector - Solo grupos dynamic; reemplaza el selector completo
+	Selector *DeviceSelector `json:"selector,omitempty"`
+}
+
+// ApplyTo aplica el request al grupo.
+func (r *UpdateGroupRequest) ApplyTo(group *DeviceGroup, at time.Time) error {
+	if r.Name != nil {
+		if *r.Name == "" || len(*r.Name) > MaxGroupNameLength {
+			return fmt.Errorf("%w: name must have 1 to %d characters", ErrInvalidGroup, MaxGroupNameLength)
+		}
+		group.Name = *r.Name
+	}
+	if r.Description != nil {
+		group.Description = *r.Description
+	}
+
+	if group.Type == GroupTypeDynamic {
+		if len(r.AddDevices) > 0 || len(r.RemoveDevices) > 0 {
+			return fmt.Errorf("%w: dynamic groups use a selector, not device_ids", ErrInvalidGroup)
+		}
+		if r.Selector != nil {
+			if err := r.Selector.Validate(); err != nil {
+				return err
+			}
+			group.Selector = r.Selector
+		}
+	} else {
+		if r.Selector != nil {
+			return fmt.Errorf("%w: static groups list device_ids, not a selector", ErrInvalidGroup)
+		}
+		if len(r.AddDevices) > 0 || len(r.RemoveDevices) > 0 {
+			removed := make(map[string]bool, len(r.RemoveDevices))
+			for _, deviceID := range r.RemoveDevices {
+				removed[deviceID] = true
+			}
+			members := make([]string, 0, len(group.DeviceIDs)+len(r.AddDevices))
+			for _, deviceIDs := range [][]string{group.DeviceIDs, r.AddDevices} {
+				for _, deviceID := range deviceIDs {
+					if !removed[deviceID] {
+						members = append(members, deviceID)
+					}
+				}
+			}
+			if err := group.SetDevices(members); err != nil {
+				return err
+			}
+		}
+	}
+
+	group.UpdatedAt = at
+	return nil
+}
+
+// ListGroupsRequest - Paginación de GET /groups.
+type ListGroupsRequest struct {
+	Limit  int `form:"limit,default=10"`
+	Offset int `form:"offset,default=0"`
+}
+
+// ListGroupsResponse - Página de grupos.
+type ListGroupsResponse struct {
+	Groups []*DeviceGroup `json:"groups"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// ListGroupDevicesRequest - Paginación de GET /groups/:group_id/devices.
+type ListGroupDevicesRequest struct {
+	Limit  int `form:"limit,default=10"`
+	Offset int `form:"offset,default=0"`
+}
+
+// Validate rechaza una paginación negativa.
+func (r *ListGroupDevicesRequest) Validate() error {
+	if r.Limit < 0 || r.Offset < 0 {
+		return fmt.Errorf("%w: limit and offset cannot be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     OPERACIONES MASIVAS
+// ═══════════════════════════════════════════════════════════════════
+
+// GroupOperation identifica la operación masiva.
+type GroupOperation string
+
+const (
+	// GroupOperationStatus - Cambio de estado de los miembros.
+	GroupOperationStatus GroupOperation = "status"
+
+	// GroupOperationCommand - Comando remoto a los miembros.
+	GroupOperationCommand GroupOperation = "command"
+
+	// GroupOperationConfiguration - Cambio del estado deseado (shadow) de los miembros.
+	GroupOperationConfiguration GroupOperation = "configuration"
+)
+
+// GroupOperationResult es el resultado de la operación en un miembro.
+type GroupOperationResult struct {
+	DeviceID string `json:"device_id"`
+
+	// Status - succeeded o failed
+	Status string `json:"status"`
+
+	// CommandID - Comando creado (solo operación command)
+	CommandID string `json:"command_id,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	// GroupResultSucceeded - La operación se aplicó al dispositivo.
+	GroupResultSucceeded = "succeeded"
+
+	// GroupResultFailed - La operación falló en el dispositivo (ver Error).
+	GroupResultFailed = "failed"
+)
+
+// GroupOperationResponse - Resultado por dispositivo de una operación masiva.
+// Los miembros son independientes: un fallo no detiene al resto.
+type GroupOperationResponse struct {
+	GroupID   string                  `json:"group_id"`
+	Operation GroupOperation          `json:"operation"`
+	Total     int                     `json:"total"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Results   []*GroupOperationResult `json:"results"`
+}
+
+// Record agrega el resultado de un miembro.
+func (r *GroupOperationResponse) Record(deviceID string, err error) *GroupOperationResult {
+	result := &GroupOperationResult{DeviceID: deviceID, Status: GroupResultSucceeded}
+	if err != nil {
+		result.Status = GroupResultFailed
+		result.Error = err.Error()
+		r.Failed++
+	} else {
+		r.Succeeded++
+	}
+	r.Total++
+	r.Results = append(r.Results, result)
+	return result
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDeviceGroupContains verifica la pertenencia a grupos static y dynamic.
+func TestDeviceGroupContains(t *testing.T) {
+	floor := 2
+	dynamic := &DeviceGroup{
+		Type: GroupTypeDynamic,
+		Selector: &DeviceSelector{
+			Tags:     map[string]string{"campus": "norte", "pilot": ""},
+			Building: "A",
+			Floor:    &floor,
+		},
+	}
+	static := &DeviceGroup{Type: GroupTypeStatic}
+	if err := static.SetDevices([]string{"bin-3", "bin-1", "bin-3"}); err != nil {
+		t.Fatalf("SetDevices() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		group  *DeviceGroup
+		device *Device
+		want   bool
+	}{
+		{
+			"Dynamic match",
+			dynamic,
+			&Device{Status: DeviceStatusActive, Tags: map[string]string{"campus": "norte", "pilot": "yes"}, Location: &Location{Building: "A", Floor: 2}},
+			true,
+		},
+		{
+			"Dynamic missing key",
+			dynamic,
+			&Device{Status: DeviceStatusActive, Tags: map[string]string{"campus": "norte"}, Location: &Location{Building: "A", Floor: 2}},
+			false,
+		},
+		{
+			"Dynamic other floor",
+			dynamic,
+			&Device{Status: DeviceStatusActive, Tags: map[string]string{"campus": "norte", "pilot": "yes"}, Location: &Location{Building: "A", Floor: 3}},
+			false,
+		},
+		{"Static member", static, &Device{DeviceID: "bin-3", Status: DeviceStatusMaintenance}, true},
+		{"Static non member", static, &Device{DeviceID: "bin-2", Status: DeviceStatusActive}, false},
+		{"Decommissioned member", static, &Device{DeviceID: "bin-1", Status: DeviceStatusDecommissioned}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.group.Contains(tt.device); got != tt.want {
+				t.Errorf("DeviceGroup.Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if len(static.DeviceIDs) != 2 || static.DeviceIDs[0] != "bin-1" {
+		t.Errorf("SetDevices() = %v, want [bin-1 bin-3]", static.DeviceIDs)
+	}
+}
+
+// TestGroupRequests verifica la validación de la definición y las actualizaciones de grupos.
+func TestGroupRequests(t *testing.T) {
+	invalid := []*CreateGroupRequest{
+		{Name: "x", Type: "smart"},
+		{Name: "x", Type: GroupTypeDynamic},
+		{Name: "x", Type: GroupTypeDynamic, Selector: &DeviceSelector{}},
+		{Name: "x", Type: GroupTypeDynamic, Selector: &DeviceSelector{Tags: map[string]string{"bad key": ""}}},
+		{Name: "x", Type: GroupTypeStatic, Selector: &DeviceSelector{Zone: "z"}},
+	}
+	for _, req := range invalid {
+		if err := req.Validate(); !errors.Is(err, ErrInvalidGroup) {
+			t.Errorf("Validate(%+v) error = %v, want ErrInvalidGroup", req, err)
+		}
+	}
+
+	group := &DeviceGroup{Type: GroupTypeStatic, DeviceIDs: []string{"bin-1", "bin-2"}}
+	update := &UpdateGroupRequest{AddDevices: []string{"bin-3"}, RemoveDevices: []string{"bin-1"}}
+	if err := update.ApplyTo(group, time.Now()); err != nil {
+		t.Fatalf("ApplyTo() error = %v", err)
+	}
+	if len(group.DeviceIDs) != 2 || group.DeviceIDs[0] != "bin-2" || group.DeviceIDs[1] != "bin-3" {
+		t.Errorf("Members = %v, want [bin-2 bin-3]", group.DeviceIDs)
+	}
+
+	selector := &UpdateGroupRequest{Selector: &DeviceSelector{Zone: "z"}}
+	if err := selector.ApplyTo(group, time.Now()); !errors.Is(err, ErrInvalidGroup) {
+		t.Errorf("Selector on static group error = %v, want ErrInvalidGroup", err)
+	}
+}
+
+// TestDeviceTags verifica la validación de tags, los filtros y el merge del PATCH.
+func TestDeviceTags(t *testing.T) {
+	selector, err := ParseTagFilters([]string{"campus:norte", "pilot"})
+	if err != nil {
+		t.Fatalf("ParseTagFilters() error = %v", err)
+	}
+	if selector["campus"] != "norte" || selector["pilot"] != "" {
+		t.Errorf("ParseTagFilters() = %v", selector)
+	}
+	if _, err := ParseTagFilters([]string{":norte"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ParseTagFilters(:norte) error = %v, want ErrInvalidInput", err)
+	}
+
+	device := &Device{Status: DeviceStatusActive, Tags: map[string]string{"campus": "sur", "pilot": "yes"}}
+	norte := "norte"
+	update := &UpdateDeviceRequest{Tags: map[string]*string{"campus": &norte, "pilot": nil}}
+	if err := update.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := device.ApplyUpdate(update, "cred_ops", time.Now()); err != nil {
+		t.Fatalf("ApplyUpdate() error = %v", err)
+	}
+	if len(device.Tags) != 1 || device.Tags["campus"] != "norte" {
+		t.Errorf("Tags = %v, want map[campus:norte]", device.Tags)
+	}
+
+	empty := ""
+	if err := (&UpdateDeviceRequest{Tags: map[string]*string{"campus": &empty}}).Validate(); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Empty tag value error = %v, want ErrInvalidInput", err)
+	}
+}
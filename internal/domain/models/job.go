@@ -3,6 +3,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -29,6 +31,7 @@ const (
 // Job represents a waste classification task that progresses through various states.
 type Job struct {
 	JobID               string                 `json:"job_id" dynamodbav:"job_id"`
+	OrgID               string                 `json:"org_id,omitempty" dynamodbav:"org_id"`
 	DeviceID            string                 `json:"device_id" dynamodbav:"device_id"`
 	Status              JobStatus              `json:"status" dynamodbav:"status"`
 	ErrorMessage        string                 `json:"error_message,omitempty" dynamodbav:"error_message,omitempty"`
@@ -36,6 +39,7 @@ type Job struct {
 	UploadURL           string                 `json:"upload_url,omitempty" dynamodbav:"-"`
 	Classification      *Classification        `json:"classification,omitempty" dynamodbav:"classification,omitempty"`
 	Decision            *Decision              `json:"decision,omitempty" dynamodbav:"decision,omitempty"`
+	Review              *JobReview             `json:"review,omitempty" dynamodbav:"review,omitempty"`
 	Metadata            map[string]interface{} `json:"metadata,omitempty" dynamodbav:"metadata,omitempty"`
 	CreatedAt           time.Time              `json:"created_at" dynamodbav:"created_at"`
 	UpdatedAt           time.Time              `json:"updated_at" dynamodbav:"updated_at"`
@@ -45,20 +49,46 @@ type Job struct {
 }
 
 // CreateJobRequest contains the parameters for creating a new classification job.
+// When ImageKey is set the image is already in the bucket and the job skips the upload step.
 type CreateJobRequest struct {
 	DeviceID  string                 `json:"device_id" binding:"required"`
 	Timestamp string                 `json:"timestamp" binding:"required"`
+	ImageKey  string                 `json:"image_key,omitempty"`
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// ImageKeyPrefix returns the folder of the device in the images bucket ("uploads/{device_id}/").
+func ImageKeyPrefix(deviceID string) string {
+	return "uploads/" + deviceID + "/"
+}
+
+// Validate checks that a pre-uploaded ImageKey belongs to the device's own folder, so a caller
+// cannot make the Classifier read another device's (or another tenant's) images.
+func (r *CreateJobRequest) Validate() error {
+	if r.DeviceID == "" || strings.Contains(r.DeviceID, "/") {
+		return fmt.Errorf("%w: invalid device_id", ErrInvalidInput)
+	}
+	if r.ImageKey == "" {
+		return nil
+	}
+
+	prefix := ImageKeyPrefix(r.DeviceID)
+	if !strings.HasPrefix(r.ImageKey, prefix) || len(r.ImageKey) == len(prefix) || strings.Contains(r.ImageKey, "..") {
+		return fmt.Errorf("%w: image_key must be an object under %s", ErrInvalidInput, prefix)
+	}
+	return nil
+}
+
 // CreateJobResponse contains the response data when creating a new job.
+// UploadURL and UploadExpiresAt are omitted when the image was already uploaded.
 type CreateJobResponse struct {
-	JobID           string    `json:"job_id"`
-	DeviceID        string    `json:"device_id"`
-	Status          JobStatus `json:"status"`
-	UploadURL       string    `json:"upload_url"`
-	UploadExpiresAt time.Time `json:"upload_expires_at"`
-	CreatedAt       time.Time `json:"created_at"`
+	JobID           string     `json:"job_id"`
+	DeviceID        string     `json:"device_id"`
+	Status          JobStatus  `json:"status"`
+	ImageKey        string     `json:"image_key"`
+	UploadURL       string     `json:"upload_url,omitempty"`
+	UploadExpiresAt *time.Time `json:"upload_expires_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 // GetJobResponse wraps the complete Job data for retrieval responses.
@@ -67,11 +97,13 @@ type GetJobResponse struct {
 }
 
 // ListJobsRequest contains filter parameters for listing jobs.
+// PendingReview only returns completed jobs whose decision still requires manual review.
 type ListJobsRequest struct {
-	DeviceID string    `form:"device_id"`
-	Status   JobStatus `form:"status"`
-	Limit    int       `form:"limit,default=10"`
-	Offset   int       `form:"offset,default=0"`
+	DeviceID      string    `form:"device_id"`
+	Status        JobStatus `form:"status"`
+	PendingReview bool      `form:"pending_review"`
+	Limit         int       `form:"limit,default=10"`
+	Offset        int       `form:"offset,default=0"`
 }
 
 // ListJobsResponse contains the paginated list of jobs.
@@ -90,6 +122,64 @@ type UpdateJobRequest struct {
 	ErrorMessage   *string         `json:"error_message,omitempty"`
 }
 
+// JobReview records how a reviewer resolved a manual review.
+type JobReview struct {
+	// OriginalDecision - Decisión manual_review del servicio de reglas
+	OriginalDecision *Decision `json:"original_decision" dynamodbav:"original_decision"`
+
+	ReviewedBy string    `json:"reviewed_by" dynamodbav:"reviewed_by"`
+	ReviewedAt time.Time `json:"reviewed_at" dynamodbav:"reviewed_at"`
+	Notes      string    `json:"notes,omitempty" dynamodbav:"notes,omitempty"`
+}
+
+// ResolveReviewRequest is the body of POST /jobs/:job_id/review.
+type ResolveReviewRequest struct {
+	// Action - accept o reject
+	Action DecisionAction `json:"action" binding:"required"`
+
+	// BinCompartment - Obligatorio si action=accept
+	BinCompartment string `json:"bin_compartment,omitempty"`
+
+	// Message - Mensaje para el usuario (por defecto uno genérico)
+	Message string `json:"message,omitempty"`
+
+	Notes string `json:"notes,omitempty"`
+}
+
+// Decision builds the reviewed decision that replaces the manual_review one.
+func (r *ResolveReviewRequest) Decision(original *Decision) (*Decision, error) {
+	if r.Action != DecisionActionAccept && r.Action != DecisionActionReject {
+		return nil, fmt.Errorf("%w: review action must be accept or reject", ErrInvalidAction)
+	}
+
+	decision := &Decision{
+		Action:                 string(r.Action),
+		BinCompartment:         r.BinCompartment,
+		Message:                r.Message,
+		ConfidenceThresholdMet: original.ConfidenceThresholdMet,
+		ConfidenceThreshold:    original.ConfidenceThreshold,
+		RuleApplied:            string(DecisionActionManualReview),
+		RuleVersion:            original.RuleVersion,
+		Reasons:                []string{"resolved by manual review"},
+	}
+	if decision.Message == "" {
+		decision.Message = "Reviewed: " + decision.Action
+	}
+	if decision.IsRejected() {
+		decision.BinCompartment = ""
+	}
+
+	if err := decision.Validate(); err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+// NeedsReview returns true if the job is completed and its decision still requires manual review.
+func (j *Job) NeedsReview() bool {
+	return j.Status == JobStatusCompleted && j.Decision != nil && j.Decision.RequiresManualReview()
+}
+
 // IsCompleted returns true if the job is in a terminal state (completed or failed).
 func (j *Job) IsCompleted() bool {
 	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -354,6 +355,37 @@ func TestJobValidate(t *testing.T) {
 	}
 }
 
+// TestCreateJobRequestValidate verifica que la imagen ya subida esté en la carpeta del dispositivo.
+func TestCreateJobRequestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageKey string
+		wantErr  bool
+	}{
+		{"Without image", "", false},
+		{"Own folder", "uploads/smart-bin-001/capture.jpg", false},
+		{"Own nested folder", "uploads/smart-bin-001/2026/01/capture.jpg", false},
+		{"Another device", "uploads/smart-bin-002/capture.jpg", true},
+		{"Device prefix", "uploads/smart-bin-0010/capture.jpg", true},
+		{"Path traversal", "uploads/smart-bin-001/../smart-bin-002/capture.jpg", true},
+		{"Folder only", "uploads/smart-bin-001/", true},
+		{"Other bucket path", "firmware/v2.bin", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateJobRequest{DeviceID: "smart-bin-001", Timestamp: "2026-01-21T10:30:00Z", ImageKey: tt.imageKey}
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateJobRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("CreateJobRequest.Validate() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
 // TestJobGetProcessingDuration verifica el cálculo de duración.
 func TestJobGetProcessingDuration(t *testing.T) {
 	now := time.Now()
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  ORGANIZATION.GO - ORGANIZACIONES (TENANTS)                    ║
+║                                                                ║
+║  Cada cliente (universidad, centro comercial) es una           ║
+║  organización. Dispositivos, jobs, grupos, alertas y           ║
+║  credenciales de API pertenecen a una sola organización y      ║
+║  solo son visibles para ella (ver Principal).                  ║
+║                                                                ║
+║  - Config por tenant: umbrales de alertas y branding           ║
+║  - Super-admin: vista cruzada de todas las organizaciones      ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// DefaultOrgID - Organización de los registros creados sin tenant (super-admin o sistema).
+const DefaultOrgID = "default"
+
+// orgIDPattern - Formato de los IDs de organización (ej: "uniandes", "mall-centro").
+var orgIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// colorPattern - Color hexadecimal "#RRGGBB".
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// Organization es un tenant del servicio.
+type Organization struct {
+	OrgID  string       `json:"org_id" dynamodbav:"org_id"`
+	Name   string       `json:"name" dynamodbav:"name"`
+	Config TenantConfig `json:"config" dynamodbav:"config"`
+
+	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// Clone retorna una copia de la organización que no comparte mapas.
+func (o *Organization) Clone() *Organization {
+	clone := *o
+	if o.Config.AlertThresholds != nil {
+		clone.Config.AlertThresholds = make(map[string]AlertThreshold, len(o.Config.AlertThresholds))
+		for ruleID, threshold := range o.Config.AlertThresholds {
+			clone.Config.AlertThresholds[ruleID] = threshold
+		}
+	}
+	return &clone
+}
+
+// TenantConfig es la configuración propia de una organización.
+type TenantConfig struct {
+	// AlertThresholds - Umbrales por rule_id que reemplazan a los de la regla global
+	AlertThresholds map[string]AlertThreshold `json:"alert_thresholds,omitempty" dynamodbav:"alert_thresholds,omitempty"`
+
+	Branding Branding `json:"branding" dynamodbav:"branding"`
+}
+
+// AlertThreshold reemplaza los umbrales de una regla de alerta para una organización.
+type AlertThreshold struct {
+	Threshold      float64 `json:"threshold" dynamodbav:"threshold"`
+	ClearThreshold float64 `json:"clear_threshold" dynamodbav:"clear_threshold"`
+}
+
+// ApplyTo retorna la regla con los umbrales de la organización.
+// La regla resultante debe seguir siendo válida (histéresis del lado "sano").
+func (t AlertThreshold) ApplyTo(rule AlertRule) (AlertRule, error) {
+	rule.Threshold = t.Threshold
+	rule.ClearThreshold = t.ClearThreshold
+	if err := rule.Validate(); err != nil {
+		return rule, fmt.Errorf("%w: thresholds for %s", err, rule.RuleID)
+	}
+	return rule, nil
+}
+
+// Branding - Datos de presentación de la organización para dashboards y reportes.
+type Branding struct {
+	DisplayName  string `json:"display_name,omitempty" dynamodbav:"display_name,omitempty"`
+	LogoURL      string `json:"logo_url,omitempty" dynamodbav:"logo_url,omitempty"`
+	PrimaryColor string `json:"primary_color,omitempty" dynamodbav:"primary_color,omitempty"`
+	SupportEmail string `json:"support_email,omitempty" dynamodbav:"support_email,omitempty"`
+}
+
+// Validate valida el branding.
+func (b *Branding) Validate() error {
+	if len(b.DisplayName) > MaxGroupNameLength {
+		return fmt.Errorf("%w: display_name exceeds %d characters", ErrInvalidInput, MaxGroupNameLength)
+	}
+	if b.LogoURL != "" {
+		parsed, err := url.Parse(b.LogoURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Errorf("%w: logo_url must be an https URL", ErrInvalidURL)
+		}
+	}
+	if b.PrimaryColor != "" && !colorPattern.MatchString(b.PrimaryColor) {
+		return fmt.Errorf("%w: primary_color must be #RRGGBB", ErrInvalidInput)
+	}
+	if b.SupportEmail != "" {
+		if _, err := mail.ParseAddress(b.SupportEmail); err != nil {
+			return fmt.Errorf("%w: invalid support_email", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// ValidateOrgID valida el formato de un ID de organización.
+func ValidateOrgID(orgID string) error {
+	if !orgIDPattern.MatchString(orgID) {
+		return fmt.Errorf("%w: org_id must be 2 to 63 lowercase letters, digits or '-'", ErrInvalidInput)
+	}
+	return nil
+}
+
+// CreateOrganizationRequest - Body para POST /admin/organizations.
+type CreateOrganizationRequest struct {
+	OrgID  string       `json:"org_id" binding:"required"`
+	Name   string       `json:"name" binding:"required"`
+	Config TenantConfig `json:"config"`
+}
+
+// Validate valida el ID y el branding. Los umbrales se validan contra las reglas de alerta.
+func (r *CreateOrganizationRequest) Validate() error {
+	if err := ValidateOrgID(r.OrgID); err != nil {
+		return err
+	}
+	if len(r.Name) > MaxGroupNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidInput, MaxGroupNameLength)
+	}
+	return r.Config.Branding.Validate()
+}
+
+// UpdateOrganizationRequest - Body para PATCH /organization y /admin/organizations/:org_id.
+// Los campos ausentes no cambian.
+type UpdateOrganizationRequest struct {
+	Name *string `json:"name,omitempty"`
+
+	// AlertThresholds - Merge por rule_id: un valor null vuelve al umbral global
+	AlertThresholds map[string]*AlertThreshold `json:"alert_thresholds,omitempty"`
+
+	// Branding - Reemplaza el branding completo
+	Branding *Branding `json:"branding,omitempty"`
+}
+
+// ApplyTo aplica el request a la organización.
+func (r *UpdateOrganizationRequest) ApplyTo(org *Organization, at time.Time) error {
+	if r.Name != nil {
+		if *r.Name == "" || len(*r.Name) > MaxGroupNameLength {
+			return fmt.Errorf("%w: name must have 1 to %d characters", ErrInvalidInput, MaxGroupNameLength)
+		}
+		org.Name = *r.Name
+	}
+	if r.Branding != nil {
+		if err := r.Branding.Validate(); err != nil {
+			return err
+		}
+		org.Config.Branding = *r.Branding
+	}
+	for ruleID, threshold := range r.AlertThresholds {
+		if threshold == nil {
+			delete(org.Config.AlertThresholds, ruleID)
+			continue
+		}
+		if org.Config.AlertThresholds == nil {
+			org.Config.AlertThresholds = make(map[string]AlertThreshold)
+		}
+		org.Config.AlertThresholds[ruleID] = *threshold
+	}
+
+	org.UpdatedAt = at
+	return nil
+}
+
+// ListOrganizationsRequest - Paginación de GET /admin/organizations.
+type ListOrganizationsRequest struct {
+	Limit  int `form:"limit,default=10"`
+	Offset int `form:"offset,default=0"`
+}
+
+// OrganizationOverview - Organización con sus totales, para la vista cruzada del super-admin.
+type OrganizationOverview struct {
+	*Organization
+
+	Devices           int `json:"devices"`
+	OnlineDevices     int `json:"online_devices"`
+	ActiveCredentials int `json:"active_credentials"`
+}
+
+// ListOrganizationsResponse - Página de organizaciones.
+type ListOrganizationsResponse struct {
+	Organizations []*OrganizationOverview `json:"organizations"`
+	Total         int                     `json:"total"`
+	Limit         int                     `json:"limit"`
+	Offset        int                     `json:"offset"`
+}
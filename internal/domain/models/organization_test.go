@@ -0,0 +1,119 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestBrandingValidate verifica las validaciones del branding.
+func TestBrandingValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		branding Branding
+		wantErr  error
+	}{
+		{"Empty", Branding{}, nil},
+		{"Complete", Branding{DisplayName: "Uniandes", LogoURL: "https://cdn.example.com/logo.png", PrimaryColor: "#FFD100", SupportEmail: "ops@example.com"}, nil},
+		{"HTTP logo", Branding{LogoURL: "http://cdn.example.com/logo.png"}, ErrInvalidURL},
+		{"Short color", Branding{PrimaryColor: "#FFF"}, ErrInvalidInput},
+		{"Invalid email", Branding{SupportEmail: "ops"}, ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.branding.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Validate() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateOrgID verifica el formato de los IDs de organización.
+func TestValidateOrgID(t *testing.T) {
+	for orgID, valid := range map[string]bool{
+		"uniandes":    true,
+		"mall-centro": true,
+		"a":           false,
+		"Uniandes":    false,
+		"-uniandes":   false,
+		"uni_andes":   false,
+	} {
+		if err := ValidateOrgID(orgID); (err == nil) != valid {
+			t.Errorf("ValidateOrgID(%q) error = %v, want valid %v", orgID, err, valid)
+		}
+	}
+}
+
+// TestAlertThresholdApplyTo verifica que los umbrales del tenant mantengan la histéresis de la regla.
+func TestAlertThresholdApplyTo(t *testing.T) {
+	rule := DefaultAlertRules()[1] // fill_high: above 90, clear 80
+
+	tenantRule, err := AlertThreshold{Threshold: 85, ClearThreshold: 70}.ApplyTo(rule)
+	if err != nil {
+		t.Fatalf("ApplyTo() error = %v", err)
+	}
+	if tenantRule.Threshold != 85 || tenantRule.ClearThreshold != 70 || tenantRule.Metric != rule.Metric {
+		t.Errorf("ApplyTo() = %+v", tenantRule)
+	}
+
+	// Para "above" el clear debe quedar por debajo del umbral
+	if _, err := (AlertThreshold{Threshold: 85, ClearThreshold: 95}).ApplyTo(rule); !errors.Is(err, ErrInvalidAlertRule) {
+		t.Errorf("ApplyTo(clear above threshold) error = %v, want ErrInvalidAlertRule", err)
+	}
+}
+
+// TestUpdateOrganizationRequestApplyTo verifica el merge de umbrales y el reemplazo del branding.
+func TestUpdateOrganizationRequestApplyTo(t *testing.T) {
+	org := &Organization{
+		OrgID: "uniandes",
+		Name:  "Uniandes",
+		Config: TenantConfig{
+			AlertThresholds: map[string]AlertThreshold{
+				"fill_high":   {Threshold: 85, ClearThreshold: 70},
+				"battery_low": {Threshold: 30, ClearThreshold: 35},
+			},
+			Branding: Branding{DisplayName: "Uniandes", PrimaryColor: "#FFD100"},
+		},
+	}
+	clone := org.Clone()
+
+	name := "Universidad de los Andes"
+	at := time.Now()
+	req := &UpdateOrganizationRequest{
+		Name: &name,
+		AlertThresholds: map[string]*AlertThreshold{
+			"battery_low": nil,
+			"errors_high": {Threshold: 5, ClearThreshold: 5},
+		},
+		Branding: &Branding{DisplayName: "Los Andes"},
+	}
+	if err := req.ApplyTo(org, at); err != nil {
+		t.Fatalf("ApplyTo() error = %v", err)
+	}
+
+	if org.Name != name || !org.UpdatedAt.Equal(at) {
+		t.Errorf("Name/UpdatedAt = %q %v", org.Name, org.UpdatedAt)
+	}
+	if _, ok := org.Config.AlertThresholds["battery_low"]; ok {
+		t.Error("battery_low threshold should be removed")
+	}
+	if len(org.Config.AlertThresholds) != 2 || org.Config.AlertThresholds["errors_high"].Threshold != 5 {
+		t.Errorf("AlertThresholds = %v", org.Config.AlertThresholds)
+	}
+	if org.Config.Branding.PrimaryColor != "" || org.Config.Branding.DisplayName != "Los Andes" {
+		t.Errorf("Branding = %+v, want replaced", org.Config.Branding)
+	}
+	if len(clone.Config.AlertThresholds) != 2 || clone.Config.AlertThresholds["battery_low"].Threshold != 30 {
+		t.Errorf("Clone shares thresholds: %v", clone.Config.AlertThresholds)
+	}
+
+	empty := ""
+	if err := (&UpdateOrganizationRequest{Name: &empty}).ApplyTo(org, at); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ApplyTo(empty name) error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,261 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  PROVISIONING.GO - IMPORTACIÓN MASIVA Y CLAIM TOKENS           ║
+║                                                                ║
+║  1. Se importa un manifiesto (CSV o JSON) con los equipos      ║
+║  2. Cada fila crea un dispositivo en estado provisioning       ║
+║     con un claim token de un solo uso                          ║
+║  3. En el primer arranque el equipo canjea su token por el     ║
+║     certificado (POST /devices/claim) y pasa a active          ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// MaxImportRows - Filas máximas por manifiesto.
+const MaxImportRows = 1000
+
+// deviceIDUnsafe - Caracteres que no pueden ir en un device_id derivado del serial.
+var deviceIDUnsafe = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ProvisionDeviceRow es una fila del manifiesto de importación.
+type ProvisionDeviceRow struct {
+	// DeviceID - Opcional; por defecto se deriva del serial ("smart-bin-<serial>")
+	DeviceID string `json:"device_id,omitempty"`
+
+	SerialNumber string    `json:"serial_number"`
+	DeviceType   string    `json:"device_type"`
+	BinType      string    `json:"bin_type,omitempty"`
+	Location     *Location `json:"location,omitempty"`
+	Capacity     int       `json:"capacity,omitempty"`
+}
+
+// Normalize completa el device_id derivado del serial.
+func (r *ProvisionDeviceRow) Normalize() {
+	r.SerialNumber = strings.TrimSpace(r.SerialNumber)
+	r.DeviceID = strings.TrimSpace(r.DeviceID)
+	if r.DeviceID == "" && r.SerialNumber != "" {
+		r.DeviceID = "smart-bin-" + strings.Trim(deviceIDUnsafe.ReplaceAllString(strings.ToLower(r.SerialNumber), "-"), "-")
+	}
+}
+
+// Validate valida la fila (después de Normalize).
+func (r *ProvisionDeviceRow) Validate() error {
+	if r.SerialNumber == "" {
+		return fmt.Errorf("%w: serial_number", ErrMissingRequiredField)
+	}
+	if r.DeviceID == "" || r.DeviceID == "smart-bin-" {
+		return ErrInvalidDeviceID
+	}
+	if !DeviceType(r.DeviceType).IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidDeviceType, r.DeviceType)
+	}
+	if r.BinType != "" && !BinType(r.BinType).IsValid() {
+		return fmt.Errorf("%w: unknown bin_type %q", ErrInvalidInput, r.BinType)
+	}
+	if r.Capacity < 0 {
+		return fmt.Errorf("%w: capacity cannot be negative", ErrInvalidInput)
+	}
+	if r.Location != nil {
+		point := GeoPoint{Latitude: r.Location.Latitude, Longitude: r.Location.Longitude}
+		if err := point.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportDevicesRequest - Manifiesto JSON para POST /devices/import.
+type ImportDevicesRequest struct {
+	Devices []ProvisionDeviceRow `json:"devices"`
+}
+
+// Validate valida el tamaño del manifiesto; las filas se validan una a una en el reporte.
+func (r *ImportDevicesRequest) Validate() error {
+	if len(r.Devices) == 0 {
+		return fmt.Errorf("%w: manifest has no devices", ErrInvalidInput)
+	}
+	if len(r.Devices) > MaxImportRows {
+		return fmt.Errorf("%w: manifest has %d devices, max %d", ErrInvalidInput, len(r.Devices), MaxImportRows)
+	}
+	return nil
+}
+
+// ImportRowStatus es el resultado de una fila.
+type ImportRowStatus string
+
+const (
+	// ImportRowCreated - Dispositivo creado en provisioning.
+	ImportRowCreated ImportRowStatus = "created"
+
+	// ImportRowFailed - Fila rechazada (ver Error).
+	ImportRowFailed ImportRowStatus = "failed"
+)
+
+// ImportRowResult es el resultado de una fila del manifiesto.
+type ImportRowResult struct {
+	// Row - Número de fila (1 = primer dispositivo, sin contar el header del CSV)
+	Row          int             `json:"row"`
+	DeviceID     string          `json:"device_id,omitempty"`
+	SerialNumber string          `json:"serial_number,omitempty"`
+	Status       ImportRowStatus `json:"status"`
+
+	// ClaimToken - Solo en filas creadas; no se vuelve a mostrar
+	ClaimToken          string     `json:"claim_token,omitempty"`
+	ClaimTokenExpiresAt *time.Time `json:"claim_token_expires_at,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// ImportDevicesResponse - Reporte por fila de la importación.
+type ImportDevicesResponse struct {
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Results []*ImportRowResult `json:"results"`
+}
+
+// ClaimDeviceRequest - Body para POST /devices/claim.
+type ClaimDeviceRequest struct {
+	ClaimToken string `json:"claim_token" binding:"required"`
+
+	// CSR - Opcional; sin CSR se genera el par de llaves
+	CSR string `json:"csr,omitempty"`
+}
+
+// ClaimTokenDeviceID extrae el device_id de un claim token ("<device_id>.<secreto>").
+func ClaimTokenDeviceID(token string) (string, error) {
+	// El secreto es base64url (sin puntos); el device_id sí puede tenerlos
+	i := strings.LastIndex(token, ".")
+	if i <= 0 || i == len(token)-1 {
+		return "", ErrInvalidClaimToken
+	}
+	return token[:i], nil
+}
+
+// provisioningColumns - Columnas reconocidas en el CSV (el orden lo define el header).
+var provisioningColumns = map[string]bool{
+	"device_id": true, "serial_number": true, "device_type": true, "bin_type": true, "capacity": true,
+	"building": true, "floor": true, "area": true, "zone": true, "latitude": true, "longitude": true,
+}
+
+// ParseProvisioningCSV lee un manifiesto CSV con header.
+// Requiere serial_number y device_type; las columnas de ubicación son opcionales.
+// Un valor numérico inválido rechaza el archivo indicando la línea.
+func ParseProvisioningCSV(r io.Reader) ([]ProvisionDeviceRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: empty manifest", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !provisioningColumns[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidInput, name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"serial_number", "device_type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidInput, required)
+		}
+	}
+
+	var rows []ProvisionDeviceRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		if len(rows) == MaxImportRows {
+			return nil, fmt.Errorf("%w: manifest has more than %d devices", ErrInvalidInput, MaxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row, err := parseProvisioningRecord(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidInput, line, err)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: manifest has no devices", ErrInvalidInput)
+	}
+	return rows, nil
+}
+
+// parseProvisioningRecord convierte un registro del CSV en una fila.
+func parseProvisioningRecord(record []string, columns map[string]int) (ProvisionDeviceRow, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	row := ProvisionDeviceRow{
+		DeviceID:     field("device_id"),
+		SerialNumber: field("serial_number"),
+		DeviceType:   field("device_type"),
+		BinType:      field("bin_type"),
+	}
+
+	if value := field("capacity"); value != "" {
+		capacity, err := strconv.Atoi(value)
+		if err != nil {
+			return row, fmt.Errorf("invalid capacity %q", value)
+		}
+		row.Capacity = capacity
+	}
+
+	location := Location{
+		Building: field("building"),
+		Area:     field("area"),
+		Zone:     field("zone"),
+	}
+	if value := field("floor"); value != "" {
+		floor, err := strconv.Atoi(value)
+		if err != nil {
+			return row, fmt.Errorf("invalid floor %q", value)
+		}
+		location.Floor = floor
+	}
+	for name, target := range map[string]*float64{"latitude": &location.Latitude, "longitude": &location.Longitude} {
+		if value := field(name); value != "" {
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return row, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = number
+		}
+	}
+	if location != (Location{}) {
+		row.Location = &location
+	}
+
+	return row, nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestParseProvisioningCSV verifica el mapeo por header y los errores con número de línea.
+func TestParseProvisioningCSV(t *testing.T) {
+	manifest := "serial_number,device_type,bin_type,latitude,longitude,floor\n" +
+		"SB2-0001,smart_bin_v2,recyclable,4.6097,-74.0817,2\n" +
+		"SB2 0002,smart_bin_v2,,,,\n"
+
+	rows, err := ParseProvisioningCSV(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("ParseProvisioningCSV() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Rows = %d, want 2", len(rows))
+	}
+	if rows[0].Location == nil || rows[0].Location.Floor != 2 || rows[0].Location.Latitude != 4.6097 {
+		t.Errorf("Row 1 location = %+v", rows[0].Location)
+	}
+	if rows[1].Location != nil {
+		t.Errorf("Row 2 location = %+v, want nil", rows[1].Location)
+	}
+
+	rows[1].Normalize()
+	if rows[1].DeviceID != "smart-bin-sb2-0002" {
+		t.Errorf("Derived device_id = %s, want smart-bin-sb2-0002", rows[1].DeviceID)
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		wantMsg  string
+	}{
+		{name: "Missing required column", manifest: "serial_number\nSB2-0001\n", wantMsg: "device_type"},
+		{name: "Unknown column", manifest: "serial_number,device_type,color\nSB2-0001,smart_bin_v2,red\n", wantMsg: "color"},
+		{name: "Invalid number", manifest: "serial_number,device_type,floor\nSB2-0001,smart_bin_v2,2\nSB2-0002,smart_bin_v2,two\n", wantMsg: "line 3"},
+		{name: "Header only", manifest: "serial_number,device_type\n", wantMsg: "no devices"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProvisioningCSV(strings.NewReader(tt.manifest))
+			if !errors.Is(err, ErrInvalidInput) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("ParseProvisioningCSV() error = %v, want %v mentioning %q", err, ErrInvalidInput, tt.wantMsg)
+			}
+		})
+	}
+}
+
+// TestClaimTokenDeviceID verifica que el device_id se extrae aunque tenga puntos.
+func TestClaimTokenDeviceID(t *testing.T) {
+	if deviceID, err := ClaimTokenDeviceID("bin.floor-2.abcDEF_123"); err != nil || deviceID != "bin.floor-2" {
+		t.Errorf("ClaimTokenDeviceID() = %q, %v", deviceID, err)
+	}
+	for _, token := range []string{"", "no-secret", ".secret", "device."} {
+		if _, err := ClaimTokenDeviceID(token); !errors.Is(err, ErrInvalidClaimToken) {
+			t.Errorf("ClaimTokenDeviceID(%q) error = %v, want %v", token, err, ErrInvalidClaimToken)
+		}
+	}
+}
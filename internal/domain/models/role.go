@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  ROLE.GO - ROLES Y PERMISOS (RBAC)                             ║
+║                                                                ║
+║  Cada credencial de API tiene uno o más roles; cada ruta       ║
+║  exige un permiso (ver middleware.RequirePermission).          ║
+║                                                                ║
+║  - device:     crear jobs y publicar eventos                   ║
+║  - reviewer:   consultar y resolver revisiones manuales        ║
+║  - operator:   consultar y gestionar dispositivos y la flota   ║
+║  - classifier: reportar el resultado de los jobs               ║
+║  - admin:      todo lo anterior y la organización (config,     ║
+║                credenciales, roles y auditoría)                ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// Role representa un rol asignable a una credencial.
+type Role string
+
+const (
+	// RoleDevice - Dispositivo (o gateway) que crea jobs y publica eventos.
+	RoleDevice Role = "device"
+
+	// RoleReviewer - Revisor de las clasificaciones que requieren revisión manual.
+	RoleReviewer Role = "reviewer"
+
+	// RoleOperator - Operador de la flota de dispositivos.
+	RoleOperator Role = "operator"
+
+	// RoleClassifier - Servicio de clasificación que reporta el resultado de los jobs.
+	RoleClassifier Role = "classifier"
+
+	// RoleAdmin - Administrador de la organización.
+	RoleAdmin Role = "admin"
+)
+
+// Permission representa una acción protegida.
+type Permission string
+
+const (
+	// PermissionRead - Consultar dispositivos, grupos, alertas, estadísticas y la organización.
+	PermissionRead Permission = "read"
+
+	// PermissionJobsRead - Consultar jobs de clasificación.
+	PermissionJobsRead Permission = "jobs:read"
+
+	// PermissionJobsCreate - Crear jobs de clasificación.
+	PermissionJobsCreate Permission = "jobs:create"
+
+	// PermissionJobsComplete - Completar o fallar jobs (callback del servicio de clasificación).
+	PermissionJobsComplete Permission = "jobs:complete"
+
+	// PermissionEventsPost - Publicar eventos de dispositivo.
+	PermissionEventsPost Permission = "events:post"
+
+	// PermissionReviewsResolve - Resolver las revisiones manuales de jobs.
+	PermissionReviewsResolve Permission = "reviews:resolve"
+
+	// PermissionDevicesManage - Registrar, modificar y operar dispositivos, grupos y alertas.
+	PermissionDevicesManage Permission = "devices:manage"
+
+	// PermissionOrganizationManage - Configurar la organización, sus credenciales y sus roles.
+	PermissionOrganizationManage Permission = "organization:manage"
+
+	// PermissionAuditRead - Consultar y exportar el registro de auditoría.
+	PermissionAuditRead Permission = "audit:read"
+)
+
+// rolePermissions - Permisos de cada rol.
+var rolePermissions = map[Role][]Permission{
+	RoleDevice:     {PermissionJobsCreate, PermissionJobsRead, PermissionEventsPost},
+	RoleReviewer:   {PermissionRead, PermissionJobsRead, PermissionReviewsResolve},
+	RoleOperator:   {PermissionRead, PermissionJobsRead, PermissionDevicesManage},
+	RoleClassifier: {PermissionJobsComplete},
+	RoleAdmin: {
+		PermissionRead, PermissionJobsRead, PermissionJobsCreate, PermissionJobsComplete, PermissionEventsPost,
+		PermissionReviewsResolve, PermissionDevicesManage, PermissionOrganizationManage,
+		PermissionAuditRead,
+	},
+}
+
+// Permissions retorna los permisos del rol.
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// IsValid retorna true si el rol existe.
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// NormalizeRoles valida los roles y los retorna ordenados y sin duplicados.
+func NormalizeRoles(roles []Role) ([]Role, error) {
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("%w: at least one role is required", ErrInvalidInput)
+	}
+
+	seen := make(map[Role]bool, len(roles))
+	normalized := make([]Role, 0, len(roles))
+	for _, role := range roles {
+		if !role.IsValid() {
+			return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+		}
+		if !seen[role] {
+			seen[role] = true
+			normalized = append(normalized, role)
+		}
+	}
+
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] < normalized[j] })
+	return normalized, nil
+}
+
+// Can retorna true si el principal tiene el permiso. El super-admin tiene todos.
+func (p *Principal) Can(permission Permission) bool {
+	if p.SuperAdmin {
+		return true
+	}
+	for _, role := range p.Roles {
+		for _, granted := range role.Permissions() {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AssignRolesRequest - Body para PUT /organization/credentials/:credential_id/roles.
+// Reemplaza los roles de la credencial.
+type AssignRolesRequest struct {
+	Roles []Role `json:"roles" binding:"required"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestNormalizeRoles verifica la validación y normalización de roles.
+func TestNormalizeRoles(t *testing.T) {
+	roles, err := NormalizeRoles([]Role{RoleReviewer, RoleDevice, RoleReviewer})
+	if err != nil {
+		t.Fatalf("NormalizeRoles() error = %v", err)
+	}
+	if !reflect.DeepEqual(roles, []Role{RoleDevice, RoleReviewer}) {
+		t.Errorf("NormalizeRoles() = %v, want [device reviewer]", roles)
+	}
+
+	if _, err := NormalizeRoles(nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("NormalizeRoles(empty) error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := NormalizeRoles([]Role{"root"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("NormalizeRoles(unknown) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+// TestPrincipalCan verifica los permisos de cada rol.
+func TestPrincipalCan(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  Principal
+		permission Permission
+		want       bool
+	}{
+		{"Device creates jobs", Principal{Roles: []Role{RoleDevice}}, PermissionJobsCreate, true},
+		{"Device posts events", Principal{Roles: []Role{RoleDevice}}, PermissionEventsPost, true},
+		{"Device cannot list devices", Principal{Roles: []Role{RoleDevice}}, PermissionRead, false},
+		{"Reviewer resolves reviews", Principal{Roles: []Role{RoleReviewer}}, PermissionReviewsResolve, true},
+		{"Reviewer cannot manage devices", Principal{Roles: []Role{RoleReviewer}}, PermissionDevicesManage, false},
+		{"Operator manages devices", Principal{Roles: []Role{RoleOperator}}, PermissionDevicesManage, true},
+		{"Operator cannot resolve reviews", Principal{Roles: []Role{RoleOperator}}, PermissionReviewsResolve, false},
+		{"Operator cannot manage organization", Principal{Roles: []Role{RoleOperator}}, PermissionOrganizationManage, false},
+		{"Classifier completes jobs", Principal{Roles: []Role{RoleClassifier}}, PermissionJobsComplete, true},
+		{"Classifier cannot read jobs", Principal{Roles: []Role{RoleClassifier}}, PermissionJobsRead, false},
+		{"Device cannot complete jobs", Principal{Roles: []Role{RoleDevice}}, PermissionJobsComplete, false},
+		{"Combined roles", Principal{Roles: []Role{RoleOperator, RoleReviewer}}, PermissionReviewsResolve, true},
+		{"Admin manages organization", Principal{Roles: []Role{RoleAdmin}}, PermissionOrganizationManage, true},
+		{"Admin reads audit log", Principal{Roles: []Role{RoleAdmin}}, PermissionAuditRead, true},
+		{"Operator cannot read audit log", Principal{Roles: []Role{RoleOperator}}, PermissionAuditRead, false},
+		{"No roles", Principal{}, PermissionRead, false},
+		{"Super-admin", Principal{SuperAdmin: true}, PermissionOrganizationManage, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.Can(tt.permission); got != tt.want {
+				t.Errorf("Principal.Can(%s) = %v, want %v", tt.permission, got, tt.want)
+			}
+		})
+	}
+}
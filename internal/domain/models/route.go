@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  ROUTE.GO - RUTAS DE RECOLECCIÓN                               ║
+║                                                                ║
+║  Orden de visita de los contenedores que necesitan vaciarse.   ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// DefaultRouteFillThreshold - Nivel de llenado (%) desde el que un contenedor entra en la ruta.
+const DefaultRouteFillThreshold = 80
+
+// PlanRouteRequest - Request para planear una ruta de recolección.
+type PlanRouteRequest struct {
+	// Start - Punto de partida del recolector
+	Start *GeoPoint `json:"start" binding:"required"`
+
+	// FillThreshold - Nivel mínimo de llenado (%) para incluir un contenedor (default: 80)
+	FillThreshold *int `json:"fill_threshold,omitempty"`
+
+	// BinType - Solo contenedores de este tipo (opcional)
+	BinType string `json:"bin_type,omitempty"`
+
+	// Building - Solo contenedores de este edificio (opcional)
+	Building string `json:"building,omitempty"`
+}
+
+// Validate valida el request y aplica los valores por defecto.
+func (r *PlanRouteRequest) Validate() error {
+	if r.Start == nil {
+		return fmt.Errorf("%w: start", ErrMissingRequiredField)
+	}
+	if err := r.Start.Validate(); err != nil {
+		return err
+	}
+
+	if r.FillThreshold == nil {
+		threshold := DefaultRouteFillThreshold
+		r.FillThreshold = &threshold
+	}
+	if !inRange(*r.FillThreshold, MinPercentage, MaxPercentage) {
+		return fmt.Errorf("%w: fill_threshold must be between 0 and 100", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// RouteStop es una parada de la ruta.
+type RouteStop struct {
+	// Order - Posición en la ruta (empieza en 1)
+	Order int `json:"order"`
+
+	DeviceID  string   `json:"device_id"`
+	BinType   string   `json:"bin_type,omitempty"`
+	FillLevel int      `json:"fill_level"`
+	Building  string   `json:"building,omitempty"`
+	Floor     int      `json:"floor,omitempty"`
+	Area      string   `json:"area,omitempty"`
+	Point     GeoPoint `json:"point"`
+
+	// DistanceFromPreviousMeters - Distancia desde la parada anterior (o el inicio)
+	DistanceFromPreviousMeters float64 `json:"distance_from_previous_m"`
+
+	// CumulativeDistanceMeters - Distancia recorrida desde el inicio
+	CumulativeDistanceMeters float64 `json:"cumulative_distance_m"`
+}
+
+// RouteGroup agrupa las paradas consecutivas de un mismo edificio y piso.
+type RouteGroup struct {
+	Building  string   `json:"building,omitempty"`
+	Floor     int      `json:"floor,omitempty"`
+	DeviceIDs []string `json:"device_ids"`
+}
+
+// RoutePlan es la ruta de recolección calculada.
+type RoutePlan struct {
+	Start         GeoPoint     `json:"start"`
+	FillThreshold int          `json:"fill_threshold"`
+	Stops         []RouteStop  `json:"stops"`
+	Groups        []RouteGroup `json:"groups"`
+
+	// TotalDistanceMeters - Distancia total estimada (línea recta entre paradas)
+	TotalDistanceMeters float64 `json:"total_distance_m"`
+
+	// Unlocated - Contenedores que superan el umbral pero no tienen coordenadas GPS
+	Unlocated []string `json:"unlocated,omitempty"`
+
+	PlannedAt time.Time `json:"planned_at"`
+}
@@ -0,0 +1,250 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  SHADOW.GO - SHADOW DEL DISPOSITIVO                            ║
+║                                                                ║
+║  Documento por dispositivo con el estado deseado (lo que       ║
+║  configura un operador), el reportado (lo que envía el         ║
+║  dispositivo) y el delta entre ambos (lo que falta aplicar).   ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// DeviceShadow es el documento de estado de un dispositivo.
+type DeviceShadow struct {
+	DeviceID string `json:"device_id" dynamodbav:"device_id"`
+
+	// Desired - Estado que el orchestrator quiere que aplique el dispositivo
+	Desired map[string]interface{} `json:"desired" dynamodbav:"desired"`
+
+	// Reported - Último estado reportado por el dispositivo (eventos device_status)
+	Reported map[string]interface{} `json:"reported" dynamodbav:"reported"`
+
+	// Delta - Claves de Desired cuyo valor difiere de Reported (calculado)
+	Delta map[string]interface{} `json:"delta,omitempty" dynamodbav:"delta,omitempty"`
+
+	// Version - Aumenta en cada cambio de Desired o Reported
+	Version int64 `json:"version" dynamodbav:"version"`
+
+	DesiredUpdatedAt  *time.Time `json:"desired_updated_at,omitempty" dynamodbav:"desired_updated_at,omitempty"`
+	ReportedUpdatedAt *time.Time `json:"reported_updated_at,omitempty" dynamodbav:"reported_updated_at,omitempty"`
+}
+
+// NewDeviceShadow crea un shadow vacío (versión 0).
+func NewDeviceShadow(deviceID string) *DeviceShadow {
+	return &DeviceShadow{
+		DeviceID: deviceID,
+		Desired:  map[string]interface{}{},
+		Reported: map[string]interface{}{},
+	}
+}
+
+// UpdateDesired mezcla patch en Desired y retorna true si algo cambió.
+// Los objetos se mezclan recursivamente y un valor null elimina la clave.
+func (s *DeviceShadow) UpdateDesired(patch map[string]interface{}, now time.Time) (bool, error) {
+	desired, changed, err := mergeState(s.Desired, patch)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	s.Desired = desired
+	s.DesiredUpdatedAt = &now
+	s.touch()
+	return true, nil
+}
+
+// UpdateReported mezcla patch en Reported y retorna true si algo cambió.
+func (s *DeviceShadow) UpdateReported(patch map[string]interface{}, now time.Time) (bool, error) {
+	reported, changed, err := mergeState(s.Reported, patch)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	s.Reported = reported
+	s.ReportedUpdatedAt = &now
+	s.touch()
+	return true, nil
+}
+
+// HasDelta retorna true si el dispositivo aún no aplicó parte del estado deseado.
+func (s *DeviceShadow) HasDelta() bool {
+	return len(s.Delta) > 0
+}
+
+// Clone retorna una copia profunda del shadow.
+func (s *DeviceShadow) Clone() *DeviceShadow {
+	clone := *s
+	clone.Desired = copyState(s.Desired)
+	clone.Reported = copyState(s.Reported)
+	clone.Delta = copyState(s.Delta)
+	return &clone
+}
+
+// touch aumenta la versión y recalcula el delta.
+func (s *DeviceShadow) touch() {
+	s.Version++
+	s.Delta = stateDelta(s.Desired, s.Reported)
+}
+
+// mergeState retorna base con patch aplicado y si hubo cambios. base no se modifica.
+// patch se normaliza con JSON para comparar igual números que llegan como int o float64.
+func mergeState(base, patch map[string]interface{}) (map[string]interface{}, bool, error) {
+	normalized, err := normalizeState(patch)
+	if err != nil {
+		return nil, false, err
+	}
+
+	merged := copyState(base)
+	if merged == nil {
+		merged = map[string]interface{}{}
+	}
+	changed := applyPatch(merged, normalized)
+	return merged, changed, nil
+}
+
+// applyPatch aplica patch sobre dst en sitio.
+func applyPatch(dst, patch map[string]interface{}) bool {
+	changed := false
+	for key, value := range patch {
+		current, exists := dst[key]
+
+		if value == nil {
+			if exists {
+				delete(dst, key)
+				changed = true
+			}
+			continue
+		}
+
+		patchObject, isObject := value.(map[string]interface{})
+		currentObject, currentIsObject := current.(map[string]interface{})
+		if isObject && currentIsObject {
+			if applyPatch(currentObject, patchObject) {
+				changed = true
+			}
+			continue
+		}
+		if isObject {
+			// Un objeto nuevo no debe guardar las claves null del patch
+			value = withoutNulls(patchObject)
+		}
+
+		if !exists || !reflect.DeepEqual(current, value) {
+			dst[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// stateDelta retorna las claves de desired que difieren de reported (recursivo en objetos).
+func stateDelta(desired, reported map[string]interface{}) map[string]interface{} {
+	delta := map[string]interface{}{}
+	for key, value := range desired {
+		desiredObject, isObject := value.(map[string]interface{})
+		reportedObject, reportedIsObject := reported[key].(map[string]interface{})
+		if isObject && reportedIsObject {
+			if nested := stateDelta(desiredObject, reportedObject); len(nested) > 0 {
+				delta[key] = nested
+			}
+			continue
+		}
+
+		if reportedValue, ok := reported[key]; !ok || !reflect.DeepEqual(value, reportedValue) {
+			delta[key] = value
+		}
+	}
+	if len(delta) == 0 {
+		return nil
+	}
+	return delta
+}
+
+// normalizeState convierte el estado a los tipos de encoding/json (float64, []interface{}, map).
+func normalizeState(state map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidShadowState, err)
+	}
+
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidShadowState, err)
+	}
+	return normalized, nil
+}
+
+// withoutNulls retorna una copia de state sin claves null.
+func withoutNulls(state map[string]interface{}) map[string]interface{} {
+	clean := make(map[string]interface{}, len(state))
+	for key, value := range state {
+		if value == nil {
+			continue
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			value = withoutNulls(object)
+		}
+		clean[key] = value
+	}
+	return clean
+}
+
+// copyState retorna una copia profunda de los objetos anidados de state.
+func copyState(state map[string]interface{}) map[string]interface{} {
+	if state == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(state))
+	for key, value := range state {
+		if object, ok := value.(map[string]interface{}); ok {
+			value = copyState(object)
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+// UpdateShadowRequest - Request para actualizar el estado deseado.
+type UpdateShadowRequest struct {
+	// Desired - Cambios al estado deseado (null elimina la clave)
+	Desired map[string]interface{} `json:"desired" binding:"required"`
+
+	// Version - Versión esperada del shadow; si no coincide se rechaza (opcional)
+	Version *int64 `json:"version,omitempty"`
+}
+
+// Validate valida el request.
+func (r *UpdateShadowRequest) Validate() error {
+	if len(r.Desired) == 0 {
+		return fmt.Errorf("%w: desired cannot be empty", ErrInvalidShadowState)
+	}
+	return nil
+}
+
+// ShadowDeltaMessage es el mensaje que recibe el dispositivo con el estado pendiente de aplicar.
+// Se publica en bins/{device_id}/shadow/delta.
+type ShadowDeltaMessage struct {
+	DeviceID    string                 `json:"device_id"`
+	Version     int64                  `json:"version"`
+	Delta       map[string]interface{} `json:"delta"`
+	PublishedAt time.Time              `json:"published_at"`
+}
+
+// NewShadowDeltaMessage construye el mensaje de delta del shadow.
+func NewShadowDeltaMessage(shadow *DeviceShadow) *ShadowDeltaMessage {
+	return &ShadowDeltaMessage{
+		DeviceID:    shadow.DeviceID,
+		Version:     shadow.Version,
+		Delta:       copyState(shadow.Delta),
+		PublishedAt: time.Now(),
+	}
+}
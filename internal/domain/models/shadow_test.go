@@ -0,0 +1,74 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDeviceShadowDelta verifica la mezcla de estados, el delta y la versión.
+func TestDeviceShadowDelta(t *testing.T) {
+	now := time.Now()
+	shadow := NewDeviceShadow("smart-bin-001")
+
+	changed, err := shadow.UpdateDesired(map[string]interface{}{
+		"capture_interval_s": 30,
+		"led":                map[string]interface{}{"brightness": 80, "color": "green"},
+	}, now)
+	if err != nil || !changed {
+		t.Fatalf("UpdateDesired() = %v, %v, want true, nil", changed, err)
+	}
+	if shadow.Version != 1 {
+		t.Errorf("Version = %d, want 1", shadow.Version)
+	}
+
+	// El dispositivo aplicó el intervalo y parte del led (int y float64 se comparan igual)
+	if _, err := shadow.UpdateReported(map[string]interface{}{
+		"capture_interval_s": 30.0,
+		"led":                map[string]interface{}{"brightness": 80},
+		"fill_level":         42,
+	}, now); err != nil {
+		t.Fatalf("UpdateReported() error = %v", err)
+	}
+	want := map[string]interface{}{"led": map[string]interface{}{"color": "green"}}
+	if !reflect.DeepEqual(shadow.Delta, want) {
+		t.Errorf("Delta = %v, want %v", shadow.Delta, want)
+	}
+
+	// Reportar lo mismo no cambia la versión
+	version := shadow.Version
+	changed, err = shadow.UpdateReported(map[string]interface{}{"fill_level": 42}, now)
+	if err != nil || changed || shadow.Version != version {
+		t.Errorf("UpdateReported(same) = %v, %v, version %d, want false, nil, %d", changed, err, shadow.Version, version)
+	}
+
+	// null elimina la clave del estado deseado y con ella el delta
+	if _, err := shadow.UpdateDesired(map[string]interface{}{
+		"led": map[string]interface{}{"color": nil},
+	}, now); err != nil {
+		t.Fatalf("UpdateDesired(null) error = %v", err)
+	}
+	if shadow.HasDelta() {
+		t.Errorf("Delta = %v, want empty", shadow.Delta)
+	}
+	if _, ok := shadow.Desired["led"].(map[string]interface{})["color"]; ok {
+		t.Errorf("Desired = %v, want led.color removed", shadow.Desired)
+	}
+}
+
+// TestDeviceShadowClone verifica que la copia no comparte objetos anidados.
+func TestDeviceShadowClone(t *testing.T) {
+	shadow := NewDeviceShadow("smart-bin-001")
+	if _, err := shadow.UpdateDesired(map[string]interface{}{
+		"led": map[string]interface{}{"brightness": 80},
+	}, time.Now()); err != nil {
+		t.Fatalf("UpdateDesired() error = %v", err)
+	}
+
+	clone := shadow.Clone()
+	clone.Desired["led"].(map[string]interface{})["brightness"] = 10.0
+
+	if got := shadow.Desired["led"].(map[string]interface{})["brightness"]; got != 80.0 {
+		t.Errorf("Original brightness = %v, want 80", got)
+	}
+}
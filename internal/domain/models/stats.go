@@ -0,0 +1,201 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  STATS.GO - ESTADÍSTICAS POR UBICACIÓN                         ║
+║                                                                ║
+║  Agregados a lo largo de la jerarquía de Location:             ║
+║  building → floor → area → zone.                               ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+// LocationLevel es un nivel de la jerarquía de ubicaciones.
+type LocationLevel string
+
+const (
+	// LocationLevelBuilding - Agrupa por Location.Building.
+	LocationLevelBuilding LocationLevel = "building"
+
+	// LocationLevelFloor - Agrupa por Location.Floor.
+	LocationLevelFloor LocationLevel = "floor"
+
+	// LocationLevelArea - Agrupa por Location.Area.
+	LocationLevelArea LocationLevel = "area"
+
+	// LocationLevelZone - Agrupa por Location.Zone.
+	LocationLevelZone LocationLevel = "zone"
+)
+
+// locationLevels - Orden de la jerarquía, de mayor a menor.
+var locationLevels = []LocationLevel{
+	LocationLevelBuilding,
+	LocationLevelFloor,
+	LocationLevelArea,
+	LocationLevelZone,
+}
+
+// IsValid retorna true si el nivel es conocido.
+func (l LocationLevel) IsValid() bool {
+	for _, level := range locationLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// LocationKey retorna el valor de la ubicación en el nivel dado ("" si no está asignado).
+func (l *Location) LocationKey(level LocationLevel) string {
+	if l == nil {
+		return ""
+	}
+
+	switch level {
+	case LocationLevelBuilding:
+		return l.Building
+	case LocationLevelFloor:
+		return strconv.Itoa(l.Floor)
+	case LocationLevelArea:
+		return l.Area
+	case LocationLevelZone:
+		return l.Zone
+	default:
+		return ""
+	}
+}
+
+// LocationStatsRequest - Filtros de la consulta de estadísticas.
+//
+// Building, Floor, Area y Zone fijan el camino en la jerarquía; Level indica
+// por qué nivel agrupar. Sin Level se agrupa por el nivel siguiente al filtro
+// más profundo (ej: building=A → agrupa por floor).
+type LocationStatsRequest struct {
+	Level    LocationLevel `form:"level"`
+	Building string        `form:"building"`
+	Floor    *int          `form:"floor"`
+	Area     string        `form:"area"`
+	Zone     string        `form:"zone"`
+
+	// GroupID - Limita los agregados a los miembros del grupo (opcional)
+	GroupID string `form:"group_id"`
+
+	// From, To - Rango de creación de los jobs contados (RFC3339, default: últimos 7 días)
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// DefaultLevel retorna el nivel siguiente al filtro más profundo.
+func (r *LocationStatsRequest) DefaultLevel() LocationLevel {
+	switch {
+	case r.Area != "":
+		return LocationLevelZone
+	case r.Floor != nil:
+		return LocationLevelArea
+	case r.Building != "":
+		return LocationLevelFloor
+	default:
+		return LocationLevelBuilding
+	}
+}
+
+// Matches retorna true si la ubicación está dentro del camino filtrado.
+func (r *LocationStatsRequest) Matches(location *Location) bool {
+	if location == nil {
+		location = &Location{}
+	}
+
+	return (r.Building == "" || location.Building == r.Building) &&
+		(r.Floor == nil || location.Floor == *r.Floor) &&
+		(r.Area == "" || location.Area == r.Area) &&
+		(r.Zone == "" || location.Zone == r.Zone)
+}
+
+// LocationStats son los agregados de un nodo de la jerarquía.
+type LocationStats struct {
+	// Key - Valor del nodo en el nivel agrupado ("" si los dispositivos no tienen ese dato)
+	Key string `json:"key"`
+
+	Devices       int `json:"devices"`
+	OnlineDevices int `json:"online_devices"`
+
+	// Jobs - Jobs creados en el rango
+	Jobs int `json:"jobs"`
+
+	AcceptedJobs int `json:"accepted_jobs"`
+	RejectedJobs int `json:"rejected_jobs"`
+
+	// AcceptanceRate, RejectionRate - Sobre los jobs con decisión (0-1)
+	AcceptanceRate float64 `json:"acceptance_rate"`
+	RejectionRate  float64 `json:"rejection_rate"`
+
+	// AverageFillLevel - Promedio sobre los dispositivos que reportaron llenado
+	AverageFillLevel *float64 `json:"average_fill_level,omitempty"`
+
+	decided   int
+	fillSum   int
+	fillCount int
+}
+
+// AddDevice suma el dispositivo al nodo.
+func (s *LocationStats) AddDevice(device *Device) {
+	s.Devices++
+	if device.Online {
+		s.OnlineDevices++
+	}
+	if device.FillLevel != nil {
+		s.fillSum += *device.FillLevel
+		s.fillCount++
+	}
+}
+
+// AddJob suma el job al nodo.
+func (s *LocationStats) AddJob(job *Job) {
+	s.Jobs++
+	if !job.HasDecision() {
+		return
+	}
+
+	s.decided++
+	switch {
+	case job.Decision.IsAccepted():
+		s.AcceptedJobs++
+	case job.Decision.IsRejected():
+		s.RejectedJobs++
+	}
+}
+
+// Finalize calcula las tasas y promedios.
+func (s *LocationStats) Finalize() {
+	if s.decided > 0 {
+		s.AcceptanceRate = float64(s.AcceptedJobs) / float64(s.decided)
+		s.RejectionRate = float64(s.RejectedJobs) / float64(s.decided)
+	}
+	if s.fillCount > 0 {
+		average := float64(s.fillSum) / float64(s.fillCount)
+		s.AverageFillLevel = &average
+	}
+}
+
+// LocationStatsResponse - Estadísticas agrupadas por un nivel de la jerarquía.
+type LocationStatsResponse struct {
+	Level LocationLevel `json:"level"`
+
+	// Path - Filtros aplicados (camino en la jerarquía)
+	Path map[LocationLevel]string `json:"path,omitempty"`
+
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// Nodes - Un elemento por valor del nivel, ordenados por Key
+	Nodes []*LocationStats `json:"nodes"`
+
+	// Totals - Agregado de todos los nodos
+	Totals *LocationStats `json:"totals"`
+}
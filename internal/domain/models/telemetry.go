@@ -0,0 +1,258 @@
+package models
+
+import (
+	"time"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  TELEMETRY.GO - TELEMETRÍA DE DISPOSITIVOS                     ║
+║                                                                ║
+║  Lecturas de hardware (batería, llenado, señal, firmware)      ║
+║  reportadas en los eventos device_status.                      ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+const (
+	// MinPercentage - Valor mínimo para batería y llenado.
+	MinPercentage = 0
+
+	// MaxPercentage - Valor máximo para batería y llenado.
+	MaxPercentage = 100
+
+	// MinSignalStrength - Señal mínima aceptada en dBm.
+	MinSignalStrength = -120
+
+	// MaxSignalStrength - Señal máxima aceptada en dBm.
+	MaxSignalStrength = 0
+)
+
+// DeviceTelemetry representa una lectura de hardware reportada por un dispositivo.
+// Los campos nil no fueron reportados y no modifican el estado guardado.
+type DeviceTelemetry struct {
+	BatteryLevel    *int      `json:"battery_level,omitempty"`
+	FillLevel       *int      `json:"fill_level,omitempty"`
+	SignalStrength  *int      `json:"signal_strength,omitempty"`
+	FirmwareVersion string    `json:"firmware_version,omitempty"`
+	ReportedAt      time.Time `json:"reported_at"`
+}
+
+// Validate verifica que las lecturas estén dentro de los rangos físicos.
+func (t *DeviceTelemetry) Validate() error {
+	if t.BatteryLevel != nil && !inRange(*t.BatteryLevel, MinPercentage, MaxPercentage) {
+		return ErrInvalidBatteryLevel
+	}
+	if t.FillLevel != nil && !inRange(*t.FillLevel, MinPercentage, MaxPercentage) {
+		return ErrInvalidFillLevel
+	}
+	if t.SignalStrength != nil && !inRange(*t.SignalStrength, MinSignalStrength, MaxSignalStrength) {
+		return ErrInvalidSignalStrength
+	}
+	if t.ReportedAt.IsZero() {
+		return ErrInvalidTimestamp
+	}
+	return nil
+}
+
+// IsEmpty retorna true si la lectura no trae ningún valor.
+func (t *DeviceTelemetry) IsEmpty() bool {
+	return t.BatteryLevel == nil &&
+		t.FillLevel == nil &&
+		t.SignalStrength == nil &&
+		t.FirmwareVersion == ""
+}
+
+// ApplyTelemetry copia la lectura al dispositivo.
+// Retorna ErrStaleEvent si la lectura no es posterior al último reporte aplicado.
+func (d *Device) ApplyTelemetry(t *DeviceTelemetry) error {
+	if d.LastReportedAt != nil && !t.ReportedAt.After(*d.LastReportedAt) {
+		return ErrStaleEvent
+	}
+
+	if t.BatteryLevel != nil {
+		level := *t.BatteryLevel
+		d.BatteryLevel = &level
+	}
+	if t.FillLevel != nil {
+		level := *t.FillLevel
+		d.FillLevel = &level
+	}
+	if t.SignalStrength != nil {
+		strength := *t.SignalStrength
+		d.SignalStrength = &strength
+	}
+	if t.FirmwareVersion != "" && t.FirmwareVersion != d.FirmwareVersion {
+		d.FirmwareVersion = t.FirmwareVersion
+		updatedAt := t.ReportedAt
+		d.FirmwareUpdatedAt = &updatedAt
+	}
+
+	reportedAt := t.ReportedAt
+	d.LastReportedAt = &reportedAt
+	d.MarkAsSeen()
+
+	return nil
+}
+
+// inRange retorna true si value está en [lower, upper].
+func inRange(value, lower, upper int) bool {
+	return value >= lower && value <= upper
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     HISTÓRICO (SERIES DE TIEMPO)
+// ═══════════════════════════════════════════════════════════════════
+
+// TelemetryMetric identifica una serie de telemetría.
+type TelemetryMetric string
+
+const (
+	// MetricBatteryLevel - Nivel de batería (%).
+	MetricBatteryLevel TelemetryMetric = "battery_level"
+
+	// MetricFillLevel - Nivel de llenado (%).
+	MetricFillLevel TelemetryMetric = "fill_level"
+
+	// MetricSignalStrength - Fuerza de señal (dBm).
+	MetricSignalStrength TelemetryMetric = "signal_strength"
+)
+
+// IsValid retorna true si la métrica es conocida.
+func (m TelemetryMetric) IsValid() bool {
+	switch m {
+	case MetricBatteryLevel, MetricFillLevel, MetricSignalStrength:
+		return true
+	default:
+		return false
+	}
+}
+
+// TelemetryResolution representa la granularidad en la que se guarda una serie.
+type TelemetryResolution string
+
+const (
+	// ResolutionRaw - Cada lectura tal como llegó.
+	ResolutionRaw TelemetryResolution = "raw"
+
+	// ResolutionHourly - Agregado por hora (min/avg/max).
+	ResolutionHourly TelemetryResolution = "hourly"
+
+	// ResolutionDaily - Agregado por día UTC (min/avg/max).
+	ResolutionDaily TelemetryResolution = "daily"
+)
+
+// BucketSize retorna el tamaño del bucket de la resolución (0 para raw).
+func (r TelemetryResolution) BucketSize() time.Duration {
+	switch r {
+	case ResolutionHourly:
+		return time.Hour
+	case ResolutionDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// TelemetryReading es una lectura individual de una métrica.
+type TelemetryReading struct {
+	DeviceID  string          `json:"device_id" dynamodbav:"device_id"`
+	Metric    TelemetryMetric `json:"metric" dynamodbav:"metric"`
+	Value     float64         `json:"value" dynamodbav:"value"`
+	Timestamp time.Time       `json:"timestamp" dynamodbav:"timestamp"`
+}
+
+// Readings descompone la lectura de hardware en una lectura por métrica reportada.
+func (t *DeviceTelemetry) Readings(deviceID string) []TelemetryReading {
+	readings := make([]TelemetryReading, 0, 3)
+
+	add := func(metric TelemetryMetric, value *int) {
+		if value == nil {
+			return
+		}
+		readings = append(readings, TelemetryReading{
+			DeviceID:  deviceID,
+			Metric:    metric,
+			Value:     float64(*value),
+			Timestamp: t.ReportedAt,
+		})
+	}
+
+	add(MetricBatteryLevel, t.BatteryLevel)
+	add(MetricFillLevel, t.FillLevel)
+	add(MetricSignalStrength, t.SignalStrength)
+
+	return readings
+}
+
+// TelemetryPoint es un punto agregado (min/avg/max) de una serie.
+// Timestamp es el inicio del bucket.
+type TelemetryPoint struct {
+	Timestamp time.Time `json:"timestamp" dynamodbav:"timestamp"`
+	Min       float64   `json:"min" dynamodbav:"min"`
+	Avg       float64   `json:"avg" dynamodbav:"-"`
+	Max       float64   `json:"max" dynamodbav:"max"`
+	Sum       float64   `json:"-" dynamodbav:"sum"`
+	Count     int       `json:"count" dynamodbav:"count"`
+}
+
+// NewTelemetryPoint crea un punto a partir de una sola lectura.
+func NewTelemetryPoint(timestamp time.Time, value float64) TelemetryPoint {
+	return TelemetryPoint{
+		Timestamp: timestamp,
+		Min:       value,
+		Avg:       value,
+		Max:       value,
+		Sum:       value,
+		Count:     1,
+	}
+}
+
+// Add agrega una lectura al punto.
+func (p *TelemetryPoint) Add(value float64) {
+	p.Merge(NewTelemetryPoint(p.Timestamp, value))
+}
+
+// Merge combina otro punto en este, conservando el Timestamp actual.
+func (p *TelemetryPoint) Merge(other TelemetryPoint) {
+	if other.Count == 0 {
+		return
+	}
+	if p.Count == 0 {
+		timestamp := p.Timestamp
+		*p = other
+		p.Timestamp = timestamp
+		return
+	}
+
+	if other.Min < p.Min {
+		p.Min = other.Min
+	}
+	if other.Max > p.Max {
+		p.Max = other.Max
+	}
+	p.Sum += other.Sum
+	p.Count += other.Count
+	p.Avg = p.Sum / float64(p.Count)
+}
+
+// TelemetryQuery define la consulta de una serie.
+type TelemetryQuery struct {
+	DeviceID string          `json:"device_id"`
+	Metric   TelemetryMetric `json:"metric"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Step     time.Duration   `json:"-"`
+}
+
+// TelemetrySeries es la respuesta de una consulta de telemetría.
+type TelemetrySeries struct {
+	DeviceID   string              `json:"device_id"`
+	Metric     TelemetryMetric     `json:"metric"`
+	From       time.Time           `json:"from"`
+	To         time.Time           `json:"to"`
+	Step       string              `json:"step"`
+	Resolution TelemetryResolution `json:"resolution"`
+	Points     []TelemetryPoint    `json:"points"`
+}
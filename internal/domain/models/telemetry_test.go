@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDeviceTelemetryValidate verifica los rangos de las lecturas.
+func TestDeviceTelemetryValidate(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		telemetry DeviceTelemetry
+		wantErr   error
+	}{
+		{
+			name:      "Valid reading",
+			telemetry: DeviceTelemetry{BatteryLevel: intPtr(80), FillLevel: intPtr(40), SignalStrength: intPtr(-60), ReportedAt: now},
+			wantErr:   nil,
+		},
+		{
+			name:      "Battery above 100",
+			telemetry: DeviceTelemetry{BatteryLevel: intPtr(101), ReportedAt: now},
+			wantErr:   ErrInvalidBatteryLevel,
+		},
+		{
+			name:      "Negative fill level",
+			telemetry: DeviceTelemetry{FillLevel: intPtr(-1), ReportedAt: now},
+			wantErr:   ErrInvalidFillLevel,
+		},
+		{
+			name:      "Positive dBm",
+			telemetry: DeviceTelemetry{SignalStrength: intPtr(5), ReportedAt: now},
+			wantErr:   ErrInvalidSignalStrength,
+		},
+		{
+			name:      "Missing timestamp",
+			telemetry: DeviceTelemetry{BatteryLevel: intPtr(50)},
+			wantErr:   ErrInvalidTimestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.telemetry.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeviceTelemetry.Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestDeviceApplyTelemetry verifica que se aplican las lecturas y se rechazan las antiguas.
+func TestDeviceApplyTelemetry(t *testing.T) {
+	fill := 75
+	reportedAt := time.Now().Add(-time.Minute)
+
+	device := &Device{DeviceID: "smart-bin-001", Status: DeviceStatusActive}
+
+	if err := device.ApplyTelemetry(&DeviceTelemetry{
+		FillLevel:       &fill,
+		FirmwareVersion: "1.4.0",
+		ReportedAt:      reportedAt,
+	}); err != nil {
+		t.Fatalf("ApplyTelemetry() error = %v", err)
+	}
+
+	if device.FillLevel == nil || *device.FillLevel != 75 {
+		t.Errorf("FillLevel = %v, want 75", device.FillLevel)
+	}
+	if device.FirmwareVersion != "1.4.0" || device.FirmwareUpdatedAt == nil {
+		t.Error("Expected firmware version and update time to be set")
+	}
+	if device.LastSeen == nil {
+		t.Error("Expected LastSeen to be set")
+	}
+
+	older := reportedAt.Add(-time.Second)
+	if err := device.ApplyTelemetry(&DeviceTelemetry{FillLevel: &fill, ReportedAt: older}); !errors.Is(err, ErrStaleEvent) {
+		t.Errorf("ApplyTelemetry() with older timestamp = %v, want %v", err, ErrStaleEvent)
+	}
+
+	if err := device.ApplyTelemetry(&DeviceTelemetry{FillLevel: &fill, ReportedAt: reportedAt}); !errors.Is(err, ErrStaleEvent) {
+		t.Errorf("ApplyTelemetry() with duplicate timestamp = %v, want %v", err, ErrStaleEvent)
+	}
+}
@@ -0,0 +1,58 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// S3Presigner genera URLs prefirmadas para que los dispositivos suban imágenes y descarguen firmware.
+type S3Presigner interface {
+	// GeneratePresignedPutURL retorna una URL PUT válida durante expiry.
+	GeneratePresignedPutURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error)
+
+	// GeneratePresignedGetURL retorna una URL GET válida durante expiry.
+	GeneratePresignedGetURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error)
+}
+
+// SQSPublisher encola jobs para que el Classifier los procese.
+type SQSPublisher interface {
+	// PublishClassificationJob publica el job en la cola de clasificación.
+	PublishClassificationJob(ctx context.Context, job *models.Job) error
+}
+
+// EventPublisher publica los eventos que genera el orchestrator (device_online, device_offline, ...).
+type EventPublisher interface {
+	// Publish entrega el evento a los suscriptores.
+	Publish(ctx context.Context, event *models.DeviceEvent) error
+}
+
+// IoTPublisher envía mensajes a los dispositivos (AWS IoT Core o cualquier broker MQTT).
+type IoTPublisher interface {
+	// PublishDecision publica la decisión de un job en el topic del dispositivo.
+	PublishDecision(ctx context.Context, message *models.DecisionMessage) error
+
+	// PublishShadowDelta publica el estado deseado pendiente de aplicar en el topic del dispositivo.
+	PublishShadowDelta(ctx context.Context, message *models.ShadowDeltaMessage) error
+
+	// PublishCommand publica un comando remoto en el topic del dispositivo.
+	PublishCommand(ctx context.Context, message *models.CommandMessage) error
+
+	// PublishFirmwareUpdate publica una actualización OTA en el topic del dispositivo.
+	PublishFirmwareUpdate(ctx context.Context, message *models.FirmwareUpdateMessage) error
+}
+
+// CertificateAuthority firma los certificados X.509 de los dispositivos.
+type CertificateAuthority interface {
+	// IssueDeviceCertificate firma un certificado con deviceID como CN y SAN.
+	// Con csrPEM vacío genera el par de llaves y retorna la llave privada en el resultado.
+	// Retorna models.ErrInvalidCSR si el CSR es inválido o pide otro CN.
+	IssueDeviceCertificate(ctx context.Context, deviceID, csrPEM string) (*models.DeviceCertificate, error)
+
+	// CACertificatePEM retorna el certificado de la CA para que los dispositivos y servidores lo usen como raíz.
+	CACertificatePEM() string
+
+	// CreateCRL firma la lista de revocación con los certificados revocados (PEM "X509 CRL").
+	CreateCRL(ctx context.Context, revoked []*models.DeviceCertificate, nextUpdate time.Time) (string, error)
+}
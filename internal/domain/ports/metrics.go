@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+
+// PipelineMetrics registra las métricas del pipeline de clasificación (Prometheus o equivalente).
+type PipelineMetrics interface {
+	// JobCreated cuenta un job nuevo según su estado inicial (pending o processing).
+	JobCreated(job *models.Job)
+
+	// JobFinished cuenta un job completado o fallido, su decisión y su tiempo de procesamiento.
+	JobFinished(job *models.Job)
+}
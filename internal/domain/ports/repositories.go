@@ -0,0 +1,218 @@
+// Package ports defines the interfaces (ports) the domain uses to talk to the outside world.
+// Adapters in internal/infrastructure implement them (DynamoDB, S3, SQS, IoT Core or local in-memory versions).
+//
+// Repositories of tenant-owned records (devices, jobs, groups, alerts, organizations, credentials and audit entries)
+// only see the records of models.OrgScope(ctx): records of other organizations behave as not found.
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// JobRepository persiste y consulta jobs de clasificación.
+type JobRepository interface {
+	// Create guarda un job nuevo. Retorna error si el JobID ya existe.
+	Create(ctx context.Context, job *models.Job) error
+
+	// GetByID retorna el job o models.ErrJobNotFound.
+	GetByID(ctx context.Context, jobID string) (*models.Job, error)
+
+	// Update reemplaza un job existente. Retorna models.ErrJobNotFound si no existe.
+	Update(ctx context.Context, job *models.Job) error
+
+	// List retorna la página de jobs que cumple los filtros y el total sin paginar.
+	List(ctx context.Context, req *models.ListJobsRequest) ([]*models.Job, int, error)
+}
+
+// DeviceRepository persiste y consulta dispositivos.
+type DeviceRepository interface {
+	// Create guarda un dispositivo nuevo. Retorna models.ErrDeviceAlreadyExists si ya existe.
+	Create(ctx context.Context, device *models.Device) error
+
+	// GetByID retorna el dispositivo o models.ErrDeviceNotFound.
+	GetByID(ctx context.Context, deviceID string) (*models.Device, error)
+
+	// Update reemplaza un dispositivo existente. Retorna models.ErrDeviceNotFound si no existe.
+	Update(ctx context.Context, device *models.Device) error
+
+	// List retorna la página de dispositivos que cumple los filtros y el total sin paginar.
+	// Con Limit 0 retorna todos los dispositivos.
+	List(ctx context.Context, req *models.ListDevicesRequest) ([]*models.Device, int, error)
+}
+
+// GroupRepository persiste los grupos de dispositivos.
+type GroupRepository interface {
+	// Create guarda un grupo nuevo.
+	Create(ctx context.Context, group *models.DeviceGroup) error
+
+	// GetByID retorna el grupo o models.ErrGroupNotFound.
+	GetByID(ctx context.Context, groupID string) (*models.DeviceGroup, error)
+
+	// Update reemplaza un grupo existente. Retorna models.ErrGroupNotFound si no existe.
+	Update(ctx context.Context, group *models.DeviceGroup) error
+
+	// Delete elimina el grupo. Retorna models.ErrGroupNotFound si no existe.
+	Delete(ctx context.Context, groupID string) error
+
+	// List retorna la página de grupos (ordenados por nombre) y el total sin paginar.
+	// Con Limit 0 retorna todos.
+	List(ctx context.Context, req *models.ListGroupsRequest) ([]*models.DeviceGroup, int, error)
+}
+
+// TelemetryRepository guarda el histórico de telemetría: lecturas crudas y agregados horarios/diarios.
+type TelemetryRepository interface {
+	// Append guarda la lectura cruda y la suma a sus buckets horario y diario.
+	Append(ctx context.Context, reading *models.TelemetryReading) error
+
+	// Query retorna los puntos de la resolución indicada en [from, to), ordenados por tiempo.
+	Query(
+		ctx context.Context,
+		deviceID string,
+		metric models.TelemetryMetric,
+		resolution models.TelemetryResolution,
+		from, to time.Time,
+	) ([]models.TelemetryPoint, error)
+
+	// DeleteBefore elimina los puntos de la resolución anteriores a cutoff y retorna cuántos borró.
+	DeleteBefore(ctx context.Context, resolution models.TelemetryResolution, cutoff time.Time) (int, error)
+}
+
+// AlertRepository persiste las alertas de mantenimiento.
+type AlertRepository interface {
+	// Create guarda una alerta nueva.
+	Create(ctx context.Context, alert *models.Alert) error
+
+	// GetByID retorna la alerta o models.ErrAlertNotFound.
+	GetByID(ctx context.Context, alertID string) (*models.Alert, error)
+
+	// Update reemplaza una alerta existente. Retorna models.ErrAlertNotFound si no existe.
+	Update(ctx context.Context, alert *models.Alert) error
+
+	// FindActive retorna la alerta activa (open o acknowledged) del dispositivo para la regla, o nil si no hay.
+	FindActive(ctx context.Context, deviceID, ruleID string) (*models.Alert, error)
+
+	// List retorna la página de alertas que cumple los filtros (más recientes primero) y el total sin paginar.
+	// Con Limit 0 retorna todas.
+	List(ctx context.Context, req *models.ListAlertsRequest) ([]*models.Alert, int, error)
+}
+
+// ShadowRepository persiste los shadows de los dispositivos.
+type ShadowRepository interface {
+	// GetByDeviceID retorna el shadow del dispositivo, o nil si aún no tiene.
+	GetByDeviceID(ctx context.Context, deviceID string) (*models.DeviceShadow, error)
+
+	// Save crea o reemplaza el shadow.
+	Save(ctx context.Context, shadow *models.DeviceShadow) error
+}
+
+// CommandRepository persiste los comandos remotos.
+type CommandRepository interface {
+	// Create guarda un comando nuevo.
+	Create(ctx context.Context, command *models.Command) error
+
+	// GetByID retorna el comando o models.ErrCommandNotFound.
+	GetByID(ctx context.Context, commandID string) (*models.Command, error)
+
+	// Update reemplaza un comando existente. Retorna models.ErrCommandNotFound si no existe.
+	Update(ctx context.Context, command *models.Command) error
+
+	// List retorna la página de comandos que cumple los filtros (más recientes primero) y el total sin paginar.
+	// Con Limit 0 retorna todos.
+	List(ctx context.Context, req *models.ListCommandsRequest) ([]*models.Command, int, error)
+}
+
+// FirmwareRepository persiste las releases de firmware, los rollouts y la actualización de cada dispositivo.
+type FirmwareRepository interface {
+	// CreateRelease guarda una release nueva. Retorna models.ErrFirmwareReleaseExists si ya existe la versión para el tipo.
+	CreateRelease(ctx context.Context, release *models.FirmwareRelease) error
+
+	// GetRelease retorna la release o models.ErrFirmwareReleaseNotFound.
+	GetRelease(ctx context.Context, releaseID string) (*models.FirmwareRelease, error)
+
+	// ListReleases retorna la página de releases (más recientes primero) y el total sin paginar.
+	ListReleases(ctx context.Context, req *models.ListFirmwareReleasesRequest) ([]*models.FirmwareRelease, int, error)
+
+	// CreateRollout guarda un rollout nuevo.
+	CreateRollout(ctx context.Context, rollout *models.FirmwareRollout) error
+
+	// GetRollout retorna el rollout o models.ErrRolloutNotFound.
+	GetRollout(ctx context.Context, rolloutID string) (*models.FirmwareRollout, error)
+
+	// UpdateRollout reemplaza un rollout existente. Retorna models.ErrRolloutNotFound si no existe.
+	UpdateRollout(ctx context.Context, rollout *models.FirmwareRollout) error
+
+	// ListRollouts retorna la página de rollouts (más recientes primero) y el total sin paginar.
+	// Con Limit 0 retorna todos.
+	ListRollouts(ctx context.Context, req *models.ListRolloutsRequest) ([]*models.FirmwareRollout, int, error)
+
+	// SaveUpdate crea o reemplaza la actualización de un dispositivo dentro de un rollout.
+	SaveUpdate(ctx context.Context, update *models.FirmwareUpdate) error
+
+	// GetUpdate retorna la actualización del dispositivo en el rollout, o nil si no fue seleccionado.
+	GetUpdate(ctx context.Context, rolloutID, deviceID string) (*models.FirmwareUpdate, error)
+
+	// ListUpdates retorna las actualizaciones de un rollout.
+	ListUpdates(ctx context.Context, rolloutID string) ([]*models.FirmwareUpdate, error)
+}
+
+// CertificateRepository guarda los certificados emitidos a los dispositivos (sin llaves privadas).
+type CertificateRepository interface {
+	// Save crea o reemplaza el certificado (por serial).
+	Save(ctx context.Context, certificate *models.DeviceCertificate) error
+
+	// GetBySerial retorna el certificado o models.ErrCertificateNotFound.
+	GetBySerial(ctx context.Context, serial string) (*models.DeviceCertificate, error)
+
+	// List retorna los certificados que cumplen los filtros, los que expiran antes primero.
+	List(ctx context.Context, req *models.ListCertificatesRequest) ([]*models.DeviceCertificate, error)
+}
+
+// OrganizationRepository persiste las organizaciones (tenants).
+type OrganizationRepository interface {
+	// Create guarda una organización nueva. Retorna models.ErrOrganizationExists si el ID ya existe.
+	Create(ctx context.Context, org *models.Organization) error
+
+	// GetByID retorna la organización o models.ErrOrganizationNotFound.
+	GetByID(ctx context.Context, orgID string) (*models.Organization, error)
+
+	// Update reemplaza una organización existente. Retorna models.ErrOrganizationNotFound si no existe.
+	Update(ctx context.Context, org *models.Organization) error
+
+	// List retorna la página de organizaciones (por ID) y el total sin paginar.
+	List(ctx context.Context, req *models.ListOrganizationsRequest) ([]*models.Organization, int, error)
+}
+
+// CredentialRepository persiste las credenciales de API.
+type CredentialRepository interface {
+	// Create guarda una credencial nueva.
+	Create(ctx context.Context, credential *models.APICredential) error
+
+	// GetByID retorna la credencial o models.ErrCredentialNotFound.
+	GetByID(ctx context.Context, credentialID string) (*models.APICredential, error)
+
+	// GetByKeyHash retorna la credencial con el hash o models.ErrCredentialNotFound.
+	// Se usa para autenticar, antes de conocer la organización: no aplica el scope.
+	GetByKeyHash(ctx context.Context, keyHash string) (*models.APICredential, error)
+
+	// Update reemplaza una credencial existente. Retorna models.ErrCredentialNotFound si no existe.
+	Update(ctx context.Context, credential *models.APICredential) error
+
+	// List retorna las credenciales de la organización, las más recientes primero.
+	List(ctx context.Context, orgID string) ([]*models.APICredential, error)
+}
+
+// AuditRepository persiste el registro de auditoría. Es append-only: no hay Update ni Delete.
+type AuditRepository interface {
+	// Append agrega una entrada ya sellada. Retorna error si su Sequence no sigue a la última.
+	Append(ctx context.Context, entry *models.AuditEntry) error
+
+	// Last retorna la última entrada de la cadena, o nil si está vacía. No aplica el scope.
+	Last(ctx context.Context) (*models.AuditEntry, error)
+
+	// List retorna la página de entradas que cumple los filtros (las más recientes primero)
+	// y el total sin paginar. Limit 0 retorna todas.
+	List(ctx context.Context, req *models.ListAuditRequest) ([]*models.AuditEntry, int, error)
+}
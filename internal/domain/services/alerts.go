@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// AlertService evalúa las reglas de mantenimiento y gestiona el ciclo de vida de las alertas.
+type AlertService struct {
+	alerts        ports.AlertRepository
+	groups        ports.GroupRepository
+	orgs          ports.OrganizationRepository
+	deviceManager *DeviceManager
+	rules         []models.AlertRule
+
+	// mu evita que dos evaluaciones concurrentes abran la misma alerta dos veces
+	mu sync.Mutex
+}
+
+// NewAlertService crea una nueva instancia de AlertService.
+// Las reglas deben venir validadas (ver LoadAlertRules).
+func NewAlertService(
+	alerts ports.AlertRepository,
+	groups ports.GroupRepository,
+	orgs ports.OrganizationRepository,
+	deviceManager *DeviceManager,
+	rules []models.AlertRule,
+) *AlertService {
+	return &AlertService{
+		alerts:        alerts,
+		groups:        groups,
+		orgs:          orgs,
+		deviceManager: deviceManager,
+		rules:         rules,
+	}
+}
+
+// LoadAlertRules reads alert rules from a JSON file (array of models.AlertRule).
+// An empty path returns models.DefaultAlertRules.
+func LoadAlertRules(path string) ([]models.AlertRule, error) {
+	if path == "" {
+		return models.DefaultAlertRules(), nil
+	}
+
+	content, err := os.ReadFile(path) // #nosec G304 -- ruta tomada de la configuración del servicio
+	if err != nil {
+		return nil, fmt.Errorf("reading alert rules: %w", err)
+	}
+
+	var rules []models.AlertRule
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return nil, fmt.Errorf("parsing alert rules: %w", err)
+	}
+
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, rules[i].RuleID)
+		}
+	}
+
+	return rules, nil
+}
+
+// RulesFor returns the rules that apply to a device type, leaving out group rules.
+// A rule for the specific type replaces the generic rules on the same metric.
+func (s *AlertService) RulesFor(deviceType string) []models.AlertRule {
+	specific := make(map[models.AlertMetric]bool)
+	for _, rule := range s.rules {
+		if rule.GroupID == "" && rule.DeviceType == deviceType {
+			specific[rule.Metric] = true
+		}
+	}
+
+	rules := make([]models.AlertRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		switch {
+		case rule.GroupID != "":
+		case rule.DeviceType == deviceType:
+			rules = append(rules, rule)
+		case rule.DeviceType == "" && !specific[rule.Metric]:
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// RulesForDevice returns the rules that apply to a device.
+// Rules of the groups the device belongs to replace the type and generic rules on the same metric,
+// and the thresholds configured by the device organization replace those of the rules.
+func (s *AlertService) RulesForDevice(ctx context.Context, device *models.Device) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	grouped := make(map[models.AlertMetric]bool)
+	groups := make(map[string]*models.DeviceGroup)
+
+	for _, rule := range s.rules {
+		if rule.GroupID == "" || (rule.DeviceType != "" && rule.DeviceType != device.DeviceType) {
+			continue
+		}
+
+		group, ok := groups[rule.GroupID]
+		if !ok {
+			var err error
+			group, err = s.groups.GetByID(ctx, rule.GroupID)
+			if err != nil && !errors.Is(err, models.ErrGroupNotFound) {
+				return nil, err
+			}
+			// Una regla de un grupo borrado no aplica a nadie
+			groups[rule.GroupID] = group
+		}
+
+		if group != nil && group.Contains(device) {
+			rules = append(rules, rule)
+			grouped[rule.Metric] = true
+		}
+	}
+
+	for _, rule := range s.RulesFor(device.DeviceType) {
+		if !grouped[rule.Metric] {
+			rules = append(rules, rule)
+		}
+	}
+
+	return s.applyTenantThresholds(ctx, device.OrgID, rules)
+}
+
+// ValidateThresholds checks tenant threshold overrides against the configured rules.
+func (s *AlertService) ValidateThresholds(thresholds map[string]*models.AlertThreshold) error {
+	for ruleID, threshold := range thresholds {
+		if threshold == nil {
+			continue
+		}
+		rule, ok := s.rule(ruleID)
+		if !ok {
+			return fmt.Errorf("%w: unknown rule %s", models.ErrInvalidAlertRule, ruleID)
+		}
+		if _, err := threshold.ApplyTo(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTenantThresholds reemplaza los umbrales de las reglas que la organización configuró.
+func (s *AlertService) applyTenantThresholds(ctx context.Context, orgID string, rules []models.AlertRule) ([]models.AlertRule, error) {
+	if orgID == "" {
+		return rules, nil
+	}
+
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if errors.Is(err, models.ErrOrganizationNotFound) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rule := range rules {
+		threshold, ok := org.Config.AlertThresholds[rule.RuleID]
+		if !ok {
+			continue
+		}
+		// Los umbrales se validan al guardarlos; uno que ya no encaja con la regla se ignora
+		if tenantRule, err := threshold.ApplyTo(rule); err == nil {
+			rules[i] = tenantRule
+		}
+	}
+	return rules, nil
+}
+
+// rule busca una regla por ID.
+func (s *AlertService) rule(ruleID string) (models.AlertRule, bool) {
+	for _, rule := range s.rules {
+		if rule.RuleID == ruleID {
+			return rule, true
+		}
+	}
+	return models.AlertRule{}, false
+}
+
+// EvaluateDevice applies every rule to the current device state.
+// It opens one alert per device and rule (repeated triggers only bump Occurrences)
+// and resolves active alerts once the value crosses the rule's clear threshold.
+func (s *AlertService) EvaluateDevice(ctx context.Context, device *models.Device) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules, err := s.RulesForDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, rule := range rules {
+		value, ok := device.MetricValue(rule.Metric)
+		if !ok {
+			continue
+		}
+
+		active, err := s.alerts.FindActive(ctx, device.DeviceID, rule.RuleID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case active == nil && rule.IsTriggered(value):
+			if err := s.openAlert(ctx, device, rule, value, now); err != nil {
+				return err
+			}
+
+		case active != nil && rule.IsTriggered(value):
+			active.Value = value
+			active.Occurrences++
+			active.LastTriggeredAt = now
+			if err := s.alerts.Update(ctx, active); err != nil {
+				return err
+			}
+
+		case active != nil && rule.IsCleared(value):
+			if err := active.Resolve("", "condition cleared"); err != nil {
+				return err
+			}
+			active.Value = value
+			if err := s.alerts.Update(ctx, active); err != nil {
+				return err
+			}
+
+			log.Info().
+				Str("alert_id", active.AlertID).
+				Str("device_id", device.DeviceID).
+				Str("rule_id", rule.RuleID).
+				Float64("value", value).
+				Msg("Alert resolved automatically")
+		}
+	}
+
+	return nil
+}
+
+// GetAlert retrieves an alert by ID.
+func (s *AlertService) GetAlert(ctx context.Context, alertID string) (*models.Alert, error) {
+	return s.alerts.GetByID(ctx, alertID)
+}
+
+// ListAlerts returns a page of alerts matching the filters.
+func (s *AlertService) ListAlerts(ctx context.Context, req *models.ListAlertsRequest) (*models.ListAlertsResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	alerts, total, err := s.alerts.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListAlertsResponse{
+		Alerts: alerts,
+		Total:  total,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}, nil
+}
+
+// AcknowledgeAlert moves an open alert to acknowledged.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, alertID string, req *models.AlertActionRequest) (*models.Alert, error) {
+	return s.modifyAlert(ctx, alertID, func(alert *models.Alert) error {
+		return alert.Acknowledge(models.ActorFromContext(ctx), req.Note)
+	})
+}
+
+// ResolveAlert closes an active alert manually.
+// Resolving an alert on a cumulative metric (total_errors) resets the device counter;
+// otherwise the next evaluation would reopen it right away.
+func (s *AlertService) ResolveAlert(ctx context.Context, alertID string, req *models.AlertActionRequest) (*models.Alert, error) {
+	alert, err := s.resolveAlert(ctx, alertID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if alert.Metric.IsCumulative() {
+		_, err := s.deviceManager.ModifyDevice(ctx, alert.DeviceID, func(device *models.Device) error {
+			device.ResetErrorCount()
+			return nil
+		})
+		if err != nil && !errors.Is(err, models.ErrDeviceNotFound) {
+			// La alerta ya quedó resuelta: el contador se reintenta en la próxima resolución
+			log.Error().
+				Err(err).
+				Str("alert_id", alert.AlertID).
+				Str("device_id", alert.DeviceID).
+				Msg("Failed to reset device error count")
+		}
+	}
+
+	return alert, nil
+}
+
+// resolveAlert cierra la alerta sin tocar el dispositivo.
+func (s *AlertService) resolveAlert(ctx context.Context, alertID string, req *models.AlertActionRequest) (*models.Alert, error) {
+	return s.modifyAlert(ctx, alertID, func(alert *models.Alert) error {
+		return alert.Resolve(models.ActorFromContext(ctx), req.Note)
+	})
+}
+
+// ResolveDeviceAlerts closes every active alert of a device, e.g. when it is decommissioned.
+// It returns the number of resolved alerts. Device counters are kept as they were.
+func (s *AlertService) ResolveDeviceAlerts(ctx context.Context, deviceID string, req *models.AlertActionRequest) (int, error) {
+	resolved := 0
+	for _, state := range []models.AlertState{models.AlertStateOpen, models.AlertStateAcknowledged} {
+		alerts, _, err := s.alerts.List(ctx, &models.ListAlertsRequest{DeviceID: deviceID, State: state})
+		if err != nil {
+			return resolved, err
+		}
+
+		for _, alert := range alerts {
+			_, err := s.resolveAlert(ctx, alert.AlertID, req)
+			switch {
+			case err == nil:
+				resolved++
+			case !errors.Is(err, models.ErrInvalidTransition):
+				// ErrInvalidTransition: se resolvió entre el List y el Resolve
+				return resolved, err
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// modifyAlert aplica modify a la alerta y la guarda.
+func (s *AlertService) modifyAlert(ctx context.Context, alertID string, modify func(*models.Alert) error) (*models.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, err := s.alerts.GetByID(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := modify(alert); err != nil {
+		return nil, err
+	}
+
+	if err := s.alerts.Update(ctx, alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// openAlert crea una alerta nueva para la regla.
+func (s *AlertService) openAlert(
+	ctx context.Context,
+	device *models.Device,
+	rule models.AlertRule,
+	value float64,
+	now time.Time,
+) error {
+	alert := &models.Alert{
+		AlertID:         "alert_" + uuid.New().String()[:8],
+		OrgID:           device.OrgID,
+		DeviceID:        device.DeviceID,
+		DeviceType:      device.DeviceType,
+		RuleID:          rule.RuleID,
+		Metric:          rule.Metric,
+		Severity:        rule.Severity,
+		State:           models.AlertStateOpen,
+		Message:         rule.Message,
+		Value:           value,
+		Threshold:       rule.Threshold,
+		Occurrences:     1,
+		OpenedAt:        now,
+		LastTriggeredAt: now,
+	}
+
+	if err := s.alerts.Create(ctx, alert); err != nil {
+		return err
+	}
+
+	log.Warn().
+		Str("alert_id", alert.AlertID).
+		Str("device_id", device.DeviceID).
+		Str("rule_id", rule.RuleID).
+		Str("severity", string(rule.Severity)).
+		Float64("value", value).
+		Msg("Alert opened")
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestAlertServiceHysteresis verifica la deduplicación y la resolución con histéresis.
+func TestAlertServiceHysteresis(t *testing.T) {
+	ctx := context.Background()
+	repository := local.NewAlertRepository()
+	service := NewAlertService(repository, local.NewGroupRepository(), local.NewOrganizationRepository(), NewDeviceManager(local.NewDeviceRepository()), models.DefaultAlertRules())
+
+	device := &models.Device{DeviceID: "smart-bin-001", DeviceType: string(models.DeviceTypeSmartBinV1)}
+
+	steps := []struct {
+		name            string
+		fill            int
+		wantState       models.AlertState
+		wantOccurrences int
+	}{
+		{"Crosses threshold", 95, models.AlertStateOpen, 1},
+		{"Inside hysteresis band", 85, models.AlertStateOpen, 1},
+		{"Triggers again", 97, models.AlertStateOpen, 2},
+		{"Below clear threshold", 75, models.AlertStateResolved, 2},
+	}
+
+	for _, step := range steps {
+		fill := step.fill
+		device.FillLevel = &fill
+		if err := service.EvaluateDevice(ctx, device); err != nil {
+			t.Fatalf("%s: EvaluateDevice() error = %v", step.name, err)
+		}
+
+		alerts, total, err := repository.List(ctx, &models.ListAlertsRequest{DeviceID: device.DeviceID})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if total != 1 {
+			t.Fatalf("%s: alerts = %d, want 1", step.name, total)
+		}
+		if alerts[0].State != step.wantState {
+			t.Errorf("%s: state = %v, want %v", step.name, alerts[0].State, step.wantState)
+		}
+		if alerts[0].Occurrences != step.wantOccurrences {
+			t.Errorf("%s: occurrences = %d, want %d", step.name, alerts[0].Occurrences, step.wantOccurrences)
+		}
+	}
+
+	// Un nuevo cruce tras resolver abre una alerta distinta
+	fill := 92
+	device.FillLevel = &fill
+	if err := service.EvaluateDevice(ctx, device); err != nil {
+		t.Fatalf("EvaluateDevice() error = %v", err)
+	}
+	if _, total, _ := repository.List(ctx, &models.ListAlertsRequest{State: models.AlertStateOpen}); total != 1 {
+		t.Errorf("Open alerts = %d, want 1", total)
+	}
+}
+
+// TestAlertServiceResolveCumulative verifica que una alerta errors_high resuelta a mano no se
+// reabra con el siguiente error: resolverla reinicia el contador del dispositivo.
+func TestAlertServiceResolveCumulative(t *testing.T) {
+	ctx := context.Background()
+	repository := local.NewAlertRepository()
+	devices := local.NewDeviceRepository()
+	deviceManager := NewDeviceManager(devices)
+	service := NewAlertService(repository, local.NewGroupRepository(), local.NewOrganizationRepository(), deviceManager, models.DefaultAlertRules())
+
+	if err := devices.Create(ctx, &models.Device{DeviceID: "smart-bin-001", Status: models.DeviceStatusActive, TotalErrors: 11}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	reportError := func() {
+		t.Helper()
+		device, err := deviceManager.ModifyDevice(ctx, "smart-bin-001", func(device *models.Device) error {
+			device.IncrementErrorCount()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ModifyDevice() error = %v", err)
+		}
+		if err := service.EvaluateDevice(ctx, device); err != nil {
+			t.Fatalf("EvaluateDevice() error = %v", err)
+		}
+	}
+
+	reportError()
+	alerts, total, _ := repository.List(ctx, &models.ListAlertsRequest{State: models.AlertStateOpen})
+	if total != 1 || alerts[0].RuleID != "errors_high" {
+		t.Fatalf("Open alerts = %v, want errors_high", alerts)
+	}
+	if _, err := service.ResolveAlert(ctx, alerts[0].AlertID, &models.AlertActionRequest{Note: "sensor replaced"}); err != nil {
+		t.Fatalf("ResolveAlert() error = %v", err)
+	}
+
+	reportError()
+	if _, total, _ := repository.List(ctx, &models.ListAlertsRequest{State: models.AlertStateOpen}); total != 0 {
+		t.Errorf("Open alerts after a new error = %d, want 0", total)
+	}
+	if device, _ := deviceManager.GetDevice(ctx, "smart-bin-001"); device.TotalErrors != 1 {
+		t.Errorf("TotalErrors = %d, want 1", device.TotalErrors)
+	}
+
+	// Los errores acumulados desde la resolución vuelven a abrirla
+	for i := 0; i < 10; i++ {
+		reportError()
+	}
+	if _, total, _ := repository.List(ctx, &models.ListAlertsRequest{State: models.AlertStateOpen}); total != 1 {
+		t.Errorf("Open alerts after 11 new errors = %d, want 1", total)
+	}
+}
+
+// TestAlertServiceAcknowledge verifica las transiciones manuales.
+func TestAlertServiceAcknowledge(t *testing.T) {
+	ctx := context.Background()
+	repository := local.NewAlertRepository()
+	service := NewAlertService(repository, local.NewGroupRepository(), local.NewOrganizationRepository(), NewDeviceManager(local.NewDeviceRepository()), models.DefaultAlertRules())
+
+	battery := 10
+	device := &models.Device{DeviceID: "smart-bin-001", BatteryLevel: &battery}
+	if err := service.EvaluateDevice(ctx, device); err != nil {
+		t.Fatalf("EvaluateDevice() error = %v", err)
+	}
+
+	alerts, _, _ := repository.List(ctx, &models.ListAlertsRequest{})
+	alertID := alerts[0].AlertID
+	action := &models.AlertActionRequest{Note: "technician on the way"}
+
+	// El actor sale de la credencial de la petición
+	ctx = models.WithPrincipal(ctx, &models.Principal{CredentialID: "cred_operator"})
+	alert, err := service.AcknowledgeAlert(ctx, alertID, action)
+	if err != nil {
+		t.Fatalf("AcknowledgeAlert() error = %v", err)
+	}
+	if alert.State != models.AlertStateAcknowledged || alert.AcknowledgedBy != "cred_operator" {
+		t.Errorf("Unexpected alert after acknowledge: %+v", alert)
+	}
+
+	if _, err := service.AcknowledgeAlert(ctx, alertID, action); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Errorf("Second acknowledge error = %v, want ErrInvalidTransition", err)
+	}
+
+	if _, err := service.ResolveAlert(ctx, alertID, action); err != nil {
+		t.Fatalf("ResolveAlert() error = %v", err)
+	}
+
+	if _, err := service.ResolveAlert(ctx, "alert_missing", action); !errors.Is(err, models.ErrAlertNotFound) {
+		t.Errorf("Resolve missing alert error = %v, want ErrAlertNotFound", err)
+	}
+}
+
+// TestAlertServiceRulesFor verifica que una regla por tipo reemplaza a la genérica.
+func TestAlertServiceRulesFor(t *testing.T) {
+	rules := append(models.DefaultAlertRules(), models.AlertRule{
+		RuleID:         "industrial_fill_high",
+		DeviceType:     string(models.DeviceTypeSmartBinIndustrial),
+		Metric:         models.AlertMetricFillLevel,
+		Operator:       models.AlertOperatorAbove,
+		Threshold:      95,
+		ClearThreshold: 85,
+		Severity:       models.AlertSeverityCritical,
+	})
+	service := NewAlertService(local.NewAlertRepository(), local.NewGroupRepository(), local.NewOrganizationRepository(), NewDeviceManager(local.NewDeviceRepository()), rules)
+
+	for _, rule := range service.RulesFor(string(models.DeviceTypeSmartBinIndustrial)) {
+		if rule.RuleID == "fill_high" {
+			t.Error("Generic fill_high should be replaced by the industrial rule")
+		}
+	}
+	if got := len(service.RulesFor(string(models.DeviceTypeSmartBinV1))); got != 3 {
+		t.Errorf("Rules for smart_bin_v1 = %d, want 3", got)
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// auditCSVHeader - Columnas del export CSV.
+var auditCSVHeader = []string{
+	"sequence", "entry_id", "created_at", "org_id", "actor", "action", "resource_type", "resource_id",
+	"request_id", "ip", "status_code", "changes", "prev_hash", "hash",
+}
+
+// AuditService registra las modificaciones en una cadena de hashes append-only y la consulta.
+type AuditService struct {
+	entries ports.AuditRepository
+
+	// mu serializa el sellado: cada entrada se encadena a la última guardada
+	mu sync.Mutex
+}
+
+// NewAuditService crea una nueva instancia de AuditService.
+func NewAuditService(entries ports.AuditRepository) *AuditService {
+	return &AuditService{entries: entries}
+}
+
+// Record seals the entry to the end of the chain and appends it.
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, err := s.entries.Last(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry.EntryID = "aud_" + uuid.New().String()[:8]
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	if entry.Changes == nil {
+		entry.Changes = []models.AuditChange{}
+	}
+	if err := entry.Seal(last); err != nil {
+		return err
+	}
+
+	if err := s.entries.Append(ctx, entry); err != nil {
+		return err
+	}
+
+	log.Debug().
+		Int64("sequence", entry.Sequence).
+		Str("actor", entry.Actor).
+		Str("action", entry.Action).
+		Str("request_id", entry.RequestID).
+		Msg("Audit entry recorded")
+
+	return nil
+}
+
+// ListEntries returns a page of the caller's audit entries, newest first.
+func (s *AuditService) ListEntries(ctx context.Context, req *models.ListAuditRequest) (*models.ListAuditResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	entries, total, err := s.entries.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListAuditResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}, nil
+}
+
+// ExportEntries writes every entry matching the filters, oldest first, as JSON lines or CSV.
+func (s *AuditService) ExportEntries(ctx context.Context, req *models.ListAuditRequest, w io.Writer) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	filter := *req
+	filter.Limit, filter.Offset = 0, 0
+	entries, _, err := s.entries.List(ctx, &filter)
+	if err != nil {
+		return err
+	}
+
+	if req.Format == models.AuditExportCSV {
+		return writeAuditCSV(w, entries)
+	}
+
+	encoder := json.NewEncoder(w)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := encoder.Encode(entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyChain recomputes every hash of the chain and reports the first broken entry.
+func (s *AuditService) VerifyChain(ctx context.Context) (*models.AuditVerification, error) {
+	// La cadena es global: se verifica completa, sin el scope del principal
+	entries, _, err := s.entries.List(models.WithPrincipal(ctx, nil), &models.ListAuditRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]*models.AuditEntry, len(entries))
+	for i, entry := range entries {
+		ordered[len(entries)-1-i] = entry
+	}
+
+	verification := models.VerifyAuditChain(ordered)
+	if !verification.Valid {
+		log.Error().
+			Int64("broken_at", *verification.BrokenAt).
+			Str("reason", verification.Reason).
+			Msg("Audit chain verification failed")
+	}
+	return verification, nil
+}
+
+// writeAuditCSV escribe las entradas en CSV, de la más antigua a la más reciente.
+func writeAuditCSV(w io.Writer, entries []*models.AuditEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		changes, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return fmt.Errorf("encoding audit changes: %w", err)
+		}
+
+		if err := writer.Write([]string{
+			strconv.FormatInt(entry.Sequence, 10),
+			entry.EntryID,
+			entry.CreatedAt.Format(time.RFC3339Nano),
+			entry.OrgID,
+			entry.Actor,
+			entry.Action,
+			entry.ResourceType,
+			entry.ResourceID,
+			entry.RequestID,
+			entry.IP,
+			strconv.Itoa(entry.StatusCode),
+			string(changes),
+			entry.PrevHash,
+			entry.Hash,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// recordTestEntries registra una modificación por organización (uniandes, mall-centro, uniandes).
+func recordTestEntries(t *testing.T, service *AuditService) {
+	t.Helper()
+
+	for _, entry := range []*models.AuditEntry{
+		{OrgID: "uniandes", Actor: "cred_a", Action: "PATCH /api/v1/devices/:device_id", ResourceType: "devices", ResourceID: "bin-1", RequestID: "req_1"},
+		{OrgID: "mall-centro", Actor: "cred_b", Action: "POST /api/v1/groups", ResourceType: "groups", RequestID: "req_2"},
+		{OrgID: "uniandes", Actor: "cred_a", Action: "DELETE /api/v1/devices/:device_id", ResourceType: "devices", ResourceID: "bin-1", RequestID: "req_3"},
+	} {
+		if err := service.Record(context.Background(), entry); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+}
+
+// TestAuditRecord verifica el encadenamiento de las entradas registradas.
+func TestAuditRecord(t *testing.T) {
+	ctx := context.Background()
+	service := NewAuditService(local.NewAuditRepository())
+	recordTestEntries(t, service)
+
+	response, err := service.ListEntries(ctx, &models.ListAuditRequest{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if response.Total != 3 || response.Entries[0].Sequence != 3 {
+		t.Fatalf("Entries = %d (first %d), want 3 newest first", response.Total, response.Entries[0].Sequence)
+	}
+	if response.Entries[0].PrevHash != response.Entries[1].Hash || response.Entries[2].PrevHash != models.AuditGenesisHash {
+		t.Errorf("Entries are not chained")
+	}
+	if response.Entries[0].EntryID == "" || response.Entries[0].CreatedAt.IsZero() {
+		t.Errorf("Entry = %+v, want ID and timestamp", response.Entries[0])
+	}
+
+	verification, err := service.VerifyChain(ctx)
+	if err != nil {
+		t.Fatalf("VerifyChain() error = %v", err)
+	}
+	if !verification.Valid || verification.Entries != 3 || verification.LastHash != response.Entries[0].Hash {
+		t.Errorf("Verification = %+v, want valid chain of 3", verification)
+	}
+}
+
+// TestAuditListEntries verifica los filtros y el aislamiento por organización.
+func TestAuditListEntries(t *testing.T) {
+	service := NewAuditService(local.NewAuditRepository())
+	recordTestEntries(t, service)
+
+	tenant := models.WithPrincipal(context.Background(), &models.Principal{OrgID: "uniandes"})
+	response, err := service.ListEntries(tenant, &models.ListAuditRequest{})
+	if err != nil {
+		t.Fatalf("ListEntries(tenant) error = %v", err)
+	}
+	if response.Total != 2 {
+		t.Errorf("Tenant entries = %d, want 2", response.Total)
+	}
+
+	response, err = service.ListEntries(context.Background(), &models.ListAuditRequest{RequestID: "req_2"})
+	if err != nil {
+		t.Fatalf("ListEntries(request_id) error = %v", err)
+	}
+	if response.Total != 1 || response.Entries[0].ResourceType != "groups" {
+		t.Errorf("Entries = %+v, want req_2", response.Entries)
+	}
+
+	if _, err := service.ListEntries(context.Background(), &models.ListAuditRequest{Format: "xml"}); !errors.Is(err, models.ErrInvalidInput) {
+		t.Errorf("ListEntries(xml) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+// TestAuditExportEntries verifica el export JSONL y CSV, de la entrada más antigua a la más reciente.
+func TestAuditExportEntries(t *testing.T) {
+	ctx := context.Background()
+	service := NewAuditService(local.NewAuditRepository())
+	recordTestEntries(t, service)
+
+	var jsonl bytes.Buffer
+	if err := service.ExportEntries(ctx, &models.ListAuditRequest{Actor: "cred_a", Limit: 1}, &jsonl); err != nil {
+		t.Fatalf("ExportEntries(jsonl) error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(jsonl.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("JSONL lines = %d, want 2 (export ignores limit)", len(lines))
+	}
+	var first models.AuditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil || first.Sequence != 1 {
+		t.Errorf("First JSONL entry = %+v (%v), want sequence 1", first, err)
+	}
+
+	var out bytes.Buffer
+	if err := service.ExportEntries(ctx, &models.ListAuditRequest{Format: models.AuditExportCSV}, &out); err != nil {
+		t.Fatalf("ExportEntries(csv) error = %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("Reading CSV error = %v", err)
+	}
+	if len(records) != 4 || records[0][0] != "sequence" || records[1][0] != "1" || records[3][0] != "3" {
+		t.Errorf("CSV = %v, want header and 3 entries in order", records)
+	}
+}
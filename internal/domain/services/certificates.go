@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// CertificateService emite, rota y revoca los certificados X.509 de los dispositivos con la CA interna.
+type CertificateService struct {
+	config        *config.Config
+	ca            ports.CertificateAuthority
+	certificates  ports.CertificateRepository
+	deviceManager *DeviceManager
+
+	// mu serializa rotaciones y revocaciones para que no se pisen entre sí.
+	mu sync.Mutex
+}
+
+// NewCertificateService crea una nueva instancia de CertificateService.
+func NewCertificateService(
+	cfg *config.Config,
+	ca ports.CertificateAuthority,
+	certificates ports.CertificateRepository,
+	deviceManager *DeviceManager,
+) *CertificateService {
+	return &CertificateService{
+		config:        cfg,
+		ca:            ca,
+		certificates:  certificates,
+		deviceManager: deviceManager,
+	}
+}
+
+// IssueCertificate signs a certificate for the device, from its CSR or from a freshly generated key pair.
+// The certificate is not tracked until TrackCertificate (the device may still fail to register).
+func (s *CertificateService) IssueCertificate(ctx context.Context, deviceID, csrPEM string) (*models.DeviceCertificate, error) {
+	if deviceID == "" {
+		return nil, models.ErrInvalidDeviceID
+	}
+
+	certificate, err := s.ca.IssueDeviceCertificate(ctx, deviceID, csrPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("device_id", deviceID).
+		Str("serial", certificate.SerialNumber).
+		Time("expires_at", certificate.ExpiresAt).
+		Bool("generated_key", certificate.PrivateKeyPEM != "").
+		Msg("Device certificate issued")
+
+	return certificate, nil
+}
+
+// TrackCertificate stores an issued certificate (never its private key) so mTLS accepts it.
+func (s *CertificateService) TrackCertificate(ctx context.Context, certificate *models.DeviceCertificate) error {
+	return s.certificates.Save(ctx, certificate)
+}
+
+// CACertificate returns the device CA certificate (PEM).
+func (s *CertificateService) CACertificate() string {
+	return s.ca.CACertificatePEM()
+}
+
+// RotateCertificate issues a new certificate for the device and supersedes its active ones.
+// The previous certificates keep working for the overlap period so the device can switch over.
+func (s *CertificateService) RotateCertificate(
+	ctx context.Context,
+	deviceID string,
+	req *models.RotateCertificateRequest,
+) (*models.RotateCertificateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	overlap := s.config.Security.DeviceCA.RotationOverlap
+	if req.OverlapHours != nil {
+		overlap = time.Duration(*req.OverlapHours) * time.Hour
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	device, err := s.deviceManager.GetDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !device.IsReachable() {
+		return nil, models.ErrDeviceInactive
+	}
+
+	previous, err := s.certificates.List(ctx, &models.ListCertificatesRequest{
+		DeviceID: deviceID,
+		Status:   models.CertificateStatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := s.IssueCertificate(ctx, deviceID, req.CSR)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.certificates.Save(ctx, certificate); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	superseded := make([]string, 0, len(previous))
+	for _, old := range previous {
+		old.Supersede(now, overlap)
+		if err := s.certificates.Save(ctx, old); err != nil {
+			return nil, err
+		}
+		superseded = append(superseded, old.SerialNumber)
+	}
+
+	if _, err := s.deviceManager.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+		certificate.ApplyTo(device)
+		device.UpdatedAt = now
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("device_id", deviceID).
+		Str("serial", certificate.SerialNumber).
+		Strs("superseded", superseded).
+		Dur("overlap", overlap).
+		Msg("Device certificate rotated")
+
+	return &models.RotateCertificateResponse{
+		DeviceCertificate: certificate,
+		CACertificate:     s.ca.CACertificatePEM(),
+		SupersededSerials: superseded,
+	}, nil
+}
+
+// RevokeCertificate revokes a certificate by serial; it is rejected by mTLS and listed in the CRL.
+func (s *CertificateService) RevokeCertificate(
+	ctx context.Context,
+	serial string,
+	req *models.RevokeCertificateRequest,
+) (*models.DeviceCertificate, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	certificate, err := s.certificates.GetBySerial(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := certificate.Revoke(req.Reason, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.certificates.Save(ctx, certificate); err != nil {
+		return nil, err
+	}
+
+	log.Warn().
+		Str("device_id", certificate.DeviceID).
+		Str("serial", serial).
+		Str("reason", string(req.Reason)).
+		Msg("Device certificate revoked")
+
+	return certificate, nil
+}
+
+// RevokeDeviceCertificates revokes every certificate of the device that is not revoked yet.
+// It returns the revoked serials.
+func (s *CertificateService) RevokeDeviceCertificates(
+	ctx context.Context,
+	deviceID string,
+	reason models.RevocationReason,
+) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	certificates, err := s.certificates.List(ctx, &models.ListCertificatesRequest{DeviceID: deviceID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	revoked := []string{}
+	for _, certificate := range certificates {
+		if certificate.Status == models.CertificateStatusRevoked {
+			continue
+		}
+		if err := certificate.Revoke(reason, now); err != nil {
+			return revoked, err
+		}
+		if err := s.certificates.Save(ctx, certificate); err != nil {
+			return revoked, err
+		}
+		revoked = append(revoked, certificate.SerialNumber)
+	}
+
+	if len(revoked) > 0 {
+		log.Warn().
+			Str("device_id", deviceID).
+			Strs("serials", revoked).
+			Str("reason", string(reason)).
+			Msg("Device certificates revoked")
+	}
+
+	return revoked, nil
+}
+
+// ListDeviceCertificates returns every certificate issued to the device.
+func (s *CertificateService) ListDeviceCertificates(ctx context.Context, deviceID string) ([]*models.DeviceCertificate, error) {
+	if _, err := s.deviceManager.GetDevice(ctx, deviceID); err != nil {
+		return nil, err
+	}
+	return s.certificates.List(ctx, &models.ListCertificatesRequest{DeviceID: deviceID})
+}
+
+// CRL returns a freshly signed certificate revocation list (PEM).
+func (s *CertificateService) CRL(ctx context.Context) (string, error) {
+	revoked, err := s.certificates.List(ctx, &models.ListCertificatesRequest{Status: models.CertificateStatusRevoked})
+	if err != nil {
+		return "", err
+	}
+	return s.ca.CreateCRL(ctx, revoked, time.Now().Add(s.config.Security.DeviceCA.CRLValidity))
+}
+
+// AuthenticateDevice checks the client certificate presented on the mTLS listener:
+// it must be a tracked certificate of the device that is neither revoked nor past its
+// rotation overlap, and the device must be active.
+func (s *CertificateService) AuthenticateDevice(ctx context.Context, deviceID, serial string) (*models.Device, error) {
+	certificate, err := s.certificates.GetBySerial(ctx, serial)
+	if errors.Is(err, models.ErrCertificateNotFound) {
+		return nil, fmt.Errorf("%w: unknown certificate %s", models.ErrUnauthorized, serial)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if certificate.DeviceID != deviceID {
+		return nil, fmt.Errorf("%w: certificate %s belongs to %s", models.ErrUnauthorized, serial, certificate.DeviceID)
+	}
+	if err := certificate.CheckAccepted(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.deviceManager.AuthenticateDevice(ctx, deviceID)
+}
+
+// ExpiringCertificates returns the active certificates that expire within days (default from config).
+func (s *CertificateService) ExpiringCertificates(
+	ctx context.Context,
+	req *models.ExpiringCertificatesRequest,
+	now time.Time,
+) (*models.ExpiringCertificatesResponse, error) {
+	days := req.Days
+	if days == 0 {
+		days = s.config.Security.DeviceCA.ExpiryWarningDays
+	}
+	if days < 0 || days > 3650 {
+		return nil, fmt.Errorf("%w: days must be between 1 and 3650", models.ErrInvalidInput)
+	}
+
+	before := now.AddDate(0, 0, days)
+	certificates, err := s.certificates.List(ctx, &models.ListCertificatesRequest{
+		Status:        models.CertificateStatusActive,
+		ExpiresBefore: &before,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExpiringCertificatesResponse{
+		Certificates: certificates,
+		Total:        len(certificates),
+		Days:         days,
+	}, nil
+}
+
+// RetireSuperseded revokes (reason superseded) the rotated certificates whose overlap ended,
+// so they show up in the CRL. Returns how many were revoked.
+func (s *CertificateService) RetireSuperseded(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	superseded, err := s.certificates.List(ctx, &models.ListCertificatesRequest{Status: models.CertificateStatusSuperseded})
+	if err != nil {
+		return 0, err
+	}
+
+	retired := 0
+	for _, certificate := range superseded {
+		if !certificate.OverlapEnded(now) {
+			continue
+		}
+		if err := certificate.Revoke(models.RevocationSuperseded, now); err != nil {
+			return retired, err
+		}
+		if err := s.certificates.Save(ctx, certificate); err != nil {
+			return retired, err
+		}
+		retired++
+	}
+
+	return retired, nil
+}
+
+// Start retires superseded certificates and reports the ones about to expire every ExpiryScanInterval
+// until ctx is canceled.
+func (s *CertificateService) Start(ctx context.Context) {
+	interval := s.config.Security.DeviceCA.ExpiryScanInterval
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.scan(ctx, now)
+			}
+		}
+	}()
+}
+
+// scan ejecuta una pasada del job de certificados.
+func (s *CertificateService) scan(ctx context.Context, now time.Time) {
+	retired, err := s.RetireSuperseded(ctx, now)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to retire superseded certificates")
+	}
+	if retired > 0 {
+		log.Info().Int("retired", retired).Msg("Superseded device certificates revoked")
+	}
+
+	expiring, err := s.ExpiringCertificates(ctx, &models.ExpiringCertificatesRequest{}, now)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list expiring certificates")
+		return
+	}
+	for _, certificate := range expiring.Certificates {
+		log.Warn().
+			Str("device_id", certificate.DeviceID).
+			Str("serial", certificate.SerialNumber).
+			Time("expires_at", certificate.ExpiresAt).
+			Msg("Device certificate expiring soon, rotate it")
+	}
+	if expiring.Total > 0 {
+		log.Warn().
+			Int("expiring", expiring.Total).
+			Int("days", expiring.Days).
+			Msg("Device certificates expiring within the warning window")
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/pki"
+)
+
+// TestIssueCertificate verifica la emisión con llave generada y con CSR, y el rechazo de CSRs ajenos.
+func TestIssueCertificate(t *testing.T) {
+	ctx := context.Background()
+	authority, err := pki.NewEphemeralAuthority(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("NewEphemeralAuthority() error = %v", err)
+	}
+	certificates := NewCertificateService(testConfig(), authority, local.NewCertificateRepository(), NewDeviceManager(local.NewDeviceRepository()))
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(certificates.CACertificate())) {
+		t.Fatal("CA certificate is not valid PEM")
+	}
+
+	verify := func(t *testing.T, issued *models.DeviceCertificate) *x509.Certificate {
+		t.Helper()
+		block, _ := pem.Decode([]byte(issued.CertificatePEM))
+		if block == nil {
+			t.Fatal("Issued certificate is not PEM")
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("ParseCertificate() error = %v", err)
+		}
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		if cert.Subject.CommonName != issued.DeviceID || len(cert.DNSNames) != 1 || cert.DNSNames[0] != issued.DeviceID {
+			t.Errorf("CN = %s, SAN = %v, want %s", cert.Subject.CommonName, cert.DNSNames, issued.DeviceID)
+		}
+		if pki.SerialHex(cert.SerialNumber) != issued.SerialNumber || !cert.NotAfter.Equal(issued.ExpiresAt) {
+			t.Errorf("Serial/expiry do not match the certificate")
+		}
+		return cert
+	}
+
+	t.Run("Generated key pair", func(t *testing.T) {
+		issued, err := certificates.IssueCertificate(ctx, "smart-bin-001", "")
+		if err != nil {
+			t.Fatalf("IssueCertificate() error = %v", err)
+		}
+		if issued.PrivateKeyPEM == "" {
+			t.Fatal("Generated key must be returned")
+		}
+		cert := verify(t, issued)
+		if d := time.Until(cert.NotAfter); d < 29*24*time.Hour || d > 31*24*time.Hour {
+			t.Errorf("Validity = %s, want ~30 days", d)
+		}
+	})
+
+	csr := func(t *testing.T, commonName string) string {
+		t.Helper()
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: commonName}}, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+	}
+
+	t.Run("Device CSR", func(t *testing.T) {
+		issued, err := certificates.IssueCertificate(ctx, "smart-bin-002", csr(t, "smart-bin-002"))
+		if err != nil {
+			t.Fatalf("IssueCertificate() error = %v", err)
+		}
+		if issued.PrivateKeyPEM != "" {
+			t.Error("Private key must not be returned for a CSR")
+		}
+		verify(t, issued)
+	})
+
+	t.Run("CSR for another device", func(t *testing.T) {
+		if _, err := certificates.IssueCertificate(ctx, "smart-bin-003", csr(t, "smart-bin-999")); !errors.Is(err, models.ErrInvalidCSR) {
+			t.Errorf("IssueCertificate() error = %v, want %v", err, models.ErrInvalidCSR)
+		}
+	})
+
+	t.Run("Malformed CSR", func(t *testing.T) {
+		if _, err := certificates.IssueCertificate(ctx, "smart-bin-003", "not a csr"); !errors.Is(err, models.ErrInvalidCSR) {
+			t.Errorf("IssueCertificate() error = %v, want %v", err, models.ErrInvalidCSR)
+		}
+	})
+}
+
+// TestRotateAndRevokeCertificate verifica el solapamiento al rotar, la revocación y la CRL.
+func TestRotateAndRevokeCertificate(t *testing.T) {
+	ctx := context.Background()
+	authority, err := pki.NewEphemeralAuthority(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("NewEphemeralAuthority() error = %v", err)
+	}
+	devices := local.NewDeviceRepository()
+	certificates := NewCertificateService(testConfig(), authority, local.NewCertificateRepository(), NewDeviceManager(devices))
+
+	if err := devices.Create(ctx, &models.Device{DeviceID: "smart-bin-001", Status: models.DeviceStatusActive}); err != nil {
+		t.Fatalf("Create device: %v", err)
+	}
+	original, err := certificates.IssueCertificate(ctx, "smart-bin-001", "")
+	if err != nil {
+		t.Fatalf("IssueCertificate() error = %v", err)
+	}
+	if err := certificates.TrackCertificate(ctx, original); err != nil {
+		t.Fatalf("TrackCertificate() error = %v", err)
+	}
+
+	overlap := 1
+	rotated, err := certificates.RotateCertificate(ctx, "smart-bin-001", &models.RotateCertificateRequest{OverlapHours: &overlap})
+	if err != nil {
+		t.Fatalf("RotateCertificate() error = %v", err)
+	}
+	if len(rotated.SupersededSerials) != 1 || rotated.SupersededSerials[0] != original.SerialNumber {
+		t.Errorf("SupersededSerials = %v, want [%s]", rotated.SupersededSerials, original.SerialNumber)
+	}
+
+	device, _ := devices.GetByID(ctx, "smart-bin-001")
+	if device.CertificateSerial != rotated.SerialNumber {
+		t.Errorf("Device certificate = %s, want %s", device.CertificateSerial, rotated.SerialNumber)
+	}
+
+	// Durante el solape se aceptan ambos certificados
+	for _, serial := range []string{original.SerialNumber, rotated.SerialNumber} {
+		if _, err := certificates.AuthenticateDevice(ctx, "smart-bin-001", serial); err != nil {
+			t.Errorf("AuthenticateDevice(%s) during overlap error = %v", serial, err)
+		}
+	}
+	if _, err := certificates.AuthenticateDevice(ctx, "smart-bin-002", rotated.SerialNumber); !errors.Is(err, models.ErrUnauthorized) {
+		t.Errorf("AuthenticateDevice(other device) error = %v, want %v", err, models.ErrUnauthorized)
+	}
+
+	retired, err := certificates.RetireSuperseded(ctx, time.Now().Add(2*time.Hour))
+	if err != nil || retired != 1 {
+		t.Fatalf("RetireSuperseded() = %d, %v, want 1", retired, err)
+	}
+	if _, err := certificates.AuthenticateDevice(ctx, "smart-bin-001", original.SerialNumber); !errors.Is(err, models.ErrCertificateRevoked) {
+		t.Errorf("AuthenticateDevice(after overlap) error = %v, want %v", err, models.ErrCertificateRevoked)
+	}
+
+	if _, err := certificates.RevokeCertificate(ctx, rotated.SerialNumber, &models.RevokeCertificateRequest{Reason: models.RevocationKeyCompromise}); err != nil {
+		t.Fatalf("RevokeCertificate() error = %v", err)
+	}
+	if _, err := certificates.RevokeCertificate(ctx, rotated.SerialNumber, &models.RevokeCertificateRequest{}); !errors.Is(err, models.ErrCertificateRevoked) {
+		t.Errorf("RevokeCertificate(twice) error = %v, want %v", err, models.ErrCertificateRevoked)
+	}
+	if _, err := certificates.AuthenticateDevice(ctx, "smart-bin-001", rotated.SerialNumber); !errors.Is(err, models.ErrCertificateRevoked) {
+		t.Errorf("AuthenticateDevice(revoked) error = %v, want %v", err, models.ErrCertificateRevoked)
+	}
+
+	crlPEM, err := certificates.CRL(ctx)
+	if err != nil {
+		t.Fatalf("CRL() error = %v", err)
+	}
+	block, _ := pem.Decode([]byte(crlPEM))
+	if block == nil || block.Type != "X509 CRL" {
+		t.Fatal("CRL is not PEM")
+	}
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseRevocationList() error = %v", err)
+	}
+	if err := crl.CheckSignatureFrom(authority.Certificate()); err != nil {
+		t.Errorf("CRL signature error = %v", err)
+	}
+
+	reasons := map[string]int{}
+	for _, entry := range crl.RevokedCertificateEntries {
+		reasons[pki.SerialHex(entry.SerialNumber)] = entry.ReasonCode
+	}
+	if len(reasons) != 2 || reasons[original.SerialNumber] != 4 || reasons[rotated.SerialNumber] != 1 {
+		t.Errorf("CRL entries = %v, want superseded (4) and key compromise (1)", reasons)
+	}
+}
+
+// TestExpiringCertificates verifica la ventana del reporte de certificados por expirar.
+func TestExpiringCertificates(t *testing.T) {
+	ctx := context.Background()
+	authority, err := pki.NewEphemeralAuthority(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("NewEphemeralAuthority() error = %v", err)
+	}
+	certificates := NewCertificateService(testConfig(), authority, local.NewCertificateRepository(), NewDeviceManager(local.NewDeviceRepository()))
+
+	issued, err := certificates.IssueCertificate(ctx, "smart-bin-001", "")
+	if err != nil {
+		t.Fatalf("IssueCertificate() error = %v", err)
+	}
+	if err := certificates.TrackCertificate(ctx, issued); err != nil {
+		t.Fatalf("TrackCertificate() error = %v", err)
+	}
+
+	tests := []struct {
+		days      int
+		wantDays  int
+		wantTotal int
+	}{
+		{days: 7, wantDays: 7, wantTotal: 0},
+		{days: 0, wantDays: 30, wantTotal: 1},
+		{days: 31, wantDays: 31, wantTotal: 1},
+	}
+
+	for _, tt := range tests {
+		response, err := certificates.ExpiringCertificates(ctx, &models.ExpiringCertificatesRequest{Days: tt.days}, time.Now())
+		if err != nil {
+			t.Fatalf("ExpiringCertificates(%d) error = %v", tt.days, err)
+		}
+		if response.Days != tt.wantDays || response.Total != tt.wantTotal {
+			t.Errorf("ExpiringCertificates(%d) = %d days, %d certificates, want %d, %d",
+				tt.days, response.Days, response.Total, tt.wantDays, tt.wantTotal)
+		}
+	}
+
+	if _, err := certificates.ExpiringCertificates(ctx, &models.ExpiringCertificatesRequest{Days: -1}, time.Now()); !errors.Is(err, models.ErrInvalidInput) {
+		t.Errorf("ExpiringCertificates(-1) error = %v, want %v", err, models.ErrInvalidInput)
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// commandExpiryInterval es cada cuánto se marcan como expired los comandos vencidos.
+const commandExpiryInterval = time.Minute
+
+// errCommandUnchanged indica a modifyCommand que no hay nada que guardar.
+var errCommandUnchanged = errors.New("command unchanged")
+
+// CommandService envía comandos remotos a los dispositivos y sigue su estado con los acks.
+type CommandService struct {
+	commands ports.CommandRepository
+	devices  ports.DeviceRepository
+	iot      ports.IoTPublisher
+
+	// mu serializa las actualizaciones de comandos (ver modifyCommand)
+	mu sync.Mutex
+}
+
+// NewCommandService crea una nueva instancia de CommandService.
+func NewCommandService(commands ports.CommandRepository, devices ports.DeviceRepository, iot ports.IoTPublisher) *CommandService {
+	return &CommandService{
+		commands: commands,
+		devices:  devices,
+		iot:      iot,
+	}
+}
+
+// CreateCommand validates a command against the device type and sends it if the device is online.
+// Offline devices receive their pending commands when they reconnect (see HandleConnectivityEvent).
+func (s *CommandService) CreateCommand(ctx context.Context, deviceID string, req *models.CreateCommandRequest) (*models.Command, error) {
+	device, err := s.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !device.IsReachable() {
+		return nil, models.ErrDeviceInactive
+	}
+
+	if err := req.Validate(device); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	command := &models.Command{
+		CommandID:   "cmd_" + uuid.New().String()[:8],
+		DeviceID:    deviceID,
+		Type:        req.Type,
+		Params:      req.Params,
+		Status:      models.CommandStatusPending,
+		RequestedBy: models.ActorFromContext(ctx),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(req.TTL()),
+	}
+
+	if err := s.commands.Create(ctx, command); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("command_id", command.CommandID).
+		Str("device_id", deviceID).
+		Str("type", string(command.Type)).
+		Time("expires_at", command.ExpiresAt).
+		Msg("Command created")
+
+	if device.Online {
+		return s.send(ctx, command), nil
+	}
+	return command, nil
+}
+
+// GetCommand returns a command of the device.
+func (s *CommandService) GetCommand(ctx context.Context, deviceID, commandID string) (*models.Command, error) {
+	command, err := s.commands.GetByID(ctx, commandID)
+	if err != nil {
+		return nil, err
+	}
+	if command.DeviceID != deviceID {
+		return nil, models.ErrCommandNotFound
+	}
+	return command, nil
+}
+
+// ListCommands returns the commands of a device, newest first.
+func (s *CommandService) ListCommands(ctx context.Context, req *models.ListCommandsRequest) (*models.ListCommandsResponse, error) {
+	if _, err := s.devices.GetByID(ctx, req.DeviceID); err != nil {
+		return nil, err
+	}
+
+	commands, total, err := s.commands.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListCommandsResponse{
+		Commands: commands,
+		Total:    total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}, nil
+}
+
+// HandleAck applies a command_ack event. Acks for commands of another device are rejected as not found.
+func (s *CommandService) HandleAck(ctx context.Context, deviceID string, ack *models.CommandAck) (*models.Command, error) {
+	command, err := s.modifyCommand(ctx, ack.CommandID, func(command *models.Command) error {
+		if command.DeviceID != deviceID {
+			return models.ErrCommandNotFound
+		}
+		return command.Transition(ack.Status, ack.Result, ack.Error, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("command_id", command.CommandID).
+		Str("device_id", deviceID).
+		Str("status", string(command.Status)).
+		Msg("Command acknowledged")
+
+	return command, nil
+}
+
+// HandleConnectivityEvent resends the pending commands when a device comes back online.
+// A command may reach the device twice, so devices must ignore a command_id they already executed.
+func (s *CommandService) HandleConnectivityEvent(ctx context.Context, event *models.DeviceEvent) {
+	if event.EventType != models.DeviceEventOnline {
+		return
+	}
+
+	commands, _, err := s.commands.List(ctx, &models.ListCommandsRequest{
+		DeviceID: event.DeviceID,
+		Status:   models.CommandStatusPending,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", event.DeviceID).
+			Msg("Failed to load pending commands on reconnect")
+		return
+	}
+
+	now := time.Now()
+	for _, command := range commands {
+		if !command.IsExpired(now) {
+			s.send(ctx, command)
+		}
+	}
+}
+
+// ExpireCommands marks as expired the open commands whose TTL elapsed before now.
+// It returns the number of expired commands.
+func (s *CommandService) ExpireCommands(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	for _, status := range []models.CommandStatus{models.CommandStatusPending, models.CommandStatusDelivered} {
+		commands, _, err := s.commands.List(ctx, &models.ListCommandsRequest{Status: status})
+		if err != nil {
+			return expired, err
+		}
+
+		for _, candidate := range commands {
+			if !candidate.IsExpired(now) {
+				continue
+			}
+
+			_, err := s.modifyCommand(ctx, candidate.CommandID, func(command *models.Command) error {
+				// Releer: pudo llegar el ack entre el List y el Modify
+				if !command.IsExpired(now) {
+					return errCommandUnchanged
+				}
+				reason := fmt.Sprintf("no response from device within %s", command.ExpiresAt.Sub(command.CreatedAt))
+				return command.Transition(models.CommandStatusExpired, nil, reason, now)
+			})
+			switch {
+			case err == nil:
+				expired++
+			case !errors.Is(err, errCommandUnchanged):
+				log.Error().
+					Err(err).
+					Str("command_id", candidate.CommandID).
+					Msg("Failed to expire command")
+			}
+		}
+	}
+	return expired, nil
+}
+
+// CancelDeviceCommands cancels the open commands of a device, e.g. when it is decommissioned.
+// It returns the number of canceled commands.
+func (s *CommandService) CancelDeviceCommands(ctx context.Context, deviceID, reason string, now time.Time) (int, error) {
+	canceled := 0
+	for _, status := range []models.CommandStatus{models.CommandStatusPending, models.CommandStatusDelivered} {
+		commands, _, err := s.commands.List(ctx, &models.ListCommandsRequest{DeviceID: deviceID, Status: status})
+		if err != nil {
+			return canceled, err
+		}
+
+		for _, candidate := range commands {
+			_, err := s.modifyCommand(ctx, candidate.CommandID, func(command *models.Command) error {
+				if command.IsFinal() {
+					return errCommandUnchanged
+				}
+				return command.Transition(models.CommandStatusCanceled, nil, reason, now)
+			})
+			switch {
+			case err == nil:
+				canceled++
+			case !errors.Is(err, errCommandUnchanged):
+				return canceled, err
+			}
+		}
+	}
+	return canceled, nil
+}
+
+// Start runs ExpireCommands every minute until ctx is canceled.
+func (s *CommandService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(commandExpiryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := s.ExpireCommands(ctx, now); err != nil {
+					log.Error().Err(err).Msg("Command expiry failed")
+				}
+			}
+		}
+	}()
+}
+
+// send publica el comando y registra SentAt. Un fallo solo se registra: se reintenta al reconectar.
+func (s *CommandService) send(ctx context.Context, command *models.Command) *models.Command {
+	if err := s.iot.PublishCommand(ctx, models.NewCommandMessage(command)); err != nil {
+		log.Error().
+			Err(err).
+			Str("command_id", command.CommandID).
+			Str("device_id", command.DeviceID).
+			Msg("Failed to send command")
+		return command
+	}
+
+	sent, err := s.modifyCommand(ctx, command.CommandID, func(command *models.Command) error {
+		now := time.Now()
+		command.SentAt = &now
+		return nil
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("command_id", command.CommandID).
+			Msg("Failed to record command delivery")
+		return command
+	}
+	return sent
+}
+
+// modifyCommand carga el comando, aplica modify y guarda el resultado.
+// Si modify retorna error no se guarda nada.
+func (s *CommandService) modifyCommand(ctx context.Context, commandID string, modify func(command *models.Command) error) (*models.Command, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	command, err := s.commands.GetByID(ctx, commandID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := modify(command); err != nil {
+		return nil, err
+	}
+
+	if err := s.commands.Update(ctx, command); err != nil {
+		return nil, err
+	}
+	return command, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestCommandLifecycle verifica el envío, el reenvío al reconectar, los acks y la expiración.
+func TestCommandLifecycle(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	for _, device := range []*models.Device{
+		{DeviceID: "smart-bin-001", DeviceType: string(models.DeviceTypeSmartBinV2), BinType: string(models.BinTypeOrganic), Status: models.DeviceStatusActive},
+		{DeviceID: "smart-bin-002", DeviceType: string(models.DeviceTypeSmartBinV1), Status: models.DeviceStatusDecommissioned},
+	} {
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	iot := local.NewIoTPublisher()
+	bus := local.NewEventBus()
+	commands := NewCommandService(local.NewCommandRepository(), devices, iot)
+	bus.Subscribe(commands.HandleConnectivityEvent)
+	heartbeat := NewHeartbeatMonitor(testConfig(), NewDeviceManager(devices), devices, bus)
+
+	if _, err := commands.CreateCommand(ctx, "smart-bin-002", &models.CreateCommandRequest{Type: models.CommandReboot}); !errors.Is(err, models.ErrDeviceInactive) {
+		t.Errorf("CreateCommand(decommissioned) error = %v, want %v", err, models.ErrDeviceInactive)
+	}
+
+	// Offline: queda pending sin enviarse
+	reboot, err := commands.CreateCommand(ctx, "smart-bin-001", &models.CreateCommandRequest{Type: models.CommandReboot})
+	if err != nil {
+		t.Fatalf("CreateCommand() error = %v", err)
+	}
+	if reboot.Status != models.CommandStatusPending || len(iot.Commands()) != 0 {
+		t.Fatalf("Command = %v, sent = %d, want pending and not sent", reboot.Status, len(iot.Commands()))
+	}
+
+	// Al reconectar se envía
+	if err := heartbeat.RecordHeartbeat(ctx, "smart-bin-001"); err != nil {
+		t.Fatalf("RecordHeartbeat() error = %v", err)
+	}
+	if sent := iot.Commands(); len(sent) != 1 || sent[0].CommandID != reboot.CommandID {
+		t.Fatalf("Sent commands = %+v, want %s", sent, reboot.CommandID)
+	}
+
+	// Online: se envía de inmediato
+	open, err := commands.CreateCommand(ctx, "smart-bin-001", &models.CreateCommandRequest{
+		Type:       models.CommandOpenCompartment,
+		Params:     map[string]interface{}{"compartment": "organic"},
+		TTLSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateCommand(open) error = %v", err)
+	}
+	if open.SentAt == nil || len(iot.Commands()) != 2 {
+		t.Errorf("Open command sent_at = %v, sent = %d, want sent", open.SentAt, len(iot.Commands()))
+	}
+
+	// Acks del dispositivo
+	for _, status := range []models.CommandStatus{models.CommandStatusDelivered, models.CommandStatusSucceeded} {
+		if _, err := commands.HandleAck(ctx, "smart-bin-001", &models.CommandAck{CommandID: reboot.CommandID, Status: status}); err != nil {
+			t.Fatalf("HandleAck(%s) error = %v", status, err)
+		}
+	}
+	if _, err := commands.HandleAck(ctx, "smart-bin-002", &models.CommandAck{CommandID: open.CommandID, Status: models.CommandStatusSucceeded}); !errors.Is(err, models.ErrCommandNotFound) {
+		t.Errorf("HandleAck(other device) error = %v, want %v", err, models.ErrCommandNotFound)
+	}
+
+	// Solo el comando sin respuesta expira
+	expired, err := commands.ExpireCommands(ctx, time.Now().Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("ExpireCommands() error = %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("ExpireCommands() = %d, want 1", expired)
+	}
+
+	listed, err := commands.ListCommands(ctx, &models.ListCommandsRequest{DeviceID: "smart-bin-001"})
+	if err != nil {
+		t.Fatalf("ListCommands() error = %v", err)
+	}
+	got := map[string]models.CommandStatus{}
+	for _, command := range listed.Commands {
+		got[command.CommandID] = command.Status
+	}
+	if got[reboot.CommandID] != models.CommandStatusSucceeded || got[open.CommandID] != models.CommandStatusExpired {
+		t.Errorf("Statuses = %v, want reboot succeeded and open expired", got)
+	}
+}
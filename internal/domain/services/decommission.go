@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/rs/zerolog/log"
+)
+
+// errDeviceUnchanged indica a ModifyDevice que no hay nada que guardar.
+var errDeviceUnchanged = errors.New("device unchanged")
+
+// DecommissionService da de baja dispositivos: revoca sus credenciales, cierra lo que tenían
+// abierto y conserva el registro y su historial (jobs, telemetría, alertas, comandos) para auditoría.
+type DecommissionService struct {
+	deviceManager *DeviceManager
+	certificates  *CertificateService
+	commands      *CommandService
+	alerts        *AlertService
+}
+
+// NewDecommissionService crea una nueva instancia de DecommissionService.
+func NewDecommissionService(
+	deviceManager *DeviceManager,
+	certificates *CertificateService,
+	commands *CommandService,
+	alerts *AlertService,
+) *DecommissionService {
+	return &DecommissionService{
+		deviceManager: deviceManager,
+		certificates:  certificates,
+		commands:      commands,
+		alerts:        alerts,
+	}
+}
+
+// Decommission retires a device for good. The device record is kept with status decommissioned;
+// its certificates are revoked (cessation_of_operation), open commands canceled and active alerts resolved.
+// Decommissioning an already decommissioned device only repeats the cleanup, so a failed run can be retried.
+func (s *DecommissionService) Decommission(
+	ctx context.Context,
+	deviceID string,
+	req *models.DecommissionDeviceRequest,
+) (*models.DecommissionDeviceResponse, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = models.DefaultDecommissionReason
+	}
+	actor := models.ActorFromContext(ctx)
+
+	now := time.Now()
+	device, err := s.deviceManager.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+		if device.Status == models.DeviceStatusDecommissioned {
+			return errDeviceUnchanged
+		}
+		return device.TransitionStatus(models.DeviceStatusDecommissioned, reason, actor, now)
+	})
+	if errors.Is(err, errDeviceUnchanged) {
+		device, err = s.deviceManager.GetDevice(ctx, deviceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.DecommissionDeviceResponse{Device: device}
+
+	// Ya no autentica por su estado; revocar además publica sus seriales en la CRL
+	response.RevokedCertificates, err = s.certificates.RevokeDeviceCertificates(ctx, deviceID, models.RevocationCessationOfOperation)
+	if err != nil {
+		return nil, err
+	}
+
+	response.CanceledCommands, err = s.commands.CancelDeviceCommands(ctx, deviceID, "device decommissioned", now)
+	if err != nil {
+		return nil, err
+	}
+
+	response.ResolvedAlerts, err = s.alerts.ResolveDeviceAlerts(ctx, deviceID, &models.AlertActionRequest{
+		Note: "device decommissioned: " + reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Warn().
+		Str("device_id", deviceID).
+		Str("reason", device.StatusReason).
+		Int("revoked_certificates", len(response.RevokedCertificates)).
+		Int("canceled_commands", response.CanceledCommands).
+		Int("resolved_alerts", response.ResolvedAlerts).
+		Msg("Device decommissioned")
+
+	return response, nil
+}
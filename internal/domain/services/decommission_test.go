@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/pki"
+)
+
+// TestDecommissionDevice verifica que la baja revoca credenciales, cierra comandos y alertas,
+// rechaza jobs nuevos y conserva el registro.
+func TestDecommissionDevice(t *testing.T) {
+	ctx := context.Background()
+	authority, err := pki.NewEphemeralAuthority(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("NewEphemeralAuthority() error = %v", err)
+	}
+
+	devices := local.NewDeviceRepository()
+	deviceManager := NewDeviceManager(devices)
+	certificates := NewCertificateService(testConfig(), authority, local.NewCertificateRepository(), deviceManager)
+	commands := NewCommandService(local.NewCommandRepository(), devices, local.NewIoTPublisher())
+	alerts := NewAlertService(local.NewAlertRepository(), local.NewGroupRepository(), local.NewOrganizationRepository(), deviceManager, models.DefaultAlertRules())
+	jobs := NewJobManager(testConfig(), local.NewJobRepository(), devices, local.NewPresigner("us-east-1"), local.NewPublisher(), local.NewIoTPublisher(), metrics.NewRegistry())
+	service := NewDecommissionService(deviceManager, certificates, commands, alerts)
+
+	const deviceID = "smart-bin-001"
+	certificate, err := certificates.IssueCertificate(ctx, deviceID, "")
+	if err != nil {
+		t.Fatalf("IssueCertificate() error = %v", err)
+	}
+	device, err := deviceManager.RegisterDevice(ctx, &models.RegisterDeviceRequest{
+		DeviceID:   deviceID,
+		DeviceType: string(models.DeviceTypeSmartBinV2),
+	}, certificate)
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	if err := certificates.TrackCertificate(ctx, certificate); err != nil {
+		t.Fatalf("TrackCertificate() error = %v", err)
+	}
+
+	// Offline: el comando queda pending
+	if _, err := commands.CreateCommand(ctx, deviceID, &models.CreateCommandRequest{Type: models.CommandReboot}); err != nil {
+		t.Fatalf("CreateCommand() error = %v", err)
+	}
+	fill := 95
+	device.FillLevel = &fill
+	if err := alerts.EvaluateDevice(ctx, device); err != nil {
+		t.Fatalf("EvaluateDevice() error = %v", err)
+	}
+	if _, err := jobs.CreateJob(ctx, &models.CreateJobRequest{DeviceID: deviceID}); err != nil {
+		t.Fatalf("CreateJob() before decommission error = %v", err)
+	}
+
+	// El ciclo de vida normal no puede dar de baja
+	updates := newTestDeviceUpdateService(devices, alerts, local.NewEventBus())
+	_, err = updates.ChangeStatus(ctx, deviceID, &models.ChangeDeviceStatusRequest{
+		Status: models.DeviceStatusDecommissioned,
+		Reason: "retired",
+	})
+	if !errors.Is(err, models.ErrInvalidTransition) {
+		t.Fatalf("ChangeStatus(decommissioned) error = %v, want ErrInvalidTransition", err)
+	}
+
+	operator := models.WithPrincipal(ctx, &models.Principal{CredentialID: "cred_ops"})
+	response, err := service.Decommission(operator, deviceID, &models.DecommissionDeviceRequest{Reason: "vandalized"})
+	if err != nil {
+		t.Fatalf("Decommission() error = %v", err)
+	}
+	if len(response.RevokedCertificates) != 1 || response.RevokedCertificates[0] != certificate.SerialNumber {
+		t.Errorf("Revoked certificates = %v, want [%s]", response.RevokedCertificates, certificate.SerialNumber)
+	}
+	if response.CanceledCommands != 1 || response.ResolvedAlerts != 1 {
+		t.Errorf("Canceled commands = %d, resolved alerts = %d, want 1 and 1", response.CanceledCommands, response.ResolvedAlerts)
+	}
+
+	stored, err := deviceManager.GetDevice(ctx, deviceID)
+	if err != nil {
+		t.Fatalf("Device record not kept: %v", err)
+	}
+	if stored.Status != models.DeviceStatusDecommissioned || stored.StatusReason != "vandalized" || stored.DecommissionedAt == nil {
+		t.Errorf("Stored device = %s (%q), decommissioned at %v", stored.Status, stored.StatusReason, stored.DecommissionedAt)
+	}
+	if changedBy := stored.StatusHistory[len(stored.StatusHistory)-1].ChangedBy; changedBy != "cred_ops" {
+		t.Errorf("Decommissioned by %q, want the caller's credential", changedBy)
+	}
+
+	if _, err := jobs.CreateJob(ctx, &models.CreateJobRequest{DeviceID: deviceID}); !errors.Is(err, models.ErrDeviceInactive) {
+		t.Errorf("CreateJob() after decommission error = %v, want ErrDeviceInactive", err)
+	}
+	if _, err := certificates.AuthenticateDevice(ctx, deviceID, certificate.SerialNumber); err == nil {
+		t.Error("Decommissioned device still authenticates")
+	}
+
+	// Repetir la baja solo repite la limpieza
+	again, err := service.Decommission(ctx, deviceID, &models.DecommissionDeviceRequest{})
+	if err != nil {
+		t.Fatalf("Second Decommission() error = %v", err)
+	}
+	if len(again.RevokedCertificates) != 0 || again.CanceledCommands != 0 || again.ResolvedAlerts != 0 {
+		t.Errorf("Second decommission closed %+v, want nothing", again)
+	}
+	if again.Device.StatusReason != "vandalized" || len(again.Device.StatusHistory) != 1 {
+		t.Errorf("Second decommission rewrote the record: %q, %d history entries", again.Device.StatusReason, len(again.Device.StatusHistory))
+	}
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/rs/zerolog/log"
+)
+
+// DeviceEventService procesa los eventos enviados por los dispositivos.
+// Es el punto común para cualquier transporte (webhook HTTP, MQTT).
+type DeviceEventService struct {
+	jobManager    *JobManager
+	deviceManager *DeviceManager
+	telemetry     *TelemetryService
+	heartbeat     *HeartbeatMonitor
+	alerts        *AlertService
+	forecast      *ForecastService
+	shadows       *ShadowService
+	commands      *CommandService
+	firmware      *FirmwareService
+}
+
+// NewDeviceEventService crea una nueva instancia de DeviceEventService.
+func NewDeviceEventService(
+	jobManager *JobManager,
+	deviceManager *DeviceManager,
+	telemetry *TelemetryService,
+	heartbeat *HeartbeatMonitor,
+	alerts *AlertService,
+	forecast *ForecastService,
+	shadows *ShadowService,
+	commands *CommandService,
+	firmware *FirmwareService,
+) *DeviceEventService {
+	return &DeviceEventService{
+		jobManager:    jobManager,
+		deviceManager: deviceManager,
+		telemetry:     telemetry,
+		heartbeat:     heartbeat,
+		alerts:        alerts,
+		forecast:      forecast,
+		shadows:       shadows,
+		commands:      commands,
+		firmware:      firmware,
+	}
+}
+
+// HandleEvent dispatches a device event to the handler for its type.
+// A principal bound to a device (mTLS certificate or device API key) only sends events for that device.
+// Events of decommissioned devices are rejected with ErrDeviceInactive before any side effect.
+func (s *DeviceEventService) HandleEvent(ctx context.Context, event *models.DeviceEvent) (*models.DeviceEventResult, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Un dispositivo (certificado o API key atada) solo publica sus propios eventos
+	if err := models.CheckDeviceFromContext(ctx, event.DeviceID); err != nil {
+		return nil, err
+	}
+
+	// Un dispositivo dado de baja no vuelve a quedar online ni genera jobs, telemetría o alertas
+	if err := s.checkNotDecommissioned(ctx, event.DeviceID); err != nil {
+		return nil, err
+	}
+
+	// Cualquier evento del dispositivo cuenta como heartbeat
+	if err := s.heartbeat.RecordHeartbeat(ctx, event.DeviceID); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", event.DeviceID).
+			Msg("Failed to record heartbeat")
+	}
+
+	result := &models.DeviceEventResult{
+		EventType: event.EventType,
+	}
+
+	switch event.EventType {
+	case models.DeviceEventImageCaptured:
+		job, err := s.handleImageCaptured(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		result.Job = job
+
+	case models.DeviceEventStatus:
+		device, err := s.handleDeviceStatus(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		result.Device = device
+		s.evaluateAlerts(ctx, device)
+
+	case models.DeviceEventError:
+		device, err := s.handleDeviceError(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		result.Device = device
+		s.evaluateAlerts(ctx, device)
+
+	case models.DeviceEventCommandAck:
+		command, err := s.handleCommandAck(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		result.Command = command
+
+	case models.DeviceEventFirmwareUpdate:
+		update, err := s.handleFirmwareUpdate(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		result.FirmwareUpdate = update
+
+	default:
+		log.Warn().
+			Str("device_id", event.DeviceID).
+			Str("event_type", string(event.EventType)).
+			Msg("Unknown event type")
+	}
+
+	result.ProcessedAt = time.Now()
+	return result, nil
+}
+
+// checkNotDecommissioned retorna ErrDeviceInactive si el dispositivo está dado de baja.
+// Los dispositivos no registrados se siguen aceptando.
+func (s *DeviceEventService) checkNotDecommissioned(ctx context.Context, deviceID string) error {
+	device, err := s.deviceManager.GetDevice(ctx, deviceID)
+	if errors.Is(err, models.ErrDeviceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if device.Status == models.DeviceStatusDecommissioned {
+		return fmt.Errorf("%w: device %s is decommissioned", models.ErrDeviceInactive, deviceID)
+	}
+	return nil
+}
+
+// handleImageCaptured crea un job usando el mismo camino que POST /api/v1/jobs.
+//
+// Data opcional:
+//   - image_key: key S3 de la imagen ya subida (el job pasa directo a processing)
+//   - metadata:  datos adicionales que se guardan en el job
+func (s *DeviceEventService) handleImageCaptured(ctx context.Context, event *models.DeviceEvent) (*models.CreateJobResponse, error) {
+	req := &models.CreateJobRequest{
+		DeviceID:  event.DeviceID,
+		Timestamp: event.Timestamp,
+		ImageKey:  event.GetString("image_key"),
+		Metadata:  event.GetMap("metadata"),
+	}
+
+	job, err := s.jobManager.CreateJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("device_id", event.DeviceID).
+		Str("job_id", job.JobID).
+		Str("status", string(job.Status)).
+		Msg("Job created from image captured event")
+
+	return job, nil
+}
+
+// handleDeviceStatus aplica la telemetría del evento al dispositivo.
+//
+// Data opcional:
+//   - battery_level:    0-100 (%)
+//   - fill_level:       0-100 (%)
+//   - signal_strength:  -120-0 (dBm)
+//   - firmware_version: versión de firmware instalada
+//
+// Todo Data se guarda además como estado reportado del shadow.
+func (s *DeviceEventService) handleDeviceStatus(ctx context.Context, event *models.DeviceEvent) (*models.Device, error) {
+	telemetry, err := telemetryFromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := s.deviceManager.ApplyTelemetry(ctx, event.DeviceID, telemetry)
+	if err != nil {
+		return nil, err
+	}
+
+	// El estado ya quedó aplicado: un fallo del histórico no rechaza el evento
+	if err := s.telemetry.Record(ctx, event.DeviceID, telemetry); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", event.DeviceID).
+			Msg("Failed to record telemetry history")
+	}
+
+	if _, err := s.shadows.UpdateReported(ctx, event.DeviceID, event.Data); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", event.DeviceID).
+			Msg("Failed to update reported shadow state")
+	}
+
+	if telemetry.FillLevel != nil {
+		if updated, err := s.forecast.UpdateForecast(ctx, event.DeviceID); err != nil {
+			log.Error().
+				Err(err).
+				Str("device_id", event.DeviceID).
+				Msg("Failed to update fill forecast")
+		} else {
+			device = updated
+		}
+	}
+
+	log.Info().
+		Str("device_id", event.DeviceID).
+		Str("battery_status", device.GetBatteryStatus()).
+		Str("fill_status", device.GetFillStatus()).
+		Msg("Device status applied")
+
+	return device, nil
+}
+
+// handleDeviceError registra el error y aumenta el contador del dispositivo.
+// Los errores de dispositivos no registrados solo se registran en el log.
+func (s *DeviceEventService) handleDeviceError(ctx context.Context, event *models.DeviceEvent) (*models.Device, error) {
+	log.Error().
+		Str("device_id", event.DeviceID).
+		Interface("data", event.Data).
+		Msg("Device error reported")
+
+	device, err := s.deviceManager.ModifyDevice(ctx, event.DeviceID, func(device *models.Device) error {
+		device.IncrementErrorCount()
+		return nil
+	})
+	if errors.Is(err, models.ErrDeviceNotFound) {
+		return nil, nil
+	}
+	return device, err
+}
+
+// handleCommandAck actualiza el estado del comando con la respuesta del dispositivo.
+//
+// Data:
+//   - command_id: ID del comando (requerido)
+//   - status:     delivered, succeeded o failed (requerido)
+//   - result:     datos devueltos por el dispositivo (opcional)
+//   - error:      motivo del fallo (opcional)
+func (s *DeviceEventService) handleCommandAck(ctx context.Context, event *models.DeviceEvent) (*models.Command, error) {
+	ack, err := models.CommandAckFromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.commands.HandleAck(ctx, event.DeviceID, ack)
+}
+
+// handleFirmwareUpdate registra el avance de una actualización OTA.
+//
+// Data:
+//   - rollout_id: ID del rollout (requerido)
+//   - status:     downloading, installing, succeeded o failed (requerido)
+//   - error:      motivo del fallo (opcional)
+func (s *DeviceEventService) handleFirmwareUpdate(ctx context.Context, event *models.DeviceEvent) (*models.FirmwareUpdate, error) {
+	progress, err := models.FirmwareProgressFromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.firmware.HandleProgress(ctx, event.DeviceID, progress)
+}
+
+// evaluateAlerts revisa las reglas de mantenimiento con el estado recién aplicado.
+// Un fallo al evaluar no rechaza el evento.
+func (s *DeviceEventService) evaluateAlerts(ctx context.Context, device *models.Device) {
+	if device == nil {
+		return
+	}
+
+	if err := s.alerts.EvaluateDevice(ctx, device); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", device.DeviceID).
+			Msg("Failed to evaluate alert rules")
+	}
+}
+
+// telemetryFromEvent extrae la lectura de hardware del payload del evento.
+func telemetryFromEvent(event *models.DeviceEvent) (*models.DeviceTelemetry, error) {
+	reportedAt, err := event.ParseTimestamp()
+	if err != nil {
+		return nil, err
+	}
+
+	telemetry := &models.DeviceTelemetry{
+		FirmwareVersion: event.GetString("firmware_version"),
+		ReportedAt:      reportedAt,
+	}
+
+	if telemetry.BatteryLevel, err = event.GetInt("battery_level"); err != nil {
+		return nil, err
+	}
+	if telemetry.FillLevel, err = event.GetInt("fill_level"); err != nil {
+		return nil, err
+	}
+	if telemetry.SignalStrength, err = event.GetInt("signal_strength"); err != nil {
+		return nil, err
+	}
+
+	return telemetry, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
+)
+
+// testConfig retorna la configuración mínima que usan los servicios.
+func testConfig() *config.Config {
+	return &config.Config{
+		AWS: config.AWSConfig{
+			Region: "us-east-1",
+			S3: config.S3Config{
+				BucketImages:       "smart-bin-test-images",
+				BucketFirmware:     "smart-bin-test-firmware",
+				PresignedURLExpiry: 15 * time.Minute,
+			},
+		},
+		Security: config.SecurityConfig{
+			DeviceCA: config.DeviceCAConfig{
+				CertValidity:      30 * 24 * time.Hour,
+				RotationOverlap:   24 * time.Hour,
+				CRLValidity:       24 * time.Hour,
+				ExpiryWarningDays: 30,
+			},
+		},
+		Telemetry: config.TelemetryConfig{
+			RawRetention:    7 * 24 * time.Hour,
+			HourlyRetention: 90 * 24 * time.Hour,
+			DailyRetention:  730 * 24 * time.Hour,
+		},
+		Forecast: config.ForecastConfig{
+			Window:     7 * 24 * time.Hour,
+			FillPerJob: 0.5,
+		},
+	}
+}
+
+// newTestDeviceEventService crea un DeviceEventService con repositorios en memoria.
+func newTestDeviceEventService(devices *local.DeviceRepository, jobs *local.JobRepository, publisher *local.Publisher) *DeviceEventService {
+	manager := NewJobManager(testConfig(), jobs, devices, local.NewPresigner("us-east-1"), publisher, local.NewIoTPublisher(), metrics.NewRegistry())
+	telemetry := NewTelemetryService(testConfig(), local.NewTelemetryRepository(), devices)
+	deviceManager := NewDeviceManager(devices)
+	heartbeat := NewHeartbeatMonitor(testConfig(), deviceManager, devices, local.NewEventBus())
+	alerts := NewAlertService(local.NewAlertRepository(), local.NewGroupRepository(), local.NewOrganizationRepository(), deviceManager, models.DefaultAlertRules())
+	forecast := NewForecastService(testConfig(), deviceManager, devices, jobs, local.NewTelemetryRepository())
+	shadows := NewShadowService(local.NewShadowRepository(), devices, local.NewIoTPublisher())
+	commands := NewCommandService(local.NewCommandRepository(), devices, local.NewIoTPublisher())
+	firmware := NewFirmwareService(
+		testConfig(), local.NewFirmwareRepository(), deviceManager, devices, local.NewPresigner("us-east-1"), local.NewIoTPublisher(),
+	)
+	return NewDeviceEventService(manager, deviceManager, telemetry, heartbeat, alerts, forecast, shadows, commands, firmware)
+}
+
+// TestHandleImageCapturedEvent verifica que image_captured crea jobs por el mismo camino que CreateJob.
+func TestHandleImageCapturedEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string]interface{}
+		wantStatus models.JobStatus
+		wantUpload bool
+		wantQueued int
+	}{
+		{
+			name:       "Image pending upload",
+			data:       nil,
+			wantStatus: models.JobStatusPending,
+			wantUpload: true,
+			wantQueued: 0,
+		},
+		{
+			name: "Image already uploaded",
+			data: map[string]interface{}{
+				"image_key": "uploads/smart-bin-001/capture.jpg",
+				"metadata":  map[string]interface{}{"weight_g": 120.0},
+			},
+			wantStatus: models.JobStatusProcessing,
+			wantUpload: false,
+			wantQueued: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := local.NewJobRepository()
+			publisher := local.NewPublisher()
+			service := newTestDeviceEventService(local.NewDeviceRepository(), jobs, publisher)
+
+			result, err := service.HandleEvent(context.Background(), &models.DeviceEvent{
+				EventType: models.DeviceEventImageCaptured,
+				DeviceID:  "smart-bin-001",
+				Timestamp: time.Now().Format(time.RFC3339),
+				Data:      tt.data,
+			})
+			if err != nil {
+				t.Fatalf("HandleEvent() error = %v", err)
+			}
+			if result.Job == nil {
+				t.Fatal("Expected a job to be created")
+			}
+			if result.Job.Status != tt.wantStatus {
+				t.Errorf("Job status = %v, want %v", result.Job.Status, tt.wantStatus)
+			}
+			if got := result.Job.UploadURL != ""; got != tt.wantUpload {
+				t.Errorf("Has upload URL = %v, want %v", got, tt.wantUpload)
+			}
+			if got := len(publisher.Messages()); got != tt.wantQueued {
+				t.Errorf("Queued jobs = %v, want %v", got, tt.wantQueued)
+			}
+
+			stored, err := jobs.GetByID(context.Background(), result.Job.JobID)
+			if err != nil {
+				t.Fatalf("Job not persisted: %v", err)
+			}
+			if stored.Status != tt.wantStatus {
+				t.Errorf("Stored status = %v, want %v", stored.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestHandleEventDecommissionedDevice verifica que los eventos de un dispositivo dado de baja se
+// rechacen sin marcarlo online ni crear jobs.
+func TestHandleEventDecommissionedDevice(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	jobs := local.NewJobRepository()
+	service := newTestDeviceEventService(devices, jobs, local.NewPublisher())
+
+	const deviceID = "smart-bin-001"
+	device, err := NewDeviceManager(devices).RegisterDevice(ctx, &models.RegisterDeviceRequest{
+		DeviceID:   deviceID,
+		DeviceType: string(models.DeviceTypeSmartBinV2),
+	}, &models.DeviceCertificate{})
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	device.Status = models.DeviceStatusDecommissioned
+	device.Online = false
+	if err := devices.Update(ctx, device); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	for _, eventType := range []models.DeviceEventType{models.DeviceEventStatus, models.DeviceEventImageCaptured} {
+		_, err := service.HandleEvent(ctx, &models.DeviceEvent{
+			EventType: eventType,
+			DeviceID:  deviceID,
+			Timestamp: time.Now().Format(time.RFC3339),
+			Data:      map[string]interface{}{"fill_level": 40.0},
+		})
+		if !errors.Is(err, models.ErrDeviceInactive) {
+			t.Errorf("HandleEvent(%s) error = %v, want ErrDeviceInactive", eventType, err)
+		}
+	}
+
+	stored, err := devices.GetByID(ctx, deviceID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.Online || stored.LastSeen != nil {
+		t.Errorf("Decommissioned device online = %v, last seen = %v; want offline and never seen", stored.Online, stored.LastSeen)
+	}
+	if _, total, _ := jobs.List(ctx, &models.ListJobsRequest{DeviceID: deviceID}); total != 0 {
+		t.Errorf("Jobs created = %d, want 0", total)
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// DeviceManager coordina el registro y el estado de los dispositivos.
+type DeviceManager struct {
+	devices ports.DeviceRepository
+
+	// mu serializa los read-modify-write de un mismo proceso (ver ModifyDevice).
+	mu sync.Mutex
+}
+
+// NewDeviceManager crea una nueva instancia de DeviceManager.
+func NewDeviceManager(devices ports.DeviceRepository) *DeviceManager {
+	return &DeviceManager{
+		devices: devices,
+	}
+}
+
+// RegisterDevice creates a new device with the given certificate.
+func (m *DeviceManager) RegisterDevice(
+	ctx context.Context,
+	req *models.RegisterDeviceRequest,
+	certificate *models.DeviceCertificate,
+) (*models.Device, error) {
+	now := time.Now()
+	device := &models.Device{
+		DeviceID:     req.DeviceID,
+		OrgID:        models.CreationOrgID(ctx),
+		DeviceType:   req.DeviceType,
+		SerialNumber: req.SerialNumber,
+		Status:       models.DeviceStatusActive,
+		Location:     req.Location,
+		BinType:      req.BinType,
+		Capacity:     req.Capacity,
+		Metadata:     req.Metadata,
+		Tags:         req.Tags,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	certificate.ApplyTo(device)
+
+	if err := device.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := m.devices.Create(ctx, device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// GetDevice retrieves a device by ID.
+func (m *DeviceManager) GetDevice(ctx context.Context, deviceID string) (*models.Device, error) {
+	if deviceID == "" {
+		return nil, models.ErrInvalidDeviceID
+	}
+	return m.devices.GetByID(ctx, deviceID)
+}
+
+// AuthenticateDevice resolves the device behind a client certificate.
+// Unknown devices are unauthorized; inactive or decommissioned ones get ErrDeviceInactive.
+func (m *DeviceManager) AuthenticateDevice(ctx context.Context, deviceID string) (*models.Device, error) {
+	device, err := m.GetDevice(ctx, deviceID)
+	if errors.Is(err, models.ErrDeviceNotFound) || errors.Is(err, models.ErrInvalidDeviceID) {
+		return nil, fmt.Errorf("%w: unknown device %q", models.ErrUnauthorized, deviceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !device.IsReachable() {
+		return nil, models.ErrDeviceInactive
+	}
+	return device, nil
+}
+
+// ListDevices returns a page of devices matching the request filters.
+func (m *DeviceManager) ListDevices(ctx context.Context, req *models.ListDevicesRequest) (*models.ListDevicesResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	if err := req.ParseGeoFilters(); err != nil {
+		return nil, err
+	}
+
+	tags, err := models.ParseTagFilters(req.Tag)
+	if err != nil {
+		return nil, err
+	}
+	req.Tags = tags
+
+	devices, total, err := m.devices.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListDevicesResponse{
+		Devices: devices,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}, nil
+}
+
+// ApplyTelemetry validates a telemetry reading and stores it on the device.
+// Readings older than (or as old as) the last applied one are rejected with models.ErrStaleEvent.
+func (m *DeviceManager) ApplyTelemetry(
+	ctx context.Context,
+	deviceID string,
+	telemetry *models.DeviceTelemetry,
+) (*models.Device, error) {
+	if err := telemetry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return m.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+		err := device.ApplyTelemetry(telemetry)
+		if errors.Is(err, models.ErrStaleEvent) {
+			log.Warn().
+				Str("device_id", deviceID).
+				Time("reported_at", telemetry.ReportedAt).
+				Time("last_reported_at", *device.LastReportedAt).
+				Msg("Discarding out-of-order telemetry")
+		}
+		return err
+	})
+}
+
+// ModifyDevice loads a device, applies modify and persists the result.
+// All read-modify-write cycles on devices go through here so they do not overwrite each other.
+// If modify returns an error nothing is persisted.
+func (m *DeviceManager) ModifyDevice(
+	ctx context.Context,
+	deviceID string,
+	modify func(device *models.Device) error,
+) (*models.Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	device, err := m.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := modify(device); err != nil {
+		return nil, err
+	}
+
+	if err := m.devices.Update(ctx, device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
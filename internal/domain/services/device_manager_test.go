@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestListDevicesNear verifica la búsqueda por radio, el orden por distancia y el filtro accepts.
+func TestListDevicesNear(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	manager := NewDeviceManager(devices)
+
+	// Separaciones aproximadas sobre la misma latitud: 0.001° de longitud ≈ 111m
+	for _, device := range []*models.Device{
+		{DeviceID: "bin-300m", BinType: string(models.BinTypeRecyclable), Location: &models.Location{Latitude: 4.6, Longitude: -74.077}},
+		{DeviceID: "bin-100m", BinType: string(models.BinTypeOrganic), Location: &models.Location{Latitude: 4.6, Longitude: -74.079}},
+		{DeviceID: "bin-200m", BinType: string(models.BinTypeMixed), Location: &models.Location{Latitude: 4.6, Longitude: -74.082}},
+		{DeviceID: "bin-far", BinType: string(models.BinTypeRecyclable), Location: &models.Location{Latitude: 4.7, Longitude: -74.08}},
+		{DeviceID: "bin-no-gps", BinType: string(models.BinTypeRecyclable)},
+	} {
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  models.ListDevicesRequest
+		want []string
+	}{
+		{
+			name: "Sorted by distance",
+			req:  models.ListDevicesRequest{Near: "4.6,-74.08", RadiusMeters: 500},
+			want: []string{"bin-100m", "bin-200m", "bin-300m"},
+		},
+		{
+			name: "Radius excludes farther bins",
+			req:  models.ListDevicesRequest{Near: "4.6,-74.08", RadiusMeters: 250},
+			want: []string{"bin-100m", "bin-200m"},
+		},
+		{
+			name: "Closest bin accepting recyclables",
+			req:  models.ListDevicesRequest{Near: "4.6,-74.08", Accepts: string(models.BinTypeRecyclable), Limit: 1},
+			want: []string{"bin-200m"},
+		},
+		{
+			name: "Bounding box",
+			req:  models.ListDevicesRequest{BBox: "4.65,-74.1,4.75,-74.0"},
+			want: []string{"bin-far"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := manager.ListDevices(ctx, &tt.req)
+			if err != nil {
+				t.Fatalf("ListDevices() error = %v", err)
+			}
+			if len(response.Devices) != len(tt.want) {
+				t.Fatalf("Devices = %d, want %d", len(response.Devices), len(tt.want))
+			}
+			for i, deviceID := range tt.want {
+				if response.Devices[i].DeviceID != deviceID {
+					t.Errorf("Devices[%d] = %s, want %s", i, response.Devices[i].DeviceID, deviceID)
+				}
+				if response.Devices[i].DistanceMeters == nil {
+					t.Errorf("Devices[%d] has no distance", i)
+				}
+			}
+		})
+	}
+
+	// Al moverse, el dispositivo sale de la celda anterior del índice
+	if _, err := manager.ModifyDevice(ctx, "bin-far", func(device *models.Device) error {
+		device.Location.Latitude = 4.6
+		device.Location.Longitude = -74.0805
+		return nil
+	}); err != nil {
+		t.Fatalf("ModifyDevice() error = %v", err)
+	}
+
+	response, err := manager.ListDevices(ctx, &models.ListDevicesRequest{Near: "4.6,-74.08", RadiusMeters: 100})
+	if err != nil {
+		t.Fatalf("ListDevices() error = %v", err)
+	}
+	if response.Total != 1 || response.Devices[0].DeviceID != "bin-far" {
+		t.Errorf("After move: %d devices, want bin-far only", response.Total)
+	}
+}
+
+// TestAuthenticateDevice verifica que solo los dispositivos registrados y activos pasan mTLS.
+func TestAuthenticateDevice(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	manager := NewDeviceManager(devices)
+
+	for _, device := range []*models.Device{
+		{DeviceID: "smart-bin-001", Status: models.DeviceStatusActive},
+		{DeviceID: "smart-bin-002", Status: models.DeviceStatusMaintenance},
+		{DeviceID: "smart-bin-003", Status: models.DeviceStatusInactive},
+		{DeviceID: "smart-bin-004", Status: models.DeviceStatusDecommissioned},
+	} {
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	tests := []struct {
+		deviceID string
+		wantErr  error
+	}{
+		{deviceID: "smart-bin-001"},
+		{deviceID: "smart-bin-002"},
+		{deviceID: "smart-bin-003", wantErr: models.ErrDeviceInactive},
+		{deviceID: "smart-bin-004", wantErr: models.ErrDeviceInactive},
+		{deviceID: "smart-bin-999", wantErr: models.ErrUnauthorized},
+		{deviceID: "", wantErr: models.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		device, err := manager.AuthenticateDevice(ctx, tt.deviceID)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("AuthenticateDevice(%q) error = %v, want %v", tt.deviceID, err, tt.wantErr)
+			continue
+		}
+		if err == nil && device.DeviceID != tt.deviceID {
+			t.Errorf("AuthenticateDevice(%q) = %s", tt.deviceID, device.DeviceID)
+		}
+	}
+}
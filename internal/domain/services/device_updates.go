@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// DeviceUpdateService aplica las actualizaciones de operadores (PATCH y cambios de estado),
+// registra las lecturas en el histórico, reevalúa las alertas y avisa cuando cambia la
+// necesidad de mantenimiento.
+type DeviceUpdateService struct {
+	deviceManager *DeviceManager
+	telemetry     *TelemetryService
+	forecast      *ForecastService
+	alerts        *AlertService
+	events        ports.EventPublisher
+}
+
+// NewDeviceUpdateService crea una nueva instancia de DeviceUpdateService.
+func NewDeviceUpdateService(
+	deviceManager *DeviceManager,
+	telemetry *TelemetryService,
+	forecast *ForecastService,
+	alerts *AlertService,
+	events ports.EventPublisher,
+) *DeviceUpdateService {
+	return &DeviceUpdateService{
+		deviceManager: deviceManager,
+		telemetry:     telemetry,
+		forecast:      forecast,
+		alerts:        alerts,
+		events:        events,
+	}
+}
+
+// UpdateDevice applies a partial update with merge semantics: absent fields are left unchanged.
+// A status change follows the lifecycle state machine; battery, fill and signal readings are recorded
+// in the telemetry history like a device_status event, and a new fill level refits the forecast.
+// If the update changes whether the device needs maintenance a maintenance_required or
+// maintenance_cleared event is published.
+func (s *DeviceUpdateService) UpdateDevice(
+	ctx context.Context,
+	deviceID string,
+	req *models.UpdateDeviceRequest,
+) (*models.Device, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	actor := models.ActorFromContext(ctx)
+
+	var neededMaintenance bool
+	device, err := s.deviceManager.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+		neededMaintenance = device.NeedsMaintenance()
+		return device.ApplyUpdate(req, actor, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if telemetry := req.Telemetry(now); telemetry != nil {
+		device = s.recordTelemetry(ctx, device, telemetry)
+	}
+
+	log.Info().
+		Str("device_id", deviceID).
+		Str("status", string(device.Status)).
+		Str("updated_by", actor).
+		Msg("Device updated")
+
+	if err := s.alerts.EvaluateDevice(ctx, device); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", deviceID).
+			Msg("Failed to evaluate alerts after device update")
+	}
+
+	if needsMaintenance := device.NeedsMaintenance(); needsMaintenance != neededMaintenance {
+		s.publishMaintenance(ctx, device, needsMaintenance)
+	}
+
+	return device, nil
+}
+
+// recordTelemetry registra las lecturas y recalcula el pronóstico si cambió el llenado.
+// El update ya quedó aplicado: un fallo del histórico o del pronóstico solo se registra en el log.
+func (s *DeviceUpdateService) recordTelemetry(ctx context.Context, device *models.Device, telemetry *models.DeviceTelemetry) *models.Device {
+	if err := s.telemetry.Record(ctx, device.DeviceID, telemetry); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", device.DeviceID).
+			Msg("Failed to record telemetry history")
+	}
+
+	if telemetry.FillLevel == nil {
+		return device
+	}
+
+	updated, err := s.forecast.UpdateForecast(ctx, device.DeviceID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", device.DeviceID).
+			Msg("Failed to update fill forecast")
+		return device
+	}
+	return updated
+}
+
+// ChangeStatus moves a device to another status of its lifecycle (PUT /devices/:device_id/status).
+// Decommissioning goes through DecommissionService, which also revokes credentials.
+func (s *DeviceUpdateService) ChangeStatus(
+	ctx context.Context,
+	deviceID string,
+	req *models.ChangeDeviceStatusRequest,
+) (*models.Device, error) {
+	return s.UpdateDevice(ctx, deviceID, &models.UpdateDeviceRequest{
+		Status:       &req.Status,
+		StatusReason: &req.Reason,
+	})
+}
+
+// publishMaintenance emite maintenance_required o maintenance_cleared.
+func (s *DeviceUpdateService) publishMaintenance(ctx context.Context, device *models.Device, required bool) {
+	eventType := models.DeviceEventMaintenanceCleared
+	if required {
+		eventType = models.DeviceEventMaintenanceRequired
+	}
+
+	data := map[string]interface{}{
+		"device_type": device.DeviceType,
+		"status":      string(device.Status),
+	}
+	if device.StatusReason != "" {
+		data["status_reason"] = device.StatusReason
+	}
+	if device.BatteryLevel != nil {
+		data["battery_level"] = *device.BatteryLevel
+	}
+	if device.FillLevel != nil {
+		data["fill_level"] = *device.FillLevel
+	}
+
+	event := &models.DeviceEvent{
+		EventType: eventType,
+		DeviceID:  device.DeviceID,
+		Timestamp: device.UpdatedAt.Format(time.RFC3339),
+		Data:      data,
+	}
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", device.DeviceID).
+			Str("event_type", string(eventType)).
+			Msg("Failed to publish maintenance event")
+	}
+}
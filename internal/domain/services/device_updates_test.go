@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// newTestDeviceUpdateService crea un DeviceUpdateService con histórico y pronóstico en memoria.
+func newTestDeviceUpdateService(devices *local.DeviceRepository, alerts *AlertService, events ports.EventPublisher) *DeviceUpdateService {
+	deviceManager := NewDeviceManager(devices)
+	telemetry := local.NewTelemetryRepository()
+	return NewDeviceUpdateService(
+		deviceManager,
+		NewTelemetryService(testConfig(), telemetry, devices),
+		NewForecastService(testConfig(), deviceManager, devices, local.NewJobRepository(), telemetry),
+		alerts,
+		events,
+	)
+}
+
+// TestUpdateDeviceMaintenanceEvents verifica que solo los cambios que alteran la necesidad
+// de mantenimiento emiten eventos.
+func TestUpdateDeviceMaintenanceEvents(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	deviceManager := NewDeviceManager(devices)
+
+	var events []models.DeviceEventType
+	bus := local.NewEventBus()
+	bus.Subscribe(func(_ context.Context, event *models.DeviceEvent) {
+		events = append(events, event.EventType)
+	})
+	service := newTestDeviceUpdateService(devices, NewAlertService(local.NewAlertRepository(), local.NewGroupRepository(), local.NewOrganizationRepository(), deviceManager, models.DefaultAlertRules()), bus)
+
+	now := time.Now()
+	if err := devices.Create(ctx, &models.Device{
+		DeviceID:   "smart-bin-001",
+		DeviceType: string(models.DeviceTypeSmartBinV2),
+		Status:     models.DeviceStatusActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	intPtr := func(v int) *int { return &v }
+	steps := []struct {
+		name       string
+		req        models.UpdateDeviceRequest
+		wantEvents []models.DeviceEventType
+	}{
+		{"Fill level still fine", models.UpdateDeviceRequest{FillLevel: intPtr(50)}, nil},
+		{"Bin almost full", models.UpdateDeviceRequest{FillLevel: intPtr(95)}, []models.DeviceEventType{models.DeviceEventMaintenanceRequired}},
+		{"Battery low while already needing maintenance", models.UpdateDeviceRequest{BatteryLevel: intPtr(10)}, nil},
+		{"Emptied and charged", models.UpdateDeviceRequest{FillLevel: intPtr(5), BatteryLevel: intPtr(100)}, []models.DeviceEventType{models.DeviceEventMaintenanceCleared}},
+	}
+
+	for _, step := range steps {
+		events = nil
+		device, err := service.UpdateDevice(ctx, "smart-bin-001", &step.req)
+		if err != nil {
+			t.Fatalf("%s: UpdateDevice() error = %v", step.name, err)
+		}
+		if len(events) != len(step.wantEvents) || (len(events) == 1 && events[0] != step.wantEvents[0]) {
+			t.Errorf("%s: events = %v, want %v", step.name, events, step.wantEvents)
+		}
+		if !device.UpdatedAt.After(now) {
+			t.Errorf("%s: UpdatedAt not bumped", step.name)
+		}
+	}
+
+	// Entrar y salir de maintenance por estado también avisa
+	events = nil
+	if _, err := service.ChangeStatus(ctx, "smart-bin-001", &models.ChangeDeviceStatusRequest{
+		Status: models.DeviceStatusMaintenance,
+		Reason: "lid sensor replacement",
+	}); err != nil {
+		t.Fatalf("ChangeStatus() error = %v", err)
+	}
+	if len(events) != 1 || events[0] != models.DeviceEventMaintenanceRequired {
+		t.Errorf("Events after ChangeStatus = %v, want [maintenance_required]", events)
+	}
+}
+
+// TestUpdateDeviceRecordsTelemetry verifica que un fill_level por PATCH quede en el histórico
+// y recalcule el pronóstico igual que un evento device_status.
+func TestUpdateDeviceRecordsTelemetry(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	telemetryRepository := local.NewTelemetryRepository()
+	deviceManager := NewDeviceManager(devices)
+	service := NewDeviceUpdateService(
+		deviceManager,
+		NewTelemetryService(testConfig(), telemetryRepository, devices),
+		NewForecastService(testConfig(), deviceManager, devices, local.NewJobRepository(), telemetryRepository),
+		NewAlertService(local.NewAlertRepository(), local.NewGroupRepository(), local.NewOrganizationRepository(), deviceManager, models.DefaultAlertRules()),
+		local.NewEventBus(),
+	)
+
+	now := time.Now()
+	if err := devices.Create(ctx, &models.Device{DeviceID: "smart-bin-001", Status: models.DeviceStatusActive, CreatedAt: now}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i, fill := range []float64{20, 30} {
+		reading := &models.TelemetryReading{
+			DeviceID:  "smart-bin-001",
+			Metric:    models.MetricFillLevel,
+			Value:     fill,
+			Timestamp: now.Add(time.Duration(i-2) * time.Hour),
+		}
+		if err := telemetryRepository.Append(ctx, reading); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	fill := 40
+	device, err := service.UpdateDevice(ctx, "smart-bin-001", &models.UpdateDeviceRequest{FillLevel: &fill})
+	if err != nil {
+		t.Fatalf("UpdateDevice() error = %v", err)
+	}
+
+	points, err := telemetryRepository.Query(ctx, "smart-bin-001", models.MetricFillLevel, models.ResolutionRaw, now.Add(-3*time.Hour), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 3 {
+		t.Errorf("Fill readings = %d, want 3", len(points))
+	}
+	if device.FillRatePerHour == nil || math.Abs(*device.FillRatePerHour-10) > 0.1 || device.PredictedFullAt == nil {
+		t.Errorf("Forecast = %v, %v; want ~10%%/h with a prediction", device.FillRatePerHour, device.PredictedFullAt)
+	}
+}
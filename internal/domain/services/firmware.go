@@ -0,0 +1,606 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// errRolloutUnchanged indica a modifyRollout que no hay nada que guardar.
+var errRolloutUnchanged = errors.New("rollout unchanged")
+
+// FirmwareService gestiona las releases de firmware y su distribución OTA por rollouts.
+type FirmwareService struct {
+	config        *config.Config
+	firmware      ports.FirmwareRepository
+	deviceManager *DeviceManager
+	devices       ports.DeviceRepository
+	presigner     ports.S3Presigner
+	iot           ports.IoTPublisher
+
+	// mu serializa las actualizaciones de rollouts y de actualizaciones por dispositivo
+	mu sync.Mutex
+}
+
+// NewFirmwareService crea una nueva instancia de FirmwareService.
+func NewFirmwareService(
+	cfg *config.Config,
+	firmware ports.FirmwareRepository,
+	deviceManager *DeviceManager,
+	devices ports.DeviceRepository,
+	presigner ports.S3Presigner,
+	iot ports.IoTPublisher,
+) *FirmwareService {
+	return &FirmwareService{
+		config:        cfg,
+		firmware:      firmware,
+		deviceManager: deviceManager,
+		devices:       devices,
+		presigner:     presigner,
+		iot:           iot,
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     RELEASES
+// ═══════════════════════════════════════════════════════════════════
+
+// CreateRelease registers a firmware release and returns a presigned URL to upload the binary.
+func (s *FirmwareService) CreateRelease(
+	ctx context.Context,
+	req *models.CreateFirmwareReleaseRequest,
+) (*models.CreateFirmwareReleaseResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	release := &models.FirmwareRelease{
+		ReleaseID:      "fw_" + uuid.New().String()[:8],
+		Version:        req.Version,
+		DeviceType:     req.DeviceType,
+		ArtifactKey:    models.FirmwareArtifactKey(req.DeviceType, req.Version),
+		ChecksumSHA256: req.ChecksumSHA256,
+		SizeBytes:      req.SizeBytes,
+		Notes:          req.Notes,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.firmware.CreateRelease(ctx, release); err != nil {
+		return nil, err
+	}
+
+	expiry := s.config.AWS.S3.PresignedURLExpiry
+	uploadURL, err := s.presigner.GeneratePresignedPutURL(ctx, s.config.AWS.S3.BucketFirmware, release.ArtifactKey, expiry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrS3Operation, err)
+	}
+
+	log.Info().
+		Str("release_id", release.ReleaseID).
+		Str("device_type", release.DeviceType).
+		Str("version", release.Version).
+		Msg("Firmware release created")
+
+	return &models.CreateFirmwareReleaseResponse{
+		Release:   release,
+		UploadURL: uploadURL,
+		ExpiresAt: release.CreatedAt.Add(expiry),
+	}, nil
+}
+
+// GetRelease returns a firmware release.
+func (s *FirmwareService) GetRelease(ctx context.Context, releaseID string) (*models.FirmwareRelease, error) {
+	return s.firmware.GetRelease(ctx, releaseID)
+}
+
+// ListReleases returns the firmware releases, newest first.
+func (s *FirmwareService) ListReleases(
+	ctx context.Context,
+	req *models.ListFirmwareReleasesRequest,
+) (*models.ListFirmwareReleasesResponse, error) {
+	releases, total, err := s.firmware.ListReleases(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListFirmwareReleasesResponse{
+		Releases: releases,
+		Total:    total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════
+//                     ROLLOUTS
+// ═══════════════════════════════════════════════════════════════════
+
+// CreateRollout starts distributing a release to a percentage of the fleet or to a group of devices.
+// Only one open (active or paused) rollout per device type is allowed.
+func (s *FirmwareService) CreateRollout(ctx context.Context, req *models.CreateRolloutRequest) (*models.FirmwareRollout, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	release, err := s.firmware.GetRelease(ctx, req.ReleaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deviceID := range req.DeviceIDs {
+		device, err := s.devices.GetByID(ctx, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		if device.DeviceType != release.DeviceType {
+			return nil, fmt.Errorf("%w: device %s is %s, release is for %s",
+				models.ErrInvalidRollout, deviceID, device.DeviceType, release.DeviceType)
+		}
+	}
+
+	now := time.Now()
+	rollout := &models.FirmwareRollout{
+		RolloutID:        "rollout_" + uuid.New().String()[:8],
+		ReleaseID:        release.ReleaseID,
+		Version:          release.Version,
+		DeviceType:       release.DeviceType,
+		Percentage:       req.Percentage,
+		DeviceIDs:        req.DeviceIDs,
+		FailureThreshold: models.DefaultRolloutFailureThreshold,
+		MinSamples:       models.DefaultRolloutMinSamples,
+		Status:           models.RolloutStatusActive,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if req.FailureThreshold != nil {
+		rollout.FailureThreshold = *req.FailureThreshold
+	}
+	if req.MinSamples != nil {
+		rollout.MinSamples = *req.MinSamples
+	}
+
+	if err := s.createRollout(ctx, rollout); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("rollout_id", rollout.RolloutID).
+		Str("device_type", rollout.DeviceType).
+		Str("version", rollout.Version).
+		Int("percentage", rollout.Percentage).
+		Int("devices", len(rollout.DeviceIDs)).
+		Msg("Firmware rollout started")
+
+	if err := s.dispatch(ctx, rollout, release, false); err != nil {
+		return nil, err
+	}
+	return s.GetRollout(ctx, rollout.RolloutID)
+}
+
+// GetRollout returns a rollout with its progress.
+func (s *FirmwareService) GetRollout(ctx context.Context, rolloutID string) (*models.FirmwareRollout, error) {
+	rollout, err := s.firmware.GetRollout(ctx, rolloutID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachProgress(ctx, rollout); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// ListRollouts returns the rollouts with their progress, newest first.
+func (s *FirmwareService) ListRollouts(ctx context.Context, req *models.ListRolloutsRequest) (*models.ListRolloutsResponse, error) {
+	rollouts, total, err := s.firmware.ListRollouts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rollout := range rollouts {
+		if err := s.attachProgress(ctx, rollout); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.ListRolloutsResponse{
+		Rollouts: rollouts,
+		Total:    total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}, nil
+}
+
+// UpdateRollout widens a percentage rollout to the next stage. The percentage can only grow.
+// If the rollout is active the newly selected devices receive the update right away.
+func (s *FirmwareService) UpdateRollout(
+	ctx context.Context,
+	rolloutID string,
+	req *models.UpdateRolloutRequest,
+) (*models.FirmwareRollout, error) {
+	rollout, err := s.modifyRollout(ctx, rolloutID, func(rollout *models.FirmwareRollout) error {
+		if rollout.Status == models.RolloutStatusCompleted {
+			return models.ErrInvalidTransition
+		}
+		if len(rollout.DeviceIDs) > 0 {
+			return fmt.Errorf("%w: rollout targets a device group, not a percentage", models.ErrInvalidRollout)
+		}
+		if req.Percentage <= rollout.Percentage || req.Percentage > 100 {
+			return fmt.Errorf("%w: percentage must be between %d and 100", models.ErrInvalidRollout, rollout.Percentage+1)
+		}
+		rollout.Percentage = req.Percentage
+		rollout.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("rollout_id", rolloutID).
+		Int("percentage", rollout.Percentage).
+		Msg("Firmware rollout widened")
+
+	if rollout.Status == models.RolloutStatusActive {
+		if err := s.dispatchRollout(ctx, rollout, false); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetRollout(ctx, rolloutID)
+}
+
+// PauseRollout stops sending the update. Devices already updating keep reporting progress.
+func (s *FirmwareService) PauseRollout(ctx context.Context, rolloutID, reason string) (*models.FirmwareRollout, error) {
+	if reason == "" {
+		reason = "paused by operator"
+	}
+
+	if _, err := s.modifyRollout(ctx, rolloutID, func(rollout *models.FirmwareRollout) error {
+		return rollout.Pause(reason, time.Now())
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("rollout_id", rolloutID).
+		Str("reason", reason).
+		Msg("Firmware rollout paused")
+
+	return s.GetRollout(ctx, rolloutID)
+}
+
+// ResumeRollout reactivates a paused rollout and sends the pending updates to the online devices.
+func (s *FirmwareService) ResumeRollout(ctx context.Context, rolloutID string) (*models.FirmwareRollout, error) {
+	rollout, err := s.modifyRollout(ctx, rolloutID, func(rollout *models.FirmwareRollout) error {
+		return rollout.Resume(time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("rollout_id", rolloutID).
+		Msg("Firmware rollout resumed")
+
+	if err := s.dispatchRollout(ctx, rollout, true); err != nil {
+		return nil, err
+	}
+	return s.GetRollout(ctx, rolloutID)
+}
+
+// HandleProgress applies a firmware_update event. When the device reports success its
+// FirmwareVersion is updated; then the rollout is paused if the failure rate crosses the
+// threshold, or completed once every selected device finished.
+func (s *FirmwareService) HandleProgress(
+	ctx context.Context,
+	deviceID string,
+	progress *models.FirmwareProgress,
+) (*models.FirmwareUpdate, error) {
+	update, changed, err := s.modifyUpdate(ctx, progress.RolloutID, deviceID, func(update *models.FirmwareUpdate) (bool, error) {
+		return update.Advance(progress.Status, progress.Error, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return update, nil
+	}
+
+	log.Info().
+		Str("rollout_id", update.RolloutID).
+		Str("device_id", deviceID).
+		Str("status", string(update.Status)).
+		Str("error", update.Error).
+		Msg("Firmware update progress")
+
+	if update.Status == models.FirmwareUpdateSucceeded {
+		_, err := s.deviceManager.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+			device.FirmwareVersion = update.Version
+			device.FirmwareUpdatedAt = &update.UpdatedAt
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if update.IsFinished() {
+		s.evaluateRollout(ctx, update.RolloutID)
+	}
+	return update, nil
+}
+
+// HandleConnectivityEvent sends the pending firmware updates of active rollouts when a device comes back online.
+// The update may reach the device twice, so devices must ignore a rollout_id they are already applying.
+func (s *FirmwareService) HandleConnectivityEvent(ctx context.Context, event *models.DeviceEvent) {
+	if event.EventType != models.DeviceEventOnline {
+		return
+	}
+
+	rollouts, _, err := s.firmware.ListRollouts(ctx, &models.ListRolloutsRequest{Status: models.RolloutStatusActive})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", event.DeviceID).
+			Msg("Failed to load rollouts on reconnect")
+		return
+	}
+
+	for _, rollout := range rollouts {
+		update, err := s.firmware.GetUpdate(ctx, rollout.RolloutID, event.DeviceID)
+		if err != nil || update == nil || update.Status != models.FirmwareUpdatePending {
+			continue
+		}
+
+		release, err := s.firmware.GetRelease(ctx, rollout.ReleaseID)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("rollout_id", rollout.RolloutID).
+				Msg("Failed to load release on reconnect")
+			continue
+		}
+		s.send(ctx, rollout, release, event.DeviceID)
+	}
+}
+
+// createRollout guarda el rollout si no hay otro abierto para el mismo tipo de dispositivo.
+func (s *FirmwareService) createRollout(ctx context.Context, rollout *models.FirmwareRollout) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, status := range []models.RolloutStatus{models.RolloutStatusActive, models.RolloutStatusPaused} {
+		_, open, err := s.firmware.ListRollouts(ctx, &models.ListRolloutsRequest{
+			DeviceType: rollout.DeviceType,
+			Status:     status,
+		})
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return models.ErrRolloutInProgress
+		}
+	}
+
+	return s.firmware.CreateRollout(ctx, rollout)
+}
+
+// dispatchRollout carga la release del rollout y llama a dispatch.
+func (s *FirmwareService) dispatchRollout(ctx context.Context, rollout *models.FirmwareRollout, resendPending bool) error {
+	release, err := s.firmware.GetRelease(ctx, rollout.ReleaseID)
+	if err != nil {
+		return err
+	}
+	return s.dispatch(ctx, rollout, release, resendPending)
+}
+
+// dispatch crea la actualización pending de cada dispositivo seleccionado que aún no tiene la versión
+// y la envía a los que están conectados. Los desconectados la reciben al reconectar.
+// Con resendPending también reenvía las pending que ya existían (al reanudar un rollout pausado).
+func (s *FirmwareService) dispatch(
+	ctx context.Context,
+	rollout *models.FirmwareRollout,
+	release *models.FirmwareRelease,
+	resendPending bool,
+) error {
+	devices, _, err := s.devices.List(ctx, &models.ListDevicesRequest{DeviceType: rollout.DeviceType})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, device := range devices {
+		if !rollout.Targets(device.DeviceID) {
+			continue
+		}
+		if device.Status == models.DeviceStatusInactive || device.Status == models.DeviceStatusDecommissioned {
+			continue
+		}
+
+		update, err := s.firmware.GetUpdate(ctx, rollout.RolloutID, device.DeviceID)
+		if err != nil {
+			return err
+		}
+		created := update == nil
+		if created {
+			if device.FirmwareVersion == rollout.Version {
+				continue
+			}
+			update = &models.FirmwareUpdate{
+				RolloutID: rollout.RolloutID,
+				DeviceID:  device.DeviceID,
+				Version:   rollout.Version,
+				Status:    models.FirmwareUpdatePending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := s.firmware.SaveUpdate(ctx, update); err != nil {
+				return err
+			}
+		}
+
+		if device.Online && (created || resendPending && update.Status == models.FirmwareUpdatePending) {
+			s.send(ctx, rollout, release, device.DeviceID)
+			sent++
+		}
+	}
+
+	log.Info().
+		Str("rollout_id", rollout.RolloutID).
+		Int("sent", sent).
+		Msg("Firmware rollout dispatched")
+
+	// Un rollout al 100% puede no tener nada que hacer (toda la flota ya tiene la versión)
+	s.evaluateRollout(ctx, rollout.RolloutID)
+	return nil
+}
+
+// send publica la actualización OTA con una URL de descarga prefirmada.
+// Un fallo solo se registra: se reintenta al reconectar.
+func (s *FirmwareService) send(ctx context.Context, rollout *models.FirmwareRollout, release *models.FirmwareRelease, deviceID string) {
+	expiry := s.config.AWS.S3.PresignedURLExpiry
+	url, err := s.presigner.GeneratePresignedGetURL(ctx, s.config.AWS.S3.BucketFirmware, release.ArtifactKey, expiry)
+	if err == nil {
+		err = s.iot.PublishFirmwareUpdate(ctx, &models.FirmwareUpdateMessage{
+			RolloutID:      rollout.RolloutID,
+			DeviceID:       deviceID,
+			Version:        release.Version,
+			URL:            url,
+			ChecksumSHA256: release.ChecksumSHA256,
+			SizeBytes:      release.SizeBytes,
+			URLExpiresAt:   time.Now().Add(expiry),
+		})
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("rollout_id", rollout.RolloutID).
+			Str("device_id", deviceID).
+			Msg("Failed to send firmware update")
+	}
+}
+
+// evaluateRollout pausa el rollout si la tasa de fallos supera el umbral, o lo completa
+// si ya no puede crecer y todos los dispositivos seleccionados terminaron.
+func (s *FirmwareService) evaluateRollout(ctx context.Context, rolloutID string) {
+	var transition string
+	_, err := s.modifyRollout(ctx, rolloutID, func(rollout *models.FirmwareRollout) error {
+		if rollout.Status != models.RolloutStatusActive {
+			return errRolloutUnchanged
+		}
+
+		updates, err := s.firmware.ListUpdates(ctx, rolloutID)
+		if err != nil {
+			return err
+		}
+		progress := models.NewRolloutProgress(updates)
+		now := time.Now()
+
+		switch {
+		case rollout.ExceedsFailureThreshold(updates):
+			transition = "paused"
+			window := rollout.FailureWindow(updates)
+			reason := fmt.Sprintf("failure rate %.0f%% exceeds threshold %.0f%% (%d of %d updates failed)",
+				window.FailureRate*100, rollout.FailureThreshold*100, window.Failed, window.Finished())
+			return rollout.Pause(reason, now)
+
+		case rollout.IsFullFleet() && progress.Finished() == progress.Targeted:
+			transition = "completed"
+			rollout.Status = models.RolloutStatusCompleted
+			rollout.CompletedAt = &now
+			rollout.UpdatedAt = now
+			return nil
+
+		default:
+			return errRolloutUnchanged
+		}
+	})
+
+	switch {
+	case err == nil:
+		log.Warn().
+			Str("rollout_id", rolloutID).
+			Str("transition", transition).
+			Msg("Firmware rollout status changed")
+	case !errors.Is(err, errRolloutUnchanged):
+		log.Error().
+			Err(err).
+			Str("rollout_id", rolloutID).
+			Msg("Failed to evaluate firmware rollout")
+	}
+}
+
+// attachProgress calcula el avance del rollout.
+func (s *FirmwareService) attachProgress(ctx context.Context, rollout *models.FirmwareRollout) error {
+	updates, err := s.firmware.ListUpdates(ctx, rollout.RolloutID)
+	if err != nil {
+		return err
+	}
+	rollout.Progress = models.NewRolloutProgress(updates)
+	return nil
+}
+
+// modifyRollout carga el rollout, aplica modify y guarda el resultado.
+// Si modify retorna error no se guarda nada.
+func (s *FirmwareService) modifyRollout(
+	ctx context.Context,
+	rolloutID string,
+	modify func(rollout *models.FirmwareRollout) error,
+) (*models.FirmwareRollout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rollout, err := s.firmware.GetRollout(ctx, rolloutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := modify(rollout); err != nil {
+		return nil, err
+	}
+
+	if err := s.firmware.UpdateRollout(ctx, rollout); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// modifyUpdate carga la actualización del dispositivo, aplica modify y guarda si hubo cambios.
+// Un dispositivo que no fue seleccionado en el rollout recibe models.ErrRolloutNotFound.
+func (s *FirmwareService) modifyUpdate(
+	ctx context.Context,
+	rolloutID, deviceID string,
+	modify func(update *models.FirmwareUpdate) (bool, error),
+) (*models.FirmwareUpdate, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update, err := s.firmware.GetUpdate(ctx, rolloutID, deviceID)
+	if err != nil {
+		return nil, false, err
+	}
+	if update == nil {
+		return nil, false, fmt.Errorf("%w: device %s is not part of rollout %s", models.ErrRolloutNotFound, deviceID, rolloutID)
+	}
+
+	changed, err := modify(update)
+	if err != nil {
+		return nil, false, err
+	}
+	if !changed {
+		return update, false, nil
+	}
+
+	if err := s.firmware.SaveUpdate(ctx, update); err != nil {
+		return nil, false, err
+	}
+	return update, true, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestFirmwareRollout verifica el envío a los dispositivos del grupo, el reenvío al reconectar,
+// la actualización de FirmwareVersion y la pausa automática por fallos.
+func TestFirmwareRollout(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	var group []string
+	for i := 1; i <= 6; i++ {
+		device := &models.Device{
+			DeviceID:        fmt.Sprintf("smart-bin-%03d", i),
+			DeviceType:      string(models.DeviceTypeSmartBinV2),
+			Status:          models.DeviceStatusActive,
+			Online:          i != 6,
+			FirmwareVersion: "2.0.0",
+		}
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+		group = append(group, device.DeviceID)
+	}
+
+	iot := local.NewIoTPublisher()
+	bus := local.NewEventBus()
+	deviceManager := NewDeviceManager(devices)
+	firmware := NewFirmwareService(testConfig(), local.NewFirmwareRepository(), deviceManager, devices, local.NewPresigner("us-east-1"), iot)
+	bus.Subscribe(firmware.HandleConnectivityEvent)
+
+	created, err := firmware.CreateRelease(ctx, &models.CreateFirmwareReleaseRequest{
+		Version:        "2.1.0",
+		DeviceType:     string(models.DeviceTypeSmartBinV2),
+		ChecksumSHA256: strings.Repeat("0f", 32),
+		SizeBytes:      2048,
+	})
+	if err != nil {
+		t.Fatalf("CreateRelease() error = %v", err)
+	}
+	if !strings.Contains(created.UploadURL, "smart-bin-test-firmware") {
+		t.Errorf("UploadURL = %s, want firmware bucket", created.UploadURL)
+	}
+
+	threshold := 0.2
+	minSamples := 3
+	rollout, err := firmware.CreateRollout(ctx, &models.CreateRolloutRequest{
+		ReleaseID:        created.Release.ReleaseID,
+		DeviceIDs:        group,
+		FailureThreshold: &threshold,
+		MinSamples:       &minSamples,
+	})
+	if err != nil {
+		t.Fatalf("CreateRollout() error = %v", err)
+	}
+	if rollout.Progress.Targeted != 6 || len(iot.FirmwareUpdates()) != 5 {
+		t.Fatalf("Targeted = %d, sent = %d, want 6 targeted and 5 sent", rollout.Progress.Targeted, len(iot.FirmwareUpdates()))
+	}
+
+	if _, err := firmware.CreateRollout(ctx, &models.CreateRolloutRequest{ReleaseID: created.Release.ReleaseID, Percentage: 10}); !errors.Is(err, models.ErrRolloutInProgress) {
+		t.Errorf("CreateRollout(second) error = %v, want %v", err, models.ErrRolloutInProgress)
+	}
+
+	// El dispositivo desconectado la recibe al reconectar
+	heartbeat := NewHeartbeatMonitor(testConfig(), deviceManager, devices, bus)
+	if err := heartbeat.RecordHeartbeat(ctx, "smart-bin-006"); err != nil {
+		t.Fatalf("RecordHeartbeat() error = %v", err)
+	}
+	if sent := iot.FirmwareUpdates(); len(sent) != 6 || sent[5].DeviceID != "smart-bin-006" {
+		t.Fatalf("Sent updates = %d, want the reconnected device last", len(sent))
+	}
+
+	report := func(deviceID string, status models.FirmwareUpdateStatus) {
+		t.Helper()
+		if _, err := firmware.HandleProgress(ctx, deviceID, &models.FirmwareProgress{RolloutID: rollout.RolloutID, Status: status}); err != nil {
+			t.Fatalf("HandleProgress(%s, %s) error = %v", deviceID, status, err)
+		}
+	}
+
+	report("smart-bin-001", models.FirmwareUpdateDownloading)
+	report("smart-bin-001", models.FirmwareUpdateSucceeded)
+	device, _ := devices.GetByID(ctx, "smart-bin-001")
+	if device.FirmwareVersion != "2.1.0" || device.FirmwareUpdatedAt == nil {
+		t.Errorf("Firmware = %s, updated_at = %v, want 2.1.0", device.FirmwareVersion, device.FirmwareUpdatedAt)
+	}
+
+	// 2 de 3 fallidos supera el 20%: el rollout se pausa
+	report("smart-bin-002", models.FirmwareUpdateFailed)
+	report("smart-bin-003", models.FirmwareUpdateFailed)
+	rollout, err = firmware.GetRollout(ctx, rollout.RolloutID)
+	if err != nil {
+		t.Fatalf("GetRollout() error = %v", err)
+	}
+	if rollout.Status != models.RolloutStatusPaused || rollout.PauseReason == "" {
+		t.Fatalf("Rollout status = %s (%q), want paused", rollout.Status, rollout.PauseReason)
+	}
+
+	if _, err := firmware.HandleProgress(ctx, "smart-bin-999", &models.FirmwareProgress{RolloutID: rollout.RolloutID, Status: models.FirmwareUpdateSucceeded}); !errors.Is(err, models.ErrRolloutNotFound) {
+		t.Errorf("HandleProgress(unknown device) error = %v, want %v", err, models.ErrRolloutNotFound)
+	}
+
+	// Reanudado y con el resto exitoso, el grupo completo termina el rollout
+	if _, err := firmware.ResumeRollout(ctx, rollout.RolloutID); err != nil {
+		t.Fatalf("ResumeRollout() error = %v", err)
+	}
+	for _, deviceID := range []string{"smart-bin-004", "smart-bin-005", "smart-bin-006"} {
+		report(deviceID, models.FirmwareUpdateSucceeded)
+	}
+	rollout, _ = firmware.GetRollout(ctx, rollout.RolloutID)
+	if rollout.Status != models.RolloutStatusCompleted || rollout.CompletedAt == nil {
+		t.Errorf("Rollout status = %s, want completed", rollout.Status)
+	}
+}
+
+// TestUpdateRolloutPercentage verifica que el porcentaje solo puede subir y que ampliar envía a los nuevos.
+func TestUpdateRolloutPercentage(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	for i := 0; i < 40; i++ {
+		device := &models.Device{
+			DeviceID:   fmt.Sprintf("smart-bin-%03d", i),
+			DeviceType: string(models.DeviceTypeSmartBinV1),
+			Status:     models.DeviceStatusActive,
+			Online:     true,
+		}
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	iot := local.NewIoTPublisher()
+	firmware := NewFirmwareService(testConfig(), local.NewFirmwareRepository(), NewDeviceManager(devices), devices, local.NewPresigner("us-east-1"), iot)
+
+	created, err := firmware.CreateRelease(ctx, &models.CreateFirmwareReleaseRequest{
+		Version:        "1.5.0",
+		DeviceType:     string(models.DeviceTypeSmartBinV1),
+		ChecksumSHA256: strings.Repeat("aa", 32),
+		SizeBytes:      512,
+	})
+	if err != nil {
+		t.Fatalf("CreateRelease() error = %v", err)
+	}
+
+	rollout, err := firmware.CreateRollout(ctx, &models.CreateRolloutRequest{ReleaseID: created.Release.ReleaseID, Percentage: 25})
+	if err != nil {
+		t.Fatalf("CreateRollout() error = %v", err)
+	}
+	initial := rollout.Progress.Targeted
+
+	if _, err := firmware.UpdateRollout(ctx, rollout.RolloutID, &models.UpdateRolloutRequest{Percentage: 10}); !errors.Is(err, models.ErrInvalidRollout) {
+		t.Errorf("UpdateRollout(lower) error = %v, want %v", err, models.ErrInvalidRollout)
+	}
+
+	rollout, err = firmware.UpdateRollout(ctx, rollout.RolloutID, &models.UpdateRolloutRequest{Percentage: 100})
+	if err != nil {
+		t.Fatalf("UpdateRollout(100) error = %v", err)
+	}
+	if rollout.Progress.Targeted != 40 || initial >= 40 {
+		t.Errorf("Targeted = %d (initially %d), want 40", rollout.Progress.Targeted, initial)
+	}
+	if len(iot.FirmwareUpdates()) != 40 {
+		t.Errorf("Sent updates = %d, want one per device", len(iot.FirmwareUpdates()))
+	}
+}
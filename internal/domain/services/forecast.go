@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// emptiedDrop - Caída de fill_level (puntos %) que se interpreta como vaciado del contenedor.
+	emptiedDrop = 20
+
+	// minFitSpan - Tiempo mínimo entre la primera y la última lectura para ajustar la regresión.
+	minFitSpan = 15 * time.Minute
+)
+
+// ForecastService estima la velocidad de llenado de cada contenedor y cuándo se llenará.
+//
+// La velocidad sale de una regresión lineal sobre las lecturas de fill_level desde
+// el último vaciado. Si no hay lecturas suficientes se usa la tasa de jobs del
+// dispositivo multiplicada por config.Forecast.FillPerJob.
+type ForecastService struct {
+	config        *config.Config
+	deviceManager *DeviceManager
+	devices       ports.DeviceRepository
+	jobs          ports.JobRepository
+	telemetry     ports.TelemetryRepository
+}
+
+// NewForecastService crea una nueva instancia de ForecastService.
+func NewForecastService(
+	cfg *config.Config,
+	deviceManager *DeviceManager,
+	devices ports.DeviceRepository,
+	jobs ports.JobRepository,
+	telemetry ports.TelemetryRepository,
+) *ForecastService {
+	return &ForecastService{
+		config:        cfg,
+		deviceManager: deviceManager,
+		devices:       devices,
+		jobs:          jobs,
+		telemetry:     telemetry,
+	}
+}
+
+// UpdateForecast refits the device fill rate and stores FillRatePerHour and PredictedFullAt.
+func (s *ForecastService) UpdateForecast(ctx context.Context, deviceID string) (*models.Device, error) {
+	device, err := s.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rate, err := s.fillRate(ctx, device, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deviceManager.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+		device.FillRatePerHour = nil
+		if rate > 0 {
+			device.FillRatePerHour = &rate
+		}
+		device.PredictedFullAt = device.PredictFullAt(rate, now)
+		return nil
+	})
+}
+
+// ListForecasts returns the fill forecasts sorted by urgency (soonest full first).
+// Devices without a prediction are listed last, by current fill level.
+func (s *ForecastService) ListForecasts(ctx context.Context, req *models.ListForecastRequest) (*models.ListForecastResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	devices, _, err := s.devices.List(ctx, &models.ListDevicesRequest{BinType: req.BinType})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	forecasts := make([]*models.FillForecast, 0, len(devices))
+	for _, device := range devices {
+		if device.FillLevel == nil {
+			continue
+		}
+		if req.Within > 0 && (device.PredictedFullAt == nil || device.PredictedFullAt.After(now.Add(req.Within))) {
+			continue
+		}
+		forecasts = append(forecasts, models.NewFillForecast(device, now))
+	}
+
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		a, b := forecasts[i], forecasts[j]
+		switch {
+		case a.PredictedFullAt != nil && b.PredictedFullAt != nil:
+			return a.PredictedFullAt.Before(*b.PredictedFullAt)
+		case a.PredictedFullAt != nil || b.PredictedFullAt != nil:
+			return a.PredictedFullAt != nil
+		default:
+			return *a.FillLevel > *b.FillLevel
+		}
+	})
+
+	total := len(forecasts)
+	start := min(req.Offset, total)
+	end := start + min(req.Limit, total-start)
+
+	return &models.ListForecastResponse{
+		Forecasts: forecasts[start:end],
+		Total:     total,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}, nil
+}
+
+// fillRate estima la velocidad de llenado (% por hora) del dispositivo.
+func (s *ForecastService) fillRate(ctx context.Context, device *models.Device, now time.Time) (float64, error) {
+	measuredAt := now
+	if device.LastReportedAt != nil {
+		measuredAt = *device.LastReportedAt
+	}
+
+	points, err := s.telemetry.Query(
+		ctx,
+		device.DeviceID,
+		models.MetricFillLevel,
+		models.ResolutionRaw,
+		measuredAt.Add(-s.config.Forecast.Window),
+		measuredAt.Add(time.Second),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rate, ok := fitFillRate(points); ok && rate > 0 {
+		return rate, nil
+	}
+
+	jobsPerHour, err := s.jobsPerHour(ctx, device, now)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Debug().
+		Str("device_id", device.DeviceID).
+		Float64("jobs_per_hour", jobsPerHour).
+		Msg("Not enough fill readings, forecasting from job rate")
+
+	return jobsPerHour * s.config.Forecast.FillPerJob, nil
+}
+
+// jobsPerHour calcula los jobs por hora del dispositivo dentro de la ventana del pronóstico.
+func (s *ForecastService) jobsPerHour(ctx context.Context, device *models.Device, now time.Time) (float64, error) {
+	window := s.config.Forecast.Window
+	if age := now.Sub(device.CreatedAt); !device.CreatedAt.IsZero() && age < window {
+		window = age
+	}
+	if window < time.Hour {
+		return 0, nil
+	}
+
+	jobs, _, err := s.jobs.List(ctx, &models.ListJobsRequest{DeviceID: device.DeviceID})
+	if err != nil {
+		return 0, err
+	}
+
+	since := now.Add(-window)
+	count := 0
+	for _, job := range jobs {
+		if job.CreatedAt.After(since) {
+			count++
+		}
+	}
+
+	return float64(count) / window.Hours(), nil
+}
+
+// fitFillRate ajusta una recta (mínimos cuadrados) a las lecturas posteriores al último vaciado.
+// Retorna la pendiente en % por hora y false si no hay suficientes lecturas.
+func fitFillRate(points []models.TelemetryPoint) (float64, bool) {
+	start := 0
+	for i := 1; i < len(points); i++ {
+		if points[i-1].Avg-points[i].Avg > emptiedDrop {
+			start = i
+		}
+	}
+	segment := points[start:]
+
+	if len(segment) < 2 || segment[len(segment)-1].Timestamp.Sub(segment[0].Timestamp) < minFitSpan {
+		return 0, false
+	}
+
+	origin := segment[0].Timestamp
+	var sumX, sumY, sumXY, sumXX float64
+	for _, point := range segment {
+		x := point.Timestamp.Sub(origin).Hours()
+		sumX += x
+		sumY += point.Avg
+		sumXY += x * point.Avg
+		sumXX += x * x
+	}
+
+	n := float64(len(segment))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+
+	return (n*sumXY - sumX*sumY) / denominator, true
+}
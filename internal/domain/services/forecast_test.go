@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestFitFillRate verifica la regresión y el corte en el último vaciado.
+func TestFitFillRate(t *testing.T) {
+	base := time.Date(2026, 1, 21, 8, 0, 0, 0, time.UTC)
+	series := func(values ...float64) []models.TelemetryPoint {
+		points := make([]models.TelemetryPoint, len(values))
+		for i, value := range values {
+			points[i] = models.NewTelemetryPoint(base.Add(time.Duration(i)*time.Hour), value)
+		}
+		return points
+	}
+
+	tests := []struct {
+		name     string
+		points   []models.TelemetryPoint
+		wantRate float64
+		wantOK   bool
+	}{
+		{"Steady filling", series(10, 15, 20, 25), 5, true},
+		{"Emptied in the middle", series(70, 80, 90, 5, 8, 11), 3, true},
+		{"Single reading", series(40), 0, false},
+		{"Just emptied", series(60, 90, 5), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := fitFillRate(tt.points)
+			if ok != tt.wantOK {
+				t.Fatalf("fitFillRate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && math.Abs(rate-tt.wantRate) > 1e-9 {
+				t.Errorf("fitFillRate() = %v, want %v", rate, tt.wantRate)
+			}
+		})
+	}
+}
+
+// TestForecastServiceUrgency verifica predicted_full_at y el orden por urgencia.
+func TestForecastServiceUrgency(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	telemetryRepository := local.NewTelemetryRepository()
+	deviceManager := NewDeviceManager(devices)
+	service := NewForecastService(testConfig(), deviceManager, devices, local.NewJobRepository(), telemetryRepository)
+
+	now := time.Now().Truncate(time.Second)
+	fills := map[string][]int{
+		"slow-bin": {10, 12, 14},  // 2%/h desde 14% → ~43h
+		"fast-bin": {50, 60, 70},  // 10%/h desde 70% → 3h
+		"idle-bin": {30, 30, 30},  // sin llenado → sin pronóstico
+		"full-bin": {80, 90, 100}, // ya lleno
+	}
+
+	for deviceID, values := range fills {
+		if err := devices.Create(ctx, &models.Device{DeviceID: deviceID, Status: models.DeviceStatusActive, CreatedAt: now}); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+		for i, value := range values {
+			level := value
+			reading := &models.DeviceTelemetry{
+				FillLevel:  &level,
+				ReportedAt: now.Add(time.Duration(i-len(values)+1) * time.Hour),
+			}
+			if _, err := deviceManager.ApplyTelemetry(ctx, deviceID, reading); err != nil {
+				t.Fatalf("ApplyTelemetry: %v", err)
+			}
+			for _, r := range reading.Readings(deviceID) {
+				if err := telemetryRepository.Append(ctx, &r); err != nil {
+					t.Fatalf("Append: %v", err)
+				}
+			}
+		}
+		if _, err := service.UpdateForecast(ctx, deviceID); err != nil {
+			t.Fatalf("UpdateForecast(%s): %v", deviceID, err)
+		}
+	}
+
+	fast, _ := devices.GetByID(ctx, "fast-bin")
+	if fast.PredictedFullAt == nil || !fast.PredictedFullAt.Equal(now.Add(3*time.Hour)) {
+		t.Errorf("fast-bin predicted_full_at = %v, want %v", fast.PredictedFullAt, now.Add(3*time.Hour))
+	}
+
+	response, err := service.ListForecasts(ctx, &models.ListForecastRequest{})
+	if err != nil {
+		t.Fatalf("ListForecasts() error = %v", err)
+	}
+
+	want := []string{"full-bin", "fast-bin", "slow-bin", "idle-bin"}
+	if len(response.Forecasts) != len(want) {
+		t.Fatalf("Forecasts = %d, want %d", len(response.Forecasts), len(want))
+	}
+	for i, deviceID := range want {
+		if response.Forecasts[i].DeviceID != deviceID {
+			t.Errorf("Forecasts[%d] = %s, want %s", i, response.Forecasts[i].DeviceID, deviceID)
+		}
+	}
+
+	within, err := service.ListForecasts(ctx, &models.ListForecastRequest{Within: 12 * time.Hour})
+	if err != nil {
+		t.Fatalf("ListForecasts(within) error = %v", err)
+	}
+	if within.Total != 2 {
+		t.Errorf("Forecasts within 12h = %d, want 2", within.Total)
+	}
+
+	page, err := service.ListForecasts(ctx, &models.ListForecastRequest{Limit: 2, Offset: 3})
+	if err != nil || len(page.Forecasts) != 1 || page.Forecasts[0].DeviceID != "idle-bin" {
+		t.Errorf("ListForecasts(limit=2, offset=3) = %v, %v; want [idle-bin]", page, err)
+	}
+
+	// Una paginación negativa es un 400, no un panic del slice
+	for _, req := range []*models.ListForecastRequest{{Limit: -1}, {Offset: -1}} {
+		if _, err := service.ListForecasts(ctx, req); !errors.Is(err, models.ErrInvalidInput) {
+			t.Errorf("ListForecasts(limit=%d, offset=%d) error = %v, want ErrInvalidInput", req.Limit, req.Offset, err)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// GroupService gestiona los grupos de dispositivos y aplica operaciones masivas a sus miembros.
+type GroupService struct {
+	groups   ports.GroupRepository
+	devices  ports.DeviceRepository
+	updates  *DeviceUpdateService
+	commands *CommandService
+	shadows  *ShadowService
+
+	// mu serializa las actualizaciones de grupos (read-modify-write)
+	mu sync.Mutex
+}
+
+// NewGroupService crea una nueva instancia de GroupService.
+func NewGroupService(
+	groups ports.GroupRepository,
+	devices ports.DeviceRepository,
+	updates *DeviceUpdateService,
+	commands *CommandService,
+	shadows *ShadowService,
+) *GroupService {
+	return &GroupService{
+		groups:   groups,
+		devices:  devices,
+		updates:  updates,
+		commands: commands,
+		shadows:  shadows,
+	}
+}
+
+// CreateGroup creates a static or dynamic group. Every device of a static group must exist.
+func (s *GroupService) CreateGroup(ctx context.Context, req *models.CreateGroupRequest) (*models.DeviceGroup, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	group := &models.DeviceGroup{
+		GroupID:     "grp_" + uuid.New().String()[:8],
+		OrgID:       models.CreationOrgID(ctx),
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+		Selector:    req.Selector,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if group.Type == models.GroupTypeStatic {
+		if err := group.SetDevices(req.DeviceIDs); err != nil {
+			return nil, err
+		}
+		if err := s.checkDevices(ctx, group.DeviceIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.groups.Create(ctx, group); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("group_id", group.GroupID).
+		Str("type", string(group.Type)).
+		Int("devices", len(group.DeviceIDs)).
+		Msg("Device group created")
+
+	return group, nil
+}
+
+// GetGroup retrieves a group by ID.
+func (s *GroupService) GetGroup(ctx context.Context, groupID string) (*models.DeviceGroup, error) {
+	return s.groups.GetByID(ctx, groupID)
+}
+
+// ListGroups returns a page of groups ordered by name.
+func (s *GroupService) ListGroups(ctx context.Context, req *models.ListGroupsRequest) (*models.ListGroupsResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	groups, total, err := s.groups.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListGroupsResponse{
+		Groups: groups,
+		Total:  total,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}, nil
+}
+
+// UpdateGroup renames a group, adds or removes static members or replaces the dynamic selector.
+func (s *GroupService) UpdateGroup(ctx context.Context, groupID string, req *models.UpdateGroupRequest) (*models.DeviceGroup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group, err := s.groups.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.ApplyTo(group, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.checkDevices(ctx, req.AddDevices); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.Update(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteGroup deletes a group. Its devices are not modified and its alert rules stop applying.
+func (s *GroupService) DeleteGroup(ctx context.Context, groupID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.groups.Delete(ctx, groupID); err != nil {
+		return err
+	}
+
+	log.Info().Str("group_id", groupID).Msg("Device group deleted")
+	return nil
+}
+
+// ListGroupDevices returns a page of the current members of a group.
+// Dynamic groups are resolved on every call; decommissioned devices are never members.
+func (s *GroupService) ListGroupDevices(
+	ctx context.Context,
+	groupID string,
+	req *models.ListGroupDevicesRequest,
+) (*models.ListDevicesResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	members, err := s.members(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := []*models.Device{}
+	if req.Offset < len(members) {
+		end := req.Offset + min(req.Limit, len(members)-req.Offset)
+		page = members[req.Offset:end]
+	}
+
+	return &models.ListDevicesResponse{
+		Devices: page,
+		Total:   len(members),
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}, nil
+}
+
+// ChangeStatus moves every member of the group to another status.
+// Members are independent: the result reports which ones failed (e.g. an invalid transition).
+func (s *GroupService) ChangeStatus(
+	ctx context.Context,
+	groupID string,
+	req *models.ChangeDeviceStatusRequest,
+) (*models.GroupOperationResponse, error) {
+	return s.apply(ctx, groupID, models.GroupOperationStatus, func(device *models.Device) (string, error) {
+		_, err := s.updates.ChangeStatus(ctx, device.DeviceID, req)
+		return "", err
+	})
+}
+
+// SendCommand creates the command for every member of the group.
+func (s *GroupService) SendCommand(
+	ctx context.Context,
+	groupID string,
+	req *models.CreateCommandRequest,
+) (*models.GroupOperationResponse, error) {
+	return s.apply(ctx, groupID, models.GroupOperationCommand, func(device *models.Device) (string, error) {
+		command, err := s.commands.CreateCommand(ctx, device.DeviceID, req)
+		if err != nil {
+			return "", err
+		}
+		return command.CommandID, nil
+	})
+}
+
+// UpdateConfiguration merges the desired state into the shadow of every member of the group.
+// Shadow versions are per device, so the request cannot carry one.
+func (s *GroupService) UpdateConfiguration(
+	ctx context.Context,
+	groupID string,
+	req *models.UpdateShadowRequest,
+) (*models.GroupOperationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Version != nil {
+		return nil, fmt.Errorf("%w: version is not supported on group updates", models.ErrInvalidInput)
+	}
+
+	return s.apply(ctx, groupID, models.GroupOperationConfiguration, func(device *models.Device) (string, error) {
+		_, err := s.shadows.UpdateDesired(ctx, device.DeviceID, req)
+		return "", err
+	})
+}
+
+// apply ejecuta la operación en cada miembro y acumula los resultados.
+func (s *GroupService) apply(
+	ctx context.Context,
+	groupID string,
+	operation models.GroupOperation,
+	run func(device *models.Device) (string, error),
+) (*models.GroupOperationResponse, error) {
+	members, err := s.members(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.GroupOperationResponse{
+		GroupID:   groupID,
+		Operation: operation,
+		Results:   make([]*models.GroupOperationResult, 0, len(members)),
+	}
+	for _, device := range members {
+		commandID, err := run(device)
+		response.Record(device.DeviceID, err).CommandID = commandID
+	}
+
+	log.Info().
+		Str("group_id", groupID).
+		Str("operation", string(operation)).
+		Int("succeeded", response.Succeeded).
+		Int("failed", response.Failed).
+		Msg("Group operation applied")
+
+	return response, nil
+}
+
+// members resuelve los miembros actuales del grupo, ordenados por ID.
+func (s *GroupService) members(ctx context.Context, groupID string) ([]*models.Device, error) {
+	group, err := s.groups.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// El repositorio pre-filtra lo que puede; Contains decide
+	filter := &models.ListDevicesRequest{}
+	if group.Selector != nil {
+		filter.DeviceType = group.Selector.DeviceType
+		filter.BinType = group.Selector.BinType
+		filter.Tags = group.Selector.Tags
+	}
+
+	devices, _, err := s.devices.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*models.Device, 0, len(devices))
+	for _, device := range devices {
+		if group.Contains(device) {
+			members = append(members, device)
+		}
+	}
+	return members, nil
+}
+
+// checkDevices verifica que los dispositivos existan.
+func (s *GroupService) checkDevices(ctx context.Context, deviceIDs []string) error {
+	for _, deviceID := range deviceIDs {
+		_, err := s.devices.GetByID(ctx, deviceID)
+		if errors.Is(err, models.ErrDeviceNotFound) {
+			return fmt.Errorf("%w: device %s not found", models.ErrInvalidGroup, deviceID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
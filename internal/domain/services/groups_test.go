@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestGroupOperations verifica la resolución de miembros y las operaciones masivas por dispositivo.
+func TestGroupOperations(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	groups := local.NewGroupRepository()
+	iot := local.NewIoTPublisher()
+	alerts := NewAlertService(local.NewAlertRepository(), groups, local.NewOrganizationRepository(), NewDeviceManager(devices), models.DefaultAlertRules())
+	updates := newTestDeviceUpdateService(devices, alerts, local.NewEventBus())
+	service := NewGroupService(groups, devices, updates, NewCommandService(local.NewCommandRepository(), devices, iot), NewShadowService(local.NewShadowRepository(), devices, iot))
+
+	for _, device := range []*models.Device{
+		{DeviceID: "bin-1", Status: models.DeviceStatusActive, Tags: map[string]string{"campus": "norte"}},
+		{DeviceID: "bin-2", Status: models.DeviceStatusProvisioning, Tags: map[string]string{"campus": "norte"}},
+		{DeviceID: "bin-3", Status: models.DeviceStatusActive, Tags: map[string]string{"campus": "sur"}},
+		{DeviceID: "bin-4", Status: models.DeviceStatusDecommissioned, Tags: map[string]string{"campus": "norte"}},
+	} {
+		device.OrgID = models.DefaultOrgID
+		device.DeviceType = string(models.DeviceTypeSmartBinV2)
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	if _, err := service.CreateGroup(ctx, &models.CreateGroupRequest{
+		Name: "Unknown", Type: models.GroupTypeStatic, DeviceIDs: []string{"bin-1", "bin-9"},
+	}); !errors.Is(err, models.ErrInvalidGroup) {
+		t.Fatalf("CreateGroup(unknown device) error = %v, want ErrInvalidGroup", err)
+	}
+
+	norte, err := service.CreateGroup(ctx, &models.CreateGroupRequest{
+		Name:     "Campus norte",
+		Type:     models.GroupTypeDynamic,
+		Selector: &models.DeviceSelector{Tags: map[string]string{"campus": "norte"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+
+	members, err := service.ListGroupDevices(ctx, norte.GroupID, &models.ListGroupDevicesRequest{})
+	if err != nil {
+		t.Fatalf("ListGroupDevices() error = %v", err)
+	}
+	if members.Total != 2 || members.Devices[0].DeviceID != "bin-1" || members.Devices[1].DeviceID != "bin-2" {
+		t.Fatalf("Members = %d %v, want bin-1 and bin-2", members.Total, members.Devices)
+	}
+	page, err := service.ListGroupDevices(ctx, norte.GroupID, &models.ListGroupDevicesRequest{Limit: 5, Offset: 1})
+	if err != nil || page.Total != 2 || len(page.Devices) != 1 || page.Devices[0].DeviceID != "bin-2" {
+		t.Errorf("ListGroupDevices(limit=5, offset=1) = %v, %v; want [bin-2]", page, err)
+	}
+
+	// Una paginación negativa es un 400, no un panic del slice
+	for _, req := range []*models.ListGroupDevicesRequest{{Limit: -5}, {Offset: -1}} {
+		if _, err := service.ListGroupDevices(ctx, norte.GroupID, req); !errors.Is(err, models.ErrInvalidInput) {
+			t.Errorf("ListGroupDevices(limit=%d, offset=%d) error = %v, want ErrInvalidInput", req.Limit, req.Offset, err)
+		}
+	}
+
+	// bin-2 sigue en provisioning: no puede pasar a maintenance, pero bin-1 sí
+	response, err := service.ChangeStatus(ctx, norte.GroupID, &models.ChangeDeviceStatusRequest{
+		Status: models.DeviceStatusMaintenance,
+		Reason: "cleaning",
+	})
+	if err != nil {
+		t.Fatalf("ChangeStatus() error = %v", err)
+	}
+	if response.Total != 2 || response.Succeeded != 1 || response.Failed != 1 || response.Results[1].Error == "" {
+		t.Errorf("ChangeStatus() = %+v, want 1 succeeded and bin-2 failed", response)
+	}
+	if device, _ := devices.GetByID(ctx, "bin-1"); device.Status != models.DeviceStatusMaintenance {
+		t.Errorf("bin-1 status = %s, want maintenance", device.Status)
+	}
+
+	configuration, err := service.UpdateConfiguration(ctx, norte.GroupID, &models.UpdateShadowRequest{
+		Desired: map[string]interface{}{"report_interval_s": 600},
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfiguration() error = %v", err)
+	}
+	if configuration.Succeeded != 2 {
+		t.Errorf("UpdateConfiguration() succeeded = %d, want 2", configuration.Succeeded)
+	}
+
+	if err := service.DeleteGroup(ctx, norte.GroupID); err != nil {
+		t.Fatalf("DeleteGroup() error = %v", err)
+	}
+	if _, err := service.ChangeStatus(ctx, norte.GroupID, &models.ChangeDeviceStatusRequest{}); !errors.Is(err, models.ErrGroupNotFound) {
+		t.Errorf("Operation on deleted group error = %v, want ErrGroupNotFound", err)
+	}
+}
+
+// TestAlertServiceGroupRules verifica que la regla de un grupo reemplaza a las de tipo y genéricas.
+func TestAlertServiceGroupRules(t *testing.T) {
+	ctx := context.Background()
+	groups := local.NewGroupRepository()
+	if err := groups.Create(ctx, &models.DeviceGroup{
+		GroupID:  "grp_events",
+		Type:     models.GroupTypeDynamic,
+		Selector: &models.DeviceSelector{Tags: map[string]string{"usage": "events"}},
+	}); err != nil {
+		t.Fatalf("Create group: %v", err)
+	}
+
+	rules := append(models.DefaultAlertRules(), models.AlertRule{
+		RuleID:         "events_fill_high",
+		GroupID:        "grp_events",
+		Metric:         models.AlertMetricFillLevel,
+		Operator:       models.AlertOperatorAbove,
+		Threshold:      60,
+		ClearThreshold: 40,
+		Severity:       models.AlertSeverityCritical,
+	})
+	service := NewAlertService(local.NewAlertRepository(), groups, local.NewOrganizationRepository(), NewDeviceManager(local.NewDeviceRepository()), rules)
+
+	member := &models.Device{DeviceID: "bin-1", Status: models.DeviceStatusActive, Tags: map[string]string{"usage": "events"}}
+	other := &models.Device{DeviceID: "bin-2", Status: models.DeviceStatusActive}
+
+	memberRules, err := service.RulesForDevice(ctx, member)
+	if err != nil {
+		t.Fatalf("RulesForDevice() error = %v", err)
+	}
+	for _, rule := range memberRules {
+		if rule.RuleID == "fill_high" {
+			t.Error("Generic fill_high should be replaced by the group rule")
+		}
+	}
+	if len(memberRules) != 3 || memberRules[0].RuleID != "events_fill_high" {
+		t.Errorf("Rules for member = %v, want the group rule plus 2 generic ones", memberRules)
+	}
+
+	otherRules, err := service.RulesForDevice(ctx, other)
+	if err != nil {
+		t.Fatalf("RulesForDevice() error = %v", err)
+	}
+	if len(otherRules) != 3 {
+		t.Errorf("Rules for non member = %d, want 3", len(otherRules))
+	}
+	for _, rule := range otherRules {
+		if rule.GroupID != "" {
+			t.Errorf("Group rule %s applied to a non member", rule.RuleID)
+		}
+	}
+}
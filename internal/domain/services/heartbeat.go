@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// HeartbeatMonitor mantiene el estado online/offline de los dispositivos.
+// Cada evento recibido cuenta como heartbeat; un escaneo periódico marca como
+// offline a los dispositivos que superan el umbral de su tipo.
+type HeartbeatMonitor struct {
+	config        *config.Config
+	deviceManager *DeviceManager
+	devices       ports.DeviceRepository
+	events        ports.EventPublisher
+}
+
+// NewHeartbeatMonitor crea una nueva instancia de HeartbeatMonitor.
+func NewHeartbeatMonitor(
+	cfg *config.Config,
+	deviceManager *DeviceManager,
+	devices ports.DeviceRepository,
+	events ports.EventPublisher,
+) *HeartbeatMonitor {
+	return &HeartbeatMonitor{
+		config:        cfg,
+		deviceManager: deviceManager,
+		devices:       devices,
+		events:        events,
+	}
+}
+
+// RecordHeartbeat marks the device as seen and, if it was offline, flips it online and emits device_online.
+// Unknown devices are ignored.
+func (m *HeartbeatMonitor) RecordHeartbeat(ctx context.Context, deviceID string) error {
+	var cameOnline bool
+
+	device, err := m.deviceManager.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+		device.MarkAsSeen()
+		cameOnline = device.SetOnline(true, *device.LastSeen)
+		return nil
+	})
+	if errors.Is(err, models.ErrDeviceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if cameOnline {
+		m.publish(ctx, models.DeviceEventOnline, device)
+	}
+	return nil
+}
+
+// Scan flips to offline every device whose last heartbeat is older than its type threshold.
+// It returns the number of devices that went offline.
+func (m *HeartbeatMonitor) Scan(ctx context.Context, now time.Time) (int, error) {
+	online := true
+	devices, _, err := m.devices.List(ctx, &models.ListDevicesRequest{Online: &online})
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, candidate := range devices {
+		if candidate.Status == models.DeviceStatusDecommissioned {
+			continue
+		}
+
+		threshold := m.config.Heartbeat.OfflineThreshold(candidate.DeviceType)
+		if candidate.IsOnlineWithin(threshold, now) {
+			continue
+		}
+
+		var wentOffline bool
+		device, err := m.deviceManager.ModifyDevice(ctx, candidate.DeviceID, func(device *models.Device) error {
+			// Releer: pudo llegar un heartbeat entre el List y el Modify
+			if device.IsOnlineWithin(threshold, now) {
+				return nil
+			}
+			wentOffline = device.SetOnline(false, now)
+			return nil
+		})
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("device_id", candidate.DeviceID).
+				Msg("Failed to mark device offline")
+			continue
+		}
+
+		if wentOffline {
+			changed++
+			m.publish(ctx, models.DeviceEventOffline, device)
+		}
+	}
+
+	return changed, nil
+}
+
+// Start runs Scan every ScanInterval until ctx is canceled.
+func (m *HeartbeatMonitor) Start(ctx context.Context) {
+	interval := m.config.Heartbeat.ScanInterval
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := m.Scan(ctx, now); err != nil {
+					log.Error().Err(err).Msg("Heartbeat scan failed")
+				}
+			}
+		}
+	}()
+}
+
+// publish emite un evento de conectividad del dispositivo.
+func (m *HeartbeatMonitor) publish(ctx context.Context, eventType models.DeviceEventType, device *models.Device) {
+	data := map[string]interface{}{
+		"device_type": device.DeviceType,
+	}
+	if device.LastSeen != nil {
+		data["last_seen"] = device.LastSeen.Format(time.RFC3339)
+	}
+
+	event := &models.DeviceEvent{
+		EventType: eventType,
+		DeviceID:  device.DeviceID,
+		Timestamp: device.OnlineChangedAt.Format(time.RFC3339),
+		Data:      data,
+	}
+
+	if err := m.events.Publish(ctx, event); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", device.DeviceID).
+			Str("event_type", string(eventType)).
+			Msg("Failed to publish connectivity event")
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestHeartbeatMonitorTransitions verifica los cambios online/offline y los eventos emitidos.
+func TestHeartbeatMonitorTransitions(t *testing.T) {
+	ctx := context.Background()
+	cfg := testConfig()
+	cfg.Heartbeat.OfflineAfter = 5 * time.Minute
+	cfg.Heartbeat.OfflineAfterByType = map[string]time.Duration{
+		string(models.DeviceTypeSmartBinIndustrial): 30 * time.Minute,
+	}
+
+	devices := local.NewDeviceRepository()
+	for _, device := range []*models.Device{
+		{DeviceID: "smart-bin-001", DeviceType: string(models.DeviceTypeSmartBinV1), Status: models.DeviceStatusActive},
+		{DeviceID: "smart-bin-100", DeviceType: string(models.DeviceTypeSmartBinIndustrial), Status: models.DeviceStatusActive},
+	} {
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	var emitted []models.DeviceEventType
+	bus := local.NewEventBus()
+	bus.Subscribe(func(_ context.Context, event *models.DeviceEvent) {
+		emitted = append(emitted, event.EventType)
+	})
+
+	monitor := NewHeartbeatMonitor(cfg, NewDeviceManager(devices), devices, bus)
+
+	for _, deviceID := range []string{"smart-bin-001", "smart-bin-100", "unknown-bin"} {
+		if err := monitor.RecordHeartbeat(ctx, deviceID); err != nil {
+			t.Fatalf("RecordHeartbeat(%s) error = %v", deviceID, err)
+		}
+	}
+	if len(emitted) != 2 || emitted[0] != models.DeviceEventOnline {
+		t.Fatalf("Emitted after heartbeats = %v, want two device_online", emitted)
+	}
+
+	// A los 10 minutos solo el v1 supera su umbral
+	changed, err := monitor.Scan(ctx, time.Now().Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("Scan() changed = %d, want 1", changed)
+	}
+	if emitted[len(emitted)-1] != models.DeviceEventOffline {
+		t.Errorf("Last event = %v, want %v", emitted[len(emitted)-1], models.DeviceEventOffline)
+	}
+
+	offline := false
+	listed, _, err := devices.List(ctx, &models.ListDevicesRequest{Online: &offline})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].DeviceID != "smart-bin-001" {
+		t.Errorf("Offline devices = %v, want [smart-bin-001]", listed)
+	}
+
+	// Un nuevo heartbeat lo devuelve a online
+	if err := monitor.RecordHeartbeat(ctx, "smart-bin-001"); err != nil {
+		t.Fatalf("RecordHeartbeat() error = %v", err)
+	}
+	if emitted[len(emitted)-1] != models.DeviceEventOnline {
+		t.Errorf("Last event = %v, want %v", emitted[len(emitted)-1], models.DeviceEventOnline)
+	}
+}
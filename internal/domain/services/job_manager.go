@@ -0,0 +1,350 @@
+// Package services contains the domain services of the Smart Bin Orchestrator.
+// Services coordinate models and ports; handlers (HTTP, MQTT) only translate transport to service calls.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// JobManager coordina el ciclo de vida de los jobs de clasificación.
+type JobManager struct {
+	config    *config.Config
+	jobs      ports.JobRepository
+	devices   ports.DeviceRepository
+	presigner ports.S3Presigner
+	publisher ports.SQSPublisher
+	iot       ports.IoTPublisher
+	metrics   ports.PipelineMetrics
+
+	// mu serializa las actualizaciones de jobs (ver modifyJob)
+	mu sync.Mutex
+}
+
+// NewJobManager crea una nueva instancia de JobManager.
+func NewJobManager(
+	cfg *config.Config,
+	jobs ports.JobRepository,
+	devices ports.DeviceRepository,
+	presigner ports.S3Presigner,
+	publisher ports.SQSPublisher,
+	iot ports.IoTPublisher,
+	metrics ports.PipelineMetrics,
+) *JobManager {
+	return &JobManager{
+		config:    cfg,
+		jobs:      jobs,
+		devices:   devices,
+		presigner: presigner,
+		publisher: publisher,
+		iot:       iot,
+		metrics:   metrics,
+	}
+}
+
+// CreateJob creates a classification job.
+// Without an image key the job stays pending and the response carries a presigned upload URL.
+// With an image key the image is already uploaded, so the job goes straight to processing and is enqueued.
+// A pre-uploaded image must be under uploads/{device_id}/ (see CreateJobRequest.Validate), and a principal
+// bound to a device only creates jobs for that device.
+// Registered devices that are not reachable (inactive, provisioning, decommissioned) get ErrDeviceInactive.
+func (m *JobManager) CreateJob(ctx context.Context, req *models.CreateJobRequest) (*models.CreateJobResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := models.CheckDeviceFromContext(ctx, req.DeviceID); err != nil {
+		return nil, err
+	}
+
+	orgID, err := m.checkDevice(ctx, req.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	jobID := generateJobID()
+
+	job := &models.Job{
+		JobID:     jobID,
+		OrgID:     orgID,
+		DeviceID:  req.DeviceID,
+		Status:    models.JobStatusPending,
+		ImageKey:  generateImageKey(req.DeviceID, jobID),
+		Metadata:  req.Metadata,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	if req.ImageKey != "" {
+		job.ImageKey = req.ImageKey
+		return m.createUploadedJob(ctx, job)
+	}
+
+	return m.createPendingJob(ctx, job)
+}
+
+// GetJob retrieves a job by ID.
+func (m *JobManager) GetJob(ctx context.Context, jobID string) (*models.Job, error) {
+	if jobID == "" {
+		return nil, models.ErrInvalidJobID
+	}
+	return m.jobs.GetByID(ctx, jobID)
+}
+
+// ListJobs returns a page of jobs matching the request filters.
+func (m *JobManager) ListJobs(ctx context.Context, req *models.ListJobsRequest) (*models.ListJobsResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	jobs, total, err := m.jobs.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListJobsResponse{
+		Jobs:   jobs,
+		Total:  total,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}, nil
+}
+
+// CompleteJob stores the classification and decision of a processing job and sends the decision to the device.
+// A delivery failure is only logged: the job stays completed and the device can still read it with GET /jobs/:id.
+func (m *JobManager) CompleteJob(
+	ctx context.Context,
+	jobID string,
+	classification *models.Classification,
+	decision *models.Decision,
+) (*models.Job, error) {
+	if err := decision.Validate(); err != nil {
+		return nil, err
+	}
+
+	job, err := m.modifyJob(ctx, jobID, func(job *models.Job) error {
+		if !job.CanTransitionTo(models.JobStatusCompleted) {
+			return models.ErrInvalidTransition
+		}
+		job.Classification = classification
+		job.Decision = decision
+		job.MarkAsCompleted()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.metrics.JobFinished(job)
+
+	if err := m.iot.PublishDecision(ctx, models.NewDecisionMessage(job)); err != nil {
+		log.Error().
+			Err(err).
+			Str("job_id", job.JobID).
+			Str("device_id", job.DeviceID).
+			Msg("Failed to deliver decision to device")
+		return job, nil
+	}
+
+	log.Info().
+		Str("job_id", job.JobID).
+		Str("device_id", job.DeviceID).
+		Str("action", decision.Action).
+		Msg("Decision delivered to device")
+
+	return job, nil
+}
+
+// ResolveReview replaces the manual_review decision of a completed job with the reviewer's decision
+// and sends it to the device. The original decision is kept in the job review.
+func (m *JobManager) ResolveReview(ctx context.Context, jobID string, req *models.ResolveReviewRequest) (*models.Job, error) {
+	job, err := m.modifyJob(ctx, jobID, func(job *models.Job) error {
+		if !job.NeedsReview() {
+			return fmt.Errorf("%w: job %s does not require manual review", models.ErrInvalidTransition, job.JobID)
+		}
+
+		decision, err := req.Decision(job.Decision)
+		if err != nil {
+			return err
+		}
+
+		job.Review = &models.JobReview{
+			OriginalDecision: job.Decision,
+			ReviewedBy:       models.ActorFromContext(ctx),
+			ReviewedAt:       time.Now(),
+			Notes:            req.Notes,
+		}
+		job.Decision = decision
+		job.UpdatedAt = job.Review.ReviewedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("job_id", job.JobID).
+		Str("action", job.Decision.Action).
+		Str("reviewed_by", job.Review.ReviewedBy).
+		Msg("Manual review resolved")
+
+	if err := m.iot.PublishDecision(ctx, models.NewDecisionMessage(job)); err != nil {
+		log.Error().
+			Err(err).
+			Str("job_id", job.JobID).
+			Str("device_id", job.DeviceID).
+			Msg("Failed to deliver reviewed decision to device")
+	}
+
+	return job, nil
+}
+
+// FailJob marks a job as failed with the given reason.
+func (m *JobManager) FailJob(ctx context.Context, jobID, reason string) (*models.Job, error) {
+	job, err := m.modifyJob(ctx, jobID, func(job *models.Job) error {
+		if !job.CanTransitionTo(models.JobStatusFailed) {
+			return models.ErrInvalidTransition
+		}
+		job.MarkAsFailed(reason)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.metrics.JobFinished(job)
+	return job, nil
+}
+
+// modifyJob carga el job, aplica modify y guarda el resultado.
+// Si modify retorna error no se guarda nada.
+func (m *JobManager) modifyJob(ctx context.Context, jobID string, modify func(job *models.Job) error) (*models.Job, error) {
+	if jobID == "" {
+		return nil, models.ErrInvalidJobID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := modify(job); err != nil {
+		return nil, err
+	}
+
+	if err := m.jobs.Update(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// createPendingJob guarda el job en pending y genera la URL de upload.
+func (m *JobManager) createPendingJob(ctx context.Context, job *models.Job) (*models.CreateJobResponse, error) {
+	expiry := m.config.AWS.S3.PresignedURLExpiry
+
+	uploadURL, err := m.presigner.GeneratePresignedPutURL(ctx, m.config.AWS.S3.BucketImages, job.ImageKey, expiry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrS3Operation, err)
+	}
+
+	if err := m.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	m.metrics.JobCreated(job)
+
+	uploadExpiresAt := time.Now().Add(expiry)
+	response := buildCreateJobResponse(job)
+	response.UploadURL = uploadURL
+	response.UploadExpiresAt = &uploadExpiresAt
+
+	return response, nil
+}
+
+// createUploadedJob guarda el job en processing y lo encola para el Classifier.
+func (m *JobManager) createUploadedJob(ctx context.Context, job *models.Job) (*models.CreateJobResponse, error) {
+	job.MarkAsProcessing()
+
+	if err := m.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	m.metrics.JobCreated(job)
+
+	if err := m.publisher.PublishClassificationJob(ctx, job); err != nil {
+		job.MarkAsFailed("failed to enqueue classification job")
+		if updateErr := m.jobs.Update(ctx, job); updateErr != nil {
+			log.Error().
+				Err(updateErr).
+				Str("job_id", job.JobID).
+				Msg("Failed to mark job as failed after enqueue error")
+		}
+		m.metrics.JobFinished(job)
+		return nil, fmt.Errorf("%w: %v", models.ErrSQSOperation, err)
+	}
+
+	log.Info().
+		Str("job_id", job.JobID).
+		Str("device_id", job.DeviceID).
+		Str("image_key", job.ImageKey).
+		Msg("Job enqueued for classification")
+
+	return buildCreateJobResponse(job), nil
+}
+
+// checkDevice rechaza jobs de dispositivos registrados que no están operativos o que pertenecen
+// a otra organización. Los jobs de dispositivos no registrados se aceptan.
+func (m *JobManager) checkDevice(ctx context.Context, deviceID string) (string, error) {
+	// Sin scope: un dispositivo de otra organización no debe pasar por "no registrado"
+	device, err := m.devices.GetByID(models.WithPrincipal(ctx, nil), deviceID)
+	if errors.Is(err, models.ErrDeviceNotFound) {
+		return models.CreationOrgID(ctx), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if scope := models.OrgScope(ctx); scope != "" && device.OrgID != scope {
+		return "", fmt.Errorf("%w: device %s belongs to another organization", models.ErrForbidden, deviceID)
+	}
+
+	if !device.IsReachable() {
+		return "", fmt.Errorf("%w: device %s is %s", models.ErrDeviceInactive, deviceID, device.Status)
+	}
+	return device.OrgID, nil
+}
+
+// buildCreateJobResponse construye el response común de creación.
+func buildCreateJobResponse(job *models.Job) *models.CreateJobResponse {
+	return &models.CreateJobResponse{
+		JobID:     job.JobID,
+		DeviceID:  job.DeviceID,
+		Status:    job.Status,
+		ImageKey:  job.ImageKey,
+		CreatedAt: job.CreatedAt,
+	}
+}
+
+// generateJobID genera un ID único para un job.
+func generateJobID() string {
+	return "job_" + uuid.New().String()[:8]
+}
+
+// generateImageKey genera la key S3 para la imagen del job.
+func generateImageKey(deviceID, jobID string) string {
+	return models.ImageKeyPrefix(deviceID) + jobID + ".jpg"
+}
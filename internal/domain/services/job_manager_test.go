@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
+)
+
+// TestCompleteJobPublishesDecision verifica que completar un job envía la decisión al dispositivo.
+func TestCompleteJobPublishesDecision(t *testing.T) {
+	ctx := context.Background()
+	iot := local.NewIoTPublisher()
+	registry := metrics.NewRegistry()
+	manager := NewJobManager(testConfig(), local.NewJobRepository(), local.NewDeviceRepository(), local.NewPresigner("us-east-1"), local.NewPublisher(), iot, registry)
+
+	created, err := manager.CreateJob(ctx, &models.CreateJobRequest{
+		DeviceID: "smart-bin-001",
+		ImageKey: "uploads/smart-bin-001/capture.jpg",
+	})
+	if err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	// Una decisión inválida no toca el job
+	if _, err := manager.CompleteJob(ctx, created.JobID, nil, &models.Decision{Action: string(models.DecisionActionAccept), Message: "Deposit"}); !errors.Is(err, models.ErrInvalidBinCompartment) {
+		t.Fatalf("CompleteJob(invalid) error = %v, want %v", err, models.ErrInvalidBinCompartment)
+	}
+
+	classification := &models.Classification{Label: "plastic", Confidence: 0.93, ModelVersion: "v1"}
+	decision := &models.Decision{
+		Action:         string(models.DecisionActionAccept),
+		BinCompartment: "recyclable",
+		Message:        "Deposit in recyclable compartment",
+	}
+	job, err := manager.CompleteJob(ctx, created.JobID, classification, decision)
+	if err != nil {
+		t.Fatalf("CompleteJob() error = %v", err)
+	}
+	if job.Status != models.JobStatusCompleted {
+		t.Errorf("Job status = %v, want %v", job.Status, models.JobStatusCompleted)
+	}
+
+	messages := iot.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Published decisions = %d, want 1", len(messages))
+	}
+	if messages[0].DeviceID != "smart-bin-001" || messages[0].JobID != created.JobID {
+		t.Errorf("Message = %+v, want device smart-bin-001 and job %s", messages[0], created.JobID)
+	}
+	if messages[0].Label != "plastic" || messages[0].Decision.BinCompartment != "recyclable" {
+		t.Errorf("Message content = %+v", messages[0])
+	}
+
+	// Un job completado no se puede volver a completar ni fallar
+	if _, err := manager.CompleteJob(ctx, created.JobID, classification, decision); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Errorf("CompleteJob(again) error = %v, want %v", err, models.ErrInvalidTransition)
+	}
+	if _, err := manager.FailJob(ctx, created.JobID, "timeout"); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Errorf("FailJob(completed) error = %v, want %v", err, models.ErrInvalidTransition)
+	}
+	if len(iot.Messages()) != 1 {
+		t.Errorf("Published decisions = %d, want 1", len(iot.Messages()))
+	}
+
+	// Solo las transiciones válidas cuentan en las métricas del pipeline
+	summary, err := registry.Summary()
+	if err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+	if summary.Jobs != (metrics.JobSummary{Created: 1, Completed: 1}) {
+		t.Errorf("Jobs metrics = %+v, want 1 created and 1 completed", summary.Jobs)
+	}
+}
+
+// TestCreateJobRejectsForeignImageKey verifica que una imagen fuera de la carpeta del dispositivo
+// no llegue a la cola del Classifier.
+func TestCreateJobRejectsForeignImageKey(t *testing.T) {
+	publisher := local.NewPublisher()
+	manager := NewJobManager(testConfig(), local.NewJobRepository(), local.NewDeviceRepository(), local.NewPresigner("us-east-1"), publisher, local.NewIoTPublisher(), metrics.NewRegistry())
+
+	_, err := manager.CreateJob(context.Background(), &models.CreateJobRequest{
+		DeviceID:  "smart-bin-001",
+		Timestamp: "2026-01-21T10:30:00Z",
+		ImageKey:  "uploads/smart-bin-002/capture.jpg",
+	})
+	if !errors.Is(err, models.ErrInvalidInput) {
+		t.Errorf("CreateJob(foreign image) error = %v, want ErrInvalidInput", err)
+	}
+	if len(publisher.Messages()) != 0 {
+		t.Errorf("Queued jobs = %d, want 0", len(publisher.Messages()))
+	}
+}
+
+// TestResolveReview verifica que el revisor reemplace la decisión manual_review y se notifique al dispositivo.
+func TestResolveReview(t *testing.T) {
+	iot := local.NewIoTPublisher()
+	jobs := local.NewJobRepository()
+	manager := NewJobManager(testConfig(), jobs, local.NewDeviceRepository(), local.NewPresigner("us-east-1"), local.NewPublisher(), iot, metrics.NewRegistry())
+	ctx := models.WithPrincipal(context.Background(), &models.Principal{
+		OrgID:        models.DefaultOrgID,
+		CredentialID: "cred_reviewer",
+		Roles:        []models.Role{models.RoleReviewer},
+	})
+
+	created, err := manager.CreateJob(ctx, &models.CreateJobRequest{
+		DeviceID: "smart-bin-001",
+		ImageKey: "uploads/smart-bin-001/capture.jpg",
+	})
+	if err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	accept := &models.ResolveReviewRequest{Action: models.DecisionActionAccept, BinCompartment: "organic", Notes: "banana peel"}
+	if _, err := manager.ResolveReview(ctx, created.JobID, accept); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Fatalf("ResolveReview(processing) error = %v, want ErrInvalidTransition", err)
+	}
+
+	original := &models.Decision{
+		Action:              string(models.DecisionActionManualReview),
+		Message:             "Low confidence - manual review required",
+		ConfidenceThreshold: 0.7,
+	}
+	if _, err := manager.CompleteJob(ctx, created.JobID, &models.Classification{Label: "organic", Confidence: 0.41}, original); err != nil {
+		t.Fatalf("CompleteJob() error = %v", err)
+	}
+
+	pending, err := manager.ListJobs(ctx, &models.ListJobsRequest{PendingReview: true})
+	if err != nil || pending.Total != 1 {
+		t.Fatalf("ListJobs(pending_review) = %+v, %v; want 1 job", pending, err)
+	}
+
+	if _, err := manager.ResolveReview(ctx, created.JobID, &models.ResolveReviewRequest{Action: models.DecisionActionManualReview}); !errors.Is(err, models.ErrInvalidAction) {
+		t.Errorf("ResolveReview(manual_review) error = %v, want ErrInvalidAction", err)
+	}
+	if _, err := manager.ResolveReview(ctx, created.JobID, &models.ResolveReviewRequest{Action: models.DecisionActionAccept}); !errors.Is(err, models.ErrInvalidBinCompartment) {
+		t.Errorf("ResolveReview(accept without compartment) error = %v, want ErrInvalidBinCompartment", err)
+	}
+
+	job, err := manager.ResolveReview(ctx, created.JobID, accept)
+	if err != nil {
+		t.Fatalf("ResolveReview() error = %v", err)
+	}
+	if job.Decision.Action != string(models.DecisionActionAccept) || job.Decision.BinCompartment != "organic" {
+		t.Errorf("Decision = %+v, want accept/organic", job.Decision)
+	}
+	if job.Review == nil || job.Review.ReviewedBy != "cred_reviewer" || job.Review.OriginalDecision.Action != original.Action {
+		t.Errorf("Review = %+v", job.Review)
+	}
+
+	// Dos decisiones publicadas: la manual_review y la revisada
+	messages := iot.Messages()
+	if len(messages) != 2 || messages[1].Decision.BinCompartment != "organic" {
+		t.Errorf("Published decisions = %+v, want reviewed decision last", messages)
+	}
+
+	if _, err := manager.ResolveReview(ctx, created.JobID, accept); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Errorf("ResolveReview(again) error = %v, want ErrInvalidTransition", err)
+	}
+	pending, _ = manager.ListJobs(ctx, &models.ListJobsRequest{PendingReview: true})
+	if pending.Total != 0 {
+		t.Errorf("ListJobs(pending_review) total = %d, want 0", pending.Total)
+	}
+}
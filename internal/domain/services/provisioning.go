@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// ProvisioningService pre-registra dispositivos desde un manifiesto y canjea sus claim tokens.
+type ProvisioningService struct {
+	config        *config.Config
+	deviceManager *DeviceManager
+	devices       ports.DeviceRepository
+	certificates  *CertificateService
+}
+
+// NewProvisioningService crea una nueva instancia de ProvisioningService.
+func NewProvisioningService(
+	cfg *config.Config,
+	deviceManager *DeviceManager,
+	devices ports.DeviceRepository,
+	certificates *CertificateService,
+) *ProvisioningService {
+	return &ProvisioningService{
+		config:        cfg,
+		deviceManager: deviceManager,
+		devices:       devices,
+		certificates:  certificates,
+	}
+}
+
+// ImportDevices creates one provisioning device per manifest row, each with a one-time claim token.
+// Rows are independent: a failed row is reported and the rest are still imported.
+func (s *ProvisioningService) ImportDevices(ctx context.Context, req *models.ImportDevicesRequest) (*models.ImportDevicesResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	response := &models.ImportDevicesResponse{
+		Total:   len(req.Devices),
+		Results: make([]*models.ImportRowResult, 0, len(req.Devices)),
+	}
+	seen := make(map[string]int, len(req.Devices))
+
+	for i := range req.Devices {
+		row := req.Devices[i]
+		row.Normalize()
+
+		result := &models.ImportRowResult{
+			Row:          i + 1,
+			DeviceID:     row.DeviceID,
+			SerialNumber: row.SerialNumber,
+			Status:       models.ImportRowFailed,
+		}
+		response.Results = append(response.Results, result)
+
+		if first, duplicated := seen[row.DeviceID]; duplicated && row.DeviceID != "" {
+			result.Error = fmt.Sprintf("duplicates row %d", first)
+			response.Failed++
+			continue
+		}
+		seen[row.DeviceID] = result.Row
+
+		token, expiresAt, err := s.provision(ctx, &row)
+		if err != nil {
+			result.Error = err.Error()
+			response.Failed++
+			continue
+		}
+
+		result.Status = models.ImportRowCreated
+		result.ClaimToken = token
+		result.ClaimTokenExpiresAt = &expiresAt
+		response.Created++
+	}
+
+	log.Info().
+		Int("total", response.Total).
+		Int("created", response.Created).
+		Int("failed", response.Failed).
+		Msg("Device manifest imported")
+
+	return response, nil
+}
+
+// ClaimDevice exchanges a claim token for the device certificate and activates the device.
+// The token is consumed: a second claim with the same token fails with ErrInvalidClaimToken.
+func (s *ProvisioningService) ClaimDevice(
+	ctx context.Context,
+	req *models.ClaimDeviceRequest,
+) (*models.Device, *models.DeviceCertificate, error) {
+	deviceID, err := models.ClaimTokenDeviceID(req.ClaimToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var certificate *models.DeviceCertificate
+	device, err := s.deviceManager.ModifyDevice(ctx, deviceID, func(device *models.Device) error {
+		if device.Status != models.DeviceStatusProvisioning || device.ClaimTokenHash == "" ||
+			subtle.ConstantTimeCompare([]byte(device.ClaimTokenHash), []byte(hashClaimToken(req.ClaimToken))) != 1 {
+			return models.ErrInvalidClaimToken
+		}
+
+		now := time.Now()
+		if device.ClaimTokenExpiresAt != nil && !now.Before(*device.ClaimTokenExpiresAt) {
+			return models.ErrClaimTokenExpired
+		}
+
+		issued, err := s.certificates.IssueCertificate(ctx, deviceID, req.CSR)
+		if err != nil {
+			return err
+		}
+		certificate = issued
+
+		certificate.ApplyTo(device)
+		device.ClaimTokenHash = ""
+		device.ClaimTokenExpiresAt = nil
+		device.ClaimedAt = &now
+		return device.TransitionStatus(models.DeviceStatusActive, "", "", now)
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrDeviceNotFound) {
+			return nil, nil, models.ErrInvalidClaimToken
+		}
+		return nil, nil, err
+	}
+
+	if err := s.certificates.TrackCertificate(ctx, certificate); err != nil {
+		return nil, nil, err
+	}
+
+	log.Info().
+		Str("device_id", deviceID).
+		Str("serial", certificate.SerialNumber).
+		Msg("Device claimed")
+
+	return device, certificate, nil
+}
+
+// provision crea el dispositivo en provisioning y retorna su claim token en claro.
+func (s *ProvisioningService) provision(ctx context.Context, row *models.ProvisionDeviceRow) (string, time.Time, error) {
+	if err := row.Validate(); err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := newClaimToken(row.DeviceID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.Provisioning.ClaimTokenTTL)
+	device := &models.Device{
+		DeviceID:            row.DeviceID,
+		OrgID:               models.CreationOrgID(ctx),
+		DeviceType:          row.DeviceType,
+		SerialNumber:        row.SerialNumber,
+		Status:              models.DeviceStatusProvisioning,
+		StatusReason:        "awaiting claim",
+		Location:            row.Location,
+		BinType:             row.BinType,
+		Capacity:            row.Capacity,
+		ClaimTokenHash:      hashClaimToken(token),
+		ClaimTokenExpiresAt: &expiresAt,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	if err := s.devices.Create(ctx, device); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// newClaimToken genera "<device_id>.<32 bytes aleatorios en base64url>".
+func newClaimToken(deviceID string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return deviceID + "." + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashClaimToken retorna el SHA-256 (hex) que se guarda en lugar del token.
+func hashClaimToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/pki"
+)
+
+// TestImportAndClaimDevices verifica el reporte por fila y que el claim token es de un solo uso.
+func TestImportAndClaimDevices(t *testing.T) {
+	ctx := context.Background()
+	authority, err := pki.NewEphemeralAuthority(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("NewEphemeralAuthority() error = %v", err)
+	}
+	cfg := testConfig()
+	cfg.Provisioning.ClaimTokenTTL = time.Hour
+
+	devices := local.NewDeviceRepository()
+	manager := NewDeviceManager(devices)
+	certificates := NewCertificateService(cfg, authority, local.NewCertificateRepository(), manager)
+	provisioning := NewProvisioningService(cfg, manager, devices, certificates)
+
+	if err := devices.Create(ctx, &models.Device{DeviceID: "smart-bin-sb2-0004", Status: models.DeviceStatusActive}); err != nil {
+		t.Fatalf("Create device: %v", err)
+	}
+
+	report, err := provisioning.ImportDevices(ctx, &models.ImportDevicesRequest{Devices: []models.ProvisionDeviceRow{
+		{SerialNumber: "SB2-0001", DeviceType: "smart_bin_v2", BinType: "recyclable"},
+		{SerialNumber: "SB2-0002", DeviceType: "toaster"},
+		{SerialNumber: "SB2-0001", DeviceType: "smart_bin_v2"},
+		{SerialNumber: "SB2-0004", DeviceType: "smart_bin_v2"},
+		{DeviceID: "lobby-bin", SerialNumber: "SB2-0005", DeviceType: "smart_bin_v1"},
+	}})
+	if err != nil {
+		t.Fatalf("ImportDevices() error = %v", err)
+	}
+
+	wantStatus := []models.ImportRowStatus{
+		models.ImportRowCreated, models.ImportRowFailed, models.ImportRowFailed, models.ImportRowFailed, models.ImportRowCreated,
+	}
+	for i, result := range report.Results {
+		if result.Status != wantStatus[i] {
+			t.Errorf("Row %d status = %s (%s), want %s", result.Row, result.Status, result.Error, wantStatus[i])
+		}
+		if (result.ClaimToken != "") != (wantStatus[i] == models.ImportRowCreated) {
+			t.Errorf("Row %d claim token present = %v", result.Row, result.ClaimToken != "")
+		}
+	}
+	if report.Created != 2 || report.Failed != 3 {
+		t.Errorf("Created/Failed = %d/%d, want 2/3", report.Created, report.Failed)
+	}
+
+	device, _ := devices.GetByID(ctx, "smart-bin-sb2-0001")
+	if device.Status != models.DeviceStatusProvisioning || device.ClaimTokenHash == "" || device.ClaimTokenHash == report.Results[0].ClaimToken {
+		t.Fatalf("Provisioned device = %s with hash %q", device.Status, device.ClaimTokenHash)
+	}
+
+	token := report.Results[0].ClaimToken
+	if _, _, err := provisioning.ClaimDevice(ctx, &models.ClaimDeviceRequest{ClaimToken: token + "x"}); !errors.Is(err, models.ErrInvalidClaimToken) {
+		t.Errorf("ClaimDevice(wrong token) error = %v, want %v", err, models.ErrInvalidClaimToken)
+	}
+
+	claimed, certificate, err := provisioning.ClaimDevice(ctx, &models.ClaimDeviceRequest{ClaimToken: token})
+	if err != nil {
+		t.Fatalf("ClaimDevice() error = %v", err)
+	}
+	if claimed.Status != models.DeviceStatusActive || claimed.ClaimedAt == nil || claimed.CertificateSerial != certificate.SerialNumber {
+		t.Errorf("Claimed device = %s, claimed_at %v, serial %s", claimed.Status, claimed.ClaimedAt, claimed.CertificateSerial)
+	}
+	if _, err := certificates.AuthenticateDevice(ctx, claimed.DeviceID, certificate.SerialNumber); err != nil {
+		t.Errorf("AuthenticateDevice(claimed) error = %v", err)
+	}
+
+	if _, _, err := provisioning.ClaimDevice(ctx, &models.ClaimDeviceRequest{ClaimToken: token}); !errors.Is(err, models.ErrInvalidClaimToken) {
+		t.Errorf("ClaimDevice(reused token) error = %v, want %v", err, models.ErrInvalidClaimToken)
+	}
+
+	// Token vencido
+	if _, err := manager.ModifyDevice(ctx, "lobby-bin", func(device *models.Device) error {
+		expired := time.Now().Add(-time.Minute)
+		device.ClaimTokenExpiresAt = &expired
+		return nil
+	}); err != nil {
+		t.Fatalf("ModifyDevice() error = %v", err)
+	}
+	if _, _, err := provisioning.ClaimDevice(ctx, &models.ClaimDeviceRequest{ClaimToken: report.Results[4].ClaimToken}); !errors.Is(err, models.ErrClaimTokenExpired) {
+		t.Errorf("ClaimDevice(expired) error = %v, want %v", err, models.ErrClaimTokenExpired)
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+)
+
+// RoutePlanner calcula rutas de recolección sobre los contenedores que necesitan vaciarse.
+//
+// Los contenedores se agrupan por edificio: primero se ordenan los edificios
+// (vecino más cercano + 2-opt sobre sus centroides) y dentro de cada edificio
+// se recorren los pisos en orden ascendente, ordenando las paradas de cada piso
+// con la misma heurística. Los contenedores sin edificio son grupos de una parada.
+type RoutePlanner struct {
+	devices ports.DeviceRepository
+}
+
+// NewRoutePlanner crea una nueva instancia de RoutePlanner.
+func NewRoutePlanner(devices ports.DeviceRepository) *RoutePlanner {
+	return &RoutePlanner{
+		devices: devices,
+	}
+}
+
+// routeCandidate es un contenedor seleccionado para la ruta.
+type routeCandidate struct {
+	device *models.Device
+	point  models.GeoPoint
+}
+
+// routeCluster agrupa los candidatos de un edificio.
+type routeCluster struct {
+	building string
+	floors   map[int][]routeCandidate
+	centroid models.GeoPoint
+}
+
+// PlanRoute selects the bins at or above the fill threshold and returns them in visit order.
+func (p *RoutePlanner) PlanRoute(ctx context.Context, req *models.PlanRouteRequest) (*models.RoutePlan, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	devices, _, err := p.devices.List(ctx, &models.ListDevicesRequest{BinType: req.BinType})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.RoutePlan{
+		Start:         *req.Start,
+		FillThreshold: *req.FillThreshold,
+		Stops:         []models.RouteStop{},
+		Groups:        []models.RouteGroup{},
+		PlannedAt:     time.Now(),
+	}
+
+	candidates := make([]routeCandidate, 0)
+	for _, device := range devices {
+		if device.Status == models.DeviceStatusDecommissioned ||
+			device.FillLevel == nil || *device.FillLevel < *req.FillThreshold {
+			continue
+		}
+		if req.Building != "" && (device.Location == nil || device.Location.Building != req.Building) {
+			continue
+		}
+
+		point, ok := device.Location.Point()
+		if !ok {
+			plan.Unlocated = append(plan.Unlocated, device.DeviceID)
+			continue
+		}
+		candidates = append(candidates, routeCandidate{device: device, point: point})
+	}
+	sort.Strings(plan.Unlocated)
+
+	position := *req.Start
+	for _, cluster := range orderClusters(*req.Start, clusterByBuilding(candidates)) {
+		floors := make([]int, 0, len(cluster.floors))
+		for floor := range cluster.floors {
+			floors = append(floors, floor)
+		}
+		sort.Ints(floors)
+
+		for _, floor := range floors {
+			stops := cluster.floors[floor]
+			points := make([]models.GeoPoint, len(stops))
+			for i, stop := range stops {
+				points[i] = stop.point
+			}
+
+			group := models.RouteGroup{Building: cluster.building, Floor: floor}
+			for _, index := range planPath(position, points) {
+				appendStop(plan, stops[index], position)
+				position = stops[index].point
+				group.DeviceIDs = append(group.DeviceIDs, stops[index].device.DeviceID)
+			}
+			plan.Groups = append(plan.Groups, group)
+		}
+	}
+
+	return plan, nil
+}
+
+// clusterByBuilding agrupa los candidatos por edificio y piso.
+func clusterByBuilding(candidates []routeCandidate) []*routeCluster {
+	clusters := make([]*routeCluster, 0)
+	byBuilding := make(map[string]*routeCluster)
+
+	for _, candidate := range candidates {
+		location := candidate.device.Location
+
+		cluster, ok := byBuilding[location.Building]
+		if !ok || location.Building == "" {
+			cluster = &routeCluster{building: location.Building, floors: make(map[int][]routeCandidate)}
+			clusters = append(clusters, cluster)
+			if location.Building != "" {
+				byBuilding[location.Building] = cluster
+			}
+		}
+		cluster.floors[location.Floor] = append(cluster.floors[location.Floor], candidate)
+	}
+
+	for _, cluster := range clusters {
+		var lat, lng float64
+		count := 0
+		for _, stops := range cluster.floors {
+			for _, stop := range stops {
+				lat += stop.point.Latitude
+				lng += stop.point.Longitude
+				count++
+			}
+		}
+		cluster.centroid = models.GeoPoint{Latitude: lat / float64(count), Longitude: lng / float64(count)}
+	}
+
+	// Orden determinístico antes de aplicar la heurística
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].building < clusters[j].building
+	})
+
+	return clusters
+}
+
+// orderClusters ordena los edificios según la ruta sobre sus centroides.
+func orderClusters(start models.GeoPoint, clusters []*routeCluster) []*routeCluster {
+	points := make([]models.GeoPoint, len(clusters))
+	for i, cluster := range clusters {
+		points[i] = cluster.centroid
+	}
+
+	ordered := make([]*routeCluster, 0, len(clusters))
+	for _, index := range planPath(start, points) {
+		ordered = append(ordered, clusters[index])
+	}
+	return ordered
+}
+
+// appendStop agrega una parada al plan y acumula la distancia desde from.
+func appendStop(plan *models.RoutePlan, candidate routeCandidate, from models.GeoPoint) {
+	distance := from.DistanceTo(candidate.point)
+	plan.TotalDistanceMeters += distance
+
+	device := candidate.device
+	plan.Stops = append(plan.Stops, models.RouteStop{
+		Order:                      len(plan.Stops) + 1,
+		DeviceID:                   device.DeviceID,
+		BinType:                    device.BinType,
+		FillLevel:                  *device.FillLevel,
+		Building:                   device.Location.Building,
+		Floor:                      device.Location.Floor,
+		Area:                       device.Location.Area,
+		Point:                      candidate.point,
+		DistanceFromPreviousMeters: distance,
+		CumulativeDistanceMeters:   plan.TotalDistanceMeters,
+	})
+}
+
+// planPath retorna el orden de visita de points partiendo de start (ruta abierta, sin regreso).
+// Construye la ruta con vecino más cercano y la mejora con 2-opt.
+func planPath(start models.GeoPoint, points []models.GeoPoint) []int {
+	if len(points) == 0 {
+		return nil
+	}
+
+	// Vecino más cercano
+	order := make([]int, 0, len(points))
+	visited := make([]bool, len(points))
+	current := start
+	for range points {
+		next := -1
+		best := 0.0
+		for i, point := range points {
+			if visited[i] {
+				continue
+			}
+			if distance := current.DistanceTo(point); next < 0 || distance < best {
+				next, best = i, distance
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		current = points[next]
+	}
+
+	// 2-opt: invierte tramos mientras acorten la ruta
+	at := func(position int) models.GeoPoint {
+		if position < 0 {
+			return start
+		}
+		return points[order[position]]
+	}
+
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(order)-1; i++ {
+			for k := i + 1; k < len(order); k++ {
+				// Tramo [i, k]: se reemplazan las aristas (i-1, i) y (k, k+1)
+				before := at(i - 1).DistanceTo(at(i))
+				after := at(i - 1).DistanceTo(at(k))
+				if k+1 < len(order) {
+					before += at(k).DistanceTo(at(k + 1))
+					after += at(i).DistanceTo(at(k + 1))
+				}
+
+				if after < before-1e-6 {
+					for left, right := i, k; left < right; left, right = left+1, right-1 {
+						order[left], order[right] = order[right], order[left]
+					}
+					improved = true
+				}
+			}
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestPlanPathTwoOpt verifica que 2-opt mejora la ruta del vecino más cercano.
+func TestPlanPathTwoOpt(t *testing.T) {
+	start := models.GeoPoint{Latitude: 0, Longitude: 0}
+	// El vecino más cercano visita 2 → 0 → 3 → 1 (≈18.2 unidades);
+	// la ruta óptima es 1 → 3 → 2 → 0 (≈16.5 unidades).
+	points := []models.GeoPoint{
+		{Latitude: -0.002, Longitude: 0.004},
+		{Latitude: 0.003, Longitude: -0.003},
+		{Latitude: 0, Longitude: 0.004},
+		{Latitude: 0.002, Longitude: 0.005},
+	}
+
+	order := planPath(start, points)
+
+	want := []int{1, 3, 2, 0}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("planPath() = %v, want %v", order, want)
+		}
+	}
+}
+
+// TestRoutePlannerGroupsByBuilding verifica la selección por umbral y el agrupamiento por edificio/piso.
+func TestRoutePlannerGroupsByBuilding(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+
+	add := func(deviceID string, fill int, location *models.Location) {
+		level := fill
+		device := &models.Device{DeviceID: deviceID, Status: models.DeviceStatusActive, FillLevel: &level, Location: location}
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	add("a-2", 90, &models.Location{Building: "A", Floor: 2, Latitude: 4.6001, Longitude: -74.0801})
+	add("a-1", 85, &models.Location{Building: "A", Floor: 1, Latitude: 4.6001, Longitude: -74.0801})
+	add("b-1", 95, &models.Location{Building: "B", Floor: 1, Latitude: 4.6050, Longitude: -74.0850})
+	add("a-low", 40, &models.Location{Building: "A", Floor: 1, Latitude: 4.6001, Longitude: -74.0801})
+	add("no-gps", 99, &models.Location{Building: "C"})
+
+	plan, err := NewRoutePlanner(devices).PlanRoute(ctx, &models.PlanRouteRequest{
+		Start: &models.GeoPoint{Latitude: 4.6000, Longitude: -74.0800},
+	})
+	if err != nil {
+		t.Fatalf("PlanRoute() error = %v", err)
+	}
+
+	want := []string{"a-1", "a-2", "b-1"}
+	if len(plan.Stops) != len(want) {
+		t.Fatalf("Stops = %d, want %d", len(plan.Stops), len(want))
+	}
+	for i, deviceID := range want {
+		if plan.Stops[i].DeviceID != deviceID || plan.Stops[i].Order != i+1 {
+			t.Errorf("Stops[%d] = %s (order %d), want %s", i, plan.Stops[i].DeviceID, plan.Stops[i].Order, deviceID)
+		}
+	}
+
+	if len(plan.Groups) != 3 {
+		t.Errorf("Groups = %d, want 3 (A/1, A/2, B/1)", len(plan.Groups))
+	}
+	if len(plan.Unlocated) != 1 || plan.Unlocated[0] != "no-gps" {
+		t.Errorf("Unlocated = %v, want [no-gps]", plan.Unlocated)
+	}
+
+	last := plan.Stops[len(plan.Stops)-1]
+	if last.CumulativeDistanceMeters != plan.TotalDistanceMeters || plan.TotalDistanceMeters <= 0 {
+		t.Errorf("Total distance = %v, last cumulative = %v", plan.TotalDistanceMeters, last.CumulativeDistanceMeters)
+	}
+
+	threshold := 101
+	if _, err := NewRoutePlanner(devices).PlanRoute(ctx, &models.PlanRouteRequest{
+		Start:         &models.GeoPoint{Latitude: 4.6, Longitude: -74.08},
+		FillThreshold: &threshold,
+	}); err == nil {
+		t.Error("Expected error for fill_threshold above 100")
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// ShadowService mantiene el shadow (desired, reported, delta) de cada dispositivo
+// y envía el delta al dispositivo cuando está conectado.
+type ShadowService struct {
+	shadows ports.ShadowRepository
+	devices ports.DeviceRepository
+	iot     ports.IoTPublisher
+
+	// mu serializa las actualizaciones de shadows (ver modifyShadow)
+	mu sync.Mutex
+}
+
+// NewShadowService crea una nueva instancia de ShadowService.
+func NewShadowService(shadows ports.ShadowRepository, devices ports.DeviceRepository, iot ports.IoTPublisher) *ShadowService {
+	return &ShadowService{
+		shadows: shadows,
+		devices: devices,
+		iot:     iot,
+	}
+}
+
+// GetShadow returns the shadow of a device. A device that never had state gets an empty shadow (version 0).
+func (s *ShadowService) GetShadow(ctx context.Context, deviceID string) (*models.DeviceShadow, error) {
+	if _, err := s.devices.GetByID(ctx, deviceID); err != nil {
+		return nil, err
+	}
+
+	shadow, err := s.shadows.GetByDeviceID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if shadow == nil {
+		shadow = models.NewDeviceShadow(deviceID)
+	}
+	return shadow, nil
+}
+
+// UpdateDesired merges the request into the desired state.
+// With req.Version set the update is rejected with ErrShadowVersionConflict if the shadow changed meanwhile.
+// If the device is online and something is left to apply, the delta is pushed right away.
+func (s *ShadowService) UpdateDesired(ctx context.Context, deviceID string, req *models.UpdateShadowRequest) (*models.DeviceShadow, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	device, err := s.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	shadow, changed, err := s.modifyShadow(ctx, deviceID, func(shadow *models.DeviceShadow) (bool, error) {
+		if req.Version != nil && *req.Version != shadow.Version {
+			return false, models.ErrShadowVersionConflict
+		}
+		return shadow.UpdateDesired(req.Desired, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if changed && device.Online && shadow.HasDelta() {
+		s.pushDelta(ctx, shadow)
+	}
+
+	return shadow, nil
+}
+
+// UpdateReported merges the state reported by the device (device_status events).
+func (s *ShadowService) UpdateReported(ctx context.Context, deviceID string, reported map[string]interface{}) (*models.DeviceShadow, error) {
+	if len(reported) == 0 {
+		return s.GetShadow(ctx, deviceID)
+	}
+
+	shadow, _, err := s.modifyShadow(ctx, deviceID, func(shadow *models.DeviceShadow) (bool, error) {
+		return shadow.UpdateReported(reported, time.Now())
+	})
+	return shadow, err
+}
+
+// HandleConnectivityEvent pushes the pending delta when a device comes back online.
+// It is subscribed to the orchestrator events (device_online, device_offline).
+func (s *ShadowService) HandleConnectivityEvent(ctx context.Context, event *models.DeviceEvent) {
+	if event.EventType != models.DeviceEventOnline {
+		return
+	}
+
+	shadow, err := s.shadows.GetByDeviceID(ctx, event.DeviceID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", event.DeviceID).
+			Msg("Failed to load shadow on reconnect")
+		return
+	}
+
+	if shadow != nil && shadow.HasDelta() {
+		s.pushDelta(ctx, shadow)
+	}
+}
+
+// modifyShadow carga (o crea) el shadow, aplica modify y guarda si hubo cambios.
+func (s *ShadowService) modifyShadow(
+	ctx context.Context,
+	deviceID string,
+	modify func(shadow *models.DeviceShadow) (bool, error),
+) (*models.DeviceShadow, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shadow, err := s.shadows.GetByDeviceID(ctx, deviceID)
+	if err != nil {
+		return nil, false, err
+	}
+	if shadow == nil {
+		shadow = models.NewDeviceShadow(deviceID)
+	}
+
+	changed, err := modify(shadow)
+	if err != nil {
+		return nil, false, err
+	}
+	if !changed {
+		return shadow, false, nil
+	}
+
+	if err := s.shadows.Save(ctx, shadow); err != nil {
+		return nil, false, err
+	}
+	return shadow, true, nil
+}
+
+// pushDelta envía el delta al dispositivo. Un fallo solo se registra: se reintenta en la próxima reconexión.
+func (s *ShadowService) pushDelta(ctx context.Context, shadow *models.DeviceShadow) {
+	if err := s.iot.PublishShadowDelta(ctx, models.NewShadowDeltaMessage(shadow)); err != nil {
+		log.Error().
+			Err(err).
+			Str("device_id", shadow.DeviceID).
+			Int64("version", shadow.Version).
+			Msg("Failed to push shadow delta")
+		return
+	}
+
+	log.Info().
+		Str("device_id", shadow.DeviceID).
+		Int64("version", shadow.Version).
+		Int("keys", len(shadow.Delta)).
+		Msg("Shadow delta pushed to device")
+}
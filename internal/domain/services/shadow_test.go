@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestShadowDeltaDelivery verifica cuándo se envía el delta al dispositivo.
+func TestShadowDeltaDelivery(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	if err := devices.Create(ctx, &models.Device{
+		DeviceID:   "smart-bin-001",
+		DeviceType: string(models.DeviceTypeSmartBinV1),
+		Status:     models.DeviceStatusActive,
+	}); err != nil {
+		t.Fatalf("Create device: %v", err)
+	}
+
+	iot := local.NewIoTPublisher()
+	bus := local.NewEventBus()
+	shadows := NewShadowService(local.NewShadowRepository(), devices, iot)
+	bus.Subscribe(shadows.HandleConnectivityEvent)
+	heartbeat := NewHeartbeatMonitor(testConfig(), NewDeviceManager(devices), devices, bus)
+
+	if _, err := shadows.UpdateDesired(ctx, "unknown-bin", &models.UpdateShadowRequest{
+		Desired: map[string]interface{}{"capture_interval_s": 30},
+	}); !errors.Is(err, models.ErrDeviceNotFound) {
+		t.Fatalf("UpdateDesired(unknown) error = %v, want %v", err, models.ErrDeviceNotFound)
+	}
+
+	// Offline: se guarda pero no se envía
+	shadow, err := shadows.UpdateDesired(ctx, "smart-bin-001", &models.UpdateShadowRequest{
+		Desired: map[string]interface{}{"capture_interval_s": 30},
+	})
+	if err != nil {
+		t.Fatalf("UpdateDesired() error = %v", err)
+	}
+	if len(iot.Deltas()) != 0 {
+		t.Fatalf("Deltas sent while offline = %d, want 0", len(iot.Deltas()))
+	}
+
+	stale := shadow.Version - 1
+	if _, err := shadows.UpdateDesired(ctx, "smart-bin-001", &models.UpdateShadowRequest{
+		Desired: map[string]interface{}{"capture_interval_s": 60},
+		Version: &stale,
+	}); !errors.Is(err, models.ErrShadowVersionConflict) {
+		t.Errorf("UpdateDesired(stale) error = %v, want %v", err, models.ErrShadowVersionConflict)
+	}
+
+	// Al reconectar se envía el delta pendiente
+	if err := heartbeat.RecordHeartbeat(ctx, "smart-bin-001"); err != nil {
+		t.Fatalf("RecordHeartbeat() error = %v", err)
+	}
+	deltas := iot.Deltas()
+	if len(deltas) != 1 || deltas[0].Delta["capture_interval_s"] != 30.0 {
+		t.Fatalf("Deltas after reconnect = %+v, want capture_interval_s=30", deltas)
+	}
+
+	// Online: el cambio se envía de inmediato
+	if _, err := shadows.UpdateDesired(ctx, "smart-bin-001", &models.UpdateShadowRequest{
+		Desired: map[string]interface{}{"led": map[string]interface{}{"brightness": 80}},
+	}); err != nil {
+		t.Fatalf("UpdateDesired(online) error = %v", err)
+	}
+	if len(iot.Deltas()) != 2 {
+		t.Errorf("Deltas = %d, want 2", len(iot.Deltas()))
+	}
+
+	// Cuando el dispositivo reporta el estado aplicado el delta desaparece
+	shadow, err = shadows.UpdateReported(ctx, "smart-bin-001", map[string]interface{}{
+		"capture_interval_s": 30,
+		"led":                map[string]interface{}{"brightness": 80},
+	})
+	if err != nil {
+		t.Fatalf("UpdateReported() error = %v", err)
+	}
+	if shadow.HasDelta() {
+		t.Errorf("Delta = %v, want empty", shadow.Delta)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+)
+
+// defaultStatsRange - Rango de jobs agregado cuando no se envía from.
+const defaultStatsRange = 7 * 24 * time.Hour
+
+// StatsService agrega dispositivos y jobs a lo largo de la jerarquía de ubicaciones.
+type StatsService struct {
+	devices ports.DeviceRepository
+	jobs    ports.JobRepository
+	groups  ports.GroupRepository
+}
+
+// NewStatsService crea una nueva instancia de StatsService.
+func NewStatsService(devices ports.DeviceRepository, jobs ports.JobRepository, groups ports.GroupRepository) *StatsService {
+	return &StatsService{
+		devices: devices,
+		jobs:    jobs,
+		groups:  groups,
+	}
+}
+
+// LocationStats returns per-node aggregates for one level of the location hierarchy.
+// Jobs are attributed to the current location of their device.
+func (s *StatsService) LocationStats(ctx context.Context, req *models.LocationStatsRequest) (*models.LocationStatsResponse, error) {
+	if req.Level == "" {
+		req.Level = req.DefaultLevel()
+	}
+	if !req.Level.IsValid() {
+		return nil, fmt.Errorf("%w: level %s", models.ErrInvalidInput, req.Level)
+	}
+
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if req.From.IsZero() {
+		req.From = req.To.Add(-defaultStatsRange)
+	}
+	if !req.From.Before(req.To) {
+		return nil, fmt.Errorf("%w: from must be before to", models.ErrInvalidTimeRange)
+	}
+
+	var group *models.DeviceGroup
+	if req.GroupID != "" {
+		var err error
+		if group, err = s.groups.GetByID(ctx, req.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	devices, _, err := s.devices.List(ctx, &models.ListDevicesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*models.LocationStats)
+	nodeOf := make(map[string]*models.LocationStats, len(devices))
+	totals := &models.LocationStats{}
+
+	for _, device := range devices {
+		if !req.Matches(device.Location) || (group != nil && !group.Contains(device)) {
+			continue
+		}
+
+		key := device.Location.LocationKey(req.Level)
+		node, ok := nodes[key]
+		if !ok {
+			node = &models.LocationStats{Key: key}
+			nodes[key] = node
+		}
+
+		node.AddDevice(device)
+		totals.AddDevice(device)
+		nodeOf[device.DeviceID] = node
+	}
+
+	jobs, _, err := s.jobs.List(ctx, &models.ListJobsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		node, ok := nodeOf[job.DeviceID]
+		if !ok || job.CreatedAt.Before(req.From) || !job.CreatedAt.Before(req.To) {
+			continue
+		}
+		node.AddJob(job)
+		totals.AddJob(job)
+	}
+
+	response := &models.LocationStatsResponse{
+		Level:  req.Level,
+		Path:   statsPath(req),
+		From:   req.From,
+		To:     req.To,
+		Nodes:  make([]*models.LocationStats, 0, len(nodes)),
+		Totals: totals,
+	}
+
+	for _, node := range nodes {
+		node.Finalize()
+		response.Nodes = append(response.Nodes, node)
+	}
+	totals.Finalize()
+
+	sort.Slice(response.Nodes, func(i, j int) bool {
+		return lessLocationKey(req.Level, response.Nodes[i].Key, response.Nodes[j].Key)
+	})
+
+	return response, nil
+}
+
+// statsPath retorna los filtros de la jerarquía aplicados en la consulta.
+func statsPath(req *models.LocationStatsRequest) map[models.LocationLevel]string {
+	path := make(map[models.LocationLevel]string)
+	if req.Building != "" {
+		path[models.LocationLevelBuilding] = req.Building
+	}
+	if req.Floor != nil {
+		path[models.LocationLevelFloor] = strconv.Itoa(*req.Floor)
+	}
+	if req.Area != "" {
+		path[models.LocationLevelArea] = req.Area
+	}
+	if req.Zone != "" {
+		path[models.LocationLevelZone] = req.Zone
+	}
+	return path
+}
+
+// lessLocationKey ordena los pisos numéricamente y el resto alfabéticamente.
+func lessLocationKey(level models.LocationLevel, a, b string) bool {
+	if level == models.LocationLevelFloor {
+		floorA, errA := strconv.Atoi(a)
+		floorB, errB := strconv.Atoi(b)
+		if errA == nil && errB == nil {
+			return floorA < floorB
+		}
+	}
+	return a < b
+}
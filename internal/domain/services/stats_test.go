@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestLocationStatsDrillDown verifica los agregados por edificio y el drill-down a pisos.
+func TestLocationStatsDrillDown(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	jobs := local.NewJobRepository()
+	now := time.Now()
+
+	fill := func(level int) *int { return &level }
+	for _, device := range []*models.Device{
+		{DeviceID: "a-1", Online: true, FillLevel: fill(40), Location: &models.Location{Building: "A", Floor: 1, Area: "Cafeteria"}},
+		{DeviceID: "a-2", Online: false, FillLevel: fill(80), Location: &models.Location{Building: "A", Floor: 2}},
+		{DeviceID: "a-10", Online: true, Location: &models.Location{Building: "A", Floor: 10}},
+		{DeviceID: "b-1", Online: true, FillLevel: fill(10), Location: &models.Location{Building: "B", Floor: 1}},
+	} {
+		if err := devices.Create(ctx, device); err != nil {
+			t.Fatalf("Create device: %v", err)
+		}
+	}
+
+	for i, job := range []struct {
+		deviceID string
+		action   models.DecisionAction
+		age      time.Duration
+	}{
+		{"a-1", models.DecisionActionAccept, time.Hour},
+		{"a-1", models.DecisionActionReject, time.Hour},
+		{"a-2", models.DecisionActionAccept, time.Hour},
+		{"a-2", "", time.Hour},                                    // sin decisión: cuenta como job pero no en las tasas
+		{"a-1", models.DecisionActionAccept, 30 * 24 * time.Hour}, // fuera del rango por defecto
+		{"b-1", models.DecisionActionReject, time.Hour},
+	} {
+		stored := &models.Job{
+			JobID:     "job-" + string(rune('a'+i)),
+			DeviceID:  job.deviceID,
+			Status:    models.JobStatusCompleted,
+			CreatedAt: now.Add(-job.age),
+		}
+		if job.action != "" {
+			stored.Decision = &models.Decision{Action: string(job.action)}
+		}
+		if err := jobs.Create(ctx, stored); err != nil {
+			t.Fatalf("Create job: %v", err)
+		}
+	}
+
+	service := NewStatsService(devices, jobs, local.NewGroupRepository())
+
+	buildings, err := service.LocationStats(ctx, &models.LocationStatsRequest{})
+	if err != nil {
+		t.Fatalf("LocationStats() error = %v", err)
+	}
+	if buildings.Level != models.LocationLevelBuilding || len(buildings.Nodes) != 2 {
+		t.Fatalf("Level = %v with %d nodes, want building with 2", buildings.Level, len(buildings.Nodes))
+	}
+
+	a := buildings.Nodes[0]
+	if a.Key != "A" || a.Devices != 3 || a.OnlineDevices != 2 || a.Jobs != 4 {
+		t.Errorf("Building A = %+v", a)
+	}
+	if a.AcceptanceRate != 2.0/3 || a.RejectionRate != 1.0/3 {
+		t.Errorf("Building A rates = %v/%v, want 0.67/0.33", a.AcceptanceRate, a.RejectionRate)
+	}
+	if a.AverageFillLevel == nil || *a.AverageFillLevel != 60 {
+		t.Errorf("Building A average fill = %v, want 60", a.AverageFillLevel)
+	}
+	if buildings.Totals.Devices != 4 || buildings.Totals.Jobs != 5 {
+		t.Errorf("Totals = %+v", buildings.Totals)
+	}
+
+	floors, err := service.LocationStats(ctx, &models.LocationStatsRequest{Building: "A"})
+	if err != nil {
+		t.Fatalf("LocationStats(building=A) error = %v", err)
+	}
+	if floors.Level != models.LocationLevelFloor {
+		t.Errorf("Drill-down level = %v, want floor", floors.Level)
+	}
+	want := []string{"1", "2", "10"}
+	if len(floors.Nodes) != len(want) {
+		t.Fatalf("Floors = %d, want %d", len(floors.Nodes), len(want))
+	}
+	for i, key := range want {
+		if floors.Nodes[i].Key != key {
+			t.Errorf("Floors[%d] = %s, want %s", i, floors.Nodes[i].Key, key)
+		}
+	}
+
+	if _, err := service.LocationStats(ctx, &models.LocationStatsRequest{Level: "campus"}); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
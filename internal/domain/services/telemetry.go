@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultTelemetryRange - Rango consultado cuando no se envía from.
+	defaultTelemetryRange = 24 * time.Hour
+
+	// maxTelemetryPoints - Máximo de puntos que puede retornar una consulta.
+	maxTelemetryPoints = 5000
+)
+
+// autoSteps son los steps candidatos cuando la consulta no define uno.
+var autoSteps = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// TelemetryService guarda y consulta el histórico de telemetría de los dispositivos.
+type TelemetryService struct {
+	config    *config.Config
+	telemetry ports.TelemetryRepository
+	devices   ports.DeviceRepository
+}
+
+// NewTelemetryService crea una nueva instancia de TelemetryService.
+func NewTelemetryService(
+	cfg *config.Config,
+	telemetry ports.TelemetryRepository,
+	devices ports.DeviceRepository,
+) *TelemetryService {
+	return &TelemetryService{
+		config:    cfg,
+		telemetry: telemetry,
+		devices:   devices,
+	}
+}
+
+// Record appends every metric present in the reading to the device history.
+func (s *TelemetryService) Record(ctx context.Context, deviceID string, telemetry *models.DeviceTelemetry) error {
+	for _, reading := range telemetry.Readings(deviceID) {
+		if err := s.telemetry.Append(ctx, &reading); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns a metric series downsampled to the requested step.
+// Rollups are used whenever their bucket size divides the step; otherwise raw readings are aggregated.
+func (s *TelemetryService) Query(ctx context.Context, query *models.TelemetryQuery) (*models.TelemetrySeries, error) {
+	if !query.Metric.IsValid() {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidMetric, query.Metric)
+	}
+
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultTelemetryRange)
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: from must be before to", models.ErrInvalidTimeRange)
+	}
+
+	window := query.To.Sub(query.From)
+	if query.Step == 0 {
+		query.Step = chooseStep(window)
+	}
+	if query.Step < 0 || window/query.Step > maxTelemetryPoints {
+		return nil, fmt.Errorf("%w: step too small for range", models.ErrInvalidTimeRange)
+	}
+
+	if _, err := s.devices.GetByID(ctx, query.DeviceID); err != nil {
+		return nil, err
+	}
+
+	resolution := chooseResolution(query.Step)
+
+	points, err := s.telemetry.Query(ctx, query.DeviceID, query.Metric, resolution, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TelemetrySeries{
+		DeviceID:   query.DeviceID,
+		Metric:     query.Metric,
+		From:       query.From,
+		To:         query.To,
+		Step:       query.Step.String(),
+		Resolution: resolution,
+		Points:     downsample(points, query.Step),
+	}, nil
+}
+
+// ApplyRetention deletes raw readings and rollups older than their configured retention.
+func (s *TelemetryService) ApplyRetention(ctx context.Context, now time.Time) error {
+	policies := []struct {
+		resolution models.TelemetryResolution
+		retention  time.Duration
+	}{
+		{models.ResolutionRaw, s.config.Telemetry.RawRetention},
+		{models.ResolutionHourly, s.config.Telemetry.HourlyRetention},
+		{models.ResolutionDaily, s.config.Telemetry.DailyRetention},
+	}
+
+	for _, policy := range policies {
+		if policy.retention <= 0 {
+			continue
+		}
+
+		deleted, err := s.telemetry.DeleteBefore(ctx, policy.resolution, now.Add(-policy.retention))
+		if err != nil {
+			return err
+		}
+
+		if deleted > 0 {
+			log.Info().
+				Str("resolution", string(policy.resolution)).
+				Int("deleted", deleted).
+				Dur("retention", policy.retention).
+				Msg("Telemetry retention applied")
+		}
+	}
+
+	return nil
+}
+
+// StartRetention applies the retention policy every RetentionInterval until ctx is canceled.
+func (s *TelemetryService) StartRetention(ctx context.Context) {
+	interval := s.config.Telemetry.RetentionInterval
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := s.ApplyRetention(ctx, now); err != nil {
+					log.Error().Err(err).Msg("Failed to apply telemetry retention")
+				}
+			}
+		}
+	}()
+}
+
+// chooseStep elige el step más pequeño que no exceda 500 puntos en el rango.
+func chooseStep(window time.Duration) time.Duration {
+	for _, step := range autoSteps {
+		if window/step <= 500 {
+			return step
+		}
+	}
+	return autoSteps[len(autoSteps)-1]
+}
+
+// chooseResolution elige la resolución más gruesa cuyo bucket divide al step.
+func chooseResolution(step time.Duration) models.TelemetryResolution {
+	for _, resolution := range []models.TelemetryResolution{models.ResolutionDaily, models.ResolutionHourly} {
+		if step%resolution.BucketSize() == 0 {
+			return resolution
+		}
+	}
+	return models.ResolutionRaw
+}
+
+// downsample agrupa los puntos (ordenados por tiempo) en buckets de tamaño step alineados a UTC.
+func downsample(points []models.TelemetryPoint, step time.Duration) []models.TelemetryPoint {
+	result := make([]models.TelemetryPoint, 0)
+
+	for _, point := range points {
+		start := point.Timestamp.UTC().Truncate(step)
+
+		last := len(result) - 1
+		if last < 0 || !result[last].Timestamp.Equal(start) {
+			result = append(result, models.TelemetryPoint{Timestamp: start})
+			last++
+		}
+		result[last].Merge(point)
+	}
+
+	return result
+}
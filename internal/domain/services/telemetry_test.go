@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+)
+
+// TestTelemetryQueryDownsampling verifica los agregados min/avg/max por step.
+func TestTelemetryQueryDownsampling(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	if err := devices.Create(ctx, &models.Device{DeviceID: "smart-bin-001", DeviceType: "smart_bin_v1", Status: models.DeviceStatusActive}); err != nil {
+		t.Fatalf("Create device: %v", err)
+	}
+	service := NewTelemetryService(testConfig(), local.NewTelemetryRepository(), devices)
+
+	base := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	readings := []struct {
+		offset time.Duration
+		fill   int
+	}{
+		{10 * time.Minute, 20},
+		{40 * time.Minute, 40},
+		{70 * time.Minute, 60},
+		{26 * time.Hour, 90},
+	}
+	for _, r := range readings {
+		fill := r.fill
+		if err := service.Record(ctx, "smart-bin-001", &models.DeviceTelemetry{FillLevel: &fill, ReportedAt: base.Add(r.offset)}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		step           time.Duration
+		wantResolution models.TelemetryResolution
+		wantFirst      models.TelemetryPoint
+		wantPoints     int
+	}{
+		{
+			name:           "Raw readings into 30 minute buckets",
+			step:           30 * time.Minute,
+			wantResolution: models.ResolutionRaw,
+			wantFirst:      models.TelemetryPoint{Min: 20, Avg: 20, Max: 20, Count: 1},
+			wantPoints:     4,
+		},
+		{
+			name:           "Hourly rollups",
+			step:           time.Hour,
+			wantResolution: models.ResolutionHourly,
+			wantFirst:      models.TelemetryPoint{Min: 20, Avg: 30, Max: 40, Count: 2},
+			wantPoints:     3,
+		},
+		{
+			name:           "Daily rollups",
+			step:           24 * time.Hour,
+			wantResolution: models.ResolutionDaily,
+			wantFirst:      models.TelemetryPoint{Min: 20, Avg: 40, Max: 60, Count: 3},
+			wantPoints:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := service.Query(ctx, &models.TelemetryQuery{
+				DeviceID: "smart-bin-001",
+				Metric:   models.MetricFillLevel,
+				From:     base.Add(-24 * time.Hour),
+				To:       base.Add(48 * time.Hour),
+				Step:     tt.step,
+			})
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if series.Resolution != tt.wantResolution {
+				t.Errorf("Resolution = %v, want %v", series.Resolution, tt.wantResolution)
+			}
+			if len(series.Points) != tt.wantPoints {
+				t.Fatalf("Points = %d, want %d", len(series.Points), tt.wantPoints)
+			}
+			first := series.Points[0]
+			if first.Min != tt.wantFirst.Min || first.Avg != tt.wantFirst.Avg || first.Max != tt.wantFirst.Max || first.Count != tt.wantFirst.Count {
+				t.Errorf("First point = %+v, want %+v", first, tt.wantFirst)
+			}
+		})
+	}
+
+	// La retención elimina las lecturas crudas pero conserva los agregados
+	if err := service.ApplyRetention(ctx, base.Add(8*24*time.Hour)); err != nil {
+		t.Fatalf("ApplyRetention() error = %v", err)
+	}
+	for step, want := range map[time.Duration]int{30 * time.Minute: 1, time.Hour: 3} {
+		series, err := service.Query(ctx, &models.TelemetryQuery{
+			DeviceID: "smart-bin-001",
+			Metric:   models.MetricFillLevel,
+			From:     base.Add(-24 * time.Hour),
+			To:       base.Add(48 * time.Hour),
+			Step:     step,
+		})
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if len(series.Points) != want {
+			t.Errorf("Points after retention with step %v = %d, want %d", step, len(series.Points), want)
+		}
+	}
+}
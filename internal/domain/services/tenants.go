@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// TenantService gestiona las organizaciones, sus credenciales de API y la autenticación de peticiones.
+type TenantService struct {
+	config      *config.Config
+	orgs        ports.OrganizationRepository
+	credentials ports.CredentialRepository
+	devices     ports.DeviceRepository
+	alerts      *AlertService
+
+	// mu serializa las actualizaciones de organizaciones y credenciales (read-modify-write)
+	mu sync.Mutex
+}
+
+// NewTenantService crea una nueva instancia de TenantService.
+func NewTenantService(
+	cfg *config.Config,
+	orgs ports.OrganizationRepository,
+	credentials ports.CredentialRepository,
+	devices ports.DeviceRepository,
+	alerts *AlertService,
+) *TenantService {
+	return &TenantService{
+		config:      cfg,
+		orgs:        orgs,
+		credentials: credentials,
+		devices:     devices,
+		alerts:      alerts,
+	}
+}
+
+// EnsureOrganization creates the organization if it does not exist yet (used at startup for DefaultOrgID).
+func (s *TenantService) EnsureOrganization(ctx context.Context, orgID, name string) error {
+	_, err := s.orgs.GetByID(ctx, orgID)
+	if !errors.Is(err, models.ErrOrganizationNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	err = s.orgs.Create(ctx, &models.Organization{
+		OrgID:     orgID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if errors.Is(err, models.ErrOrganizationExists) {
+		return nil
+	}
+	return err
+}
+
+// CreateOrganization creates a tenant. Alert thresholds must match the configured rules.
+func (s *TenantService) CreateOrganization(ctx context.Context, req *models.CreateOrganizationRequest) (*models.Organization, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	thresholds := make(map[string]*models.AlertThreshold, len(req.Config.AlertThresholds))
+	for ruleID, threshold := range req.Config.AlertThresholds {
+		threshold := threshold
+		thresholds[ruleID] = &threshold
+	}
+	if err := s.alerts.ValidateThresholds(thresholds); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	org := &models.Organization{
+		OrgID:     req.OrgID,
+		Name:      req.Name,
+		Config:    req.Config,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.orgs.Create(ctx, org); err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("org_id", org.OrgID).Msg("Organization created")
+	return org, nil
+}
+
+// GetOrganization retrieves an organization by ID.
+func (s *TenantService) GetOrganization(ctx context.Context, orgID string) (*models.Organization, error) {
+	return s.orgs.GetByID(ctx, orgID)
+}
+
+// CurrentOrganization returns the organization of the caller.
+func (s *TenantService) CurrentOrganization(ctx context.Context) (*models.Organization, error) {
+	return s.orgs.GetByID(ctx, models.CreationOrgID(ctx))
+}
+
+// UpdateOrganization renames an organization, merges its alert thresholds or replaces its branding.
+func (s *TenantService) UpdateOrganization(
+	ctx context.Context,
+	orgID string,
+	req *models.UpdateOrganizationRequest,
+) (*models.Organization, error) {
+	if err := s.alerts.ValidateThresholds(req.AlertThresholds); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.ApplyTo(org, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// ListOrganizations returns a page of organizations with their device and credential totals
+// (cross-tenant view of the super-admin).
+func (s *TenantService) ListOrganizations(
+	ctx context.Context,
+	req *models.ListOrganizationsRequest,
+) (*models.ListOrganizationsResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	orgs, total, err := s.orgs.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, _, err := s.devices.List(ctx, &models.ListDevicesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	overviews := make([]*models.OrganizationOverview, 0, len(orgs))
+	byOrg := make(map[string]*models.OrganizationOverview, len(orgs))
+	for _, org := range orgs {
+		overview := &models.OrganizationOverview{Organization: org}
+		overviews = append(overviews, overview)
+		byOrg[org.OrgID] = overview
+
+		credentials, err := s.credentials.List(ctx, org.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		for _, credential := range credentials {
+			if credential.IsActive() {
+				overview.ActiveCredentials++
+			}
+		}
+	}
+
+	for _, device := range devices {
+		overview, ok := byOrg[device.OrgID]
+		if !ok {
+			continue
+		}
+		overview.Devices++
+		if device.Online {
+			overview.OnlineDevices++
+		}
+	}
+
+	return &models.ListOrganizationsResponse{
+		Organizations: overviews,
+		Total:         total,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	}, nil
+}
+
+// CreateCredential issues an API key for the organization. The key is only returned here.
+func (s *TenantService) CreateCredential(
+	ctx context.Context,
+	orgID string,
+	req *models.CreateCredentialRequest,
+) (*models.CreateCredentialResponse, error) {
+	if req.Name == "" || len(req.Name) > models.MaxGroupNameLength {
+		return nil, fmt.Errorf("%w: name must have 1 to %d characters", models.ErrInvalidInput, models.MaxGroupNameLength)
+	}
+	roles, err := models.NormalizeRoles(req.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.ValidateCredentialDevice(roles, req.DeviceID); err != nil {
+		return nil, err
+	}
+	if _, err := s.orgs.GetByID(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if req.DeviceID != "" {
+		if err := s.checkOrgDevice(ctx, orgID, req.DeviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("generating api key: %w", err)
+	}
+
+	credential := &models.APICredential{
+		CredentialID: "cred_" + uuid.New().String()[:8],
+		OrgID:        orgID,
+		Name:         req.Name,
+		Roles:        roles,
+		DeviceID:     req.DeviceID,
+		KeyPrefix:    key[:12],
+		KeyHash:      hashAPIKey(key),
+		CreatedAt:    time.Now(),
+	}
+	if err := s.credentials.Create(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("org_id", orgID).
+		Str("credential_id", credential.CredentialID).
+		Msg("API credential created")
+
+	return &models.CreateCredentialResponse{Credential: credential, APIKey: key}, nil
+}
+
+// ListCredentials returns the credentials of the organization, newest first.
+func (s *TenantService) ListCredentials(ctx context.Context, orgID string) ([]*models.APICredential, error) {
+	if _, err := s.orgs.GetByID(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.credentials.List(ctx, orgID)
+}
+
+// GetCredential retrieves an API credential by ID (without its key).
+func (s *TenantService) GetCredential(ctx context.Context, credentialID string) (*models.APICredential, error) {
+	return s.credentials.GetByID(ctx, credentialID)
+}
+
+// AssignRoles replaces the roles of an API key of the organization. Active keys use them on the next request.
+func (s *TenantService) AssignRoles(
+	ctx context.Context,
+	orgID, credentialID string,
+	req *models.AssignRolesRequest,
+) (*models.APICredential, error) {
+	roles, err := models.NormalizeRoles(req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credential, err := s.credentials.GetByID(ctx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if credential.OrgID != orgID {
+		return nil, models.ErrCredentialNotFound
+	}
+	if !credential.IsActive() {
+		return nil, fmt.Errorf("%w: credential is revoked", models.ErrInvalidTransition)
+	}
+	if err := models.ValidateCredentialDevice(roles, credential.DeviceID); err != nil {
+		return nil, err
+	}
+
+	credential.Roles = roles
+	if err := s.credentials.Update(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("org_id", orgID).
+		Str("credential_id", credentialID).
+		Interface("roles", roles).
+		Msg("API credential roles assigned")
+
+	return credential, nil
+}
+
+// RevokeCredential revokes an API key of the organization. Revoked keys are rejected immediately.
+func (s *TenantService) RevokeCredential(ctx context.Context, orgID, credentialID string) (*models.APICredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credential, err := s.credentials.GetByID(ctx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if credential.OrgID != orgID {
+		return nil, models.ErrCredentialNotFound
+	}
+
+	if err := credential.Revoke(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.credentials.Update(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("org_id", orgID).
+		Str("credential_id", credentialID).
+		Msg("API credential revoked")
+
+	return credential, nil
+}
+
+// Authenticate resolves the principal of a request from its API key and optional X-Org-ID header.
+// The admin key (or no key when ADMIN_API_KEY is unset) is a super-admin that may act on one
+// organization through X-Org-ID; an organization key is always limited to its organization.
+func (s *TenantService) Authenticate(ctx context.Context, apiKey, orgID string) (*models.Principal, error) {
+	adminKey := s.config.Security.AdminAPIKey
+
+	var principal *models.Principal
+	switch {
+	case apiKey == "" && adminKey == "":
+		principal = &models.Principal{SuperAdmin: true}
+	case apiKey == "":
+		return nil, fmt.Errorf("%w: API key required", models.ErrUnauthorized)
+	case adminKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(adminKey)) == 1:
+		principal = &models.Principal{SuperAdmin: true}
+	default:
+		credential, err := s.credentials.GetByKeyHash(ctx, hashAPIKey(apiKey))
+		if errors.Is(err, models.ErrCredentialNotFound) {
+			return nil, fmt.Errorf("%w: invalid API key", models.ErrUnauthorized)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !credential.IsActive() {
+			return nil, fmt.Errorf("%w: API key revoked", models.ErrUnauthorized)
+		}
+
+		s.touch(ctx, credential)
+		principal = &models.Principal{
+			OrgID:        credential.OrgID,
+			CredentialID: credential.CredentialID,
+			DeviceID:     credential.DeviceID,
+			Roles:        credential.Roles,
+		}
+	}
+
+	if orgID == "" || orgID == principal.OrgID {
+		return principal, nil
+	}
+	if !principal.SuperAdmin {
+		return nil, fmt.Errorf("%w: API key belongs to another organization", models.ErrForbidden)
+	}
+	if _, err := s.orgs.GetByID(ctx, orgID); err != nil {
+		return nil, err
+	}
+	principal.OrgID = orgID
+	return principal, nil
+}
+
+// checkOrgDevice retorna ErrDeviceNotFound si el dispositivo no existe en la organización.
+func (s *TenantService) checkOrgDevice(ctx context.Context, orgID, deviceID string) error {
+	device, err := s.devices.GetByID(models.WithPrincipal(ctx, nil), deviceID)
+	if err != nil {
+		return err
+	}
+	if device.OrgID != orgID {
+		return models.ErrDeviceNotFound
+	}
+	return nil
+}
+
+// touch registra el último uso de la credencial; un fallo no impide la petición.
+// Se relee bajo el lock para no pisar una revocación concurrente.
+func (s *TenantService) touch(ctx context.Context, credential *models.APICredential) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.credentials.GetByID(ctx, credential.CredentialID)
+	if err == nil {
+		now := time.Now()
+		current.LastUsedAt = &now
+		err = s.credentials.Update(ctx, current)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("credential_id", credential.CredentialID).Msg("Failed to record API key usage")
+	}
+}
+
+// newAPIKey genera "sbk_" + 32 bytes aleatorios en base64url.
+func newAPIKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return models.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashAPIKey retorna el SHA-256 (hex) que se guarda en lugar de la key.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
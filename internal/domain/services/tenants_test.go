@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
+)
+
+// newTestTenantService crea un TenantService con las organizaciones "uniandes" y "mall-centro".
+func newTestTenantService(t *testing.T, devices *local.DeviceRepository) *TenantService {
+	t.Helper()
+
+	cfg := testConfig()
+	cfg.Security.AdminAPIKey = "admin-key"
+	orgs := local.NewOrganizationRepository()
+	alerts := NewAlertService(local.NewAlertRepository(), local.NewGroupRepository(), orgs, NewDeviceManager(devices), models.DefaultAlertRules())
+	service := NewTenantService(cfg, orgs, local.NewCredentialRepository(), devices, alerts)
+
+	for _, orgID := range []string{"uniandes", "mall-centro"} {
+		if _, err := service.CreateOrganization(context.Background(), &models.CreateOrganizationRequest{
+			OrgID: orgID,
+			Name:  orgID,
+		}); err != nil {
+			t.Fatalf("CreateOrganization(%s) error = %v", orgID, err)
+		}
+	}
+	return service
+}
+
+// TestTenantAuthenticate verifica la resolución del principal por API key y X-Org-ID.
+func TestTenantAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	service := newTestTenantService(t, local.NewDeviceRepository())
+
+	created, err := service.CreateCredential(ctx, "uniandes", &models.CreateCredentialRequest{Name: "dashboard", Roles: []models.Role{models.RoleOperator, models.RoleReviewer}})
+	if err != nil {
+		t.Fatalf("CreateCredential() error = %v", err)
+	}
+	if created.Credential.KeyHash == created.APIKey || created.Credential.KeyPrefix != created.APIKey[:12] {
+		t.Fatalf("Credential = %+v", created.Credential)
+	}
+
+	principal, err := service.Authenticate(ctx, created.APIKey, "")
+	if err != nil {
+		t.Fatalf("Authenticate(credential) error = %v", err)
+	}
+	if principal.OrgID != "uniandes" || principal.SuperAdmin || principal.CredentialID != created.Credential.CredentialID ||
+		!principal.Can(models.PermissionReviewsResolve) {
+		t.Errorf("Principal = %+v, want uniandes credential", principal)
+	}
+
+	principal, err = service.Authenticate(ctx, "admin-key", "mall-centro")
+	if err != nil {
+		t.Fatalf("Authenticate(admin) error = %v", err)
+	}
+	if !principal.SuperAdmin || principal.OrgID != "mall-centro" {
+		t.Errorf("Principal = %+v, want super-admin on mall-centro", principal)
+	}
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		orgID   string
+		wantErr error
+	}{
+		{"Missing key", "", "", models.ErrUnauthorized},
+		{"Unknown key", models.APIKeyPrefix + "unknown", "", models.ErrUnauthorized},
+		{"Other organization", created.APIKey, "mall-centro", models.ErrForbidden},
+		{"Unknown organization", "admin-key", "unknown-org", models.ErrOrganizationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Authenticate(ctx, tt.apiKey, tt.orgID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	credentials, err := service.ListCredentials(ctx, "uniandes")
+	if err != nil || len(credentials) != 1 || credentials[0].LastUsedAt == nil {
+		t.Fatalf("ListCredentials() = %v, %v; want one used credential", credentials, err)
+	}
+
+	if _, err := service.AssignRoles(ctx, "uniandes", created.Credential.CredentialID, &models.AssignRolesRequest{Roles: []models.Role{"root"}}); !errors.Is(err, models.ErrInvalidInput) {
+		t.Errorf("AssignRoles(unknown role) error = %v, want ErrInvalidInput", err)
+	}
+	// Sin dispositivo atado la credencial no puede recibir el rol device
+	if _, err := service.AssignRoles(ctx, "uniandes", created.Credential.CredentialID, &models.AssignRolesRequest{Roles: []models.Role{models.RoleDevice}}); !errors.Is(err, models.ErrInvalidInput) {
+		t.Errorf("AssignRoles(device without device_id) error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := service.AssignRoles(ctx, "uniandes", created.Credential.CredentialID, &models.AssignRolesRequest{Roles: []models.Role{models.RoleReviewer}}); err != nil {
+		t.Fatalf("AssignRoles() error = %v", err)
+	}
+	principal, err = service.Authenticate(ctx, created.APIKey, "")
+	if err != nil {
+		t.Fatalf("Authenticate(credential) error = %v", err)
+	}
+	if principal.Can(models.PermissionDevicesManage) || !principal.Can(models.PermissionRead) {
+		t.Errorf("Principal roles = %v, want only reviewer", principal.Roles)
+	}
+
+	// Una credencial de otra organización no se puede revocar
+	if _, err := service.RevokeCredential(ctx, "mall-centro", created.Credential.CredentialID); !errors.Is(err, models.ErrCredentialNotFound) {
+		t.Errorf("RevokeCredential(other org) error = %v, want ErrCredentialNotFound", err)
+	}
+	if _, err := service.RevokeCredential(ctx, "uniandes", created.Credential.CredentialID); err != nil {
+		t.Fatalf("RevokeCredential() error = %v", err)
+	}
+	if _, err := service.Authenticate(ctx, created.APIKey, ""); !errors.Is(err, models.ErrUnauthorized) {
+		t.Errorf("Authenticate(revoked) error = %v, want ErrUnauthorized", err)
+	}
+}
+
+// TestTenantIsolation verifica que cada organización solo vea sus dispositivos y que el
+// super-admin tenga la vista cruzada.
+func TestTenantIsolation(t *testing.T) {
+	devices := local.NewDeviceRepository()
+	service := newTestTenantService(t, devices)
+	manager := NewDeviceManager(devices)
+
+	uniandes := models.WithPrincipal(context.Background(), &models.Principal{OrgID: "uniandes"})
+	mall := models.WithPrincipal(context.Background(), &models.Principal{OrgID: "mall-centro"})
+	admin := models.WithPrincipal(context.Background(), &models.Principal{SuperAdmin: true})
+
+	for _, deviceID := range []string{"bin-1", "bin-2"} {
+		if _, err := manager.RegisterDevice(uniandes, &models.RegisterDeviceRequest{
+			DeviceID:   deviceID,
+			DeviceType: string(models.DeviceTypeSmartBinV2),
+		}, &models.DeviceCertificate{}); err != nil {
+			t.Fatalf("RegisterDevice(%s) error = %v", deviceID, err)
+		}
+	}
+	if _, err := manager.RegisterDevice(mall, &models.RegisterDeviceRequest{
+		DeviceID:   "bin-3",
+		DeviceType: string(models.DeviceTypeSmartBinV2),
+	}, &models.DeviceCertificate{}); err != nil {
+		t.Fatalf("RegisterDevice(bin-3) error = %v", err)
+	}
+
+	if _, err := manager.GetDevice(mall, "bin-1"); !errors.Is(err, models.ErrDeviceNotFound) {
+		t.Errorf("GetDevice(other org) error = %v, want ErrDeviceNotFound", err)
+	}
+	listed, err := manager.ListDevices(mall, &models.ListDevicesRequest{})
+	if err != nil || listed.Total != 1 || listed.Devices[0].DeviceID != "bin-3" {
+		t.Errorf("ListDevices(mall-centro) = %+v, %v; want bin-3", listed, err)
+	}
+
+	// Un tenant no puede crear registros en otra organización
+	if err := devices.Create(mall, &models.Device{DeviceID: "bin-4", OrgID: "uniandes"}); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("Create(other org) error = %v, want ErrForbidden", err)
+	}
+
+	if _, err := service.GetOrganization(uniandes, "mall-centro"); !errors.Is(err, models.ErrOrganizationNotFound) {
+		t.Errorf("GetOrganization(other org) error = %v, want ErrOrganizationNotFound", err)
+	}
+
+	overview, err := service.ListOrganizations(admin, &models.ListOrganizationsRequest{})
+	if err != nil {
+		t.Fatalf("ListOrganizations() error = %v", err)
+	}
+	if overview.Total != 2 {
+		t.Fatalf("ListOrganizations() total = %d, want 2", overview.Total)
+	}
+	counts := make(map[string]int)
+	for _, org := range overview.Organizations {
+		counts[org.OrgID] = org.Devices
+	}
+	if counts["uniandes"] != 2 || counts["mall-centro"] != 1 {
+		t.Errorf("Device counts = %v, want uniandes:2 mall-centro:1", counts)
+	}
+}
+
+// TestTenantCrossOrgJobs verifica que un tenant no pueda crear jobs (ni publicar decisiones)
+// para dispositivos de otra organización.
+func TestTenantCrossOrgJobs(t *testing.T) {
+	devices := local.NewDeviceRepository()
+	iot := local.NewIoTPublisher()
+	manager := NewJobManager(testConfig(), local.NewJobRepository(), devices, local.NewPresigner("us-east-1"), local.NewPublisher(), iot, metrics.NewRegistry())
+
+	uniandes := models.WithPrincipal(context.Background(), &models.Principal{OrgID: "uniandes"})
+	mall := models.WithPrincipal(context.Background(), &models.Principal{OrgID: "mall-centro"})
+
+	if _, err := NewDeviceManager(devices).RegisterDevice(mall, &models.RegisterDeviceRequest{
+		DeviceID:   "bin-3",
+		DeviceType: string(models.DeviceTypeSmartBinV2),
+	}, &models.DeviceCertificate{}); err != nil {
+		t.Fatalf("RegisterDevice(bin-3) error = %v", err)
+	}
+
+	for _, req := range []*models.CreateJobRequest{
+		{DeviceID: "bin-3", Timestamp: "2026-01-21T10:30:00Z"},
+		{DeviceID: "bin-3", Timestamp: "2026-01-21T10:30:00Z", ImageKey: "uploads/bin-3/capture.jpg"},
+	} {
+		if _, err := manager.CreateJob(uniandes, req); !errors.Is(err, models.ErrForbidden) {
+			t.Errorf("CreateJob(other org, image %q) error = %v, want ErrForbidden", req.ImageKey, err)
+		}
+	}
+
+	// Los dispositivos no registrados y los propios se siguen aceptando
+	created, err := manager.CreateJob(uniandes, &models.CreateJobRequest{DeviceID: "bin-9", Timestamp: "2026-01-21T10:30:00Z"})
+	if err != nil {
+		t.Fatalf("CreateJob(unregistered) error = %v", err)
+	}
+	if job, err := manager.GetJob(uniandes, created.JobID); err != nil || job.OrgID != "uniandes" {
+		t.Errorf("GetJob(unregistered) = %+v, %v; want job of uniandes", job, err)
+	}
+	if _, err := manager.CreateJob(mall, &models.CreateJobRequest{DeviceID: "bin-3", Timestamp: "2026-01-21T10:30:00Z"}); err != nil {
+		t.Errorf("CreateJob(own device) error = %v", err)
+	}
+}
+
+// TestTenantDeviceCredential verifica que una API key con el rol device quede atada a un
+// dispositivo de su organización y solo actúe en nombre de ese dispositivo.
+func TestTenantDeviceCredential(t *testing.T) {
+	ctx := context.Background()
+	devices := local.NewDeviceRepository()
+	service := newTestTenantService(t, devices)
+	deviceManager := NewDeviceManager(devices)
+
+	for deviceID, orgID := range map[string]string{"bin-1": "uniandes", "bin-2": "uniandes", "bin-3": "mall-centro"} {
+		if _, err := deviceManager.RegisterDevice(models.WithPrincipal(ctx, &models.Principal{OrgID: orgID}), &models.RegisterDeviceRequest{
+			DeviceID:   deviceID,
+			DeviceType: string(models.DeviceTypeSmartBinV2),
+		}, &models.DeviceCertificate{}); err != nil {
+			t.Fatalf("RegisterDevice(%s) error = %v", deviceID, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		deviceID string
+		wantErr  error
+	}{
+		{"Missing device", "", models.ErrInvalidInput},
+		{"Unknown device", "bin-9", models.ErrDeviceNotFound},
+		{"Device of another organization", "bin-3", models.ErrDeviceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateCredential(ctx, "uniandes", &models.CreateCredentialRequest{Name: "bin", Roles: []models.Role{models.RoleDevice}, DeviceID: tt.deviceID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateCredential() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	created, err := service.CreateCredential(ctx, "uniandes", &models.CreateCredentialRequest{Name: "bin-1", Roles: []models.Role{models.RoleDevice}, DeviceID: "bin-1"})
+	if err != nil {
+		t.Fatalf("CreateCredential() error = %v", err)
+	}
+	principal, err := service.Authenticate(ctx, created.APIKey, "")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.DeviceID != "bin-1" {
+		t.Fatalf("Principal device = %q, want bin-1", principal.DeviceID)
+	}
+
+	// La key solo crea jobs para su propio dispositivo, aunque el otro sea de la misma organización
+	manager := NewJobManager(testConfig(), local.NewJobRepository(), devices, local.NewPresigner("us-east-1"), local.NewPublisher(), local.NewIoTPublisher(), metrics.NewRegistry())
+	bin := models.WithPrincipal(ctx, principal)
+	if _, err := manager.CreateJob(bin, &models.CreateJobRequest{DeviceID: "bin-2", Timestamp: "2026-01-21T10:30:00Z"}); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("CreateJob(other device) error = %v, want ErrForbidden", err)
+	}
+	if _, err := manager.CreateJob(bin, &models.CreateJobRequest{DeviceID: "bin-1", Timestamp: "2026-01-21T10:30:00Z"}); err != nil {
+		t.Errorf("CreateJob(own device) error = %v", err)
+	}
+}
+
+// TestTenantAlertThresholds verifica que los umbrales de la organización reemplacen a los globales.
+func TestTenantAlertThresholds(t *testing.T) {
+	ctx := context.Background()
+	service := newTestTenantService(t, local.NewDeviceRepository())
+
+	if _, err := service.UpdateOrganization(ctx, "uniandes", &models.UpdateOrganizationRequest{
+		AlertThresholds: map[string]*models.AlertThreshold{"unknown_rule": {Threshold: 1}},
+	}); !errors.Is(err, models.ErrInvalidAlertRule) {
+		t.Errorf("UpdateOrganization(unknown rule) error = %v, want ErrInvalidAlertRule", err)
+	}
+	if _, err := service.UpdateOrganization(ctx, "uniandes", &models.UpdateOrganizationRequest{
+		AlertThresholds: map[string]*models.AlertThreshold{"fill_high": {Threshold: 85, ClearThreshold: 95}},
+	}); !errors.Is(err, models.ErrInvalidAlertRule) {
+		t.Errorf("UpdateOrganization(inverted hysteresis) error = %v, want ErrInvalidAlertRule", err)
+	}
+
+	if _, err := service.UpdateOrganization(ctx, "uniandes", &models.UpdateOrganizationRequest{
+		AlertThresholds: map[string]*models.AlertThreshold{"fill_high": {Threshold: 75, ClearThreshold: 60}},
+	}); err != nil {
+		t.Fatalf("UpdateOrganization() error = %v", err)
+	}
+
+	thresholds := func(orgID string) float64 {
+		rules, err := service.alerts.RulesForDevice(ctx, &models.Device{
+			DeviceID:   "bin-1",
+			OrgID:      orgID,
+			DeviceType: string(models.DeviceTypeSmartBinV2),
+		})
+		if err != nil {
+			t.Fatalf("RulesForDevice(%s) error = %v", orgID, err)
+		}
+		for _, rule := range rules {
+			if rule.RuleID == "fill_high" {
+				return rule.Threshold
+			}
+		}
+		t.Fatalf("RulesForDevice(%s) has no fill_high rule", orgID)
+		return 0
+	}
+
+	if got := thresholds("uniandes"); got != 75 {
+		t.Errorf("uniandes fill_high threshold = %v, want 75", got)
+	}
+	if got := thresholds("mall-centro"); got != 90 {
+		t.Errorf("mall-centro fill_high threshold = %v, want 90", got)
+	}
+}
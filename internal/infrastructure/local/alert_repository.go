@@ -0,0 +1,115 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// AlertRepository implementa ports.AlertRepository en memoria.
+type AlertRepository struct {
+	mu     sync.RWMutex
+	alerts map[string]*models.Alert
+}
+
+// NewAlertRepository crea un repositorio de alertas vacío.
+func NewAlertRepository() *AlertRepository {
+	return &AlertRepository{
+		alerts: make(map[string]*models.Alert),
+	}
+}
+
+// Create guarda una alerta nueva.
+func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	if !visible(ctx, alert.OrgID) {
+		return fmt.Errorf("%w: alert belongs to another organization", models.ErrForbidden)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.alerts[alert.AlertID]; exists {
+		return fmt.Errorf("%w: alert %s already exists", models.ErrDynamoDBOperation, alert.AlertID)
+	}
+
+	stored := *alert
+	r.alerts[alert.AlertID] = &stored
+	return nil
+}
+
+// GetByID retorna una copia de la alerta.
+func (r *AlertRepository) GetByID(ctx context.Context, alertID string) (*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alert, ok := r.alerts[alertID]
+	if !ok || !visible(ctx, alert.OrgID) {
+		return nil, models.ErrAlertNotFound
+	}
+
+	found := *alert
+	return &found, nil
+}
+
+// Update reemplaza una alerta existente. La organización de la alerta no cambia.
+func (r *AlertRepository) Update(ctx context.Context, alert *models.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.alerts[alert.AlertID]
+	if !ok || !visible(ctx, stored.OrgID) || alert.OrgID != stored.OrgID {
+		return models.ErrAlertNotFound
+	}
+
+	updated := *alert
+	r.alerts[alert.AlertID] = &updated
+	return nil
+}
+
+// FindActive retorna la alerta activa del dispositivo para la regla.
+func (r *AlertRepository) FindActive(ctx context.Context, deviceID, ruleID string) (*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, alert := range r.alerts {
+		if alert.DeviceID == deviceID && alert.RuleID == ruleID && alert.IsActive() && visible(ctx, alert.OrgID) {
+			found := *alert
+			return &found, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// List filtra por organización, dispositivo, estado y métrica, ordena por apertura (más reciente primero) y pagina.
+func (r *AlertRepository) List(ctx context.Context, req *models.ListAlertsRequest) ([]*models.Alert, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*models.Alert, 0, len(r.alerts))
+	for _, alert := range r.alerts {
+		if !visible(ctx, alert.OrgID) {
+			continue
+		}
+		if req.DeviceID != "" && alert.DeviceID != req.DeviceID {
+			continue
+		}
+		if req.State != "" && alert.State != req.State {
+			continue
+		}
+		if req.Metric != "" && alert.Metric != req.Metric {
+			continue
+		}
+		found := *alert
+		matched = append(matched, &found)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].OpenedAt.After(matched[j].OpenedAt)
+	})
+
+	return paginate(matched, req.Offset, req.Limit), len(matched), nil
+}
@@ -0,0 +1,66 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// AuditRepository implementa ports.AuditRepository en memoria.
+type AuditRepository struct {
+	mu sync.RWMutex
+
+	// entries - Cadena en orden de Sequence
+	entries []*models.AuditEntry
+}
+
+// NewAuditRepository crea un registro de auditoría vacío.
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+// Append agrega una copia de la entrada al final de la cadena.
+func (r *AuditRepository) Append(_ context.Context, entry *models.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if expected := int64(len(r.entries)) + 1; entry.Sequence != expected {
+		return fmt.Errorf("%w: audit sequence %d, expected %d", models.ErrDynamoDBOperation, entry.Sequence, expected)
+	}
+
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+// Last retorna una copia de la última entrada, sin aplicar el scope.
+func (r *AuditRepository) Last(_ context.Context) (*models.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.entries) == 0 {
+		return nil, nil
+	}
+	last := *r.entries[len(r.entries)-1]
+	return &last, nil
+}
+
+// List retorna las entradas visibles que cumplen los filtros, las más recientes primero.
+func (r *AuditRepository) List(ctx context.Context, req *models.ListAuditRequest) ([]*models.AuditEntry, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*models.AuditEntry, 0)
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		if !visible(ctx, entry.OrgID) || !req.Matches(entry) {
+			continue
+		}
+		found := *entry
+		matched = append(matched, &found)
+	}
+
+	return paginate(matched, req.Offset, req.Limit), len(matched), nil
+}
@@ -0,0 +1,92 @@
+package local
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// CertificateRepository implementa ports.CertificateRepository en memoria.
+type CertificateRepository struct {
+	mu           sync.RWMutex
+	certificates map[string]*models.DeviceCertificate
+}
+
+// NewCertificateRepository crea un repositorio de certificados vacío.
+func NewCertificateRepository() *CertificateRepository {
+	return &CertificateRepository{
+		certificates: make(map[string]*models.DeviceCertificate),
+	}
+}
+
+// Save crea o reemplaza el certificado. Nunca guarda la llave privada.
+func (r *CertificateRepository) Save(_ context.Context, certificate *models.DeviceCertificate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyCertificate(certificate)
+	stored.PrivateKeyPEM = ""
+	r.certificates[certificate.SerialNumber] = stored
+	return nil
+}
+
+// GetBySerial retorna una copia del certificado.
+func (r *CertificateRepository) GetBySerial(_ context.Context, serial string) (*models.DeviceCertificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	certificate, ok := r.certificates[serial]
+	if !ok {
+		return nil, models.ErrCertificateNotFound
+	}
+	return copyCertificate(certificate), nil
+}
+
+// List retorna copias de los certificados que cumplen los filtros, los que expiran antes primero.
+func (r *CertificateRepository) List(_ context.Context, req *models.ListCertificatesRequest) ([]*models.DeviceCertificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*models.DeviceCertificate, 0)
+	for _, certificate := range r.certificates {
+		if req.DeviceID != "" && certificate.DeviceID != req.DeviceID {
+			continue
+		}
+		if req.Status != "" && certificate.Status != req.Status {
+			continue
+		}
+		if req.ExpiresBefore != nil && !certificate.ExpiresAt.Before(*req.ExpiresBefore) {
+			continue
+		}
+		matched = append(matched, copyCertificate(certificate))
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].ExpiresAt.Equal(matched[j].ExpiresAt) {
+			return matched[i].ExpiresAt.Before(matched[j].ExpiresAt)
+		}
+		return matched[i].SerialNumber < matched[j].SerialNumber
+	})
+	return matched, nil
+}
+
+// copyCertificate copia el certificado sin compartir los timestamps opcionales.
+func copyCertificate(certificate *models.DeviceCertificate) *models.DeviceCertificate {
+	copied := *certificate
+	copied.SupersededAt = copyTime(certificate.SupersededAt)
+	copied.AcceptedUntil = copyTime(certificate.AcceptedUntil)
+	copied.RevokedAt = copyTime(certificate.RevokedAt)
+	return &copied
+}
+
+// copyTime copia un timestamp opcional.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
@@ -0,0 +1,89 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// CommandRepository implementa ports.CommandRepository en memoria.
+type CommandRepository struct {
+	mu       sync.RWMutex
+	commands map[string]*models.Command
+}
+
+// NewCommandRepository crea un repositorio de comandos vacío.
+func NewCommandRepository() *CommandRepository {
+	return &CommandRepository{
+		commands: make(map[string]*models.Command),
+	}
+}
+
+// Create guarda un comando nuevo.
+func (r *CommandRepository) Create(_ context.Context, command *models.Command) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.commands[command.CommandID]; exists {
+		return fmt.Errorf("%w: command %s already exists", models.ErrDynamoDBOperation, command.CommandID)
+	}
+
+	stored := *command
+	r.commands[command.CommandID] = &stored
+	return nil
+}
+
+// GetByID retorna una copia del comando.
+func (r *CommandRepository) GetByID(_ context.Context, commandID string) (*models.Command, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	command, ok := r.commands[commandID]
+	if !ok {
+		return nil, models.ErrCommandNotFound
+	}
+
+	found := *command
+	return &found, nil
+}
+
+// Update reemplaza un comando existente.
+func (r *CommandRepository) Update(_ context.Context, command *models.Command) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.commands[command.CommandID]; !ok {
+		return models.ErrCommandNotFound
+	}
+
+	stored := *command
+	r.commands[command.CommandID] = &stored
+	return nil
+}
+
+// List retorna los comandos que cumplen los filtros, más recientes primero.
+func (r *CommandRepository) List(_ context.Context, req *models.ListCommandsRequest) ([]*models.Command, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*models.Command, 0, len(r.commands))
+	for _, command := range r.commands {
+		if req.DeviceID != "" && command.DeviceID != req.DeviceID {
+			continue
+		}
+		if req.Status != "" && command.Status != req.Status {
+			continue
+		}
+		found := *command
+		matched = append(matched, &found)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	return paginate(matched, req.Offset, req.Limit), len(matched), nil
+}
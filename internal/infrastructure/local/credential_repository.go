@@ -0,0 +1,113 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// CredentialRepository implementa ports.CredentialRepository en memoria.
+type CredentialRepository struct {
+	mu          sync.RWMutex
+	credentials map[string]*models.APICredential
+
+	// byKeyHash - Índice hash de la key → credential_id
+	byKeyHash map[string]string
+}
+
+// NewCredentialRepository crea un repositorio de credenciales vacío.
+func NewCredentialRepository() *CredentialRepository {
+	return &CredentialRepository{
+		credentials: make(map[string]*models.APICredential),
+		byKeyHash:   make(map[string]string),
+	}
+}
+
+// Create guarda una credencial nueva.
+func (r *CredentialRepository) Create(ctx context.Context, credential *models.APICredential) error {
+	if !visible(ctx, credential.OrgID) {
+		return fmt.Errorf("%w: credential belongs to another organization", models.ErrForbidden)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.credentials[credential.CredentialID]; exists {
+		return fmt.Errorf("%w: credential %s already exists", models.ErrDynamoDBOperation, credential.CredentialID)
+	}
+
+	stored := *credential
+	r.credentials[credential.CredentialID] = &stored
+	r.byKeyHash[credential.KeyHash] = credential.CredentialID
+	return nil
+}
+
+// GetByID retorna una copia de la credencial.
+func (r *CredentialRepository) GetByID(ctx context.Context, credentialID string) (*models.APICredential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	credential, ok := r.credentials[credentialID]
+	if !ok || !visible(ctx, credential.OrgID) {
+		return nil, models.ErrCredentialNotFound
+	}
+
+	found := *credential
+	return &found, nil
+}
+
+// GetByKeyHash retorna una copia de la credencial con el hash, sin aplicar el scope.
+func (r *CredentialRepository) GetByKeyHash(_ context.Context, keyHash string) (*models.APICredential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	credentialID, ok := r.byKeyHash[keyHash]
+	if !ok {
+		return nil, models.ErrCredentialNotFound
+	}
+
+	found := *r.credentials[credentialID]
+	return &found, nil
+}
+
+// Update reemplaza una credencial existente. La organización y la key no cambian.
+func (r *CredentialRepository) Update(ctx context.Context, credential *models.APICredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.credentials[credential.CredentialID]
+	if !ok || !visible(ctx, stored.OrgID) || credential.OrgID != stored.OrgID || credential.KeyHash != stored.KeyHash {
+		return models.ErrCredentialNotFound
+	}
+
+	updated := *credential
+	r.credentials[credential.CredentialID] = &updated
+	return nil
+}
+
+// List retorna las credenciales de la organización, las más recientes primero.
+func (r *CredentialRepository) List(ctx context.Context, orgID string) ([]*models.APICredential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !visible(ctx, orgID) {
+		return []*models.APICredential{}, nil
+	}
+
+	credentials := make([]*models.APICredential, 0)
+	for _, credential := range r.credentials {
+		if credential.OrgID == orgID {
+			found := *credential
+			credentials = append(credentials, &found)
+		}
+	}
+
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].CreatedAt.After(credentials[j].CreatedAt)
+	})
+
+	return credentials, nil
+}
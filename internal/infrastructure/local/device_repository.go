@@ -0,0 +1,207 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// maxGeohashCells - Máximo de celdas consultadas en el índice por búsqueda.
+const maxGeohashCells = 32
+
+// DeviceRepository implementa ports.DeviceRepository en memoria.
+//
+// Las búsquedas por cercanía usan un índice geohash: cada dispositivo con
+// coordenadas se registra bajo todos los prefijos de su geohash, de modo que
+// una celda de cualquier precisión resuelve sus dispositivos con un lookup.
+type DeviceRepository struct {
+	mu      sync.RWMutex
+	devices map[string]*models.Device
+	geohash map[string]map[string]struct{}
+}
+
+// NewDeviceRepository crea un repositorio de dispositivos vacío.
+func NewDeviceRepository() *DeviceRepository {
+	return &DeviceRepository{
+		devices: make(map[string]*models.Device),
+		geohash: make(map[string]map[string]struct{}),
+	}
+}
+
+// Create guarda un dispositivo nuevo.
+func (r *DeviceRepository) Create(ctx context.Context, device *models.Device) error {
+	if !visible(ctx, device.OrgID) {
+		return fmt.Errorf("%w: device belongs to another organization", models.ErrForbidden)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.devices[device.DeviceID]; exists {
+		return models.ErrDeviceAlreadyExists
+	}
+
+	r.devices[device.DeviceID] = cloneDevice(device)
+	r.indexLocation(device.DeviceID, nil, device.Location)
+	return nil
+}
+
+// GetByID retorna una copia del dispositivo.
+func (r *DeviceRepository) GetByID(ctx context.Context, deviceID string) (*models.Device, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	device, ok := r.devices[deviceID]
+	if !ok || !visible(ctx, device.OrgID) {
+		return nil, models.ErrDeviceNotFound
+	}
+
+	return cloneDevice(device), nil
+}
+
+// Update reemplaza un dispositivo existente. La organización del dispositivo no cambia.
+func (r *DeviceRepository) Update(ctx context.Context, device *models.Device) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.devices[device.DeviceID]
+	if !ok || !visible(ctx, stored.OrgID) || device.OrgID != stored.OrgID {
+		return models.ErrDeviceNotFound
+	}
+
+	r.indexLocation(device.DeviceID, stored.Location, device.Location)
+	r.devices[device.DeviceID] = cloneDevice(device)
+	return nil
+}
+
+// List filtra por organización, status, conectividad, tipo de dispositivo, tipo de contenedor, área y tags, y pagina.
+// Con filtros geográficos ordena por distancia al centro de búsqueda; si no, por ID.
+func (r *DeviceRepository) List(ctx context.Context, req *models.ListDevicesRequest) ([]*models.Device, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	candidates := r.devices
+	if req.Bounds != nil {
+		candidates = r.devicesIn(*req.Bounds)
+	}
+
+	var origin models.GeoPoint
+	switch {
+	case req.Center != nil:
+		origin = *req.Center
+	case req.Bounds != nil:
+		origin = req.Bounds.Center()
+	}
+
+	matched := make([]*models.Device, 0, len(candidates))
+	for _, device := range candidates {
+		if !visible(ctx, device.OrgID) {
+			continue
+		}
+		if req.Status != "" && device.Status != req.Status {
+			continue
+		}
+		if req.DeviceType != "" && device.DeviceType != req.DeviceType {
+			continue
+		}
+		if req.BinType != "" && device.BinType != req.BinType {
+			continue
+		}
+		if req.Online != nil && device.Online != *req.Online {
+			continue
+		}
+		if req.Accepts != "" && !device.AcceptsCompartment(req.Accepts) {
+			continue
+		}
+		if !device.MatchesTags(req.Tags) {
+			continue
+		}
+
+		found := cloneDevice(device)
+		if req.Bounds != nil {
+			point, _ := device.Location.Point()
+			if !req.Bounds.Contains(point) {
+				continue
+			}
+
+			distance := origin.DistanceTo(point)
+			if req.Center != nil && req.RadiusMeters > 0 && distance > req.RadiusMeters {
+				continue
+			}
+			found.DistanceMeters = &distance
+		}
+		matched = append(matched, found)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if req.Bounds != nil && *matched[i].DistanceMeters != *matched[j].DistanceMeters {
+			return *matched[i].DistanceMeters < *matched[j].DistanceMeters
+		}
+		return matched[i].DeviceID < matched[j].DeviceID
+	})
+
+	return paginate(matched, req.Offset, req.Limit), len(matched), nil
+}
+
+// devicesIn retorna los dispositivos indexados en las celdas geohash que cubren bounds.
+// Es un superconjunto: el llamador debe verificar bounds con las coordenadas exactas.
+func (r *DeviceRepository) devicesIn(bounds models.GeoBounds) map[string]*models.Device {
+	result := make(map[string]*models.Device)
+	for _, cell := range models.GeohashCover(bounds, maxGeohashCells) {
+		for deviceID := range r.geohash[cell] {
+			result[deviceID] = r.devices[deviceID]
+		}
+	}
+	return result
+}
+
+// indexLocation mueve el dispositivo en el índice geohash de previous a current.
+func (r *DeviceRepository) indexLocation(deviceID string, previous, current *models.Location) {
+	oldHash, newHash := "", ""
+	if point, ok := previous.Point(); ok {
+		oldHash = models.EncodeGeohash(point, models.GeohashPrecision)
+	}
+	if point, ok := current.Point(); ok {
+		newHash = models.EncodeGeohash(point, models.GeohashPrecision)
+	}
+	if oldHash == newHash {
+		return
+	}
+
+	for i := 1; i <= len(oldHash); i++ {
+		prefix := oldHash[:i]
+		delete(r.geohash[prefix], deviceID)
+		if len(r.geohash[prefix]) == 0 {
+			delete(r.geohash, prefix)
+		}
+	}
+	for i := 1; i <= len(newHash); i++ {
+		prefix := newHash[:i]
+		if r.geohash[prefix] == nil {
+			r.geohash[prefix] = make(map[string]struct{})
+		}
+		r.geohash[prefix][deviceID] = struct{}{}
+	}
+}
+
+// cloneDevice copia el dispositivo para que el llamador no comparta la Location guardada.
+func cloneDevice(device *models.Device) *models.Device {
+	clone := *device
+	if device.Location != nil {
+		location := *device.Location
+		clone.Location = &location
+	}
+	if device.StatusHistory != nil {
+		clone.StatusHistory = append([]models.StatusChange(nil), device.StatusHistory...)
+	}
+	if device.Tags != nil {
+		clone.Tags = make(map[string]string, len(device.Tags))
+		for key, value := range device.Tags {
+			clone.Tags[key] = value
+		}
+	}
+	return &clone
+}
@@ -0,0 +1,51 @@
+package local
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/rs/zerolog/log"
+)
+
+// EventHandler procesa un evento publicado en el EventBus.
+type EventHandler func(ctx context.Context, event *models.DeviceEvent)
+
+// EventBus implementa ports.EventPublisher entregando los eventos en proceso
+// a los suscriptores registrados, de forma síncrona y en orden de suscripción.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+// NewEventBus crea un bus de eventos sin suscriptores.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registra un handler que recibirá todos los eventos publicados.
+func (b *EventBus) Subscribe(handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish entrega el evento a todos los suscriptores.
+func (b *EventBus) Publish(ctx context.Context, event *models.DeviceEvent) error {
+	b.mu.RLock()
+	handlers := make([]EventHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	log.Info().
+		Str("device_id", event.DeviceID).
+		Str("event_type", string(event.EventType)).
+		Msg("Orchestrator event published")
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+
+	return nil
+}
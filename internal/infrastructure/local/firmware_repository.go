@@ -0,0 +1,204 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// FirmwareRepository implementa ports.FirmwareRepository en memoria.
+type FirmwareRepository struct {
+	mu       sync.RWMutex
+	releases map[string]*models.FirmwareRelease
+	rollouts map[string]*models.FirmwareRollout
+
+	// updates indexa por rollout_id y luego por device_id
+	updates map[string]map[string]*models.FirmwareUpdate
+}
+
+// NewFirmwareRepository crea un repositorio de firmware vacío.
+func NewFirmwareRepository() *FirmwareRepository {
+	return &FirmwareRepository{
+		releases: make(map[string]*models.FirmwareRelease),
+		rollouts: make(map[string]*models.FirmwareRollout),
+		updates:  make(map[string]map[string]*models.FirmwareUpdate),
+	}
+}
+
+// CreateRelease guarda una release nueva.
+func (r *FirmwareRepository) CreateRelease(_ context.Context, release *models.FirmwareRelease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.releases {
+		if existing.DeviceType == release.DeviceType && existing.Version == release.Version {
+			return models.ErrFirmwareReleaseExists
+		}
+	}
+
+	stored := *release
+	r.releases[release.ReleaseID] = &stored
+	return nil
+}
+
+// GetRelease retorna una copia de la release.
+func (r *FirmwareRepository) GetRelease(_ context.Context, releaseID string) (*models.FirmwareRelease, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	release, ok := r.releases[releaseID]
+	if !ok {
+		return nil, models.ErrFirmwareReleaseNotFound
+	}
+
+	found := *release
+	return &found, nil
+}
+
+// ListReleases retorna las releases que cumplen los filtros, más recientes primero.
+func (r *FirmwareRepository) ListReleases(_ context.Context, req *models.ListFirmwareReleasesRequest) ([]*models.FirmwareRelease, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*models.FirmwareRelease, 0, len(r.releases))
+	for _, release := range r.releases {
+		if req.DeviceType != "" && release.DeviceType != req.DeviceType {
+			continue
+		}
+		found := *release
+		matched = append(matched, &found)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	return paginate(matched, req.Offset, req.Limit), len(matched), nil
+}
+
+// CreateRollout guarda un rollout nuevo.
+func (r *FirmwareRepository) CreateRollout(_ context.Context, rollout *models.FirmwareRollout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rollouts[rollout.RolloutID]; exists {
+		return fmt.Errorf("%w: rollout %s already exists", models.ErrDynamoDBOperation, rollout.RolloutID)
+	}
+
+	r.rollouts[rollout.RolloutID] = copyRollout(rollout)
+	return nil
+}
+
+// GetRollout retorna una copia del rollout.
+func (r *FirmwareRepository) GetRollout(_ context.Context, rolloutID string) (*models.FirmwareRollout, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rollout, ok := r.rollouts[rolloutID]
+	if !ok {
+		return nil, models.ErrRolloutNotFound
+	}
+	return copyRollout(rollout), nil
+}
+
+// UpdateRollout reemplaza un rollout existente.
+func (r *FirmwareRepository) UpdateRollout(_ context.Context, rollout *models.FirmwareRollout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rollouts[rollout.RolloutID]; !ok {
+		return models.ErrRolloutNotFound
+	}
+
+	r.rollouts[rollout.RolloutID] = copyRollout(rollout)
+	return nil
+}
+
+// ListRollouts retorna los rollouts que cumplen los filtros, más recientes primero.
+func (r *FirmwareRepository) ListRollouts(_ context.Context, req *models.ListRolloutsRequest) ([]*models.FirmwareRollout, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*models.FirmwareRollout, 0, len(r.rollouts))
+	for _, rollout := range r.rollouts {
+		if req.DeviceType != "" && rollout.DeviceType != req.DeviceType {
+			continue
+		}
+		if req.Status != "" && rollout.Status != req.Status {
+			continue
+		}
+		matched = append(matched, copyRollout(rollout))
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	return paginate(matched, req.Offset, req.Limit), len(matched), nil
+}
+
+// SaveUpdate crea o reemplaza la actualización del dispositivo.
+func (r *FirmwareRepository) SaveUpdate(_ context.Context, update *models.FirmwareUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updates, ok := r.updates[update.RolloutID]
+	if !ok {
+		updates = make(map[string]*models.FirmwareUpdate)
+		r.updates[update.RolloutID] = updates
+	}
+
+	stored := *update
+	updates[update.DeviceID] = &stored
+	return nil
+}
+
+// GetUpdate retorna una copia de la actualización, o nil si el dispositivo no está en el rollout.
+func (r *FirmwareRepository) GetUpdate(_ context.Context, rolloutID, deviceID string) (*models.FirmwareUpdate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	update, ok := r.updates[rolloutID][deviceID]
+	if !ok {
+		return nil, nil
+	}
+
+	found := *update
+	return &found, nil
+}
+
+// ListUpdates retorna copias de las actualizaciones del rollout ordenadas por dispositivo.
+func (r *FirmwareRepository) ListUpdates(_ context.Context, rolloutID string) ([]*models.FirmwareUpdate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	updates := make([]*models.FirmwareUpdate, 0, len(r.updates[rolloutID]))
+	for _, update := range r.updates[rolloutID] {
+		found := *update
+		updates = append(updates, &found)
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].DeviceID < updates[j].DeviceID
+	})
+	return updates, nil
+}
+
+// copyRollout copia el rollout sin compartir DeviceIDs ni los timestamps opcionales.
+func copyRollout(rollout *models.FirmwareRollout) *models.FirmwareRollout {
+	copied := *rollout
+	copied.DeviceIDs = append([]string(nil), rollout.DeviceIDs...)
+	if rollout.ResumedAt != nil {
+		resumedAt := *rollout.ResumedAt
+		copied.ResumedAt = &resumedAt
+	}
+	if rollout.CompletedAt != nil {
+		completedAt := *rollout.CompletedAt
+		copied.CompletedAt = &completedAt
+	}
+	copied.Progress = nil
+	return &copied
+}
@@ -0,0 +1,103 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// GroupRepository implementa ports.GroupRepository en memoria.
+type GroupRepository struct {
+	mu     sync.RWMutex
+	groups map[string]*models.DeviceGroup
+}
+
+// NewGroupRepository crea un repositorio de grupos vacío.
+func NewGroupRepository() *GroupRepository {
+	return &GroupRepository{
+		groups: make(map[string]*models.DeviceGroup),
+	}
+}
+
+// Create guarda un grupo nuevo.
+func (r *GroupRepository) Create(ctx context.Context, group *models.DeviceGroup) error {
+	if !visible(ctx, group.OrgID) {
+		return fmt.Errorf("%w: group belongs to another organization", models.ErrForbidden)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.groups[group.GroupID]; exists {
+		return fmt.Errorf("%w: group %s already exists", models.ErrDynamoDBOperation, group.GroupID)
+	}
+
+	r.groups[group.GroupID] = group.Clone()
+	return nil
+}
+
+// GetByID retorna una copia del grupo.
+func (r *GroupRepository) GetByID(ctx context.Context, groupID string) (*models.DeviceGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	group, ok := r.groups[groupID]
+	if !ok || !visible(ctx, group.OrgID) {
+		return nil, models.ErrGroupNotFound
+	}
+	return group.Clone(), nil
+}
+
+// Update reemplaza un grupo existente. La organización del grupo no cambia.
+func (r *GroupRepository) Update(ctx context.Context, group *models.DeviceGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.groups[group.GroupID]
+	if !ok || !visible(ctx, stored.OrgID) || group.OrgID != stored.OrgID {
+		return models.ErrGroupNotFound
+	}
+
+	r.groups[group.GroupID] = group.Clone()
+	return nil
+}
+
+// Delete elimina el grupo.
+func (r *GroupRepository) Delete(ctx context.Context, groupID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	group, ok := r.groups[groupID]
+	if !ok || !visible(ctx, group.OrgID) {
+		return models.ErrGroupNotFound
+	}
+
+	delete(r.groups, groupID)
+	return nil
+}
+
+// List retorna los grupos de la organización ordenados por nombre y paginados.
+func (r *GroupRepository) List(ctx context.Context, req *models.ListGroupsRequest) ([]*models.DeviceGroup, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := make([]*models.DeviceGroup, 0, len(r.groups))
+	for _, group := range r.groups {
+		if !visible(ctx, group.OrgID) {
+			continue
+		}
+		groups = append(groups, group.Clone())
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Name != groups[j].Name {
+			return groups[i].Name < groups[j].Name
+		}
+		return groups[i].GroupID < groups[j].GroupID
+	})
+
+	return paginate(groups, req.Offset, req.Limit), len(groups), nil
+}
@@ -0,0 +1,128 @@
+package local
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/rs/zerolog/log"
+)
+
+// IoTPublisher implementa ports.IoTPublisher guardando las decisiones en memoria.
+type IoTPublisher struct {
+	mu       sync.Mutex
+	messages []*models.DecisionMessage
+	deltas   []*models.ShadowDeltaMessage
+	commands []*models.CommandMessage
+	updates  []*models.FirmwareUpdateMessage
+}
+
+// NewIoTPublisher crea un publisher en memoria vacío.
+func NewIoTPublisher() *IoTPublisher {
+	return &IoTPublisher{}
+}
+
+// PublishDecision guarda el mensaje.
+func (p *IoTPublisher) PublishDecision(_ context.Context, message *models.DecisionMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored := *message
+	p.messages = append(p.messages, &stored)
+
+	log.Debug().
+		Str("job_id", message.JobID).
+		Str("device_id", message.DeviceID).
+		Msg("Decision published to local IoT publisher")
+
+	return nil
+}
+
+// PublishShadowDelta guarda el delta.
+func (p *IoTPublisher) PublishShadowDelta(_ context.Context, message *models.ShadowDeltaMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored := *message
+	p.deltas = append(p.deltas, &stored)
+
+	log.Debug().
+		Str("device_id", message.DeviceID).
+		Int64("version", message.Version).
+		Msg("Shadow delta published to local IoT publisher")
+
+	return nil
+}
+
+// PublishCommand guarda el comando.
+func (p *IoTPublisher) PublishCommand(_ context.Context, message *models.CommandMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored := *message
+	p.commands = append(p.commands, &stored)
+
+	log.Debug().
+		Str("device_id", message.DeviceID).
+		Str("command_id", message.CommandID).
+		Msg("Command published to local IoT publisher")
+
+	return nil
+}
+
+// PublishFirmwareUpdate guarda la actualización OTA.
+func (p *IoTPublisher) PublishFirmwareUpdate(_ context.Context, message *models.FirmwareUpdateMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored := *message
+	p.updates = append(p.updates, &stored)
+
+	log.Debug().
+		Str("device_id", message.DeviceID).
+		Str("rollout_id", message.RolloutID).
+		Str("version", message.Version).
+		Msg("Firmware update published to local IoT publisher")
+
+	return nil
+}
+
+// Messages retorna una copia de las decisiones publicadas.
+func (p *IoTPublisher) Messages() []*models.DecisionMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	messages := make([]*models.DecisionMessage, len(p.messages))
+	copy(messages, p.messages)
+	return messages
+}
+
+// Deltas retorna una copia de los deltas de shadow publicados.
+func (p *IoTPublisher) Deltas() []*models.ShadowDeltaMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	deltas := make([]*models.ShadowDeltaMessage, len(p.deltas))
+	copy(deltas, p.deltas)
+	return deltas
+}
+
+// Commands retorna una copia de los comandos publicados.
+func (p *IoTPublisher) Commands() []*models.CommandMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	commands := make([]*models.CommandMessage, len(p.commands))
+	copy(commands, p.commands)
+	return commands
+}
+
+// FirmwareUpdates retorna una copia de las actualizaciones OTA publicadas.
+func (p *IoTPublisher) FirmwareUpdates() []*models.FirmwareUpdateMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	updates := make([]*models.FirmwareUpdateMessage, len(p.updates))
+	copy(updates, p.updates)
+	return updates
+}
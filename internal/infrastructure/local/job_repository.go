@@ -0,0 +1,119 @@
+// Package local provides in-process implementations of the domain ports.
+// They keep state in memory and are used for local development and tests
+// until the AWS adapters (DynamoDB, S3, SQS, IoT Core) are wired in.
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// JobRepository implementa ports.JobRepository en memoria.
+type JobRepository struct {
+	mu   sync.RWMutex
+	jobs map[string]*models.Job
+}
+
+// NewJobRepository crea un repositorio de jobs vacío.
+func NewJobRepository() *JobRepository {
+	return &JobRepository{
+		jobs: make(map[string]*models.Job),
+	}
+}
+
+// Create guarda un job nuevo.
+func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
+	if !visible(ctx, job.OrgID) {
+		return fmt.Errorf("%w: job belongs to another organization", models.ErrForbidden)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[job.JobID]; exists {
+		return fmt.Errorf("%w: job %s already exists", models.ErrDynamoDBOperation, job.JobID)
+	}
+
+	stored := *job
+	r.jobs[job.JobID] = &stored
+	return nil
+}
+
+// GetByID retorna una copia del job.
+func (r *JobRepository) GetByID(ctx context.Context, jobID string) (*models.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[jobID]
+	if !ok || !visible(ctx, job.OrgID) {
+		return nil, models.ErrJobNotFound
+	}
+
+	found := *job
+	return &found, nil
+}
+
+// Update reemplaza un job existente. La organización del job no cambia.
+func (r *JobRepository) Update(ctx context.Context, job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[job.JobID]
+	if !ok || !visible(ctx, stored.OrgID) || job.OrgID != stored.OrgID {
+		return models.ErrJobNotFound
+	}
+
+	updated := *job
+	r.jobs[job.JobID] = &updated
+	return nil
+}
+
+// List filtra por organización, device y status, ordena por fecha de creación (más reciente primero) y pagina.
+func (r *JobRepository) List(ctx context.Context, req *models.ListJobsRequest) ([]*models.Job, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*models.Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		if !visible(ctx, job.OrgID) {
+			continue
+		}
+		if req.DeviceID != "" && job.DeviceID != req.DeviceID {
+			continue
+		}
+		if req.Status != "" && job.Status != req.Status {
+			continue
+		}
+		if req.PendingReview && !job.NeedsReview() {
+			continue
+		}
+		found := *job
+		matched = append(matched, &found)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	return paginate(matched, req.Offset, req.Limit), len(matched), nil
+}
+
+// paginate retorna la ventana [offset, offset+limit) del slice.
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
@@ -0,0 +1,79 @@
+package local
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// OrganizationRepository implementa ports.OrganizationRepository en memoria.
+type OrganizationRepository struct {
+	mu   sync.RWMutex
+	orgs map[string]*models.Organization
+}
+
+// NewOrganizationRepository crea un repositorio de organizaciones vacío.
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{
+		orgs: make(map[string]*models.Organization),
+	}
+}
+
+// Create guarda una organización nueva.
+func (r *OrganizationRepository) Create(_ context.Context, org *models.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orgs[org.OrgID]; exists {
+		return models.ErrOrganizationExists
+	}
+
+	r.orgs[org.OrgID] = org.Clone()
+	return nil
+}
+
+// GetByID retorna una copia de la organización.
+func (r *OrganizationRepository) GetByID(ctx context.Context, orgID string) (*models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	org, ok := r.orgs[orgID]
+	if !ok || !visible(ctx, orgID) {
+		return nil, models.ErrOrganizationNotFound
+	}
+	return org.Clone(), nil
+}
+
+// Update reemplaza una organización existente.
+func (r *OrganizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.orgs[org.OrgID]; !ok || !visible(ctx, org.OrgID) {
+		return models.ErrOrganizationNotFound
+	}
+
+	r.orgs[org.OrgID] = org.Clone()
+	return nil
+}
+
+// List retorna las organizaciones visibles ordenadas por ID y paginadas.
+func (r *OrganizationRepository) List(ctx context.Context, req *models.ListOrganizationsRequest) ([]*models.Organization, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orgs := make([]*models.Organization, 0, len(r.orgs))
+	for orgID, org := range r.orgs {
+		if visible(ctx, orgID) {
+			orgs = append(orgs, org.Clone())
+		}
+	}
+
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].OrgID < orgs[j].OrgID
+	})
+
+	return paginate(orgs, req.Offset, req.Limit), len(orgs), nil
+}
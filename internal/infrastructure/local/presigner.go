@@ -0,0 +1,35 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Presigner implementa ports.S3Presigner generando URLs mock con el formato de S3.
+// No firma nada: sirve para desarrollo local sin credenciales AWS.
+type Presigner struct {
+	region string
+}
+
+// NewPresigner crea un presigner mock para la región indicada.
+func NewPresigner(region string) *Presigner {
+	return &Presigner{
+		region: region,
+	}
+}
+
+// GeneratePresignedPutURL retorna una URL con forma de URL prefirmada de S3.
+func (p *Presigner) GeneratePresignedPutURL(_ context.Context, bucket, key string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Expires=%d",
+		bucket,
+		p.region,
+		key,
+		int(expiry.Seconds()),
+	), nil
+}
+
+// GeneratePresignedGetURL retorna una URL de descarga con forma de URL prefirmada de S3.
+func (p *Presigner) GeneratePresignedGetURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error) {
+	return p.GeneratePresignedPutURL(ctx, bucket, key, expiry)
+}
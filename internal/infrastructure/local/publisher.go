@@ -0,0 +1,46 @@
+package local
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Publisher implementa ports.SQSPublisher guardando los mensajes en memoria.
+type Publisher struct {
+	mu       sync.Mutex
+	messages []*models.Job
+}
+
+// NewPublisher crea una cola en memoria vacía.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// PublishClassificationJob agrega el job a la cola en memoria.
+func (p *Publisher) PublishClassificationJob(_ context.Context, job *models.Job) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	message := *job
+	p.messages = append(p.messages, &message)
+
+	log.Debug().
+		Str("job_id", job.JobID).
+		Int("queue_depth", len(p.messages)).
+		Msg("Classification job published to local queue")
+
+	return nil
+}
+
+// Messages retorna una copia de los jobs publicados.
+func (p *Publisher) Messages() []*models.Job {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	messages := make([]*models.Job, len(p.messages))
+	copy(messages, p.messages)
+	return messages
+}
@@ -0,0 +1,14 @@
+package local
+
+import (
+	"context"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// visible retorna true si un registro de orgID es visible con el scope del context.
+// Sin scope (super-admin o procesos internos) todo es visible.
+func visible(ctx context.Context, orgID string) bool {
+	scope := models.OrgScope(ctx)
+	return scope == "" || scope == orgID
+}
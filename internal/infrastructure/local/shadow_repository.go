@@ -0,0 +1,42 @@
+package local
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// ShadowRepository implementa ports.ShadowRepository en memoria.
+type ShadowRepository struct {
+	mu      sync.RWMutex
+	shadows map[string]*models.DeviceShadow
+}
+
+// NewShadowRepository crea un repositorio de shadows vacío.
+func NewShadowRepository() *ShadowRepository {
+	return &ShadowRepository{
+		shadows: make(map[string]*models.DeviceShadow),
+	}
+}
+
+// GetByDeviceID retorna una copia del shadow, o nil si no existe.
+func (r *ShadowRepository) GetByDeviceID(_ context.Context, deviceID string) (*models.DeviceShadow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shadow, ok := r.shadows[deviceID]
+	if !ok {
+		return nil, nil
+	}
+	return shadow.Clone(), nil
+}
+
+// Save guarda una copia del shadow.
+func (r *ShadowRepository) Save(_ context.Context, shadow *models.DeviceShadow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shadows[shadow.DeviceID] = shadow.Clone()
+	return nil
+}
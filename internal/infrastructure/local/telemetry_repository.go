@@ -0,0 +1,171 @@
+package local
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// seriesKey identifica una serie (dispositivo + métrica).
+type seriesKey struct {
+	deviceID string
+	metric   models.TelemetryMetric
+}
+
+// series guarda una serie en sus tres resoluciones.
+type series struct {
+	raw    []models.TelemetryPoint
+	hourly map[int64]*models.TelemetryPoint
+	daily  map[int64]*models.TelemetryPoint
+}
+
+// TelemetryRepository implementa ports.TelemetryRepository en memoria.
+type TelemetryRepository struct {
+	mu     sync.RWMutex
+	series map[seriesKey]*series
+}
+
+// NewTelemetryRepository crea un histórico de telemetría vacío.
+func NewTelemetryRepository() *TelemetryRepository {
+	return &TelemetryRepository{
+		series: make(map[seriesKey]*series),
+	}
+}
+
+// Append guarda la lectura cruda y actualiza los agregados horario y diario.
+func (r *TelemetryRepository) Append(_ context.Context, reading *models.TelemetryReading) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := seriesKey{deviceID: reading.DeviceID, metric: reading.Metric}
+	s, ok := r.series[key]
+	if !ok {
+		s = &series{
+			hourly: make(map[int64]*models.TelemetryPoint),
+			daily:  make(map[int64]*models.TelemetryPoint),
+		}
+		r.series[key] = s
+	}
+
+	timestamp := reading.Timestamp.UTC()
+	point := models.NewTelemetryPoint(timestamp, reading.Value)
+
+	// Insertar manteniendo el orden por tiempo
+	index := sort.Search(len(s.raw), func(i int) bool {
+		return s.raw[i].Timestamp.After(timestamp)
+	})
+	s.raw = append(s.raw, models.TelemetryPoint{})
+	copy(s.raw[index+1:], s.raw[index:])
+	s.raw[index] = point
+
+	addToBucket(s.hourly, timestamp.Truncate(time.Hour), point)
+	addToBucket(s.daily, timestamp.Truncate(24*time.Hour), point)
+
+	return nil
+}
+
+// Query retorna los puntos de la resolución en [from, to).
+func (r *TelemetryRepository) Query(
+	_ context.Context,
+	deviceID string,
+	metric models.TelemetryMetric,
+	resolution models.TelemetryResolution,
+	from, to time.Time,
+) ([]models.TelemetryPoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.series[seriesKey{deviceID: deviceID, metric: metric}]
+	if !ok {
+		return []models.TelemetryPoint{}, nil
+	}
+
+	var source []models.TelemetryPoint
+	switch resolution {
+	case models.ResolutionHourly:
+		source = sortedBuckets(s.hourly)
+	case models.ResolutionDaily:
+		source = sortedBuckets(s.daily)
+	default:
+		source = s.raw
+	}
+
+	points := make([]models.TelemetryPoint, 0, len(source))
+	for _, point := range source {
+		if point.Timestamp.Before(from) || !point.Timestamp.Before(to) {
+			continue
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// DeleteBefore elimina los puntos de la resolución anteriores a cutoff.
+func (r *TelemetryRepository) DeleteBefore(
+	_ context.Context,
+	resolution models.TelemetryResolution,
+	cutoff time.Time,
+) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for key, s := range r.series {
+		switch resolution {
+		case models.ResolutionHourly:
+			deleted += deleteBuckets(s.hourly, cutoff)
+		case models.ResolutionDaily:
+			deleted += deleteBuckets(s.daily, cutoff)
+		default:
+			index := sort.Search(len(s.raw), func(i int) bool {
+				return !s.raw[i].Timestamp.Before(cutoff)
+			})
+			deleted += index
+			s.raw = append([]models.TelemetryPoint(nil), s.raw[index:]...)
+		}
+
+		if len(s.raw) == 0 && len(s.hourly) == 0 && len(s.daily) == 0 {
+			delete(r.series, key)
+		}
+	}
+
+	return deleted, nil
+}
+
+// addToBucket suma el punto al bucket que empieza en start.
+func addToBucket(buckets map[int64]*models.TelemetryPoint, start time.Time, point models.TelemetryPoint) {
+	bucket, ok := buckets[start.Unix()]
+	if !ok {
+		bucket = &models.TelemetryPoint{Timestamp: start}
+		buckets[start.Unix()] = bucket
+	}
+	bucket.Merge(point)
+}
+
+// sortedBuckets retorna los buckets ordenados por tiempo.
+func sortedBuckets(buckets map[int64]*models.TelemetryPoint) []models.TelemetryPoint {
+	points := make([]models.TelemetryPoint, 0, len(buckets))
+	for _, bucket := range buckets {
+		points = append(points, *bucket)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+	return points
+}
+
+// deleteBuckets elimina los buckets que empiezan antes de cutoff.
+func deleteBuckets(buckets map[int64]*models.TelemetryPoint, cutoff time.Time) int {
+	deleted := 0
+	for start, bucket := range buckets {
+		if bucket.Timestamp.Before(cutoff) {
+			delete(buckets, start)
+			deleted++
+		}
+	}
+	return deleted
+}
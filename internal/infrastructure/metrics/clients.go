@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/ports"
+)
+
+// Los decoradores de este archivo envuelven los puertos de clientes externos y registran
+// cada llamada en orchestrator_outbound_* sin cambiar su comportamiento.
+
+// presigner instrumenta un ports.S3Presigner.
+type presigner struct {
+	next    ports.S3Presigner
+	metrics *Registry
+}
+
+// InstrumentPresigner wraps an S3 presigner with outbound metrics (client "s3").
+func InstrumentPresigner(next ports.S3Presigner, metrics *Registry) ports.S3Presigner {
+	return &presigner{next: next, metrics: metrics}
+}
+
+// GeneratePresignedPutURL delega y registra la llamada.
+func (p *presigner) GeneratePresignedPutURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error) {
+	started := time.Now()
+	url, err := p.next.GeneratePresignedPutURL(ctx, bucket, key, expiry)
+	p.metrics.observeOutbound("s3", "presign_put", started, err)
+	return url, err
+}
+
+// GeneratePresignedGetURL delega y registra la llamada.
+func (p *presigner) GeneratePresignedGetURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error) {
+	started := time.Now()
+	url, err := p.next.GeneratePresignedGetURL(ctx, bucket, key, expiry)
+	p.metrics.observeOutbound("s3", "presign_get", started, err)
+	return url, err
+}
+
+// sqsPublisher instrumenta un ports.SQSPublisher.
+type sqsPublisher struct {
+	next    ports.SQSPublisher
+	metrics *Registry
+}
+
+// InstrumentSQSPublisher wraps the classification queue publisher with outbound metrics (client "sqs").
+func InstrumentSQSPublisher(next ports.SQSPublisher, metrics *Registry) ports.SQSPublisher {
+	return &sqsPublisher{next: next, metrics: metrics}
+}
+
+// PublishClassificationJob delega y registra la llamada.
+func (p *sqsPublisher) PublishClassificationJob(ctx context.Context, job *models.Job) error {
+	started := time.Now()
+	err := p.next.PublishClassificationJob(ctx, job)
+	p.metrics.observeOutbound("sqs", "publish_classification_job", started, err)
+	return err
+}
+
+// iotPublisher instrumenta un ports.IoTPublisher.
+type iotPublisher struct {
+	next    ports.IoTPublisher
+	metrics *Registry
+}
+
+// InstrumentIoTPublisher wraps the device publisher (MQTT or local) with outbound metrics (client "iot").
+func InstrumentIoTPublisher(next ports.IoTPublisher, metrics *Registry) ports.IoTPublisher {
+	return &iotPublisher{next: next, metrics: metrics}
+}
+
+// PublishDecision delega y registra la llamada.
+func (p *iotPublisher) PublishDecision(ctx context.Context, message *models.DecisionMessage) error {
+	started := time.Now()
+	err := p.next.PublishDecision(ctx, message)
+	p.metrics.observeOutbound("iot", "publish_decision", started, err)
+	return err
+}
+
+// PublishShadowDelta delega y registra la llamada.
+func (p *iotPublisher) PublishShadowDelta(ctx context.Context, message *models.ShadowDeltaMessage) error {
+	started := time.Now()
+	err := p.next.PublishShadowDelta(ctx, message)
+	p.metrics.observeOutbound("iot", "publish_shadow_delta", started, err)
+	return err
+}
+
+// PublishCommand delega y registra la llamada.
+func (p *iotPublisher) PublishCommand(ctx context.Context, message *models.CommandMessage) error {
+	started := time.Now()
+	err := p.next.PublishCommand(ctx, message)
+	p.metrics.observeOutbound("iot", "publish_command", started, err)
+	return err
+}
+
+// PublishFirmwareUpdate delega y registra la llamada.
+func (p *iotPublisher) PublishFirmwareUpdate(ctx context.Context, message *models.FirmwareUpdateMessage) error {
+	started := time.Now()
+	err := p.next.PublishFirmwareUpdate(ctx, message)
+	p.metrics.observeOutbound("iot", "publish_firmware_update", started, err)
+	return err
+}
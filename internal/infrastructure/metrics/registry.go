@@ -0,0 +1,184 @@
+// Package metrics implements the Prometheus instrumentation of the orchestrator: HTTP requests,
+// the classification pipeline (ports.PipelineMetrics), outbound clients (S3, SQS, IoT) and the Go runtime.
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+/*
+╔════════════════════════════════════════════════════════════════╗
+║                                                                ║
+║  REGISTRY.GO - MÉTRICAS PROMETHEUS                             ║
+║                                                                ║
+║  - orchestrator_http_*       requests, latencia, en curso      ║
+║  - orchestrator_jobs_*       jobs creados y terminados         ║
+║  - orchestrator_job_*        decisiones y procesamiento        ║
+║  - orchestrator_outbound_*   llamadas a S3, SQS e IoT          ║
+║  - go_* / process_*          runtime y proceso                 ║
+║                                                                ║
+║  Se exponen en texto Prometheus en METRICS_PORT (ver Handler)  ║
+║  y resumidas en JSON en GET /metrics (ver Summary).            ║
+║                                                                ║
+╚════════════════════════════════════════════════════════════════╝
+*/
+
+const namespace = "orchestrator"
+
+// Registry agrupa los collectors del servicio en un registro propio (no el global de Prometheus).
+type Registry struct {
+	registry *prometheus.Registry
+
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	httpInFlight    prometheus.Gauge
+	jobsCreated     *prometheus.CounterVec
+	jobsFinished    *prometheus.CounterVec
+	jobDecisions    *prometheus.CounterVec
+	jobProcessing   *prometheus.HistogramVec
+	outboundCalls   *prometheus.CounterVec
+	outboundLatency *prometheus.HistogramVec
+
+	// maxHTTPNanos - Latencia máxima observada (solo para el resumen JSON)
+	maxHTTPNanos atomic.Int64
+
+	startTime time.Time
+}
+
+// NewRegistry crea el registro con los collectors del servicio y los del runtime de Go.
+func NewRegistry() *Registry {
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests being served.",
+		}),
+
+		jobsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_created_total",
+			Help:      "Classification jobs created by initial status (pending or processing).",
+		}, []string{"status"}),
+
+		jobsFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_finished_total",
+			Help:      "Classification jobs finished by final status (completed or failed).",
+		}, []string{"status"}),
+
+		jobDecisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "job_decisions_total",
+			Help:      "Decisions of completed jobs by action.",
+		}, []string{"action"}),
+
+		jobProcessing: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_processing_duration_seconds",
+			Help:      "Time from enqueue to classification result by final status.",
+			Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		}, []string{"status"}),
+
+		outboundCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "outbound_requests_total",
+			Help:      "Calls to external services by client, operation and result (success or error).",
+		}, []string{"client", "operation", "result"}),
+
+		outboundLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "outbound_request_duration_seconds",
+			Help:      "Latency of calls to external services by client and operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"client", "operation"}),
+
+		startTime: time.Now(),
+	}
+
+	r.registry.MustRegister(
+		r.httpRequests, r.httpDuration, r.httpInFlight,
+		r.jobsCreated, r.jobsFinished, r.jobDecisions, r.jobProcessing,
+		r.outboundCalls, r.outboundLatency,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+// Handler serves every metric in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
+
+// RequestStarted counts an HTTP request in flight; the returned function observes its end.
+// route is the route template (e.g. "/api/v1/devices/:device_id"), never the raw path.
+func (r *Registry) RequestStarted(method, route string) func(status int) {
+	started := time.Now()
+	r.httpInFlight.Inc()
+
+	return func(status int) {
+		elapsed := time.Since(started)
+		r.httpInFlight.Dec()
+		r.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+		r.httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+
+		for {
+			current := r.maxHTTPNanos.Load()
+			if elapsed.Nanoseconds() <= current || r.maxHTTPNanos.CompareAndSwap(current, elapsed.Nanoseconds()) {
+				return
+			}
+		}
+	}
+}
+
+// JobCreated counts a new job by its initial status.
+func (r *Registry) JobCreated(job *models.Job) {
+	r.jobsCreated.WithLabelValues(string(job.Status)).Inc()
+}
+
+// JobFinished counts a completed or failed job, its decision and its processing time.
+func (r *Registry) JobFinished(job *models.Job) {
+	status := string(job.Status)
+	r.jobsFinished.WithLabelValues(status).Inc()
+
+	if job.Decision != nil {
+		r.jobDecisions.WithLabelValues(job.Decision.Action).Inc()
+	}
+	if duration := job.GetProcessingDuration(); duration != nil {
+		r.jobProcessing.WithLabelValues(status).Observe(math.Max(duration.Seconds(), 0))
+	}
+}
+
+// observeOutbound registra una llamada a un servicio externo.
+func (r *Registry) observeOutbound(client, operation string, started time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	r.outboundCalls.WithLabelValues(client, operation, result).Inc()
+	r.outboundLatency.WithLabelValues(client, operation).Observe(time.Since(started).Seconds())
+}
@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Summary es el resumen JSON de las métricas (GET /metrics).
+type Summary struct {
+	Requests  RequestSummary             `json:"requests"`
+	Latency   LatencySummary             `json:"latency"`
+	Jobs      JobSummary                 `json:"jobs"`
+	Outbound  map[string]OutboundSummary `json:"outbound"`
+	Resources ResourceSummary            `json:"resources"`
+}
+
+// RequestSummary - Requests HTTP desde el arranque. Errors son las respuestas 4xx y 5xx.
+type RequestSummary struct {
+	Total         uint64  `json:"total"`
+	Success       uint64  `json:"success"`
+	Errors        uint64  `json:"errors"`
+	InFlight      float64 `json:"in_flight"`
+	RatePerMinute float64 `json:"rate_per_minute"`
+}
+
+// LatencySummary - Latencia HTTP en milisegundos. Los percentiles se interpolan en los buckets
+// del histograma, igual que histogram_quantile de Prometheus.
+type LatencySummary struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// JobSummary - Jobs de clasificación desde el arranque.
+type JobSummary struct {
+	Created   uint64 `json:"created"`
+	Completed uint64 `json:"completed"`
+	Failed    uint64 `json:"failed"`
+}
+
+// OutboundSummary - Llamadas a un servicio externo (s3, sqs, iot).
+type OutboundSummary struct {
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+}
+
+// ResourceSummary - Estado del runtime de Go.
+type ResourceSummary struct {
+	Goroutines int     `json:"goroutines"`
+	MemoryMB   float64 `json:"memory_mb"`
+	HeapMB     float64 `json:"heap_mb"`
+	GCCycles   uint32  `json:"gc_cycles"`
+}
+
+// Summary aggregates the collectors into the JSON summary.
+func (r *Registry) Summary() (*Summary, error) {
+	families, err := r.registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	summary := &Summary{Outbound: make(map[string]OutboundSummary)}
+
+	for _, metric := range byName[namespace+"_http_requests_total"].GetMetric() {
+		count := uint64(metric.GetCounter().GetValue())
+		summary.Requests.Total += count
+		if status, _ := strconv.Atoi(label(metric, "status")); status >= 400 {
+			summary.Requests.Errors += count
+		} else {
+			summary.Requests.Success += count
+		}
+	}
+	for _, metric := range byName[namespace+"_http_requests_in_flight"].GetMetric() {
+		summary.Requests.InFlight = metric.GetGauge().GetValue()
+	}
+	if minutes := time.Since(r.startTime).Minutes(); minutes > 0 {
+		summary.Requests.RatePerMinute = round(float64(summary.Requests.Total) / minutes)
+	}
+
+	// La interpolación no conoce la distribución dentro del bucket: se limita al máximo observado
+	buckets, count := mergeBuckets(byName[namespace+"_http_request_duration_seconds"])
+	maxMillis := float64(r.maxHTTPNanos.Load()) / float64(time.Millisecond)
+	quantile := func(q float64) float64 {
+		return round(math.Min(bucketQuantile(q, buckets, count)*1000, maxMillis))
+	}
+	summary.Latency = LatencySummary{
+		P50: quantile(0.50),
+		P95: quantile(0.95),
+		P99: quantile(0.99),
+		Max: round(maxMillis),
+	}
+
+	for _, metric := range byName[namespace+"_jobs_created_total"].GetMetric() {
+		summary.Jobs.Created += uint64(metric.GetCounter().GetValue())
+	}
+	for _, metric := range byName[namespace+"_jobs_finished_total"].GetMetric() {
+		switch label(metric, "status") {
+		case "completed":
+			summary.Jobs.Completed += uint64(metric.GetCounter().GetValue())
+		case "failed":
+			summary.Jobs.Failed += uint64(metric.GetCounter().GetValue())
+		}
+	}
+
+	for _, metric := range byName[namespace+"_outbound_requests_total"].GetMetric() {
+		client := label(metric, "client")
+		outbound := summary.Outbound[client]
+		outbound.Requests += uint64(metric.GetCounter().GetValue())
+		if label(metric, "result") == "error" {
+			outbound.Errors += uint64(metric.GetCounter().GetValue())
+		}
+		summary.Outbound[client] = outbound
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	summary.Resources = ResourceSummary{
+		Goroutines: runtime.NumGoroutine(),
+		MemoryMB:   round(float64(memStats.Sys) / (1 << 20)),
+		HeapMB:     round(float64(memStats.HeapAlloc) / (1 << 20)),
+		GCCycles:   memStats.NumGC,
+	}
+
+	return summary, nil
+}
+
+// bucket es un bucket acumulado del histograma.
+type bucket struct {
+	upperBound float64
+	count      uint64
+}
+
+// mergeBuckets suma los buckets de todas las series del histograma (todas usan los mismos límites).
+func mergeBuckets(family *dto.MetricFamily) ([]bucket, uint64) {
+	counts := make(map[float64]uint64)
+	var total uint64
+	for _, metric := range family.GetMetric() {
+		histogram := metric.GetHistogram()
+		total += histogram.GetSampleCount()
+		for _, b := range histogram.GetBucket() {
+			counts[b.GetUpperBound()] += b.GetCumulativeCount()
+		}
+	}
+
+	buckets := make([]bucket, 0, len(counts))
+	for upperBound, count := range counts {
+		buckets = append(buckets, bucket{upperBound: upperBound, count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].upperBound < buckets[j].upperBound })
+	return buckets, total
+}
+
+// bucketQuantile interpola el cuantil q dentro del bucket que lo contiene. Si cae por encima
+// del último límite retorna ese límite.
+func bucketQuantile(q float64, buckets []bucket, total uint64) float64 {
+	if total == 0 || len(buckets) == 0 {
+		return 0
+	}
+
+	rank := q * float64(total)
+	lowerBound, lowerCount := 0.0, uint64(0)
+	for _, b := range buckets {
+		if float64(b.count) >= rank {
+			if b.count == lowerCount {
+				return b.upperBound
+			}
+			return lowerBound + (b.upperBound-lowerBound)*(rank-float64(lowerCount))/float64(b.count-lowerCount)
+		}
+		lowerBound, lowerCount = b.upperBound, b.count
+	}
+	return buckets[len(buckets)-1].upperBound
+}
+
+// label retorna el valor de un label de la serie.
+func label(metric *dto.Metric, name string) string {
+	for _, pair := range metric.GetLabel() {
+		if pair.GetName() == name {
+			return pair.GetValue()
+		}
+	}
+	return ""
+}
+
+// round redondea a dos decimales.
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,193 @@
+// Package mqtt contiene el adaptador MQTT 3.1.1 usado para hablar con los dispositivos.
+// Es compatible con AWS IoT Core (TLS con certificado de cliente) y con brokers
+// estándar como Mosquitto.
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+)
+
+// MessageHandler procesa un mensaje recibido en topic.
+type MessageHandler func(topic string, payload []byte)
+
+// subscription es una suscripción que se renueva en cada reconexión.
+type subscription struct {
+	qos     byte
+	handler MessageHandler
+}
+
+// Client es una conexión MQTT 3.1.1 con reconexión automática.
+type Client struct {
+	config config.IoTConfig
+	client paho.Client
+
+	// Con clean session el broker olvida las suscripciones al desconectar,
+	// así que se guardan aquí y se renuevan en onConnect.
+	mu            sync.Mutex
+	subscriptions map[string]subscription
+}
+
+// NewClient crea un cliente con la configuración de IoT (aún no conecta).
+func NewClient(cfg config.IoTConfig) (*Client, error) {
+	broker := BrokerURL(cfg.Endpoint)
+	c := &Client{
+		config:        cfg,
+		subscriptions: make(map[string]subscription),
+	}
+
+	options := paho.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(cfg.ClientID).
+		SetProtocolVersion(4). // MQTT 3.1.1
+		SetCleanSession(true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetOrderMatters(false).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			log.Warn().Err(err).Str("broker", broker).Msg("MQTT connection lost")
+		}).
+		SetOnConnectHandler(func(_ paho.Client) {
+			log.Info().Str("broker", broker).Msg("MQTT connected")
+			c.resubscribe()
+		})
+
+	if cfg.Username != "" {
+		options.SetUsername(cfg.Username)
+		options.SetPassword(cfg.Password)
+	}
+
+	if strings.HasPrefix(broker, "ssl://") || strings.HasPrefix(broker, "tls://") {
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		options.SetTLSConfig(tlsConfig)
+	}
+
+	c.client = paho.NewClient(options)
+	return c, nil
+}
+
+// Connect abre la conexión y espera hasta ConnectTimeout.
+func (c *Client) Connect(ctx context.Context) error {
+	return c.wait(ctx, c.client.Connect(), c.config.ConnectTimeout)
+}
+
+// Publish publica payload en topic y espera el ack del broker (PUBACK en QoS 1).
+func (c *Client) Publish(ctx context.Context, topic string, qos byte, payload []byte) error {
+	return c.wait(ctx, c.client.Publish(topic, qos, false, payload), c.config.PublishTimeout)
+}
+
+// Subscribe registra handler para filter. Si el cliente está conectado la suscripción
+// se hace de inmediato; si no, al conectar. En ambos casos se renueva tras cada reconexión.
+func (c *Client) Subscribe(ctx context.Context, filter string, qos byte, handler MessageHandler) error {
+	c.mu.Lock()
+	c.subscriptions[filter] = subscription{qos: qos, handler: handler}
+	c.mu.Unlock()
+
+	if !c.client.IsConnectionOpen() {
+		return nil
+	}
+	return c.wait(ctx, c.client.Subscribe(filter, qos, wrapHandler(handler)), c.config.PublishTimeout)
+}
+
+// resubscribe renueva todas las suscripciones registradas (se llama en cada conexión).
+func (c *Client) resubscribe() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for filter, sub := range c.subscriptions {
+		// No se puede esperar el token dentro del OnConnectHandler: el resultado se registra en otra goroutine
+		token := c.client.Subscribe(filter, sub.qos, wrapHandler(sub.handler))
+		go func(filter string, token paho.Token) {
+			if token.Wait() && token.Error() != nil {
+				log.Error().Err(token.Error()).Str("filter", filter).Msg("MQTT subscription failed")
+				return
+			}
+			log.Info().Str("filter", filter).Msg("MQTT subscribed")
+		}(filter, token)
+	}
+}
+
+// wrapHandler adapta un MessageHandler al callback de paho.
+func wrapHandler(handler MessageHandler) paho.MessageHandler {
+	return func(_ paho.Client, message paho.Message) {
+		handler(message.Topic(), message.Payload())
+	}
+}
+
+// Close desconecta el cliente esperando hasta 250ms a que terminen los envíos pendientes.
+func (c *Client) Close() {
+	c.client.Disconnect(250)
+}
+
+// wait espera el token hasta timeout o hasta que se cancele ctx.
+func (c *Client) wait(ctx context.Context, token paho.Token, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-timer.C:
+		return fmt.Errorf("mqtt operation timed out after %s", timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// BrokerURL normaliza el endpoint: un host sin esquema se trata como IoT Core (ssl://host:8883).
+func BrokerURL(endpoint string) string {
+	if strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	if !strings.Contains(endpoint, ":") {
+		endpoint += ":8883"
+	}
+	return "ssl://" + endpoint
+}
+
+// newTLSConfig carga la CA y el certificado de cliente configurados.
+func newTLSConfig(cfg config.IoTConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading IoT CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("IoT CA file %s has no valid certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading IoT client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
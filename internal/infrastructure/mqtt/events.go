@@ -0,0 +1,176 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/rs/zerolog/log"
+)
+
+// EventHandler procesa un evento de dispositivo (implementado por services.DeviceEventService).
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event *models.DeviceEvent) (*models.DeviceEventResult, error)
+}
+
+// DeadLetter es el mensaje que se publica en el dead-letter topic cuando un payload no se puede procesar.
+type DeadLetter struct {
+	Topic      string    `json:"topic"`
+	Payload    string    `json:"payload"`
+	Error      string    `json:"error"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// EventSubscriber recibe eventos de dispositivos por MQTT y los procesa igual que
+// POST /api/v1/webhooks/device-event.
+type EventSubscriber struct {
+	client  *Client
+	handler EventHandler
+}
+
+// NewEventSubscriber crea un subscriber que usa el topic, grupo y dead-letter de la configuración del cliente.
+func NewEventSubscriber(client *Client, handler EventHandler) *EventSubscriber {
+	return &EventSubscriber{
+		client:  client,
+		handler: handler,
+	}
+}
+
+// Start se suscribe a los eventos. La suscripción se mantiene tras reconexiones hasta Close del cliente.
+func (s *EventSubscriber) Start(ctx context.Context) error {
+	cfg := s.client.config
+	filter := SubscriptionFilter(cfg.EventsTopic, cfg.SharedGroup)
+
+	if err := s.client.Subscribe(ctx, filter, cfg.QoS, s.handleMessage); err != nil {
+		return fmt.Errorf("%w: subscribing to %s: %v", models.ErrIoTOperation, filter, err)
+	}
+
+	log.Info().
+		Str("filter", filter).
+		Str("dead_letter_topic", cfg.DeadLetterTopic).
+		Msg("Listening for device events over MQTT")
+
+	return nil
+}
+
+// handleMessage decodifica y procesa un evento.
+// Los payloads mal formados van al dead-letter topic; los errores al procesar un evento
+// válido (ej: dispositivo inexistente) solo se registran, igual que en el webhook.
+func (s *EventSubscriber) handleMessage(topic string, payload []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	event, err := DecodeEvent(s.client.config.EventsTopic, topic, payload)
+	if err != nil {
+		s.deadLetter(ctx, topic, payload, err)
+		return
+	}
+
+	result, err := s.handler.HandleEvent(ctx, event)
+	if err != nil {
+		if isMalformedEvent(err) {
+			s.deadLetter(ctx, topic, payload, err)
+			return
+		}
+		log.Error().
+			Err(err).
+			Str("device_id", event.DeviceID).
+			Str("event_type", string(event.EventType)).
+			Str("topic", topic).
+			Msg("Failed to process device event")
+		return
+	}
+
+	log.Debug().
+		Str("device_id", event.DeviceID).
+		Str("event_type", string(result.EventType)).
+		Str("topic", topic).
+		Msg("Device event processed from MQTT")
+}
+
+// deadLetter publica el payload original con el motivo del rechazo.
+func (s *EventSubscriber) deadLetter(ctx context.Context, topic string, payload []byte, reason error) {
+	log.Warn().
+		Err(reason).
+		Str("topic", topic).
+		Msg("Malformed device event, sending to dead-letter topic")
+
+	message, err := json.Marshal(&DeadLetter{
+		Topic:      topic,
+		Payload:    string(payload),
+		Error:      reason.Error(),
+		ReceivedAt: time.Now(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("topic", topic).Msg("Failed to encode dead letter")
+		return
+	}
+
+	cfg := s.client.config
+	if err := s.client.Publish(ctx, cfg.DeadLetterTopic, cfg.QoS, message); err != nil {
+		log.Error().
+			Err(err).
+			Str("topic", topic).
+			Str("dead_letter_topic", cfg.DeadLetterTopic).
+			Msg("Failed to publish dead letter")
+	}
+}
+
+// DecodeEvent convierte un mensaje recibido por filter (ej: bins/+/events) en un DeviceEvent.
+// El device_id del payload es opcional; si viene debe coincidir con el del topic.
+func DecodeEvent(filter, topic string, payload []byte) (*models.DeviceEvent, error) {
+	var event models.DeviceEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+
+	topicDeviceID := TopicDeviceID(filter, topic)
+	switch {
+	case event.DeviceID == "":
+		event.DeviceID = topicDeviceID
+	case topicDeviceID != "" && event.DeviceID != topicDeviceID:
+		return nil, fmt.Errorf("%w: device_id %q does not match topic %q", models.ErrInvalidDeviceID, event.DeviceID, topic)
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// TopicDeviceID retorna el nivel de topic que corresponde al primer '+' de filter,
+// ej: filter bins/+/events y topic bins/smart-bin-001/events → smart-bin-001.
+// Retorna "" si filter no tiene '+' o topic no tiene ese nivel.
+func TopicDeviceID(filter, topic string) string {
+	levels := strings.Split(topic, "/")
+	for i, level := range strings.Split(filter, "/") {
+		if level == "+" && i < len(levels) {
+			return levels[i]
+		}
+	}
+	return ""
+}
+
+// SubscriptionFilter antepone $share/<group>/ cuando hay grupo de suscripción compartida.
+func SubscriptionFilter(topic, group string) string {
+	if group == "" {
+		return topic
+	}
+	return "$share/" + group + "/" + topic
+}
+
+// isMalformedEvent indica si err se debe al contenido del evento y no al procesamiento.
+func isMalformedEvent(err error) bool {
+	return errors.Is(err, models.ErrInvalidInput) ||
+		errors.Is(err, models.ErrInvalidDeviceID) ||
+		errors.Is(err, models.ErrMissingRequiredField) ||
+		errors.Is(err, models.ErrInvalidTimestamp) ||
+		errors.Is(err, models.ErrInvalidBatteryLevel) ||
+		errors.Is(err, models.ErrInvalidFillLevel) ||
+		errors.Is(err, models.ErrInvalidSignalStrength)
+}
@@ -0,0 +1,63 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// IoTPublisher implementa ports.IoTPublisher sobre MQTT.
+type IoTPublisher struct {
+	client *Client
+	qos    byte
+}
+
+// NewIoTPublisher crea un publisher que usa los topics y QoS de la configuración del cliente.
+func NewIoTPublisher(client *Client) *IoTPublisher {
+	return &IoTPublisher{
+		client: client,
+		qos:    client.config.QoS,
+	}
+}
+
+// PublishDecision publica la decisión en el topic del dispositivo (ej: bins/smart-bin-001/decisions).
+func (p *IoTPublisher) PublishDecision(ctx context.Context, message *models.DecisionMessage) error {
+	return p.publish(ctx, p.client.config.DecisionTopic, message.DeviceID, message)
+}
+
+// PublishShadowDelta publica el delta del shadow en el topic del dispositivo (ej: bins/smart-bin-001/shadow/delta).
+func (p *IoTPublisher) PublishShadowDelta(ctx context.Context, message *models.ShadowDeltaMessage) error {
+	return p.publish(ctx, p.client.config.ShadowDeltaTopic, message.DeviceID, message)
+}
+
+// PublishCommand publica el comando en el topic del dispositivo (ej: bins/smart-bin-001/commands).
+func (p *IoTPublisher) PublishCommand(ctx context.Context, message *models.CommandMessage) error {
+	return p.publish(ctx, p.client.config.CommandTopic, message.DeviceID, message)
+}
+
+// PublishFirmwareUpdate publica la actualización OTA en el topic del dispositivo (ej: bins/smart-bin-001/ota).
+func (p *IoTPublisher) PublishFirmwareUpdate(ctx context.Context, message *models.FirmwareUpdateMessage) error {
+	return p.publish(ctx, p.client.config.FirmwareTopic, message.DeviceID, message)
+}
+
+// publish serializa message y lo publica en el topic del dispositivo.
+func (p *IoTPublisher) publish(ctx context.Context, template, deviceID string, message interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("%w: %v", models.ErrIoTOperation, err)
+	}
+
+	if err := p.client.Publish(ctx, DeviceTopic(template, deviceID), p.qos, payload); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrIoTOperation, err)
+	}
+
+	return nil
+}
+
+// DeviceTopic reemplaza {device_id} en el template del topic.
+func DeviceTopic(template, deviceID string) string {
+	return strings.ReplaceAll(template, "{device_id}", deviceID)
+}
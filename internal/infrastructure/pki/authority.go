@@ -0,0 +1,244 @@
+// Package pki contiene la CA interna que firma los certificados X.509 de los dispositivos.
+// La llave de la CA se carga desde archivo; en desarrollo se puede generar una CA efímera.
+package pki
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// clockSkew adelanta NotBefore para tolerar relojes de dispositivos algo atrasados.
+const clockSkew = 5 * time.Minute
+
+// Authority implementa ports.CertificateAuthority con una CA en memoria.
+type Authority struct {
+	cert     *x509.Certificate
+	certPEM  string
+	key      crypto.Signer
+	validity time.Duration
+}
+
+// LoadAuthority carga el certificado y la llave de la CA (PEM).
+// La llave puede ser PKCS#8, EC o PKCS#1 (RSA).
+func LoadAuthority(certFile, keyFile string, validity time.Duration) (*Authority, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading device CA certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading device CA key: %w", err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("device CA file %s has no PEM certificate", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing device CA certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("device CA certificate %s is not a CA", certFile)
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing device CA key: %w", err)
+	}
+	if !publicKeysEqual(cert.PublicKey, key.Public()) {
+		return nil, errors.New("device CA key does not match its certificate")
+	}
+
+	return &Authority{
+		cert:     cert,
+		certPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: block.Bytes})),
+		key:      key,
+		validity: validity,
+	}, nil
+}
+
+// NewEphemeralAuthority genera una CA autofirmada que solo vive en memoria.
+// Sirve para desarrollo y tests: al reiniciar, los certificados emitidos dejan de validar.
+func NewEphemeralAuthority(validity time.Duration) (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Smart Bin Device CA (ephemeral)", Organization: []string{"Smart Bin"}},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authority{
+		cert:     cert,
+		certPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		key:      key,
+		validity: validity,
+	}, nil
+}
+
+// IssueDeviceCertificate firma un certificado de cliente para el dispositivo.
+func (a *Authority) IssueDeviceCertificate(_ context.Context, deviceID, csrPEM string) (*models.DeviceCertificate, error) {
+	issued := &models.DeviceCertificate{DeviceID: deviceID, Status: models.CertificateStatusActive}
+
+	var publicKey crypto.PublicKey
+	if csrPEM == "" {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrCertificateIssuance, err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrCertificateIssuance, err)
+		}
+		publicKey = key.Public()
+		issued.PrivateKeyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	} else {
+		csr, err := parseCSR(csrPEM, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = csr.PublicKey
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrCertificateIssuance, err)
+	}
+
+	// X.509 guarda los tiempos con precisión de segundos
+	now := time.Now().UTC().Truncate(time.Second)
+	notAfter := now.Add(a.validity)
+	if notAfter.After(a.cert.NotAfter) {
+		notAfter = a.cert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: deviceID},
+		DNSNames:     []string{deviceID},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, publicKey, a.key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrCertificateIssuance, err)
+	}
+
+	issued.CertificatePEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	issued.SerialNumber = SerialHex(serial)
+	issued.NotBefore = template.NotBefore
+	issued.ExpiresAt = template.NotAfter
+	return issued, nil
+}
+
+// CACertificatePEM retorna el certificado de la CA.
+func (a *Authority) CACertificatePEM() string {
+	return a.certPEM
+}
+
+// Certificate retorna el certificado de la CA parseado.
+func (a *Authority) Certificate() *x509.Certificate {
+	return a.cert
+}
+
+// SerialHex formatea un serial como lo guarda el dispositivo (hex en minúsculas).
+func SerialHex(serial *big.Int) string {
+	return hex.EncodeToString(serial.Bytes())
+}
+
+// parseCSR valida la firma del CSR y que su CN, si lo trae, sea el del dispositivo.
+func parseCSR(csrPEM, deviceID string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("%w: expected a PEM CERTIFICATE REQUEST block", models.ErrInvalidCSR)
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidCSR, err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidCSR, err)
+	}
+	if csr.Subject.CommonName != "" && csr.Subject.CommonName != deviceID {
+		return nil, fmt.Errorf("%w: CN %q does not match device %s", models.ErrInvalidCSR, csr.Subject.CommonName, deviceID)
+	}
+
+	return csr, nil
+}
+
+// parsePrivateKey acepta llaves PKCS#8, EC y PKCS#1.
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// publicKeysEqual compara dos llaves públicas.
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+// newSerial genera un serial aleatorio de 128 bits.
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -0,0 +1,60 @@
+package pki
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+)
+
+// revocationReasonCodes traduce los motivos a los códigos de RFC 5280 (CRLReason).
+var revocationReasonCodes = map[models.RevocationReason]int{
+	models.RevocationUnspecified:          0,
+	models.RevocationKeyCompromise:        1,
+	models.RevocationSuperseded:           4,
+	models.RevocationCessationOfOperation: 5,
+}
+
+// CreateCRL firma una CRL con los certificados revocados.
+// El número de CRL sale del reloj para que siempre crezca, incluso entre reinicios.
+func (a *Authority) CreateCRL(_ context.Context, revoked []*models.DeviceCertificate, nextUpdate time.Time) (string, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entries := make([]x509.RevocationListEntry, 0, len(revoked))
+	for _, certificate := range revoked {
+		serial, ok := new(big.Int).SetString(certificate.SerialNumber, 16)
+		if !ok {
+			return "", fmt.Errorf("invalid serial %q for device %s", certificate.SerialNumber, certificate.DeviceID)
+		}
+
+		revokedAt := now
+		if certificate.RevokedAt != nil {
+			revokedAt = certificate.RevokedAt.UTC()
+		}
+
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: revokedAt,
+			ReasonCode:     revocationReasonCodes[certificate.RevocationReason],
+		})
+	}
+
+	template := &x509.RevocationList{
+		Number:                    big.NewInt(time.Now().UnixNano()),
+		ThisUpdate:                now,
+		NextUpdate:                nextUpdate.UTC(),
+		RevokedCertificateEntries: entries,
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, template, a.cert, a.key)
+	if err != nil {
+		return "", fmt.Errorf("signing CRL: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})), nil
+}
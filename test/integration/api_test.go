@@ -0,0 +1,104 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/api/router"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/services"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/local"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/metrics"
+)
+
+// newAPI crea el router HTTP con los servicios en memoria que usan las rutas de jobs y webhooks.
+func newAPI(t *testing.T) (http.Handler, *services.TenantService) {
+	t.Helper()
+
+	cfg := &config.Config{
+		Server:   config.ServerConfig{ServiceName: "orchestrator-it", Environment: "test"},
+		Security: config.SecurityConfig{AdminAPIKey: "admin-key"},
+		AWS: config.AWSConfig{
+			Region: "us-east-1",
+			S3:     config.S3Config{BucketImages: "smart-bin-it-images", PresignedURLExpiry: 15 * time.Minute},
+		},
+	}
+
+	devices := local.NewDeviceRepository()
+	orgs := local.NewOrganizationRepository()
+	registry := metrics.NewRegistry()
+	alerts := services.NewAlertService(local.NewAlertRepository(), local.NewGroupRepository(), orgs, services.NewDeviceManager(devices), models.DefaultAlertRules())
+	tenants := services.NewTenantService(cfg, orgs, local.NewCredentialRepository(), devices, alerts)
+	if err := tenants.EnsureOrganization(context.Background(), models.DefaultOrgID, "Default"); err != nil {
+		t.Fatalf("EnsureOrganization() error = %v", err)
+	}
+
+	deps := &router.Dependencies{
+		JobManager: services.NewJobManager(cfg, local.NewJobRepository(), devices,
+			local.NewPresigner("us-east-1"), local.NewPublisher(), local.NewIoTPublisher(), registry),
+		DeviceManager: services.NewDeviceManager(devices),
+		Alerts:        alerts,
+		Tenants:       tenants,
+		Audit:         services.NewAuditService(local.NewAuditRepository()),
+		Metrics:       registry,
+	}
+	return router.NewRouter(cfg, deps), tenants
+}
+
+// TestClassificationCallbackRequiresCredential verifica que el callback de clasificación exija
+// una API key con permiso jobs:complete.
+func TestClassificationCallbackRequiresCredential(t *testing.T) {
+	api, tenants := newAPI(t)
+
+	classifier, err := tenants.CreateCredential(context.Background(), models.DefaultOrgID, &models.CreateCredentialRequest{
+		Name:  "classifier",
+		Roles: []models.Role{models.RoleClassifier},
+	})
+	if err != nil {
+		t.Fatalf("CreateCredential(classifier) error = %v", err)
+	}
+	operator, err := tenants.CreateCredential(context.Background(), models.DefaultOrgID, &models.CreateCredentialRequest{
+		Name:  "dashboard",
+		Roles: []models.Role{models.RoleOperator},
+	})
+	if err != nil {
+		t.Fatalf("CreateCredential(operator) error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		apiKey string
+		want   int
+	}{
+		{"Without API key", "", http.StatusUnauthorized},
+		{"Invalid API key", "sbk_invalid", http.StatusUnauthorized},
+		{"Operator key", operator.APIKey, http.StatusForbidden},
+		{"Classifier key", classifier.APIKey, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/classification",
+				strings.NewReader(`{"job_id":"job_unknown","status":"failed","error":"timeout"}`))
+			request.Header.Set("Content-Type", "application/json")
+			if tt.apiKey != "" {
+				request.Header.Set("X-API-Key", tt.apiKey)
+			}
+
+			response := httptest.NewRecorder()
+			api.ServeHTTP(response, request)
+
+			// Con la key del classifier llega al servicio: el job no existe
+			if response.Code != tt.want {
+				t.Errorf("Status = %d, want %d (%s)", response.Code, tt.want, response.Body.String())
+			}
+		})
+	}
+}
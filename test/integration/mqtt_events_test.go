@@ -0,0 +1,112 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/mqtt"
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// recordingHandler guarda los eventos que le llegan al subscriber.
+type recordingHandler struct {
+	events chan *models.DeviceEvent
+}
+
+func (h *recordingHandler) HandleEvent(_ context.Context, event *models.DeviceEvent) (*models.DeviceEventResult, error) {
+	h.events <- event
+	return &models.DeviceEventResult{EventType: event.EventType, ProcessedAt: time.Now()}, nil
+}
+
+// TestEventSubscriber verifica la ingesta de bins/+/events y el envío de payloads inválidos al dead-letter topic.
+func TestEventSubscriber(t *testing.T) {
+	ctx := context.Background()
+
+	device := paho.NewClient(paho.NewClientOptions().
+		AddBroker(brokerURL()).
+		SetClientID("smart-bin-it-device"))
+	if token := device.Connect(); !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Skipf("MQTT broker not available at %s: %v", brokerURL(), token.Error())
+	}
+	defer device.Disconnect(250)
+
+	deadLetters := make(chan []byte, 1)
+	token := device.Subscribe("orchestrator/events/dead-letter", 1, func(_ paho.Client, message paho.Message) {
+		deadLetters <- message.Payload()
+	})
+	if !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("Subscribe() error = %v", token.Error())
+	}
+
+	client, err := mqtt.NewClient(config.IoTConfig{
+		Endpoint:        brokerURL(),
+		ClientID:        "orchestrator-it-events",
+		QoS:             1,
+		EventsTopic:     "bins/+/events",
+		SharedGroup:     "orchestrator-it",
+		DeadLetterTopic: "orchestrator/events/dead-letter",
+		ConnectTimeout:  5 * time.Second,
+		PublishTimeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Close()
+
+	handler := &recordingHandler{events: make(chan *models.DeviceEvent, 1)}
+	if err := mqtt.NewEventSubscriber(client, handler).Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// El device_id se toma del topic
+	publish(t, device, "bins/smart-bin-007/events",
+		`{"event_type":"device_status","timestamp":"2026-01-21T10:30:00Z","data":{"fill_level":40}}`)
+
+	select {
+	case event := <-handler.events:
+		if event.DeviceID != "smart-bin-007" || event.EventType != models.DeviceEventStatus {
+			t.Errorf("Event = %+v, want device_status from smart-bin-007", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event not received")
+	}
+
+	publish(t, device, "bins/smart-bin-007/events", `{"event_type":`)
+
+	select {
+	case payload := <-deadLetters:
+		var letter mqtt.DeadLetter
+		if err := json.Unmarshal(payload, &letter); err != nil {
+			t.Fatalf("Invalid dead letter %s: %v", payload, err)
+		}
+		if letter.Topic != "bins/smart-bin-007/events" || letter.Error == "" {
+			t.Errorf("Dead letter = %+v", letter)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Dead letter not received")
+	}
+
+	select {
+	case event := <-handler.events:
+		t.Errorf("Malformed payload reached the handler: %+v", event)
+	default:
+	}
+}
+
+// publish publica payload como lo haría un dispositivo.
+func publish(t *testing.T, client paho.Client, topic, payload string) {
+	t.Helper()
+	token := client.Publish(topic, 1, false, payload)
+	if !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("Publish(%s) error = %v", topic, token.Error())
+	}
+}
@@ -0,0 +1,91 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/config"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/domain/models"
+	"github.com/Junior_Jurado/Caneca-Inteligente-Orchestrator/internal/infrastructure/mqtt"
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// brokerURL retorna el broker de pruebas (Mosquitto local por defecto).
+//
+//	docker run --rm -p 1883:1883 eclipse-mosquitto:2 mosquitto -c /mosquitto-no-auth.conf
+func brokerURL() string {
+	if url := os.Getenv("MQTT_BROKER_URL"); url != "" {
+		return url
+	}
+	return "tcp://localhost:1883"
+}
+
+// TestPublishDecision verifica que la decisión llega a bins/{device_id}/decisions.
+func TestPublishDecision(t *testing.T) {
+	ctx := context.Background()
+
+	received := make(chan []byte, 1)
+	subscriber := paho.NewClient(paho.NewClientOptions().
+		AddBroker(brokerURL()).
+		SetClientID("orchestrator-it-subscriber"))
+	if token := subscriber.Connect(); !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Skipf("MQTT broker not available at %s: %v", brokerURL(), token.Error())
+	}
+	defer subscriber.Disconnect(250)
+
+	token := subscriber.Subscribe("bins/+/decisions", 1, func(_ paho.Client, message paho.Message) {
+		received <- message.Payload()
+	})
+	if !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("Subscribe() error = %v", token.Error())
+	}
+
+	client, err := mqtt.NewClient(config.IoTConfig{
+		Endpoint:       brokerURL(),
+		ClientID:       "orchestrator-it-publisher",
+		QoS:            1,
+		DecisionTopic:  "bins/{device_id}/decisions",
+		ConnectTimeout: 5 * time.Second,
+		PublishTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Close()
+
+	publisher := mqtt.NewIoTPublisher(client)
+	err = publisher.PublishDecision(ctx, &models.DecisionMessage{
+		JobID:    "job-it-001",
+		DeviceID: "smart-bin-001",
+		Decision: &models.Decision{
+			Action:         string(models.DecisionActionAccept),
+			BinCompartment: "recyclable",
+			Message:        "Deposit in recyclable compartment",
+		},
+		PublishedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("PublishDecision() error = %v", err)
+	}
+
+	select {
+	case payload := <-received:
+		var message models.DecisionMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			t.Fatalf("Invalid payload %s: %v", payload, err)
+		}
+		if message.JobID != "job-it-001" || message.Decision.Action != "accept" {
+			t.Errorf("Received = %+v", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Decision not received")
+	}
+}